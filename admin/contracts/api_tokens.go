@@ -0,0 +1,15 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// APITokens defines the contract for personal access token operations required by the admin API.
+type APITokens interface {
+	// ListByUser retrieves all tokens of a user, newest first.
+	ListByUser(ctx context.Context, userID string) ([]*models.APIToken, error)
+	// DeleteByUserAndID removes a token only if it belongs to the given user.
+	DeleteByUserAndID(ctx context.Context, userID string, id int64) error
+}
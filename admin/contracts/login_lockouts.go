@@ -0,0 +1,15 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// LoginLockouts defines the contract for failed login tracking required by the admin API.
+type LoginLockouts interface {
+	// Get retrieves the lockout record for a user.
+	Get(ctx context.Context, userID string) (*models.LoginLockout, error)
+	// Delete removes the lockout record for a user.
+	Delete(ctx context.Context, userID string) error
+}
@@ -0,0 +1,22 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// OAuthClients defines the contract for the authorization server's client
+// registry required by the admin API.
+type OAuthClients interface {
+	// Get retrieves a client by ID.
+	Get(ctx context.Context, key string) (*models.OAuthClient, error)
+	// Set creates or replaces a client.
+	Set(ctx context.Context, key string, client *models.OAuthClient) error
+	// List returns a paginated list of clients ordered by created_at DESC.
+	List(ctx context.Context, limit, offset int) ([]*models.OAuthClient, error)
+	// Count returns the total number of clients.
+	Count(ctx context.Context) (float64, error)
+	// Delete removes a client, with the consents users granted it.
+	Delete(ctx context.Context, key string) error
+}
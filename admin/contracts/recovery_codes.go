@@ -0,0 +1,9 @@
+package contracts
+
+import "context"
+
+// RecoveryCodes defines the contract for MFA recovery code operations required by the admin API.
+type RecoveryCodes interface {
+	// CountUnusedByUser returns the number of unconsumed recovery codes for the given userID.
+	CountUnusedByUser(ctx context.Context, userID string) (float64, error)
+}
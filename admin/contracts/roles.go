@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Roles defines the contract for admin role operations required by the admin API.
+type Roles interface {
+	// Get retrieves a role by its slug.
+	Get(ctx context.Context, key string) (*models.Role, error)
+	// List returns every role ordered by ID.
+	List(ctx context.Context) ([]*models.Role, error)
+	// ListByUser returns the roles assigned to a user, ordered by ID.
+	ListByUser(ctx context.Context, userID string) ([]*models.Role, error)
+	// Assign gives a user a role they do not already hold.
+	Assign(ctx context.Context, userID, roleID string) error
+	// Unassign takes a role from a user.
+	Unassign(ctx context.Context, userID, roleID string) error
+}
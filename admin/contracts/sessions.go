@@ -8,11 +8,11 @@ import (
 
 // Sessions defines the contract for session operations required by the admin API.
 type Sessions interface {
-	// Get retrieves a session by its token.
-	Get(ctx context.Context, token string) (*models.Session, error)
-	// Delete removes a session by its token.
-	Delete(ctx context.Context, token string) error
-	// ListByUser returns up to limit session tokens belonging to userID.
+	// GetByID retrieves a session by its public ID.
+	GetByID(ctx context.Context, id string) (*models.Session, error)
+	// DeleteWithUserIndex removes a session and its user index entry.
+	DeleteWithUserIndex(ctx context.Context, session *models.Session) error
+	// ListByUser returns the public IDs of up to limit sessions belonging to userID.
 	ListByUser(ctx context.Context, userID string, limit int) ([]string, error)
 	// DeleteByUser revokes all sessions for the given userID.
 	DeleteByUser(ctx context.Context, userID string) error
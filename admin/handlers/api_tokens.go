@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/admin/contracts"
+	"github.com/zoobzio/sumatra/admin/transformers"
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ListUserAPITokens returns a user's personal access tokens.
+var ListUserAPITokens = rocco.GET("/users/{id}/tokens", requirePermission(models.PermissionUsersRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminAPITokenListResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	tokens := sum.MustUse[contracts.APITokens](req.Context)
+
+	id := req.Params.Path["id"]
+
+	if _, err := users.Get(req.Context, id); err != nil {
+		return wire.AdminAPITokenListResponse{}, ErrUserNotFound
+	}
+
+	list, err := tokens.ListByUser(req.Context, id)
+	if err != nil {
+		return wire.AdminAPITokenListResponse{}, err
+	}
+
+	return transformers.APITokensToAdminList(list), nil
+})).WithSummary("List API tokens").
+	WithDescription("Returns the user's personal access tokens, newest first. Tokens themselves are never returned. Requires users:read.").
+	WithTags("API Tokens").
+	WithPathParams("id").
+	WithErrors(ErrUserNotFound, ErrPermissionDenied).
+	WithAuthentication()
+
+// RevokeUserAPIToken deletes one of a user's personal access tokens.
+var RevokeUserAPIToken = rocco.DELETE("/users/{id}/tokens/{tokenID}", requirePermission(models.PermissionTokensRevoke, func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	tokens := sum.MustUse[contracts.APITokens](req.Context)
+
+	id := req.Params.Path["id"]
+
+	tokenID, err := strconv.ParseInt(req.Params.Path["tokenID"], 10, 64)
+	if err != nil {
+		return rocco.NoBody{}, ErrAPITokenNotFound
+	}
+
+	if _, err := users.Get(req.Context, id); err != nil {
+		return rocco.NoBody{}, ErrUserNotFound
+	}
+
+	list, err := tokens.ListByUser(req.Context, id)
+	if err != nil {
+		return rocco.NoBody{}, err
+	}
+	found := false
+	for _, t := range list {
+		if t.ID == tokenID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return rocco.NoBody{}, ErrAPITokenNotFound
+	}
+
+	if err := tokens.DeleteByUserAndID(req.Context, id, tokenID); err != nil {
+		return rocco.NoBody{}, err
+	}
+
+	return rocco.NoBody{}, nil
+})).WithSummary("Revoke API token").
+	WithDescription("Deletes one of the user's personal access tokens. Requests made with it fail immediately. Requires tokens:revoke.").
+	WithTags("API Tokens").
+	WithPathParams("id", "tokenID").
+	WithErrors(ErrUserNotFound, ErrAPITokenNotFound, ErrPermissionDenied).
+	WithAuthentication().
+	WithSuccessStatus(204)
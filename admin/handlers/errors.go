@@ -6,6 +6,22 @@ import "github.com/zoobzio/rocco"
 var (
 	// ErrUserNotFound is returned when a requested user does not exist.
 	ErrUserNotFound = rocco.ErrNotFound.WithMessage("user not found")
-	// ErrSessionNotFound is returned when a requested session token cannot be found.
+	// ErrSessionNotFound is returned when a requested session cannot be found.
 	ErrSessionNotFound = rocco.ErrNotFound.WithMessage("session not found")
+	// ErrAPITokenNotFound is returned when an API token does not exist or belongs to another user.
+	ErrAPITokenNotFound = rocco.ErrNotFound.WithMessage("api token not found")
+	// ErrOAuthClientNotFound is returned when a requested OAuth client does not exist.
+	ErrOAuthClientNotFound = rocco.ErrNotFound.WithMessage("oauth client not found")
+	// ErrInvalidOAuthClient is returned when a client registration is inconsistent,
+	// such as a public client allowed client_credentials.
+	ErrInvalidOAuthClient = rocco.ErrBadRequest.WithMessage("invalid oauth client")
+	// ErrPublicOAuthClient is returned when rotating the secret of a public client.
+	ErrPublicOAuthClient = rocco.ErrConflict.WithMessage("public clients have no secret")
+	// ErrPermissionDenied is returned when the operator's roles do not grant the
+	// permission an endpoint requires.
+	ErrPermissionDenied = rocco.ErrForbidden.WithMessage("permission denied")
+	// ErrRoleNotFound is returned when a role does not exist or is not assigned to the user.
+	ErrRoleNotFound = rocco.ErrNotFound.WithMessage("role not found")
+	// ErrOwnRoles is returned when an operator assigns or unassigns their own roles.
+	ErrOwnRoles = rocco.ErrForbidden.WithMessage("operators cannot change their own roles")
 )
@@ -9,9 +9,35 @@ func All() []rocco.Endpoint {
 		ListUsers,
 		GetUser,
 		DeleteUser,
+		GetLockout,
+		ClearLockout,
 
 		// Sessions
 		ListSessions,
 		RevokeSession,
+
+		// API tokens
+		ListUserAPITokens,
+		RevokeUserAPIToken,
+
+		// Roles
+		ListRoles,
+		ListUserRoles,
+		AssignRole,
+		UnassignRole,
+
+		// OAuth clients
+		ListOAuthClients,
+		GetOAuthClient,
+		CreateOAuthClient,
+		UpdateOAuthClient,
+		RotateOAuthClientSecret,
+		DeleteOAuthClient,
+
+		// MFA
+		GetRecoveryCodeCount,
+
+		// Reports
+		GetPasswordHashReport,
 	}
 }
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/admin/contracts"
+	"github.com/zoobzio/sumatra/admin/transformers"
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// GetLockout reports a user's failed login count and lockout state.
+var GetLockout = rocco.GET("/users/{id}/lockout", requirePermission(models.PermissionUsersRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminLockoutResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	lockouts := sum.MustUse[contracts.LoginLockouts](req.Context)
+
+	id := req.Params.Path["id"]
+
+	if _, err := users.Get(req.Context, id); err != nil {
+		return wire.AdminLockoutResponse{}, ErrUserNotFound
+	}
+
+	l, err := lockouts.Get(req.Context, id)
+	if errors.Is(err, grub.ErrNotFound) {
+		l, err = nil, nil
+	}
+	if err != nil {
+		return wire.AdminLockoutResponse{}, err
+	}
+
+	return transformers.LockoutToAdminResponse(id, l, time.Now()), nil
+})).WithSummary("Get lockout state").
+	WithDescription("Returns the user's failed login count and whether the account is currently locked. Requires users:read.").
+	WithTags("Users").
+	WithPathParams("id").
+	WithErrors(ErrUserNotFound, ErrPermissionDenied).
+	WithAuthentication()
+
+// ClearLockout unlocks a user's account and resets their failed login history.
+var ClearLockout = rocco.DELETE("/users/{id}/lockout", requirePermission(models.PermissionLockoutsManage, func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	lockouts := sum.MustUse[contracts.LoginLockouts](req.Context)
+
+	id := req.Params.Path["id"]
+
+	if _, err := users.Get(req.Context, id); err != nil {
+		return rocco.NoBody{}, ErrUserNotFound
+	}
+
+	if err := lockouts.Delete(req.Context, id); err != nil && !errors.Is(err, grub.ErrNotFound) {
+		return rocco.NoBody{}, err
+	}
+
+	return rocco.NoBody{}, nil
+})).WithSummary("Clear lockout").
+	WithDescription("Unlocks the user's account and resets the failed login history. Succeeds if the user has no lockout. Requires lockouts:manage.").
+	WithTags("Users").
+	WithPathParams("id").
+	WithErrors(ErrUserNotFound, ErrPermissionDenied).
+	WithAuthentication().
+	WithSuccessStatus(204)
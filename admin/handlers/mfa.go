@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/admin/contracts"
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// GetRecoveryCodeCount reports how many unused MFA recovery codes a user has left.
+var GetRecoveryCodeCount = rocco.GET("/users/{id}/mfa/recovery-codes", requirePermission(models.PermissionUsersRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminRecoveryCodesResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	recoveryCodes := sum.MustUse[contracts.RecoveryCodes](req.Context)
+
+	id := req.Params.Path["id"]
+
+	if _, err := users.Get(req.Context, id); err != nil {
+		return wire.AdminRecoveryCodesResponse{}, ErrUserNotFound
+	}
+
+	remaining, err := recoveryCodes.CountUnusedByUser(req.Context, id)
+	if err != nil {
+		return wire.AdminRecoveryCodesResponse{}, err
+	}
+
+	return wire.AdminRecoveryCodesResponse{
+		UserID:    id,
+		Remaining: int(remaining),
+	}, nil
+})).WithSummary("Get recovery code count").
+	WithDescription("Returns the number of unused MFA recovery codes for a user. Code values are never exposed. Requires users:read.").
+	WithTags("MFA").
+	WithPathParams("id").
+	WithErrors(ErrUserNotFound, ErrPermissionDenied).
+	WithAuthentication()
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/admin/contracts"
+	"github.com/zoobzio/sumatra/admin/transformers"
+	"github.com/zoobzio/sumatra/admin/wire"
+	intauthserver "github.com/zoobzio/sumatra/internal/authserver"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ListOAuthClients returns a paginated list of the clients registered with the
+// authorization server. Accepts optional query parameters: limit (default 50)
+// and offset (default 0).
+var ListOAuthClients = rocco.GET("/oauth-clients", requirePermission(models.PermissionOAuthClientsRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminOAuthClientListResponse, error) {
+	clients := sum.MustUse[contracts.OAuthClients](req.Context)
+
+	limit := 50
+	if l := req.Params.Query["limit"]; l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if o := req.Params.Query["offset"]; o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	list, err := clients.List(req.Context, limit, offset)
+	if err != nil {
+		return wire.AdminOAuthClientListResponse{}, err
+	}
+
+	total, err := clients.Count(req.Context)
+	if err != nil {
+		return wire.AdminOAuthClientListResponse{}, err
+	}
+
+	return transformers.OAuthClientsToAdminList(list, int(total)), nil
+})).WithSummary("List OAuth clients").
+	WithDescription("Returns a paginated list of the clients registered with the authorization server. Requires oauth_clients:read.").
+	WithTags("OAuth Clients").
+	WithQueryParams("limit", "offset").
+	WithErrors(ErrPermissionDenied).
+	WithAuthentication()
+
+// GetOAuthClient returns a single client by ID.
+var GetOAuthClient = rocco.GET("/oauth-clients/{id}", requirePermission(models.PermissionOAuthClientsRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminOAuthClientResponse, error) {
+	clients := sum.MustUse[contracts.OAuthClients](req.Context)
+
+	client, err := clients.Get(req.Context, req.Params.Path["id"])
+	if err != nil {
+		return wire.AdminOAuthClientResponse{}, ErrOAuthClientNotFound
+	}
+
+	return transformers.OAuthClientToAdminResponse(client), nil
+})).WithSummary("Get OAuth client").
+	WithDescription("Returns a single registered client by ID. Requires oauth_clients:read.").
+	WithTags("OAuth Clients").
+	WithPathParams("id").
+	WithErrors(ErrOAuthClientNotFound, ErrPermissionDenied).
+	WithAuthentication()
+
+// CreateOAuthClient registers a client. Confidential clients are issued a
+// secret, returned only in this response.
+var CreateOAuthClient = rocco.POST("/oauth-clients", requirePermission(models.PermissionOAuthClientsManage, func(req *rocco.Request[wire.AdminOAuthClientCreateRequest]) (wire.AdminOAuthClientSecretResponse, error) {
+	clients := sum.MustUse[contracts.OAuthClients](req.Context)
+	tokenHasher := sum.MustUse[*intsession.TokenHasher](req.Context)
+
+	id, err := intauthserver.NewClientID()
+	if err != nil {
+		return wire.AdminOAuthClientSecretResponse{}, err
+	}
+
+	var secret, secretHash string
+	if !req.Body.Public {
+		secret, err = intauthserver.NewClientSecret()
+		if err != nil {
+			return wire.AdminOAuthClientSecretResponse{}, err
+		}
+		secretHash = tokenHasher.Hash(secret)
+	}
+
+	client := transformers.OAuthClientFromCreate(req.Body, id, secretHash)
+	if err := client.Validate(); err != nil {
+		return wire.AdminOAuthClientSecretResponse{}, ErrInvalidOAuthClient.WithMessage(err.Error())
+	}
+	now := time.Now()
+	client.CreatedAt = now
+	client.UpdatedAt = now
+
+	if err := clients.Set(req.Context, client.ID, client); err != nil {
+		return wire.AdminOAuthClientSecretResponse{}, err
+	}
+
+	return transformers.OAuthClientWithSecret(client, secret), nil
+})).WithSummary("Register OAuth client").
+	WithDescription("Registers a client with the authorization server. The secret of a confidential client is returned only in this response. Requires oauth_clients:manage.").
+	WithTags("OAuth Clients").
+	WithErrors(ErrInvalidOAuthClient, ErrPermissionDenied).
+	WithAuthentication().
+	WithSuccessStatus(201)
+
+// UpdateOAuthClient replaces a client's name, redirect URIs, grant types,
+// scopes and consent setting.
+var UpdateOAuthClient = rocco.PUT("/oauth-clients/{id}", requirePermission(models.PermissionOAuthClientsManage, func(req *rocco.Request[wire.AdminOAuthClientUpdateRequest]) (wire.AdminOAuthClientResponse, error) {
+	clients := sum.MustUse[contracts.OAuthClients](req.Context)
+
+	client, err := clients.Get(req.Context, req.Params.Path["id"])
+	if err != nil {
+		return wire.AdminOAuthClientResponse{}, ErrOAuthClientNotFound
+	}
+
+	transformers.ApplyOAuthClientUpdate(req.Body, client)
+	if err := client.Validate(); err != nil {
+		return wire.AdminOAuthClientResponse{}, ErrInvalidOAuthClient.WithMessage(err.Error())
+	}
+	client.UpdatedAt = time.Now()
+
+	if err := clients.Set(req.Context, client.ID, client); err != nil {
+		return wire.AdminOAuthClientResponse{}, err
+	}
+
+	return transformers.OAuthClientToAdminResponse(client), nil
+})).WithSummary("Update OAuth client").
+	WithDescription("Replaces a client's name, redirect URIs, grant types, scopes and consent setting. Whether it is public cannot be changed. Requires oauth_clients:manage.").
+	WithTags("OAuth Clients").
+	WithPathParams("id").
+	WithErrors(ErrOAuthClientNotFound, ErrInvalidOAuthClient, ErrPermissionDenied).
+	WithAuthentication()
+
+// RotateOAuthClientSecret replaces a confidential client's secret. The old
+// secret stops working immediately.
+var RotateOAuthClientSecret = rocco.POST("/oauth-clients/{id}/secret", requirePermission(models.PermissionOAuthClientsManage, func(req *rocco.Request[rocco.NoBody]) (wire.AdminOAuthClientSecretResponse, error) {
+	clients := sum.MustUse[contracts.OAuthClients](req.Context)
+	tokenHasher := sum.MustUse[*intsession.TokenHasher](req.Context)
+
+	client, err := clients.Get(req.Context, req.Params.Path["id"])
+	if err != nil {
+		return wire.AdminOAuthClientSecretResponse{}, ErrOAuthClientNotFound
+	}
+	if client.Public {
+		return wire.AdminOAuthClientSecretResponse{}, ErrPublicOAuthClient
+	}
+
+	secret, err := intauthserver.NewClientSecret()
+	if err != nil {
+		return wire.AdminOAuthClientSecretResponse{}, err
+	}
+	client.SecretHash = tokenHasher.Hash(secret)
+	client.UpdatedAt = time.Now()
+
+	if err := clients.Set(req.Context, client.ID, client); err != nil {
+		return wire.AdminOAuthClientSecretResponse{}, err
+	}
+
+	return transformers.OAuthClientWithSecret(client, secret), nil
+})).WithSummary("Rotate OAuth client secret").
+	WithDescription("Issues a confidential client a new secret, returned only in this response. The old secret stops working immediately. Requires oauth_clients:manage.").
+	WithTags("OAuth Clients").
+	WithPathParams("id").
+	WithErrors(ErrOAuthClientNotFound, ErrPublicOAuthClient, ErrPermissionDenied).
+	WithAuthentication()
+
+// DeleteOAuthClient removes a client and the consents users granted it.
+// Tokens already issued to it remain valid until they expire.
+var DeleteOAuthClient = rocco.DELETE("/oauth-clients/{id}", requirePermission(models.PermissionOAuthClientsManage, func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	clients := sum.MustUse[contracts.OAuthClients](req.Context)
+
+	id := req.Params.Path["id"]
+	if _, err := clients.Get(req.Context, id); err != nil {
+		return rocco.NoBody{}, ErrOAuthClientNotFound
+	}
+
+	if err := clients.Delete(req.Context, id); err != nil {
+		return rocco.NoBody{}, err
+	}
+
+	return rocco.NoBody{}, nil
+})).WithSummary("Delete OAuth client").
+	WithDescription("Deletes a client and the consents users granted it. Access tokens already issued remain valid until they expire; its refresh tokens stop working. Requires oauth_clients:manage.").
+	WithTags("OAuth Clients").
+	WithPathParams("id").
+	WithErrors(ErrOAuthClientNotFound, ErrPermissionDenied).
+	WithAuthentication().
+	WithSuccessStatus(204)
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/admin/contracts"
+	"github.com/zoobzio/sumatra/admin/transformers"
+	"github.com/zoobzio/sumatra/admin/wire"
+	intpassword "github.com/zoobzio/sumatra/internal/password"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// passwordReportPageSize is how many users the password hash report reads per query.
+const passwordReportPageSize = 500
+
+// GetPasswordHashReport counts users per password hash parameter set.
+var GetPasswordHashReport = rocco.GET("/reports/password-hashes", requirePermission(models.PermissionReportsRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminPasswordHashReport, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	hasher := sum.MustUse[*intpassword.Hasher](req.Context)
+
+	counts := make(map[string]int)
+	withoutPassword := 0
+	for offset := 0; ; offset += passwordReportPageSize {
+		page, err := users.List(req.Context, passwordReportPageSize, offset)
+		if err != nil {
+			return wire.AdminPasswordHashReport{}, err
+		}
+		for _, u := range page {
+			if u.PasswordHash == nil || *u.PasswordHash == "" {
+				withoutPassword++
+				continue
+			}
+			counts[intpassword.Describe(*u.PasswordHash)]++
+		}
+		if len(page) < passwordReportPageSize {
+			break
+		}
+	}
+
+	return transformers.PasswordHashCountsToAdminReport(counts, hasher.Parameters().String(), withoutPassword), nil
+})).WithSummary("Password hash report").
+	WithDescription("Counts users per password hash parameter set. Users on outdated parameters are upgraded on their next successful login. Requires reports:read.").
+	WithTags("Reports").
+	WithErrors(ErrPermissionDenied).
+	WithAuthentication()
@@ -0,0 +1,19 @@
+package handlers
+
+import "github.com/zoobzio/rocco"
+
+// requirePermission wraps an endpoint's handler so it only runs for operators
+// whose roles grant permission. The operator authenticator carries those
+// permissions as the identity's scopes.
+//
+//	var DeleteUser = rocco.DELETE("/users/{id}", requirePermission(models.PermissionUsersDelete,
+//		func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) { ... }))
+func requirePermission[In, Out any](permission string, fn func(*rocco.Request[In]) (Out, error)) func(*rocco.Request[In]) (Out, error) {
+	return func(req *rocco.Request[In]) (Out, error) {
+		if req.Identity == nil || !req.Identity.HasScope(permission) {
+			var zero Out
+			return zero, ErrPermissionDenied.WithMessage("permission required: " + permission)
+		}
+		return fn(req)
+	}
+}
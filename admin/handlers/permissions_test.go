@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"testing"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// scopedIdentity is an operator identity granting scopes.
+type scopedIdentity struct {
+	scopes []string
+}
+
+func (i scopedIdentity) ID() string                 { return "op1" }
+func (i scopedIdentity) TenantID() string           { return "" }
+func (i scopedIdentity) Email() string              { return "" }
+func (i scopedIdentity) Scopes() []string           { return i.scopes }
+func (i scopedIdentity) Roles() []string            { return nil }
+func (i scopedIdentity) HasScope(scope string) bool { return slices.Contains(i.scopes, scope) }
+func (i scopedIdentity) HasRole(string) bool        { return false }
+func (i scopedIdentity) Stats() map[string]int      { return nil }
+
+func guardedHandler(called *bool) func(*rocco.Request[rocco.NoBody]) (string, error) {
+	return requirePermission(models.PermissionUsersRead, func(*rocco.Request[rocco.NoBody]) (string, error) {
+		*called = true
+		return "ok", nil
+	})
+}
+
+func TestRequirePermission_Granted(t *testing.T) {
+	var called bool
+	out, err := guardedHandler(&called)(&rocco.Request[rocco.NoBody]{
+		Identity: scopedIdentity{scopes: []string{models.PermissionSessionsRead, models.PermissionUsersRead}},
+	})
+	if err != nil || out != "ok" || !called {
+		t.Errorf("got %q, %v (called %v), want the handler to run", out, err, called)
+	}
+}
+
+func TestRequirePermission_Missing(t *testing.T) {
+	var called bool
+	out, err := guardedHandler(&called)(&rocco.Request[rocco.NoBody]{
+		Identity: scopedIdentity{scopes: []string{models.PermissionSessionsRead}},
+	})
+	var rerr *rocco.Error
+	if !errors.As(err, &rerr) || rerr.Status() != ErrPermissionDenied.Status() {
+		t.Errorf("got %v, want ErrPermissionDenied", err)
+	}
+	if called || out != "" {
+		t.Errorf("handler ran without permission: %q", out)
+	}
+}
+
+func TestRequirePermission_NoIdentity(t *testing.T) {
+	var called bool
+	if _, err := guardedHandler(&called)(&rocco.Request[rocco.NoBody]{}); err == nil || called {
+		t.Errorf("got %v (called %v), want ErrPermissionDenied", err, called)
+	}
+}
+
+// TestAll_EveryEndpointRequiresPermission walks the endpoints All registers and
+// fails for any whose handler is not wrapped in requirePermission, so a new
+// admin endpoint cannot ship open to every operator.
+func TestAll_EveryEndpointRequiresPermission(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, ok := pkgs["handlers"]
+	if !ok {
+		t.Fatal("handlers package not found")
+	}
+
+	// The permission each endpoint variable is declared with, or "" if none.
+	permissions := map[string]string{}
+	var registered []string
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					vs, ok := spec.(*ast.ValueSpec)
+					if !ok || len(vs.Names) != 1 || len(vs.Values) != 1 {
+						continue
+					}
+					if route := routeCall(vs.Values[0]); route != nil {
+						permissions[vs.Names[0].Name] = wrappedPermission(route)
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Name.Name == "All" {
+					registered = endpointNames(d)
+				}
+			}
+		}
+	}
+
+	if len(registered) == 0 {
+		t.Fatal("All registers no endpoints")
+	}
+	for _, name := range registered {
+		permission, ok := permissions[name]
+		switch {
+		case !ok:
+			t.Errorf("%s: not declared with a rocco route in this package", name)
+		case permission == "":
+			t.Errorf("%s: handler is not wrapped in requirePermission", name)
+		}
+	}
+}
+
+// routeCall returns the rocco.GET/POST/... call at the root of an endpoint
+// declaration's builder chain, or nil.
+func routeCall(expr ast.Expr) *ast.CallExpr {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return nil
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "rocco" {
+			switch sel.Sel.Name {
+			case "GET", "POST", "PUT", "PATCH", "DELETE":
+				return call
+			}
+			return nil
+		}
+		expr = sel.X
+	}
+}
+
+// wrappedPermission returns the permission a route's handler is wrapped with,
+// rendered as written, or "" if it is not wrapped in requirePermission.
+func wrappedPermission(route *ast.CallExpr) string {
+	if len(route.Args) != 2 {
+		return ""
+	}
+	call, ok := route.Args[1].(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return ""
+	}
+	fn, ok := call.Fun.(*ast.Ident)
+	if !ok || fn.Name != "requirePermission" {
+		return ""
+	}
+	perm, ok := call.Args[0].(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	return perm.Sel.Name
+}
+
+// endpointNames returns the identifiers in All's returned slice literal.
+func endpointNames(all *ast.FuncDecl) []string {
+	var names []string
+	ast.Inspect(all.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			if id, ok := elt.(*ast.Ident); ok {
+				names = append(names, id.Name)
+			}
+		}
+		return false
+	})
+	return names
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"slices"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/admin/contracts"
+	"github.com/zoobzio/sumatra/admin/transformers"
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ListRoles returns every admin role and the permissions it grants.
+var ListRoles = rocco.GET("/roles", requirePermission(models.PermissionUsersRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminRoleListResponse, error) {
+	roles := sum.MustUse[contracts.Roles](req.Context)
+
+	list, err := roles.List(req.Context)
+	if err != nil {
+		return wire.AdminRoleListResponse{}, err
+	}
+
+	return transformers.RolesToAdminList(list), nil
+})).WithSummary("List roles").
+	WithDescription("Returns every admin role and the permissions it grants. Requires users:read.").
+	WithTags("Roles").
+	WithErrors(ErrPermissionDenied).
+	WithAuthentication()
+
+// ListUserRoles returns the roles assigned to a user.
+var ListUserRoles = rocco.GET("/users/{id}/roles", requirePermission(models.PermissionUsersRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminRoleListResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	roles := sum.MustUse[contracts.Roles](req.Context)
+
+	id := req.Params.Path["id"]
+
+	if _, err := users.Get(req.Context, id); err != nil {
+		return wire.AdminRoleListResponse{}, ErrUserNotFound
+	}
+
+	list, err := roles.ListByUser(req.Context, id)
+	if err != nil {
+		return wire.AdminRoleListResponse{}, err
+	}
+
+	return transformers.RolesToAdminList(list), nil
+})).WithSummary("List user roles").
+	WithDescription("Returns the admin roles assigned to the user. Requires users:read.").
+	WithTags("Roles").
+	WithPathParams("id").
+	WithErrors(ErrUserNotFound, ErrPermissionDenied).
+	WithAuthentication()
+
+// AssignRole gives a user a role. Assigning a role the user already holds
+// succeeds without change.
+var AssignRole = rocco.PUT("/users/{id}/roles/{roleID}", requirePermission(models.PermissionRolesManage, func(req *rocco.Request[rocco.NoBody]) (wire.AdminRoleListResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	roles := sum.MustUse[contracts.Roles](req.Context)
+
+	id := req.Params.Path["id"]
+	roleID := req.Params.Path["roleID"]
+
+	if id == req.Identity.ID() {
+		return wire.AdminRoleListResponse{}, ErrOwnRoles
+	}
+	if _, err := users.Get(req.Context, id); err != nil {
+		return wire.AdminRoleListResponse{}, ErrUserNotFound
+	}
+	if _, err := roles.Get(req.Context, roleID); err != nil {
+		return wire.AdminRoleListResponse{}, ErrRoleNotFound
+	}
+
+	held, err := roles.ListByUser(req.Context, id)
+	if err != nil {
+		return wire.AdminRoleListResponse{}, err
+	}
+	if !slices.ContainsFunc(held, func(r *models.Role) bool { return r.ID == roleID }) {
+		if err := roles.Assign(req.Context, id, roleID); err != nil {
+			return wire.AdminRoleListResponse{}, err
+		}
+		if held, err = roles.ListByUser(req.Context, id); err != nil {
+			return wire.AdminRoleListResponse{}, err
+		}
+	}
+
+	return transformers.RolesToAdminList(held), nil
+})).WithSummary("Assign role").
+	WithDescription("Gives the user a role and returns the roles they now hold. The user still needs is_admin to reach the admin API. Operators cannot change their own roles. Requires roles:manage.").
+	WithTags("Roles").
+	WithPathParams("id", "roleID").
+	WithErrors(ErrUserNotFound, ErrRoleNotFound, ErrOwnRoles, ErrPermissionDenied).
+	WithAuthentication()
+
+// UnassignRole takes a role from a user.
+var UnassignRole = rocco.DELETE("/users/{id}/roles/{roleID}", requirePermission(models.PermissionRolesManage, func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	roles := sum.MustUse[contracts.Roles](req.Context)
+
+	id := req.Params.Path["id"]
+	roleID := req.Params.Path["roleID"]
+
+	if id == req.Identity.ID() {
+		return rocco.NoBody{}, ErrOwnRoles
+	}
+	if _, err := users.Get(req.Context, id); err != nil {
+		return rocco.NoBody{}, ErrUserNotFound
+	}
+
+	held, err := roles.ListByUser(req.Context, id)
+	if err != nil {
+		return rocco.NoBody{}, err
+	}
+	if !slices.ContainsFunc(held, func(r *models.Role) bool { return r.ID == roleID }) {
+		return rocco.NoBody{}, ErrRoleNotFound
+	}
+
+	if err := roles.Unassign(req.Context, id, roleID); err != nil {
+		return rocco.NoBody{}, err
+	}
+
+	return rocco.NoBody{}, nil
+})).WithSummary("Unassign role").
+	WithDescription("Takes a role from the user. Operators cannot change their own roles. Requires roles:manage.").
+	WithTags("Roles").
+	WithPathParams("id", "roleID").
+	WithErrors(ErrUserNotFound, ErrRoleNotFound, ErrOwnRoles, ErrPermissionDenied).
+	WithAuthentication().
+	WithSuccessStatus(204)
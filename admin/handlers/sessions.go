@@ -10,7 +10,7 @@ import (
 )
 
 // ListSessions returns all sessions for a given user_id query parameter.
-var ListSessions = rocco.GET("/sessions", func(req *rocco.Request[rocco.NoBody]) (wire.AdminSessionListResponse, error) {
+var ListSessions = rocco.GET("/sessions", requirePermission(models.PermissionSessionsRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminSessionListResponse, error) {
 	sessions := sum.MustUse[contracts.Sessions](req.Context)
 
 	userID := req.Params.Query["user_id"]
@@ -18,14 +18,14 @@ var ListSessions = rocco.GET("/sessions", func(req *rocco.Request[rocco.NoBody])
 		return wire.AdminSessionListResponse{}, rocco.ErrBadRequest.WithMessage("query parameter 'user_id' is required")
 	}
 
-	tokens, err := sessions.ListByUser(req.Context, userID, 0)
+	ids, err := sessions.ListByUser(req.Context, userID, 0)
 	if err != nil {
 		return wire.AdminSessionListResponse{}, err
 	}
 
-	records := make([]*models.Session, 0, len(tokens))
-	for _, token := range tokens {
-		s, err := sessions.Get(req.Context, token)
+	records := make([]*models.Session, 0, len(ids))
+	for _, id := range ids {
+		s, err := sessions.GetByID(req.Context, id)
 		if err != nil || s == nil {
 			continue
 		}
@@ -33,31 +33,31 @@ var ListSessions = rocco.GET("/sessions", func(req *rocco.Request[rocco.NoBody])
 	}
 
 	return transformers.SessionsToAdminList(records), nil
-}).WithSummary("List sessions").
-	WithDescription("Returns all active sessions for the specified user. The user_id query parameter is required.").
+})).WithSummary("List sessions").
+	WithDescription("Returns all active sessions for the specified user. The user_id query parameter is required. Requires sessions:read.").
 	WithTags("Sessions").
 	WithQueryParams("user_id").
+	WithErrors(ErrPermissionDenied).
 	WithAuthentication()
 
-// RevokeSession deletes a specific session by its token.
-var RevokeSession = rocco.DELETE("/sessions/{token}", func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+// RevokeSession deletes a specific session by its public ID.
+var RevokeSession = rocco.DELETE("/sessions/{id}", requirePermission(models.PermissionSessionsRevoke, func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
 	sessions := sum.MustUse[contracts.Sessions](req.Context)
 
-	token := req.Params.Path["token"]
-
-	if _, err := sessions.Get(req.Context, token); err != nil {
+	s, err := sessions.GetByID(req.Context, req.Params.Path["id"])
+	if err != nil || s == nil {
 		return rocco.NoBody{}, ErrSessionNotFound
 	}
 
-	if err := sessions.Delete(req.Context, token); err != nil {
+	if err := sessions.DeleteWithUserIndex(req.Context, s); err != nil {
 		return rocco.NoBody{}, err
 	}
 
 	return rocco.NoBody{}, nil
-}).WithSummary("Revoke session").
-	WithDescription("Revokes a specific session by its token.").
+})).WithSummary("Revoke session").
+	WithDescription("Revokes a specific session by its public ID, as returned by ListSessions. Requires sessions:revoke.").
 	WithTags("Sessions").
-	WithPathParams("token").
-	WithErrors(ErrSessionNotFound).
+	WithPathParams("id").
+	WithErrors(ErrSessionNotFound, ErrPermissionDenied).
 	WithAuthentication().
 	WithSuccessStatus(204)
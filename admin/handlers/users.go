@@ -8,11 +8,12 @@ import (
 	"github.com/zoobzio/sumatra/admin/contracts"
 	"github.com/zoobzio/sumatra/admin/transformers"
 	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
 )
 
 // ListUsers returns a paginated list of all users in the system.
 // Accepts optional query parameters: limit (default 50) and offset (default 0).
-var ListUsers = rocco.GET("/users", func(req *rocco.Request[rocco.NoBody]) (wire.AdminUserListResponse, error) {
+var ListUsers = rocco.GET("/users", requirePermission(models.PermissionUsersRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminUserListResponse, error) {
 	users := sum.MustUse[contracts.Users](req.Context)
 
 	limit := 50
@@ -40,14 +41,15 @@ var ListUsers = rocco.GET("/users", func(req *rocco.Request[rocco.NoBody]) (wire
 	}
 
 	return transformers.UsersToAdminList(list, int(total)), nil
-}).WithSummary("List users").
-	WithDescription("Returns a paginated list of all users in the system.").
+})).WithSummary("List users").
+	WithDescription("Returns a paginated list of all users in the system. Requires users:read.").
 	WithTags("Users").
 	WithQueryParams("limit", "offset").
+	WithErrors(ErrPermissionDenied).
 	WithAuthentication()
 
 // GetUser returns a single user by ID.
-var GetUser = rocco.GET("/users/{id}", func(req *rocco.Request[rocco.NoBody]) (wire.AdminUserResponse, error) {
+var GetUser = rocco.GET("/users/{id}", requirePermission(models.PermissionUsersRead, func(req *rocco.Request[rocco.NoBody]) (wire.AdminUserResponse, error) {
 	users := sum.MustUse[contracts.Users](req.Context)
 
 	id := req.Params.Path["id"]
@@ -58,17 +60,18 @@ var GetUser = rocco.GET("/users/{id}", func(req *rocco.Request[rocco.NoBody]) (w
 	}
 
 	return transformers.UserToAdminResponse(user), nil
-}).WithSummary("Get user").
-	WithDescription("Returns a single user by ID.").
+})).WithSummary("Get user").
+	WithDescription("Returns a single user by ID. Requires users:read.").
 	WithTags("Users").
 	WithPathParams("id").
-	WithErrors(ErrUserNotFound).
+	WithErrors(ErrUserNotFound, ErrPermissionDenied).
 	WithAuthentication()
 
-// DeleteUser removes a user and cascades to their sessions and provider links.
-var DeleteUser = rocco.DELETE("/users/{id}", func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+// DeleteUser removes a user and cascades to their sessions, OAuth refresh tokens and provider links.
+var DeleteUser = rocco.DELETE("/users/{id}", requirePermission(models.PermissionUsersDelete, func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
 	users := sum.MustUse[contracts.Users](req.Context)
 	sessions := sum.MustUse[contracts.Sessions](req.Context)
+	refreshTokens := sum.MustUse[contracts.OAuthRefreshTokens](req.Context)
 	providers := sum.MustUse[contracts.Providers](req.Context)
 
 	id := req.Params.Path["id"]
@@ -83,6 +86,11 @@ var DeleteUser = rocco.DELETE("/users/{id}", func(req *rocco.Request[rocco.NoBod
 		return rocco.NoBody{}, err
 	}
 
+	// Cascade: revoke all refresh tokens issued to OAuth clients.
+	if err := refreshTokens.RevokeByUser(req.Context, id); err != nil {
+		return rocco.NoBody{}, err
+	}
+
 	// Cascade: remove all provider links.
 	if err := providers.DeleteByUser(req.Context, id); err != nil {
 		return rocco.NoBody{}, err
@@ -94,10 +102,10 @@ var DeleteUser = rocco.DELETE("/users/{id}", func(req *rocco.Request[rocco.NoBod
 	}
 
 	return rocco.NoBody{}, nil
-}).WithSummary("Delete user").
-	WithDescription("Deletes a user and cascades the deletion to their sessions and OAuth provider links.").
+})).WithSummary("Delete user").
+	WithDescription("Deletes a user and cascades the deletion to their sessions, the refresh tokens of OAuth clients they authorized, and their OAuth provider links. Requires users:delete.").
 	WithTags("Users").
 	WithPathParams("id").
-	WithErrors(ErrUserNotFound).
+	WithErrors(ErrUserNotFound, ErrPermissionDenied).
 	WithAuthentication().
 	WithSuccessStatus(204)
@@ -0,0 +1,36 @@
+package transformers
+
+import (
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// APITokenToAdminResponse transforms an APIToken model to an AdminAPITokenResponse.
+func APITokenToAdminResponse(t *models.APIToken) wire.AdminAPITokenResponse {
+	scopes := t.ScopeList()
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return wire.AdminAPITokenResponse{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// APITokensToAdminList transforms a slice of APIToken models to an
+// AdminAPITokenListResponse.
+func APITokensToAdminList(tokens []*models.APIToken) wire.AdminAPITokenListResponse {
+	resp := wire.AdminAPITokenListResponse{
+		Tokens: make([]wire.AdminAPITokenResponse, len(tokens)),
+	}
+	for i, t := range tokens {
+		resp.Tokens[i] = APITokenToAdminResponse(t)
+	}
+	return resp
+}
@@ -0,0 +1,63 @@
+package transformers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func newTestAPIToken() *models.APIToken {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &models.APIToken{
+		ID:        7,
+		UserID:    "01942d3a-1234-7abc-8def-0123456789ab",
+		Name:      "CI deploys",
+		Prefix:    "mpat_Yp3bHk2",
+		TokenHash: "9c56cc51b374c3ba189210d5b6d4bf57790d351c96c47c02190ecf1e430635ab",
+		Scopes:    "read",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// APITokenToAdminResponse
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestAPITokenToAdminResponse_MapsFields(t *testing.T) {
+	tok := newTestAPIToken()
+	resp := APITokenToAdminResponse(tok)
+
+	if resp.ID != tok.ID || resp.UserID != tok.UserID || resp.Prefix != tok.Prefix {
+		t.Errorf("got %+v", resp)
+	}
+	if len(resp.Scopes) != 1 || resp.Scopes[0] != "read" {
+		t.Errorf("Scopes: got %v", resp.Scopes)
+	}
+	if resp.ExpiresAt != nil || resp.LastUsedAt != nil {
+		t.Error("unset times should stay nil")
+	}
+}
+
+func TestAPITokenToAdminResponse_NeverExposesHash(t *testing.T) {
+	tok := newTestAPIToken()
+	resp := APITokenToAdminResponse(tok)
+
+	if strings.Contains(fmt.Sprintf("%+v", resp), tok.TokenHash) {
+		t.Error("response contains the token hash")
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// APITokensToAdminList
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestAPITokensToAdminList(t *testing.T) {
+	resp := APITokensToAdminList([]*models.APIToken{newTestAPIToken(), newTestAPIToken()})
+	if len(resp.Tokens) != 2 {
+		t.Errorf("got %d tokens, want 2", len(resp.Tokens))
+	}
+}
@@ -0,0 +1,29 @@
+package transformers
+
+import (
+	"time"
+
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// LockoutToAdminResponse transforms a LoginLockout model to an AdminLockoutResponse
+// as seen at now. A nil record means the user has no recent failures.
+func LockoutToAdminResponse(userID string, l *models.LoginLockout, now time.Time) wire.AdminLockoutResponse {
+	resp := wire.AdminLockoutResponse{UserID: userID}
+	if l == nil {
+		return resp
+	}
+	resp.Locked = l.IsLocked(now)
+	resp.Failures = l.Failures
+	resp.Lockouts = l.Lockouts
+	if !l.LastFailureAt.IsZero() {
+		t := l.LastFailureAt
+		resp.LastFailureAt = &t
+	}
+	if resp.Locked {
+		t := *l.LockedUntil
+		resp.LockedUntil = &t
+	}
+	return resp
+}
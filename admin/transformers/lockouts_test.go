@@ -0,0 +1,63 @@
+package transformers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func TestLockoutToAdminResponse_NoRecord(t *testing.T) {
+	resp := LockoutToAdminResponse("user_id", nil, time.Now())
+
+	if resp.UserID != "user_id" {
+		t.Errorf("UserID: got %q want %q", resp.UserID, "user_id")
+	}
+	if resp.Locked || resp.Failures != 0 || resp.LastFailureAt != nil || resp.LockedUntil != nil {
+		t.Errorf("expected empty lockout state, got %+v", resp)
+	}
+}
+
+func TestLockoutToAdminResponse_Locked(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	until := now.Add(5 * time.Minute)
+	l := &models.LoginLockout{
+		UserID:        "user_id",
+		Lockouts:      2,
+		LastFailureAt: now,
+		LockedUntil:   &until,
+	}
+
+	resp := LockoutToAdminResponse("user_id", l, now)
+
+	if !resp.Locked {
+		t.Error("expected Locked=true")
+	}
+	if resp.Lockouts != 2 {
+		t.Errorf("Lockouts: got %d want 2", resp.Lockouts)
+	}
+	if resp.LockedUntil == nil || !resp.LockedUntil.Equal(until) {
+		t.Errorf("LockedUntil: got %v want %v", resp.LockedUntil, until)
+	}
+	if resp.LastFailureAt == nil || !resp.LastFailureAt.Equal(now) {
+		t.Errorf("LastFailureAt: got %v want %v", resp.LastFailureAt, now)
+	}
+}
+
+func TestLockoutToAdminResponse_ExpiredLock(t *testing.T) {
+	now := time.Now().UTC()
+	until := now.Add(-time.Minute)
+	l := &models.LoginLockout{UserID: "user_id", Failures: 1, Lockouts: 1, LockedUntil: &until}
+
+	resp := LockoutToAdminResponse("user_id", l, now)
+
+	if resp.Locked {
+		t.Error("expected lapsed lock to report Locked=false")
+	}
+	if resp.LockedUntil != nil {
+		t.Errorf("expected LockedUntil omitted once lapsed, got %v", resp.LockedUntil)
+	}
+	if resp.Failures != 1 {
+		t.Errorf("Failures: got %d want 1", resp.Failures)
+	}
+}
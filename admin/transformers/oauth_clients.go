@@ -0,0 +1,75 @@
+package transformers
+
+import (
+	"strings"
+
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// OAuthClientToAdminResponse transforms an OAuthClient model to an
+// AdminOAuthClientResponse. The secret hash is never included.
+func OAuthClientToAdminResponse(c *models.OAuthClient) wire.AdminOAuthClientResponse {
+	return wire.AdminOAuthClientResponse{
+		ID:           c.ID,
+		Name:         c.Name,
+		Public:       c.Public,
+		RedirectURIs: nonNil(c.RedirectURIList()),
+		GrantTypes:   nonNil(c.GrantTypeList()),
+		Scopes:       nonNil(c.ScopeList()),
+		SkipConsent:  c.SkipConsent,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+// OAuthClientsToAdminList transforms a slice of OAuthClient models and a total
+// count to an AdminOAuthClientListResponse.
+func OAuthClientsToAdminList(clients []*models.OAuthClient, total int) wire.AdminOAuthClientListResponse {
+	resp := wire.AdminOAuthClientListResponse{
+		Clients: make([]wire.AdminOAuthClientResponse, len(clients)),
+		Total:   total,
+	}
+	for i, c := range clients {
+		resp.Clients[i] = OAuthClientToAdminResponse(c)
+	}
+	return resp
+}
+
+// OAuthClientWithSecret pairs a client with its newly generated secret.
+func OAuthClientWithSecret(c *models.OAuthClient, secret string) wire.AdminOAuthClientSecretResponse {
+	return wire.AdminOAuthClientSecretResponse{
+		Client:       OAuthClientToAdminResponse(c),
+		ClientSecret: secret,
+	}
+}
+
+// OAuthClientFromCreate builds an OAuthClient from a registration request.
+func OAuthClientFromCreate(req wire.AdminOAuthClientCreateRequest, id, secretHash string) *models.OAuthClient {
+	c := &models.OAuthClient{ID: id, Public: req.Public, SecretHash: secretHash}
+	ApplyOAuthClientUpdate(wire.AdminOAuthClientUpdateRequest{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		GrantTypes:   req.GrantTypes,
+		Scopes:       req.Scopes,
+		SkipConsent:  req.SkipConsent,
+	}, c)
+	return c
+}
+
+// ApplyOAuthClientUpdate replaces the updatable fields of c with those of req.
+func ApplyOAuthClientUpdate(req wire.AdminOAuthClientUpdateRequest, c *models.OAuthClient) {
+	c.Name = req.Name
+	c.RedirectURIs = strings.Join(req.RedirectURIs, " ")
+	c.GrantTypes = strings.Join(req.GrantTypes, " ")
+	c.Scopes = strings.Join(req.Scopes, " ")
+	c.SkipConsent = req.SkipConsent
+}
+
+// nonNil returns s, or an empty slice for nil so it encodes as [].
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
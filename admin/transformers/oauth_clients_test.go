@@ -0,0 +1,121 @@
+package transformers
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+func newTestOAuthClient() *models.OAuthClient {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &models.OAuthClient{
+		ID:           "mcl_Yp3bHk2xQ0mC8vR1",
+		Name:         "Wiki",
+		SecretHash:   "9c56cc51b374c3ba189210d5b6d4bf57790d351c96c47c02190ecf1e430635ab",
+		RedirectURIs: "https://wiki.example.com/callback https://wiki.example.com/alt",
+		GrantTypes:   "authorization_code refresh_token",
+		Scopes:       "openid profile",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// OAuthClientToAdminResponse
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestOAuthClientToAdminResponse_SplitsLists(t *testing.T) {
+	resp := OAuthClientToAdminResponse(newTestOAuthClient())
+
+	if !slices.Equal(resp.RedirectURIs, []string{"https://wiki.example.com/callback", "https://wiki.example.com/alt"}) {
+		t.Errorf("RedirectURIs: got %v", resp.RedirectURIs)
+	}
+	if !slices.Equal(resp.GrantTypes, []string{"authorization_code", "refresh_token"}) {
+		t.Errorf("GrantTypes: got %v", resp.GrantTypes)
+	}
+	if !slices.Equal(resp.Scopes, []string{"openid", "profile"}) {
+		t.Errorf("Scopes: got %v", resp.Scopes)
+	}
+}
+
+func TestOAuthClientToAdminResponse_NeverExposesSecretHash(t *testing.T) {
+	c := newTestOAuthClient()
+	b, err := json.Marshal(OAuthClientToAdminResponse(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), c.SecretHash) {
+		t.Error("response contains the secret hash")
+	}
+}
+
+func TestOAuthClientToAdminResponse_EmptyListsEncodeAsArrays(t *testing.T) {
+	c := newTestOAuthClient()
+	c.RedirectURIs = ""
+	b, err := json.Marshal(OAuthClientToAdminResponse(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"redirect_uris":[]`) {
+		t.Errorf("got %s", b)
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// OAuthClientFromCreate / ApplyOAuthClientUpdate
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestOAuthClientFromCreate_RoundTrips(t *testing.T) {
+	req := wire.AdminOAuthClientCreateRequest{
+		Name:         "SPA",
+		Public:       true,
+		RedirectURIs: []string{"https://spa.example.com/cb"},
+		GrantTypes:   []string{"authorization_code"},
+		Scopes:       []string{"openid", "email"},
+		SkipConsent:  true,
+	}
+	c := OAuthClientFromCreate(req, "mcl_x", "")
+
+	if c.ID != "mcl_x" || !c.Public || !c.SkipConsent || c.Name != "SPA" {
+		t.Errorf("client: got %+v", c)
+	}
+	if c.Scopes != "openid email" {
+		t.Errorf("Scopes: got %q", c.Scopes)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestApplyOAuthClientUpdate_KeepsIdentityAndSecret(t *testing.T) {
+	c := newTestOAuthClient()
+	ApplyOAuthClientUpdate(wire.AdminOAuthClientUpdateRequest{
+		Name:         "Wiki 2",
+		RedirectURIs: []string{"https://wiki.example.com/new"},
+		GrantTypes:   []string{"authorization_code"},
+		Scopes:       []string{"openid"},
+	}, c)
+
+	if c.ID != "mcl_Yp3bHk2xQ0mC8vR1" || c.SecretHash == "" {
+		t.Error("update changed the client's identity or secret")
+	}
+	if c.Name != "Wiki 2" || c.RedirectURIs != "https://wiki.example.com/new" || c.GrantTypes != "authorization_code" {
+		t.Errorf("client: got %+v", c)
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// OAuthClientsToAdminList
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestOAuthClientsToAdminList(t *testing.T) {
+	resp := OAuthClientsToAdminList([]*models.OAuthClient{newTestOAuthClient()}, 7)
+	if len(resp.Clients) != 1 || resp.Total != 7 {
+		t.Errorf("got %d clients, total %d", len(resp.Clients), resp.Total)
+	}
+}
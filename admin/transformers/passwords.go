@@ -0,0 +1,36 @@
+package transformers
+
+import (
+	"sort"
+
+	"github.com/zoobzio/sumatra/admin/wire"
+)
+
+// PasswordHashCountsToAdminReport transforms per-parameter-set user counts into
+// an AdminPasswordHashReport. current is the parameter set new hashes use.
+func PasswordHashCountsToAdminReport(counts map[string]int, current string, withoutPassword int) wire.AdminPasswordHashReport {
+	resp := wire.AdminPasswordHashReport{
+		Current:         current,
+		WithoutPassword: withoutPassword,
+		TotalUsers:      withoutPassword,
+		Sets:            make([]wire.AdminPasswordHashSet, 0, len(counts)),
+	}
+	for params, n := range counts {
+		resp.TotalUsers += n
+		if params != current {
+			resp.Outdated += n
+		}
+		resp.Sets = append(resp.Sets, wire.AdminPasswordHashSet{
+			Parameters: params,
+			Users:      n,
+			Current:    params == current,
+		})
+	}
+	sort.Slice(resp.Sets, func(i, j int) bool {
+		if resp.Sets[i].Users != resp.Sets[j].Users {
+			return resp.Sets[i].Users > resp.Sets[j].Users
+		}
+		return resp.Sets[i].Parameters < resp.Sets[j].Parameters
+	})
+	return resp
+}
@@ -0,0 +1,41 @@
+package transformers
+
+import "testing"
+
+func TestPasswordHashCountsToAdminReport(t *testing.T) {
+	const current = "argon2id v=19 m=65536 t=3 p=4 s=16 k=32"
+	counts := map[string]int{
+		current: 7,
+		"argon2id v=19 m=19456 t=2 p=1 s=16 k=32": 3,
+		"unknown": 3,
+	}
+
+	resp := PasswordHashCountsToAdminReport(counts, current, 2)
+
+	if resp.Current != current {
+		t.Errorf("Current: got %q", resp.Current)
+	}
+	if resp.TotalUsers != 15 || resp.WithoutPassword != 2 || resp.Outdated != 6 {
+		t.Errorf("totals: got total=%d without=%d outdated=%d", resp.TotalUsers, resp.WithoutPassword, resp.Outdated)
+	}
+	if len(resp.Sets) != 3 {
+		t.Fatalf("Sets: got %d want 3", len(resp.Sets))
+	}
+	if resp.Sets[0].Parameters != current || !resp.Sets[0].Current || resp.Sets[0].Users != 7 {
+		t.Errorf("Sets[0]: got %+v", resp.Sets[0])
+	}
+	// Ties are ordered by label.
+	if resp.Sets[1].Parameters != "argon2id v=19 m=19456 t=2 p=1 s=16 k=32" || resp.Sets[2].Parameters != "unknown" {
+		t.Errorf("tie order: got %q, %q", resp.Sets[1].Parameters, resp.Sets[2].Parameters)
+	}
+	if resp.Sets[1].Current || resp.Sets[2].Current {
+		t.Error("only the current set should be marked current")
+	}
+}
+
+func TestPasswordHashCountsToAdminReport_Empty(t *testing.T) {
+	resp := PasswordHashCountsToAdminReport(nil, "argon2id", 0)
+	if resp.Sets == nil || len(resp.Sets) != 0 || resp.TotalUsers != 0 {
+		t.Errorf("got %+v", resp)
+	}
+}
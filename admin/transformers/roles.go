@@ -0,0 +1,31 @@
+package transformers
+
+import (
+	"github.com/zoobzio/sumatra/admin/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// RoleToAdminResponse transforms a Role model to an AdminRoleResponse.
+func RoleToAdminResponse(r *models.Role) wire.AdminRoleResponse {
+	permissions := r.PermissionList()
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return wire.AdminRoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: permissions,
+	}
+}
+
+// RolesToAdminList transforms a slice of Role models to an AdminRoleListResponse.
+func RolesToAdminList(roles []*models.Role) wire.AdminRoleListResponse {
+	resp := wire.AdminRoleListResponse{
+		Roles: make([]wire.AdminRoleResponse, len(roles)),
+	}
+	for i, r := range roles {
+		resp.Roles[i] = RoleToAdminResponse(r)
+	}
+	return resp
+}
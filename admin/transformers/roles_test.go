@@ -0,0 +1,62 @@
+package transformers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func newTestRole() *models.Role {
+	return &models.Role{
+		ID:          "support",
+		Name:        "Support",
+		Description: "Views users and sessions and signs users out.",
+		Permissions: "users:read sessions:read sessions:revoke",
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// RoleToAdminResponse
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestRoleToAdminResponse_MapsFields(t *testing.T) {
+	r := newTestRole()
+	resp := RoleToAdminResponse(r)
+
+	if resp.ID != r.ID || resp.Name != r.Name || resp.Description != r.Description {
+		t.Errorf("got %+v", resp)
+	}
+	want := []string{"users:read", "sessions:read", "sessions:revoke"}
+	if !slices.Equal(resp.Permissions, want) {
+		t.Errorf("Permissions: got %v want %v", resp.Permissions, want)
+	}
+}
+
+func TestRoleToAdminResponse_NoPermissions(t *testing.T) {
+	r := newTestRole()
+	r.Permissions = ""
+	resp := RoleToAdminResponse(r)
+
+	if resp.Permissions == nil || len(resp.Permissions) != 0 {
+		t.Errorf("expected an empty, non-nil list, got %v", resp.Permissions)
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// RolesToAdminList
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestRolesToAdminList(t *testing.T) {
+	resp := RolesToAdminList([]*models.Role{newTestRole(), newTestRole()})
+	if len(resp.Roles) != 2 {
+		t.Errorf("got %d roles, want 2", len(resp.Roles))
+	}
+}
+
+func TestRolesToAdminList_Empty(t *testing.T) {
+	resp := RolesToAdminList(nil)
+	if resp.Roles == nil || len(resp.Roles) != 0 {
+		t.Errorf("expected an empty, non-nil list, got %v", resp.Roles)
+	}
+}
@@ -5,28 +5,27 @@ import (
 	"github.com/zoobzio/sumatra/models"
 )
 
-// maskToken reduces a session token to its first 8 characters followed by "..."
-// so that it can be displayed in the admin UI without exposing the full secret.
-func maskToken(token string) string {
-	if len(token) <= 8 {
-		return token + "..."
-	}
-	return token[:8] + "..."
-}
-
 // SessionToAdminResponse transforms a Session model to an AdminSessionResponse.
-// The token is partially masked for display — first 8 characters + "...".
+// Sessions are identified by their public ID; the token itself is never stored
+// or shown.
 func SessionToAdminResponse(s *models.Session) wire.AdminSessionResponse {
 	return wire.AdminSessionResponse{
-		Token:     maskToken(s.Token),
-		UserID:    s.UserID,
-		CreatedAt: s.CreatedAt,
-		ExpiresAt: s.ExpiresAt,
+		ID:          s.ID(),
+		UserID:      s.UserID,
+		CreatedAt:   s.CreatedAt,
+		ExpiresAt:   s.ExpiresAt,
+		LastSeenAt:  s.LastSeenAt,
+		Method:      string(s.Method),
+		IP:          s.IP,
+		UserAgent:   s.UserAgent,
+		Browser:     s.Browser,
+		OS:          s.OS,
+		DeviceClass: s.DeviceClass,
 	}
 }
 
 // SessionsToAdminList transforms a slice of Session models to an
-// AdminSessionListResponse. Each token is partially masked.
+// AdminSessionListResponse.
 func SessionsToAdminList(sessions []*models.Session) wire.AdminSessionListResponse {
 	resp := wire.AdminSessionListResponse{
 		Sessions: make([]wire.AdminSessionResponse, len(sessions)),
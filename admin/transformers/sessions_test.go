@@ -1,6 +1,7 @@
 package transformers
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -12,82 +13,32 @@ func newTestSession() *models.Session {
 	now := time.Now().UTC().Truncate(time.Second)
 	return &models.Session{
 		Token:     "abcdefghijklmnopqrstuvwxyz012345",
+		TokenHash: "9c56cc51b374c3ba189210d5b6d4bf57790d351c96c47c02190ecf1e430635ab",
 		UserID:    "01942d3a-1234-7abc-8def-0123456789ab",
 		CreatedAt: now,
 		ExpiresAt: now.Add(168 * time.Hour),
 	}
 }
 
-// ──────────────────────────────────────────────────────────────────────────────
-// maskToken (internal helper — tested via SessionToAdminResponse)
-// ──────────────────────────────────────────────────────────────────────────────
-
-func TestMaskToken_LongToken(t *testing.T) {
-	tok := "abcdefghijklmnopqrstuvwxyz"
-	masked := maskToken(tok)
-
-	if !strings.HasSuffix(masked, "...") {
-		t.Errorf("masked token should end with '...': got %q", masked)
-	}
-	if !strings.HasPrefix(masked, tok[:8]) {
-		t.Errorf("masked token should start with first 8 chars: got %q", masked)
-	}
-	if len(masked) != 11 { // 8 chars + "..."
-		t.Errorf("masked token length: got %d want 11", len(masked))
-	}
-}
-
-func TestMaskToken_ExactlyEightChars(t *testing.T) {
-	tok := "12345678"
-	masked := maskToken(tok)
-
-	// len == 8, so the code takes the else branch: token[:8] + "..."
-	if masked != "12345678..." {
-		t.Errorf("got %q want %q", masked, "12345678...")
-	}
-}
-
-func TestMaskToken_ShortToken(t *testing.T) {
-	tok := "abc"
-	masked := maskToken(tok)
-
-	// len <= 8, so entire token + "..."
-	if masked != "abc..." {
-		t.Errorf("got %q want %q", masked, "abc...")
-	}
-}
-
-func TestMaskToken_EmptyToken(t *testing.T) {
-	masked := maskToken("")
-
-	if masked != "..." {
-		t.Errorf("got %q want %q", masked, "...")
-	}
-}
-
 // ──────────────────────────────────────────────────────────────────────────────
 // SessionToAdminResponse
 // ──────────────────────────────────────────────────────────────────────────────
 
-func TestSessionToAdminResponse_TokenIsMasked(t *testing.T) {
+func TestSessionToAdminResponse_UsesPublicID(t *testing.T) {
 	s := newTestSession()
 	resp := SessionToAdminResponse(s)
 
-	// Token must not be the original.
-	if resp.Token == s.Token {
-		t.Error("expected token to be masked, but got the full token")
-	}
-	if !strings.HasSuffix(resp.Token, "...") {
-		t.Errorf("masked token should end with '...': got %q", resp.Token)
+	if resp.ID != s.TokenHash {
+		t.Errorf("ID: got %q want %q", resp.ID, s.TokenHash)
 	}
 }
 
-func TestSessionToAdminResponse_TokenPrefix(t *testing.T) {
+func TestSessionToAdminResponse_NeverExposesToken(t *testing.T) {
 	s := newTestSession()
 	resp := SessionToAdminResponse(s)
 
-	if !strings.HasPrefix(resp.Token, s.Token[:8]) {
-		t.Errorf("masked token should start with first 8 chars of original: got %q", resp.Token)
+	if strings.Contains(fmt.Sprintf("%+v", resp), s.Token) {
+		t.Error("response contains the raw session token")
 	}
 }
 
@@ -112,17 +63,28 @@ func TestSessionToAdminResponse_MapsTimestamps(t *testing.T) {
 	}
 }
 
-func TestSessionToAdminResponse_ShortTokenMasked(t *testing.T) {
-	s := &models.Session{
-		Token:     "short",
-		UserID:    "01942d3a-1234-7abc-8def-0123456789ab",
-		CreatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(time.Hour),
-	}
+func TestSessionToAdminResponse_MapsClientMetadata(t *testing.T) {
+	s := newTestSession()
+	s.LastSeenAt = s.CreatedAt.Add(time.Minute)
+	s.Method = models.LoginMethod("github")
+	s.IP = "203.0.113.7"
+	s.UserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X)"
+	s.Browser = "Safari 17"
+	s.OS = "iOS"
+	s.DeviceClass = models.DeviceClassMobile
 	resp := SessionToAdminResponse(s)
 
-	if resp.Token != "short..." {
-		t.Errorf("short token: got %q want %q", resp.Token, "short...")
+	if !resp.LastSeenAt.Equal(s.LastSeenAt) {
+		t.Errorf("LastSeenAt: got %v want %v", resp.LastSeenAt, s.LastSeenAt)
+	}
+	if resp.Method != "github" {
+		t.Errorf("Method: got %q want %q", resp.Method, "github")
+	}
+	if resp.IP != s.IP || resp.UserAgent != s.UserAgent {
+		t.Errorf("IP/UserAgent: got %q/%q", resp.IP, resp.UserAgent)
+	}
+	if resp.Browser != s.Browser || resp.OS != s.OS || resp.DeviceClass != s.DeviceClass {
+		t.Errorf("client: got %q/%q/%q", resp.Browser, resp.OS, resp.DeviceClass)
 	}
 }
 
@@ -146,10 +108,10 @@ func TestSessionsToAdminList_NilSlice(t *testing.T) {
 	}
 }
 
-func TestSessionsToAdminList_MasksAllTokens(t *testing.T) {
+func TestSessionsToAdminList_MapsAllIDs(t *testing.T) {
 	sessions := []*models.Session{
 		newTestSession(),
-		{Token: "anotherlongtoken123", UserID: "user-2", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
+		{TokenHash: "3f0a9e1b", UserID: "user-2", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
 	}
 	resp := SessionsToAdminList(sessions)
 
@@ -157,11 +119,8 @@ func TestSessionsToAdminList_MasksAllTokens(t *testing.T) {
 		t.Fatalf("expected 2 sessions, got %d", len(resp.Sessions))
 	}
 	for i, s := range sessions {
-		if resp.Sessions[i].Token == s.Token {
-			t.Errorf("sessions[%d].Token was not masked", i)
-		}
-		if !strings.HasSuffix(resp.Sessions[i].Token, "...") {
-			t.Errorf("sessions[%d].Token should end with '...': got %q", i, resp.Sessions[i].Token)
+		if resp.Sessions[i].ID != s.ID() {
+			t.Errorf("sessions[%d].ID: got %q want %q", i, resp.Sessions[i].ID, s.ID())
 		}
 	}
 }
@@ -16,6 +16,7 @@ func UserToAdminResponse(u *models.User) wire.AdminUserResponse {
 		EmailVerified: u.EmailVerified,
 		Name:          u.Name,
 		AvatarURL:     u.AvatarURL,
+		IsAdmin:       u.IsAdmin,
 		CreatedAt:     u.CreatedAt,
 		UpdatedAt:     u.UpdatedAt,
 	}
@@ -17,6 +17,7 @@ func newTestUser() *models.User {
 		EmailVerified: true,
 		Name:          &name,
 		AvatarURL:     &avatar,
+		IsAdmin:       true,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
@@ -39,6 +40,9 @@ func TestUserToAdminResponse_MapsScalarFields(t *testing.T) {
 	if resp.EmailVerified != u.EmailVerified {
 		t.Errorf("EmailVerified: got %v want %v", resp.EmailVerified, u.EmailVerified)
 	}
+	if resp.IsAdmin != u.IsAdmin {
+		t.Errorf("IsAdmin: got %v want %v", resp.IsAdmin, u.IsAdmin)
+	}
 }
 
 func TestUserToAdminResponse_MapsTimestamps(t *testing.T) {
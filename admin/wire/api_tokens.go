@@ -0,0 +1,51 @@
+package wire
+
+import (
+	"slices"
+	"time"
+)
+
+// AdminAPITokenResponse is the admin API response for a personal access token.
+// The token and its hash are never returned.
+type AdminAPITokenResponse struct {
+	ID         int64      `json:"id" description:"Token ID" example:"1"`
+	UserID     string     `json:"user_id" description:"ID of the owning user" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	Name       string     `json:"name" description:"Label for the token" example:"CI deploys"`
+	Prefix     string     `json:"prefix" description:"First characters of the token" example:"mpat_Yp3bHk2"`
+	Scopes     []string   `json:"scopes" description:"Granted scopes" example:"[\"read\"]"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" description:"Expiry, absent for tokens that do not expire"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" description:"Time the token was last used, to the minute"`
+	CreatedAt  time.Time  `json:"created_at" description:"Time the token was created"`
+}
+
+// Clone returns a deep copy of AdminAPITokenResponse.
+func (r AdminAPITokenResponse) Clone() AdminAPITokenResponse {
+	c := r
+	c.Scopes = slices.Clone(r.Scopes)
+	if r.ExpiresAt != nil {
+		e := *r.ExpiresAt
+		c.ExpiresAt = &e
+	}
+	if r.LastUsedAt != nil {
+		l := *r.LastUsedAt
+		c.LastUsedAt = &l
+	}
+	return c
+}
+
+// AdminAPITokenListResponse is the admin API response for a list of personal access tokens.
+type AdminAPITokenListResponse struct {
+	Tokens []AdminAPITokenResponse `json:"tokens" description:"Personal access tokens, newest first"`
+}
+
+// Clone returns a deep copy of AdminAPITokenListResponse.
+func (r AdminAPITokenListResponse) Clone() AdminAPITokenListResponse {
+	c := r
+	if r.Tokens != nil {
+		c.Tokens = make([]AdminAPITokenResponse, len(r.Tokens))
+		for i, t := range r.Tokens {
+			c.Tokens[i] = t.Clone()
+		}
+	}
+	return c
+}
@@ -0,0 +1,27 @@
+package wire
+
+import "time"
+
+// AdminLockoutResponse is the admin API response describing a user's failed login state.
+type AdminLockoutResponse struct {
+	UserID        string     `json:"user_id" description:"ID of the user" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	Locked        bool       `json:"locked" description:"Whether the account is currently locked"`
+	Failures      int        `json:"failures" description:"Failed attempts counting towards the next lockout" example:"2"`
+	Lockouts      int        `json:"lockouts" description:"Lockouts since the failure history was last reset" example:"1"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty" description:"Time of the most recent failed attempt"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty" description:"When the current lockout ends"`
+}
+
+// Clone returns a deep copy of AdminLockoutResponse.
+func (r AdminLockoutResponse) Clone() AdminLockoutResponse {
+	c := r
+	if r.LastFailureAt != nil {
+		t := *r.LastFailureAt
+		c.LastFailureAt = &t
+	}
+	if r.LockedUntil != nil {
+		t := *r.LockedUntil
+		c.LockedUntil = &t
+	}
+	return c
+}
@@ -0,0 +1,13 @@
+package wire
+
+// AdminRecoveryCodesResponse is the admin API response reporting a user's remaining recovery codes.
+// Code values are never exposed; only the count of unused codes is reported.
+type AdminRecoveryCodesResponse struct {
+	UserID    string `json:"user_id" description:"ID of the user" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	Remaining int    `json:"remaining" description:"Number of unused recovery codes" example:"8"`
+}
+
+// Clone returns a deep copy of AdminRecoveryCodesResponse.
+func (r AdminRecoveryCodesResponse) Clone() AdminRecoveryCodesResponse {
+	return r
+}
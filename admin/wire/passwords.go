@@ -0,0 +1,27 @@
+package wire
+
+// AdminPasswordHashSet counts the users whose password hash uses one parameter set.
+type AdminPasswordHashSet struct {
+	Parameters string `json:"parameters" description:"Hash algorithm and parameters" example:"argon2id v=19 m=65536 t=3 p=4 s=16 k=32"`
+	Users      int    `json:"users" description:"Number of users on this parameter set" example:"1200"`
+	Current    bool   `json:"current" description:"Whether new hashes are made with this parameter set"`
+}
+
+// AdminPasswordHashReport is the admin API response summarising password hash parameters across users.
+type AdminPasswordHashReport struct {
+	Current         string                 `json:"current" description:"Parameter set new hashes are made with" example:"argon2id v=19 m=65536 t=3 p=4 s=16 k=32"`
+	TotalUsers      int                    `json:"total_users" description:"Number of users examined" example:"1250"`
+	WithoutPassword int                    `json:"without_password" description:"Users with no password set" example:"40"`
+	Outdated        int                    `json:"outdated" description:"Users whose hash will be upgraded on their next login" example:"10"`
+	Sets            []AdminPasswordHashSet `json:"sets" description:"User counts per parameter set, largest first"`
+}
+
+// Clone returns a deep copy of AdminPasswordHashReport.
+func (r AdminPasswordHashReport) Clone() AdminPasswordHashReport {
+	c := r
+	if r.Sets != nil {
+		c.Sets = make([]AdminPasswordHashSet, len(r.Sets))
+		copy(c.Sets, r.Sets)
+	}
+	return c
+}
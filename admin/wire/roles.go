@@ -0,0 +1,35 @@
+package wire
+
+import "slices"
+
+// AdminRoleResponse is the admin API response for a role.
+type AdminRoleResponse struct {
+	ID          string   `json:"id" description:"Role slug" example:"support"`
+	Name        string   `json:"name" description:"Display name" example:"Support"`
+	Description string   `json:"description" description:"What the role is for"`
+	Permissions []string `json:"permissions" description:"Admin permissions the role grants" example:"[\"users:read\",\"sessions:read\",\"sessions:revoke\"]"`
+}
+
+// Clone returns a deep copy of AdminRoleResponse.
+func (r AdminRoleResponse) Clone() AdminRoleResponse {
+	c := r
+	c.Permissions = slices.Clone(r.Permissions)
+	return c
+}
+
+// AdminRoleListResponse is the admin API response for a list of roles.
+type AdminRoleListResponse struct {
+	Roles []AdminRoleResponse `json:"roles" description:"Roles ordered by ID"`
+}
+
+// Clone returns a deep copy of AdminRoleListResponse.
+func (r AdminRoleListResponse) Clone() AdminRoleListResponse {
+	c := r
+	if r.Roles != nil {
+		c.Roles = make([]AdminRoleResponse, len(r.Roles))
+		for i, role := range r.Roles {
+			c.Roles[i] = role.Clone()
+		}
+	}
+	return c
+}
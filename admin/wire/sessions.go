@@ -3,12 +3,21 @@ package wire
 import "time"
 
 // AdminSessionResponse is the admin API response for a session record.
-// The token is partially masked (first 8 characters + "...") for display safety.
+// Sessions are identified by a public ID derived from the token, which is
+// never stored or returned.
 type AdminSessionResponse struct {
-	Token     string    `json:"token" description:"Session token (partially masked)" example:"a1b2c3d4..."`
+	ID        string    `json:"id" description:"Public session ID, derived from the token" example:"9c56cc51b374c3ba189210d5b6d4bf57790d351c96c47c02190ecf1e430635ab"`
 	UserID    string    `json:"user_id" description:"ID of the owning user" example:"01942d3a-1234-7abc-8def-0123456789ab"`
 	CreatedAt time.Time `json:"created_at" description:"Session creation time"`
 	ExpiresAt time.Time `json:"expires_at" description:"Session expiry time"`
+	// LastSeenAt is zero for sessions created before last-seen tracking.
+	LastSeenAt  time.Time `json:"last_seen_at,omitempty" description:"Approximate time the session was last used"`
+	Method      string    `json:"method,omitempty" description:"How the user signed in" example:"github"`
+	IP          string    `json:"ip,omitempty" description:"Client IP address at sign-in" example:"203.0.113.7"`
+	UserAgent   string    `json:"user_agent,omitempty" description:"Raw User-Agent header at sign-in"`
+	Browser     string    `json:"browser,omitempty" description:"Browser parsed from the user agent" example:"Chrome 124"`
+	OS          string    `json:"os,omitempty" description:"Operating system parsed from the user agent" example:"Windows"`
+	DeviceClass string    `json:"device_class,omitempty" description:"Device class: desktop, mobile, tablet, bot or unknown" example:"desktop"`
 }
 
 // Clone returns a deep copy of AdminSessionResponse.
@@ -11,6 +11,7 @@ type AdminUserResponse struct {
 	EmailVerified bool      `json:"email_verified" description:"Whether the email address has been verified"`
 	Name          *string   `json:"name,omitempty" description:"Display name" example:"Jane Doe"`
 	AvatarURL     *string   `json:"avatar_url,omitempty" description:"Avatar URL" example:"https://avatars.githubusercontent.com/u/1"`
+	IsAdmin       bool      `json:"is_admin" description:"Whether the user may operate the admin API"`
 	CreatedAt     time.Time `json:"created_at" description:"Account creation time"`
 	UpdatedAt     time.Time `json:"updated_at" description:"Last update time"`
 }
@@ -0,0 +1,17 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// APITokens defines the contract for personal access token operations required by the public API.
+type APITokens interface {
+	// Create stores a token under the hash of raw and returns it with its generated ID.
+	Create(ctx context.Context, raw string, token *models.APIToken) (*models.APIToken, error)
+	// ListByUser retrieves all tokens of a user, newest first.
+	ListByUser(ctx context.Context, userID string) ([]*models.APIToken, error)
+	// DeleteByUserAndID removes a token only if it belongs to the given user.
+	DeleteByUserAndID(ctx context.Context, userID string, id int64) error
+}
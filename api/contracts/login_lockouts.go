@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	intlockout "github.com/zoobzio/sumatra/internal/lockout"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// LoginLockouts defines the contract for failed login tracking required by the public API.
+// Records are keyed by subject: a user ID, or a derived key for an email with no account.
+type LoginLockouts interface {
+	// Get retrieves the lockout record for a subject.
+	Get(ctx context.Context, subject string) (*models.LoginLockout, error)
+	// RecordFailure atomically counts a failed attempt against a subject under
+	// policy, keeping the record for ttl. It reports whether the failure started a lockout.
+	RecordFailure(ctx context.Context, subject string, policy intlockout.Policy, now time.Time, ttl time.Duration) (*models.LoginLockout, bool, error)
+	// Delete removes the lockout record for a subject.
+	Delete(ctx context.Context, subject string) error
+}
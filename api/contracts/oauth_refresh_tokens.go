@@ -0,0 +1,10 @@
+package contracts
+
+import "context"
+
+// OAuthRefreshTokens defines the contract for refresh tokens issued to OAuth
+// clients required by the public API.
+type OAuthRefreshTokens interface {
+	// RevokeByUser revokes every refresh token issued for the given userID.
+	RevokeByUser(ctx context.Context, userID string) error
+}
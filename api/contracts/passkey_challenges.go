@@ -0,0 +1,19 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// PasskeyChallenges defines the contract for in-flight WebAuthn ceremony state
+// required by the public API.
+type PasskeyChallenges interface {
+	// Get retrieves a passkey challenge by its ID.
+	Get(ctx context.Context, id string) (*models.PasskeyChallenge, error)
+	// Set stores a passkey challenge with the given TTL.
+	Set(ctx context.Context, challenge *models.PasskeyChallenge, ttl time.Duration) error
+	// Delete removes a passkey challenge by its ID.
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,24 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Passkeys defines the contract for WebAuthn credential operations required by the public API.
+type Passkeys interface {
+	// Create inserts a new passkey and returns it with its generated ID.
+	Create(ctx context.Context, passkey *models.Passkey) (*models.Passkey, error)
+	// Set updates an existing passkey, e.g. after a sign-in advances its counter.
+	Set(ctx context.Context, key string, passkey *models.Passkey) error
+	// GetByCredentialID retrieves a passkey by its base64url-encoded credential ID.
+	GetByCredentialID(ctx context.Context, credentialID string) (*models.Passkey, error)
+	// ListByUser retrieves all passkeys for a user.
+	ListByUser(ctx context.Context, userID string) ([]*models.Passkey, error)
+	// CountByUser returns the number of passkeys registered to a user.
+	// Used by the last-auth-method check when unlinking providers.
+	CountByUser(ctx context.Context, userID string) (float64, error)
+	// DeleteByUserAndID removes a passkey only if it belongs to the given user.
+	DeleteByUserAndID(ctx context.Context, userID string, id int64) error
+}
@@ -0,0 +1,17 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// PendingLinks defines the contract for provider logins held for confirmation
+// required by the public API.
+type PendingLinks interface {
+	// Set stores a pending link with the given TTL.
+	Set(ctx context.Context, link *models.PendingLink, ttl time.Duration) error
+	// Take retrieves and deletes a pending link by its ID in one atomic step.
+	Take(ctx context.Context, id string) (*models.PendingLink, error)
+}
@@ -0,0 +1,19 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// RecoveryCodes defines the contract for MFA recovery code operations required by the public API.
+type RecoveryCodes interface {
+	// ListUnusedByUser retrieves all unconsumed recovery codes for a user.
+	ListUnusedByUser(ctx context.Context, userID string) ([]*models.RecoveryCode, error)
+	// Replace discards all existing recovery codes for a user and stores the given set.
+	Replace(ctx context.Context, userID string, codes []*models.RecoveryCode) error
+	// MarkUsed consumes a recovery code. Fails if the code was already consumed.
+	MarkUsed(ctx context.Context, id int64) error
+	// DeleteByUser removes all recovery codes for a user.
+	DeleteByUser(ctx context.Context, userID string) error
+}
@@ -11,9 +11,18 @@ import (
 type Sessions interface {
 	// Get retrieves a session by its token.
 	Get(ctx context.Context, token string) (*models.Session, error)
+	// GetByID retrieves a session by its public ID.
+	GetByID(ctx context.Context, id string) (*models.Session, error)
 	// SetWithUserIndex stores a session and writes a corresponding user index entry.
 	// The user index enables future enumeration and bulk-revocation of sessions.
 	SetWithUserIndex(ctx context.Context, session *models.Session, ttl time.Duration) error
 	// Delete removes a session by its token.
 	Delete(ctx context.Context, token string) error
+	// DeleteWithUserIndex removes a session and its user index entry.
+	DeleteWithUserIndex(ctx context.Context, session *models.Session) error
+	// ListByUser returns the public IDs of up to limit sessions belonging to userID.
+	ListByUser(ctx context.Context, userID string, limit int) ([]string, error)
+	// DeleteByUserExcept revokes every session of userID except the one with
+	// public ID keep, in one round trip, and returns the IDs of those revoked.
+	DeleteByUserExcept(ctx context.Context, userID, keep string) ([]string, error)
 }
@@ -0,0 +1,19 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// TOTPSecrets defines the contract for TOTP enrollment operations required by the public API.
+type TOTPSecrets interface {
+	// Get retrieves the TOTP enrollment for a user by user ID.
+	Get(ctx context.Context, userID string) (*models.TOTPSecret, error)
+	// Set creates or updates a TOTP enrollment.
+	Set(ctx context.Context, userID string, secret *models.TOTPSecret) error
+	// AdvanceStep records the last accepted time step if it is later than the stored one.
+	AdvanceStep(ctx context.Context, userID string, step int64) error
+	// Delete removes the TOTP enrollment for a user.
+	Delete(ctx context.Context, userID string) error
+}
@@ -14,6 +14,8 @@ type VerificationTokens interface {
 	Get(ctx context.Context, token string) (*models.VerificationToken, error)
 	// Set stores a verification token with the given TTL.
 	Set(ctx context.Context, token *models.VerificationToken, ttl time.Duration) error
+	// Take retrieves and deletes a verification token in one atomic step.
+	Take(ctx context.Context, token string) (*models.VerificationToken, error)
 	// Delete removes a verification token by its token string.
 	Delete(ctx context.Context, token string) error
 }
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/api/contracts"
+	"github.com/zoobzio/sumatra/api/transformers"
+	"github.com/zoobzio/sumatra/api/wire"
+	intauthn "github.com/zoobzio/sumatra/internal/authn"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// CreateAPIToken creates a personal access token for the authenticated user.
+// The token is returned only in this response. Tokens can only be created from
+// a session, so a leaked token cannot be used to mint others.
+var CreateAPIToken = rocco.POST("/me/tokens", requireSession(func(req *rocco.Request[wire.APITokenCreateRequest]) (wire.APITokenCreatedResponse, error) {
+	tokens := sum.MustUse[contracts.APITokens](req.Context)
+
+	now := time.Now()
+	if req.Body.ExpiresAt != nil && !req.Body.ExpiresAt.After(now) {
+		return wire.APITokenCreatedResponse{}, ErrInvalidExpiry
+	}
+
+	raw, prefix, err := intauthn.GenerateAPIToken()
+	if err != nil {
+		return wire.APITokenCreatedResponse{}, ErrAPITokensFailed
+	}
+	token := &models.APIToken{
+		UserID:    req.Identity.ID(),
+		Name:      req.Body.Name,
+		Prefix:    prefix,
+		Scopes:    strings.Join(req.Body.Scopes, " "),
+		ExpiresAt: req.Body.ExpiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	created, err := tokens.Create(req.Context, raw, token)
+	if err != nil {
+		return wire.APITokenCreatedResponse{}, ErrAPITokensFailed
+	}
+
+	return transformers.APITokenToCreatedResponse(created, raw), nil
+})).WithSummary("Create API token").
+	WithDescription("Creates a personal access token for non-interactive clients, sent as Authorization: Bearer. A read token may only make GET requests. The token is returned only in this response. Requires a session.").
+	WithTags("API Tokens").
+	WithAuthentication().
+	WithSuccessStatus(201).
+	WithErrors(ErrSessionRequired, ErrInvalidExpiry, ErrAPITokensFailed)
+
+// ListAPITokens returns the authenticated user's personal access tokens.
+var ListAPITokens = rocco.GET("/me/tokens", func(req *rocco.Request[rocco.NoBody]) (wire.APITokenListResponse, error) {
+	tokens := sum.MustUse[contracts.APITokens](req.Context)
+
+	list, err := tokens.ListByUser(req.Context, req.Identity.ID())
+	if err != nil {
+		return wire.APITokenListResponse{}, ErrAPITokensFailed
+	}
+
+	return transformers.APITokensToList(list), nil
+}).WithSummary("List API tokens").
+	WithDescription("Returns the authenticated user's personal access tokens, newest first. Tokens themselves are never returned.").
+	WithTags("API Tokens").
+	WithAuthentication().
+	WithErrors(ErrAPITokensFailed)
+
+// RevokeAPIToken deletes one of the authenticated user's personal access tokens.
+var RevokeAPIToken = rocco.DELETE("/me/tokens/{id}", requireSession(func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	tokens := sum.MustUse[contracts.APITokens](req.Context)
+
+	userID := req.Identity.ID()
+
+	id, err := strconv.ParseInt(req.Params.Path["id"], 10, 64)
+	if err != nil {
+		return rocco.NoBody{}, ErrAPITokenNotFound
+	}
+
+	list, err := tokens.ListByUser(req.Context, userID)
+	if err != nil {
+		return rocco.NoBody{}, ErrAPITokensFailed
+	}
+	found := false
+	for _, t := range list {
+		if t.ID == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return rocco.NoBody{}, ErrAPITokenNotFound
+	}
+
+	if err := tokens.DeleteByUserAndID(req.Context, userID, id); err != nil {
+		return rocco.NoBody{}, ErrAPITokensFailed
+	}
+
+	return rocco.NoBody{}, nil
+})).WithSummary("Revoke API token").
+	WithDescription("Deletes one of the authenticated user's personal access tokens. Requests made with it fail immediately. Requires a session; API tokens are refused.").
+	WithTags("API Tokens").
+	WithAuthentication().
+	WithPathParams("id").
+	WithSuccessStatus(204).
+	WithErrors(ErrSessionRequired, ErrAPITokenNotFound, ErrAPITokensFailed)
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/zoobzio/rocco"
@@ -16,20 +19,89 @@ import (
 	"github.com/zoobzio/sumatra/models"
 )
 
-// buildSessionCookie constructs the session cookie from config and token.
-func buildSessionCookie(cfg config.Session, token string) *http.Cookie {
+// SessionCookie constructs the session cookie for token, expiring after maxAge,
+// or with the browser session when maxAge is 0. It is also used by the session
+// activity middleware when it extends a session.
+func SessionCookie(cfg config.Session, token string, maxAge time.Duration) *http.Cookie {
 	return &http.Cookie{
 		Name:     cfg.CookieName,
 		Value:    token,
 		Path:     cfg.CookiePath,
 		Domain:   cfg.CookieDomain,
-		MaxAge:   int(cfg.TTL.Seconds()),
+		MaxAge:   int(maxAge.Seconds()),
 		Secure:   cfg.CookieSecure,
 		HttpOnly: true,
 		SameSite: http.SameSiteLaxMode,
 	}
 }
 
+// mfaChallengeCookieName names the cookie carrying a pending MFA challenge
+// from Login to LoginMFA.
+const mfaChallengeCookieName = "mfa_challenge"
+
+// mfaLoginURL is where Login sends a user who must enter a second factor: the
+// login page, which posts the code to /login/mfa.
+const mfaLoginURL = "/login?step=mfa"
+
+// mfaChallengeCookie constructs the cookie carrying an MFA challenge token to
+// LoginMFA, expiring after maxAge, or clearing it when maxAge is negative. The
+// challenge stands in for the password, so it is kept out of URLs, where
+// history, logs and Referer headers would leak it, and is never sent
+// cross-site.
+func mfaChallengeCookie(cfg config.Session, token string, maxAge time.Duration) *http.Cookie {
+	c := &http.Cookie{
+		Name:     mfaChallengeCookieName,
+		Value:    token,
+		Path:     cfg.CookiePath,
+		Domain:   cfg.CookieDomain,
+		MaxAge:   int(maxAge.Seconds()),
+		Secure:   cfg.CookieSecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}
+	if maxAge < 0 {
+		c.MaxAge = -1
+	}
+	return c
+}
+
+// sanitizeReturnTo checks a requested post-login redirect against the configured
+// allowlist, returning "" when it is absent or not allowed.
+func sanitizeReturnTo(ctx context.Context, raw string) string {
+	return sum.MustUse[*intsession.ReturnToPolicy](ctx).Sanitize(raw)
+}
+
+// loginRedirectURL returns where to send the user after a successful login.
+// Stored values are checked again so allowlist changes apply to in-flight flows.
+func loginRedirectURL(ctx context.Context, returnTo string) string {
+	if target := sanitizeReturnTo(ctx, returnTo); target != "" {
+		return target
+	}
+	return "/"
+}
+
+// loginErrorURL builds the /login error redirect, carrying return_to so the
+// login page can resume towards the original destination.
+func loginErrorURL(code, returnTo string) string {
+	params := url.Values{}
+	params.Set("error", code)
+	if returnTo != "" {
+		params.Set("return_to", returnTo)
+	}
+	return "/login?" + params.Encode()
+}
+
+// passwordError maps a password policy failure to its API error: violations
+// become ErrPasswordRejected with the violated rules as details, and any other
+// failure, such as an unreadable breach corpus, becomes fallback.
+func passwordError(err, fallback error) error {
+	var policyErr *intpassword.PolicyError
+	if errors.As(err, &policyErr) {
+		return ErrPasswordRejected.WithDetails(transformers.PolicyErrorToDetails(policyErr))
+	}
+	return fallback
+}
+
 // Register creates a new user account.
 // The user must verify their email before they can log in.
 var Register = rocco.POST("/register", func(req *rocco.Request[wire.RegisterRequest]) (wire.UserResponse, error) {
@@ -37,6 +109,8 @@ var Register = rocco.POST("/register", func(req *rocco.Request[wire.RegisterRequ
 	verificationTokens := sum.MustUse[contracts.VerificationTokens](req.Context)
 	tokensCfg := sum.MustUse[config.Tokens](req.Context)
 	postmarkCfg := sum.MustUse[config.Postmark](req.Context)
+	passwordPolicy := sum.MustUse[*intpassword.Policy](req.Context)
+	hasher := sum.MustUse[*intpassword.Hasher](req.Context)
 
 	// Reject if email is already registered.
 	existing, err := users.GetByEmail(req.Context, req.Body.Email)
@@ -44,8 +118,11 @@ var Register = rocco.POST("/register", func(req *rocco.Request[wire.RegisterRequ
 		return wire.UserResponse{}, ErrEmailAlreadyExists
 	}
 
-	// Hash the password.
-	hash, err := intpassword.Hash(req.Body.Password)
+	// Screen and hash the password.
+	if err := passwordPolicy.Check(req.Body.Password, req.Body.Email); err != nil {
+		return wire.UserResponse{}, passwordError(err, ErrRegistrationFailed)
+	}
+	hash, err := hasher.Hash(req.Body.Password)
 	if err != nil {
 		return wire.UserResponse{}, ErrRegistrationFailed
 	}
@@ -77,6 +154,7 @@ var Register = rocco.POST("/register", func(req *rocco.Request[wire.RegisterRequ
 		Token:     rawToken,
 		UserID:    user.ID,
 		Type:      models.TokenTypeEmailVerify,
+		ReturnTo:  sanitizeReturnTo(req.Context, req.Body.ReturnTo),
 		CreatedAt: now,
 		ExpiresAt: now.Add(tokensCfg.EmailVerifyTTL),
 	}
@@ -94,68 +172,124 @@ var Register = rocco.POST("/register", func(req *rocco.Request[wire.RegisterRequ
 
 	return transformers.UserToResponse(user), nil
 }).WithSummary("Register").
-	WithDescription("Creates a new user account. The user must verify their email before logging in.").
+	WithDescription("Creates a new user account. The password is screened against the password policy, including a breached-password corpus; rejections list each violated rule. The user must verify their email before logging in.").
 	WithTags("Auth").
 	WithSuccessStatus(201).
-	WithErrors(ErrEmailAlreadyExists, ErrRegistrationFailed)
+	WithErrors(ErrEmailAlreadyExists, ErrPasswordRejected, ErrRegistrationFailed)
 
 // Login authenticates a user with email and password.
-// The user's email must be verified. On success, redirects to / with a session cookie.
+// The user's email must be verified. Repeated failures lock the account temporarily,
+// with each repeat lockout lasting longer. On success, redirects to return_to (or /) with a session cookie,
+// or back to the login page with an MFA challenge cookie when the user has TOTP enabled.
 var Login = rocco.POST("/login", func(req *rocco.Request[wire.LoginRequest]) (rocco.Redirect, error) {
 	users := sum.MustUse[contracts.Users](req.Context)
-	sessions := sum.MustUse[contracts.Sessions](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
+	verificationTokens := sum.MustUse[contracts.VerificationTokens](req.Context)
+	tokensCfg := sum.MustUse[config.Tokens](req.Context)
+	lockoutCfg := sum.MustUse[config.Lockout](req.Context)
+	hasher := sum.MustUse[*intpassword.Hasher](req.Context)
 
-	// Find user by email.
+	// Find user by email. Failures against an unknown email are counted under
+	// the address, so it locks exactly as an account would.
 	user, err := users.GetByEmail(req.Context, req.Body.Email)
-	if err != nil || user == nil {
-		return rocco.Redirect{}, ErrInvalidCredentials
+	if err != nil {
+		user = nil
+	}
+	subject := unknownEmailLockoutSubject(req.Body.Email)
+	if user != nil {
+		subject = user.ID
+	}
+
+	// Refuse locked accounts before checking the password, so guesses made
+	// during a lockout reveal nothing.
+	lockout, tracked := loadLockout(req.Context, subject)
+	if lockoutCfg.Enabled && lockout.IsLocked(time.Now()) {
+		return rocco.Redirect{}, ErrAccountLocked
 	}
 
 	// Require a stored password hash.
-	if user.PasswordHash == nil {
+	if user == nil || user.PasswordHash == nil {
+		if lockoutCfg.Enabled && recordLoginFailure(req.Context, subject, nil) {
+			return rocco.Redirect{}, ErrAccountLocked
+		}
 		return rocco.Redirect{}, ErrInvalidCredentials
 	}
 
 	// Verify password.
 	ok, err := intpassword.Verify(req.Body.Password, *user.PasswordHash)
 	if err != nil || !ok {
+		if lockoutCfg.Enabled && recordLoginFailure(req.Context, subject, user) {
+			return rocco.Redirect{}, ErrAccountLocked
+		}
 		return rocco.Redirect{}, ErrInvalidCredentials
 	}
+	if tracked {
+		_ = clearLockout(req.Context, user.ID)
+	}
+
+	// Upgrade hashes made with outdated parameters while the plaintext is at
+	// hand (best-effort; the old hash still verifies).
+	if hasher.NeedsRehash(*user.PasswordHash) {
+		if hash, err := hasher.Hash(req.Body.Password); err == nil {
+			user.PasswordHash = &hash
+			_ = users.Set(req.Context, user.ID, user)
+		}
+	}
 
 	// Require verified email.
 	if !user.EmailVerified {
 		return rocco.Redirect{}, ErrEmailNotVerified
 	}
 
-	// Create session.
-	sessionToken, err := intsession.GenerateToken()
+	// Defer the session to /login/mfa when a second factor is enrolled.
+	required, err := mfaRequired(req.Context, user.ID)
 	if err != nil {
 		return rocco.Redirect{}, ErrLoginFailed
 	}
-	now := time.Now()
-	sess := &models.Session{
-		Token:     sessionToken,
-		UserID:    user.ID,
-		CreatedAt: now,
-		ExpiresAt: now.Add(sessionCfg.TTL),
+	if required {
+		challenge, err := intsession.GenerateToken()
+		if err != nil {
+			return rocco.Redirect{}, ErrLoginFailed
+		}
+		now := time.Now()
+		vt := &models.VerificationToken{
+			Token:     challenge,
+			UserID:    user.ID,
+			Type:      models.TokenTypeMFAPending,
+			ReturnTo:  sanitizeReturnTo(req.Context, req.Body.ReturnTo),
+			Remember:  req.Body.Remember,
+			CreatedAt: now,
+			ExpiresAt: now.Add(tokensCfg.MFAPendingTTL),
+		}
+		if err := verificationTokens.Set(req.Context, vt, tokensCfg.MFAPendingTTL); err != nil {
+			return rocco.Redirect{}, ErrLoginFailed
+		}
+		headers := http.Header{}
+		headers.Add("Set-Cookie", mfaChallengeCookie(sum.MustUse[config.Session](req.Context), challenge, tokensCfg.MFAPendingTTL).String())
+		return rocco.Redirect{
+			URL:     mfaLoginURL,
+			Status:  http.StatusFound,
+			Headers: headers,
+		}, nil
 	}
-	if err := sessions.SetWithUserIndex(req.Context, sess, sessionCfg.TTL); err != nil {
+
+	// Create session.
+	cookie, err := startSession(req.Context, req.Request, user.ID, models.LoginMethodPassword, req.Body.Remember)
+	if err != nil {
 		return rocco.Redirect{}, ErrLoginFailed
 	}
 
 	headers := http.Header{}
-	headers.Add("Set-Cookie", buildSessionCookie(sessionCfg, sessionToken).String())
+	headers.Add("Set-Cookie", cookie.String())
 
 	return rocco.Redirect{
-		URL:     "/",
+		URL:     loginRedirectURL(req.Context, req.Body.ReturnTo),
 		Status:  http.StatusFound,
 		Headers: headers,
 	}, nil
 }).WithSummary("Login").
-	WithDescription("Authenticates a user with email and password. Redirects with session cookie on success.").
+	WithDescription("Authenticates a user with email and password. Redirects with session cookie on success, or to /login?step=mfa with a short-lived MFA challenge cookie when a second factor is required. With remember set the session is long-lived; otherwise its cookie ends with the browser session and it expires sooner when idle.").
 	WithTags("Auth").
-	WithErrors(ErrInvalidCredentials, ErrEmailNotVerified, ErrLoginFailed)
+	WithErrors(ErrInvalidCredentials, ErrAccountLocked, ErrEmailNotVerified, ErrLoginFailed)
 
 // RequestMagicLink sends a magic link sign-in email to the user.
 // Always responds 204 so callers cannot enumerate registered emails.
@@ -181,6 +315,8 @@ var RequestMagicLink = rocco.POST("/login/magic", func(req *rocco.Request[wire.M
 		Token:     rawToken,
 		UserID:    user.ID,
 		Type:      models.TokenTypeMagicLink,
+		ReturnTo:  sanitizeReturnTo(req.Context, req.Body.ReturnTo),
+		Remember:  req.Body.Remember,
 		CreatedAt: now,
 		ExpiresAt: now.Add(tokensCfg.MagicLinkTTL),
 	}
@@ -198,15 +334,13 @@ var RequestMagicLink = rocco.POST("/login/magic", func(req *rocco.Request[wire.M
 
 	return rocco.NoBody{}, nil
 }).WithSummary("Request magic link").
-	WithDescription("Sends a magic link sign-in email. Always returns 204 regardless of whether the email exists.").
+	WithDescription("Sends a magic link sign-in email. Always returns 204 regardless of whether the email exists. The remember choice applies to the session the link creates.").
 	WithTags("Auth").
 	WithSuccessStatus(204)
 
 // MagicLinkCallback validates a magic link token, creates a session, and redirects.
 var MagicLinkCallback = rocco.GET("/login/magic/callback", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
 	verificationTokens := sum.MustUse[contracts.VerificationTokens](req.Context)
-	sessions := sum.MustUse[contracts.Sessions](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
 
 	rawToken := req.Params.Query["token"]
 	if rawToken == "" {
@@ -226,26 +360,16 @@ var MagicLinkCallback = rocco.GET("/login/magic/callback", func(req *rocco.Reque
 	_ = verificationTokens.Delete(req.Context, rawToken)
 
 	// Create session.
-	sessionToken, err := intsession.GenerateToken()
+	cookie, err := startSession(req.Context, req.Request, vt.UserID, models.LoginMethodMagicLink, vt.Remember)
 	if err != nil {
 		return rocco.Redirect{}, ErrLoginFailed
 	}
-	now := time.Now()
-	sess := &models.Session{
-		Token:     sessionToken,
-		UserID:    vt.UserID,
-		CreatedAt: now,
-		ExpiresAt: now.Add(sessionCfg.TTL),
-	}
-	if err := sessions.SetWithUserIndex(req.Context, sess, sessionCfg.TTL); err != nil {
-		return rocco.Redirect{}, ErrLoginFailed
-	}
 
 	headers := http.Header{}
-	headers.Add("Set-Cookie", buildSessionCookie(sessionCfg, sessionToken).String())
+	headers.Add("Set-Cookie", cookie.String())
 
 	return rocco.Redirect{
-		URL:     "/",
+		URL:     loginRedirectURL(req.Context, vt.ReturnTo),
 		Status:  http.StatusFound,
 		Headers: headers,
 	}, nil
@@ -260,8 +384,6 @@ var MagicLinkCallback = rocco.GET("/login/magic/callback", func(req *rocco.Reque
 var VerifyEmail = rocco.POST("/verify-email", func(req *rocco.Request[wire.VerifyEmailRequest]) (rocco.Redirect, error) {
 	users := sum.MustUse[contracts.Users](req.Context)
 	verificationTokens := sum.MustUse[contracts.VerificationTokens](req.Context)
-	sessions := sum.MustUse[contracts.Sessions](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
 
 	// Validate the token.
 	vt, err := verificationTokens.Get(req.Context, req.Body.Token)
@@ -285,32 +407,29 @@ var VerifyEmail = rocco.POST("/verify-email", func(req *rocco.Request[wire.Verif
 		return rocco.Redirect{}, ErrLoginFailed
 	}
 
-	// Create session so the user is immediately logged in.
-	sessionToken, err := intsession.GenerateToken()
+	// Rotate the session the request already has, or create one so the user is
+	// immediately logged in.
+	cookie, err := rotateSessions(req.Context, req.Request, intsession.EventEmailVerified, user.ID)
 	if err != nil {
 		return rocco.Redirect{}, ErrLoginFailed
 	}
-	now := time.Now()
-	sess := &models.Session{
-		Token:     sessionToken,
-		UserID:    user.ID,
-		CreatedAt: now,
-		ExpiresAt: now.Add(sessionCfg.TTL),
-	}
-	if err := sessions.SetWithUserIndex(req.Context, sess, sessionCfg.TTL); err != nil {
-		return rocco.Redirect{}, ErrLoginFailed
+	if cookie == nil {
+		cookie, err = startSession(req.Context, req.Request, user.ID, models.LoginMethodEmailVerify, false)
+		if err != nil {
+			return rocco.Redirect{}, ErrLoginFailed
+		}
 	}
 
 	headers := http.Header{}
-	headers.Add("Set-Cookie", buildSessionCookie(sessionCfg, sessionToken).String())
+	headers.Add("Set-Cookie", cookie.String())
 
 	return rocco.Redirect{
-		URL:     "/",
+		URL:     loginRedirectURL(req.Context, vt.ReturnTo),
 		Status:  http.StatusFound,
 		Headers: headers,
 	}, nil
 }).WithSummary("Verify email").
-	WithDescription("Verifies a user's email address. Creates a session and redirects with session cookie on success.").
+	WithDescription("Verifies a user's email address and redirects with a session cookie on success. A session the request already has for the user is rotated to a new token, and the user's other sessions are revoked if configured; otherwise a session is created.").
 	WithTags("Auth").
 	WithErrors(ErrInvalidToken, ErrUserNotFound, ErrLoginFailed)
 
@@ -363,6 +482,8 @@ var RequestPasswordReset = rocco.POST("/password/reset", func(req *rocco.Request
 var ConfirmPasswordReset = rocco.POST("/password/reset/confirm", func(req *rocco.Request[wire.PasswordResetConfirmRequest]) (rocco.NoBody, error) {
 	users := sum.MustUse[contracts.Users](req.Context)
 	verificationTokens := sum.MustUse[contracts.VerificationTokens](req.Context)
+	passwordPolicy := sum.MustUse[*intpassword.Policy](req.Context)
+	hasher := sum.MustUse[*intpassword.Hasher](req.Context)
 
 	// Validate the token.
 	vt, err := verificationTokens.Get(req.Context, req.Body.Token)
@@ -373,27 +494,52 @@ var ConfirmPasswordReset = rocco.POST("/password/reset/confirm", func(req *rocco
 		return rocco.NoBody{}, ErrInvalidToken
 	}
 
+	user, err := users.Get(req.Context, vt.UserID)
+	if err != nil || user == nil {
+		return rocco.NoBody{}, ErrUserNotFound
+	}
+
+	// Screen the new password before consuming the token so a rejected
+	// password can be corrected without requesting another reset email.
+	if err := passwordPolicy.Check(req.Body.Password, user.Email); err != nil {
+		return rocco.NoBody{}, passwordError(err, ErrLoginFailed)
+	}
+
 	// Consume the token (single-use).
 	_ = verificationTokens.Delete(req.Context, req.Body.Token)
 
 	// Hash the new password.
-	hash, err := intpassword.Hash(req.Body.Password)
+	hash, err := hasher.Hash(req.Body.Password)
 	if err != nil {
 		return rocco.NoBody{}, ErrLoginFailed
 	}
 
 	// Update the user's password.
-	user, err := users.Get(req.Context, vt.UserID)
-	if err != nil || user == nil {
-		return rocco.NoBody{}, ErrUserNotFound
-	}
 	user.PasswordHash = &hash
 	if err := users.Set(req.Context, user.ID, user); err != nil {
 		return rocco.NoBody{}, ErrLoginFailed
 	}
 
+	// Proving control of the email unlocks the account.
+	if err := clearLockout(req.Context, user.ID); err != nil {
+		return rocco.NoBody{}, ErrLoginFailed
+	}
+
+	// Sessions opened with the old password must not outlive it, and neither
+	// may the refresh tokens of OAuth clients the user authorized with it.
+	if err := sum.MustUse[contracts.OAuthRefreshTokens](req.Context).RevokeByUser(req.Context, user.ID); err != nil {
+		return rocco.NoBody{}, ErrSessionsFailed
+	}
+	cookie, err := rotateSessions(req.Context, req.Request, intsession.EventPasswordReset, user.ID)
+	if err != nil {
+		return rocco.NoBody{}, ErrSessionsFailed
+	}
+	if cookie != nil {
+		intsession.SetResponseCookie(req.Context, cookie)
+	}
+
 	return rocco.NoBody{}, nil
 }).WithSummary("Confirm password reset").
-	WithDescription("Completes a password reset. The user may now log in with the new password.").
+	WithDescription("Completes a password reset and clears any login lockout. The new password is screened against the password policy; a rejected password leaves the token usable. The user's other sessions and the refresh tokens of OAuth clients they authorized are revoked, and a session the request has for the user is rotated to a new token, as configured. The user may now log in with the new password.").
 	WithTags("Auth").
-	WithErrors(ErrInvalidToken, ErrUserNotFound, ErrLoginFailed)
+	WithErrors(ErrInvalidToken, ErrUserNotFound, ErrPasswordRejected, ErrLoginFailed, ErrSessionsFailed)
@@ -12,10 +12,15 @@ var (
 	ErrSessionExpired = rocco.ErrUnauthorized.WithMessage("session expired")
 	// ErrInvalidCredentials is returned when email/password do not match.
 	ErrInvalidCredentials = rocco.ErrUnauthorized.WithMessage("invalid email or password")
+	// ErrAccountLocked is returned when a login is attempted while the account is locked after failed attempts.
+	ErrAccountLocked = rocco.ErrForbidden.WithMessage("account temporarily locked; try again later or reset your password")
 	// ErrEmailNotVerified is returned when a login is attempted before verifying email.
 	ErrEmailNotVerified = rocco.ErrForbidden.WithMessage("email address not verified")
 	// ErrInvalidToken is returned when a verification token is missing, expired, or has wrong type.
 	ErrInvalidToken = rocco.ErrBadRequest.WithMessage("invalid or expired token")
+	// ErrPasswordRejected is returned when a new password violates the password policy.
+	// Its details list each violated rule.
+	ErrPasswordRejected = rocco.ErrBadRequest.WithMessage("password does not meet requirements")
 	// ErrEmailAlreadyExists is returned when registering with an email that is already in use.
 	ErrEmailAlreadyExists = rocco.ErrConflict.WithMessage("email address already registered")
 	// ErrRegistrationFailed is returned when user creation fails for an unexpected reason.
@@ -31,10 +36,43 @@ var (
 	ErrProviderLinkFailed = rocco.ErrInternalServer.WithMessage("failed to link provider")
 	// ErrLastAuthMethod is returned when the user tries to unlink their only authentication method.
 	ErrLastAuthMethod = rocco.ErrConflict.WithMessage("cannot unlink last authentication method")
-	// ErrGitHubOAuthFailed is returned when the GitHub OAuth exchange or user fetch fails.
-	ErrGitHubOAuthFailed = rocco.ErrInternalServer.WithMessage("github oauth failed")
-	// ErrGoogleOAuthFailed is returned when the Google OAuth exchange or user fetch fails.
-	ErrGoogleOAuthFailed = rocco.ErrInternalServer.WithMessage("google oauth failed")
+	// ErrInvalidLinkState is returned when a pending provider link cannot be confirmed.
+	ErrInvalidLinkState = rocco.ErrBadRequest.WithMessage("invalid or expired link confirmation")
+	// ErrUnknownProvider is returned when the {provider} path parameter names no configured provider.
+	ErrUnknownProvider = rocco.ErrNotFound.WithMessage("unknown provider")
+	// ErrOAuthFailed is returned when an OAuth flow cannot be started for an unexpected reason.
+	ErrOAuthFailed = rocco.ErrInternalServer.WithMessage("oauth failed")
 	// ErrAccountNotLinked is returned on provider login when no account is linked to that identity.
 	ErrAccountNotLinked = rocco.ErrUnauthorized.WithMessage("no account linked to this provider account")
+
+	// ErrInvalidMFACode is returned when a TOTP code is wrong, outside the allowed skew, or already used.
+	ErrInvalidMFACode = rocco.ErrUnauthorized.WithMessage("invalid verification code")
+	// ErrMFAAlreadyEnabled is returned when enrolling or confirming while TOTP is already active.
+	ErrMFAAlreadyEnabled = rocco.ErrConflict.WithMessage("two-factor authentication already enabled")
+	// ErrMFANotEnrolled is returned when confirming or disabling TOTP without an enrollment.
+	ErrMFANotEnrolled = rocco.ErrNotFound.WithMessage("two-factor authentication not enrolled")
+	// ErrMFAFailed is returned when an MFA operation fails for an unexpected reason.
+	ErrMFAFailed = rocco.ErrInternalServer.WithMessage("two-factor authentication failed")
+
+	// ErrInvalidChallenge is returned when a passkey challenge is missing, expired, already used, or belongs to another ceremony.
+	ErrInvalidChallenge = rocco.ErrBadRequest.WithMessage("invalid or expired challenge")
+	// ErrPasskeyVerification is returned when an authenticator response fails WebAuthn verification.
+	ErrPasskeyVerification = rocco.ErrUnauthorized.WithMessage("passkey verification failed")
+	// ErrPasskeyNotFound is returned when a passkey does not exist or belongs to another user.
+	ErrPasskeyNotFound = rocco.ErrNotFound.WithMessage("passkey not found")
+	// ErrPasskeyFailed is returned when a passkey operation fails for an unexpected reason.
+	ErrPasskeyFailed = rocco.ErrInternalServer.WithMessage("passkey operation failed")
+	// ErrSessionRequired is returned when a request needs a session cookie and has none, or an unknown one.
+	ErrSessionRequired = rocco.ErrUnauthorized.WithMessage("session required")
+	// ErrAccessTokenFailed is returned when an access token cannot be minted.
+	ErrAccessTokenFailed = rocco.ErrInternalServer.WithMessage("failed to issue access token")
+	// ErrSessionsFailed is returned when the user's sessions cannot be read or changed.
+	ErrSessionsFailed = rocco.ErrInternalServer.WithMessage("session operation failed")
+
+	// ErrInvalidExpiry is returned when an API token is given an expiry that has already passed.
+	ErrInvalidExpiry = rocco.ErrBadRequest.WithMessage("expiry must be in the future")
+	// ErrAPITokenNotFound is returned when an API token does not exist or belongs to another user.
+	ErrAPITokenNotFound = rocco.ErrNotFound.WithMessage("api token not found")
+	// ErrAPITokensFailed is returned when an API token operation fails for an unexpected reason.
+	ErrAPITokensFailed = rocco.ErrInternalServer.WithMessage("api token operation failed")
 )
@@ -1,6 +1,11 @@
 package handlers
 
-import "github.com/zoobzio/rocco"
+import (
+	"net/http"
+
+	"github.com/zoobzio/rocco"
+	intratelimit "github.com/zoobzio/sumatra/internal/ratelimit"
+)
 
 // All returns all public API endpoints for registration with the router.
 func All() []rocco.Endpoint {
@@ -13,23 +18,65 @@ func All() []rocco.Endpoint {
 		VerifyEmail,
 		RequestPasswordReset,
 		ConfirmPasswordReset,
+		LoginMFA,
+		BeginPasskeyLogin,
+		LoginPasskey,
 		Logout,
-		InitiateGitHubLogin,
-		GitHubLoginCallback,
-		InitiateGoogleLogin,
-		GoogleLoginCallback,
+		InitiateProviderLogin,
+		ProviderLoginCallback,
+
+		// Tokens
+		ExchangeToken,
+		GetJWKS,
 
 		// Users
 		GetMe,
 		UpdateMe,
 
+		// Sessions
+		ListSessions,
+		RevokeSession,
+		RevokeOtherSessions,
+
+		// MFA
+		EnrollTOTP,
+		ConfirmTOTP,
+		DisableTOTP,
+		RegenerateRecoveryCodes,
+
+		// Passkeys
+		BeginPasskeyRegistration,
+		FinishPasskeyRegistration,
+		ListPasskeys,
+		DeletePasskey,
+
+		// API tokens
+		CreateAPIToken,
+		ListAPITokens,
+		RevokeAPIToken,
+
 		// Providers
 		ListProviders,
-		InitiateGitHubLink,
-		GitHubLinkCallback,
-		UnlinkGitHub,
-		InitiateGoogleLink,
-		GoogleLinkCallback,
-		UnlinkGoogle,
+		InitiateProviderLink,
+		ProviderLinkCallback,
+		ConfirmProviderLink,
+		UnlinkProvider,
+	}
+}
+
+// RateLimited returns the endpoints guarded by the auth rate limiter. Endpoints
+// that take an email are also limited per address to stop email bombing.
+func RateLimited() []intratelimit.Rule {
+	return []intratelimit.Rule{
+		{Method: http.MethodPost, Path: "/register", EmailField: "email"},
+		{Method: http.MethodPost, Path: "/login", EmailField: "email"},
+		{Method: http.MethodPost, Path: "/login/magic", EmailField: "email"},
+		{Method: http.MethodPost, Path: "/password/reset", EmailField: "email"},
+		{Method: http.MethodPost, Path: "/password/reset/confirm"},
+		{Method: http.MethodPost, Path: "/login/mfa"},
+		// Each begin call stores a challenge in Redis.
+		{Method: http.MethodPost, Path: "/login/passkey/begin"},
+		{Method: http.MethodPost, Path: "/login/passkey"},
+		{Method: http.MethodPost, Path: "/oauth/token"},
 	}
 }
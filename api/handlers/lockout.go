@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/api/contracts"
+	"github.com/zoobzio/sumatra/config"
+	extpostmark "github.com/zoobzio/sumatra/external/postmark"
+	intlockout "github.com/zoobzio/sumatra/internal/lockout"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// lockoutPolicy builds the failed-login policy from config.
+func lockoutPolicy(cfg config.Lockout) intlockout.Policy {
+	return intlockout.Policy{
+		Threshold:    cfg.Threshold,
+		Window:       cfg.Window,
+		BaseDuration: cfg.BaseDuration,
+		MaxDuration:  cfg.MaxDuration,
+	}
+}
+
+// unknownEmailLockoutSubject returns the subject failed logins for an email
+// with no password account are counted against. Such addresses lock like real
+// accounts, so a lockout does not reveal whether an address is registered; the
+// address is hashed to keep it out of Redis key names.
+func unknownEmailLockoutSubject(email string) string {
+	h := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return "email:" + hex.EncodeToString(h[:])
+}
+
+// loadLockout returns the subject's lockout record, or a fresh one if none exists.
+// Lookup errors fail open so a Redis outage does not block every sign-in.
+func loadLockout(ctx context.Context, subject string) (*models.LoginLockout, bool) {
+	lockouts := sum.MustUse[contracts.LoginLockouts](ctx)
+
+	l, err := lockouts.Get(ctx, subject)
+	if err != nil || l == nil {
+		return &models.LoginLockout{UserID: subject}, false
+	}
+	return l, true
+}
+
+// recordLoginFailure counts a failed password attempt against the subject. When
+// the attempt trips the threshold the subject is locked and, if it is a user,
+// the user is emailed. It reports whether the subject is now locked.
+func recordLoginFailure(ctx context.Context, subject string, user *models.User) bool {
+	lockouts := sum.MustUse[contracts.LoginLockouts](ctx)
+	cfg := sum.MustUse[config.Lockout](ctx)
+
+	l, locked, err := lockouts.RecordFailure(ctx, subject, lockoutPolicy(cfg), time.Now(), cfg.ResetAfter)
+	if err != nil {
+		return false
+	}
+
+	if locked && user != nil && l.LockedUntil != nil {
+		// Notify the owner (best-effort); repeated lockouts mean someone else may be guessing.
+		postmarkCfg := sum.MustUse[config.Postmark](ctx)
+		emailClient := extpostmark.NewClient(postmarkCfg.ServerToken, postmarkCfg.DefaultFrom)
+		_, _ = emailClient.SendEmail(ctx, extpostmark.EmailRequest{
+			To:      user.Email,
+			Subject: "Your account was locked",
+			TextBody: "We locked your account after several failed sign-in attempts.\n\n" +
+				"You can try again after " + l.LockedUntil.UTC().Format(time.RFC1123) + ".\n\n" +
+				"If this wasn't you, reset your password to unlock your account now.",
+		})
+	}
+	return locked
+}
+
+// clearLockout removes any lockout record for the user.
+func clearLockout(ctx context.Context, userID string) error {
+	lockouts := sum.MustUse[contracts.LoginLockouts](ctx)
+
+	if err := lockouts.Delete(ctx, userID); err != nil && !errors.Is(err, grub.ErrNotFound) {
+		return err
+	}
+	return nil
+}
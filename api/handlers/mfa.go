@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/api/contracts"
+	"github.com/zoobzio/sumatra/api/wire"
+	"github.com/zoobzio/sumatra/config"
+	intpassword "github.com/zoobzio/sumatra/internal/password"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	inttotp "github.com/zoobzio/sumatra/internal/totp"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// useTOTPCode checks a code against the enrollment and records its time step.
+// The step is only recorded if it is later than the last accepted one, so a
+// code cannot be replayed, even by requests racing with the same code.
+func useTOTPCode(ctx context.Context, secret *models.TOTPSecret, code string, skew int) bool {
+	totpSecrets := sum.MustUse[contracts.TOTPSecrets](ctx)
+
+	step, ok, err := inttotp.Validate(secret.Secret, code, time.Now(), skew)
+	if err != nil || !ok || step <= secret.LastUsedStep {
+		return false
+	}
+	if err := totpSecrets.AdvanceStep(ctx, secret.UserID, step); err != nil {
+		return false
+	}
+	secret.LastUsedStep = step
+	return true
+}
+
+// mfaRequired reports whether the user has a confirmed TOTP enrollment.
+// Lookup failures other than not-found are returned so callers fail closed.
+func mfaRequired(ctx context.Context, userID string) (bool, error) {
+	totpSecrets := sum.MustUse[contracts.TOTPSecrets](ctx)
+
+	secret, err := totpSecrets.Get(ctx, userID)
+	if errors.Is(err, grub.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return secret != nil && secret.IsEnabled(), nil
+}
+
+// issueRecoveryCodes generates a fresh set of recovery codes for the user,
+// replacing any existing set, and returns the plaintext codes for display.
+// Codes are hashed with the configured parameters; being single-use, codes
+// hashed under older parameters are never rehashed but age out as they are
+// used or regenerated.
+func issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	recoveryCodes := sum.MustUse[contracts.RecoveryCodes](ctx)
+	hasher := sum.MustUse[*intpassword.Hasher](ctx)
+
+	codes, err := inttotp.GenerateRecoveryCodes(inttotp.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*models.RecoveryCode, len(codes))
+	for i, code := range codes {
+		hash, err := hasher.Hash(code)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = &models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: hash,
+		}
+	}
+
+	if err := recoveryCodes.Replace(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// consumeRecoveryCode checks code against the user's unused recovery codes and
+// marks the matching one as used. It reports false if no unused code matches.
+func consumeRecoveryCode(ctx context.Context, userID, code string) bool {
+	recoveryCodes := sum.MustUse[contracts.RecoveryCodes](ctx)
+
+	unused, err := recoveryCodes.ListUnusedByUser(ctx, userID)
+	if err != nil {
+		return false
+	}
+
+	normalized := inttotp.NormalizeRecoveryCode(code)
+	for _, rc := range unused {
+		ok, err := intpassword.Verify(normalized, rc.CodeHash)
+		if err != nil || !ok {
+			continue
+		}
+		// MarkUsed only matches an unused row, so a concurrent use of the same
+		// code loses here rather than producing two sessions.
+		return recoveryCodes.MarkUsed(ctx, rc.ID) == nil
+	}
+	return false
+}
+
+// EnrollTOTP starts TOTP enrollment by generating a new secret for the authenticated user.
+// The enrollment stays pending until confirmed with a valid code.
+var EnrollTOTP = rocco.POST("/me/mfa/totp", requireSession(func(req *rocco.Request[rocco.NoBody]) (wire.TOTPEnrollResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	totpSecrets := sum.MustUse[contracts.TOTPSecrets](req.Context)
+	mfaCfg := sum.MustUse[config.MFA](req.Context)
+
+	user, err := users.Get(req.Context, req.Identity.ID())
+	if err != nil {
+		return wire.TOTPEnrollResponse{}, ErrUserNotFound
+	}
+
+	// Refuse to overwrite a confirmed enrollment; the user must disable it first.
+	existing, err := totpSecrets.Get(req.Context, user.ID)
+	if err == nil && existing != nil && existing.IsEnabled() {
+		return wire.TOTPEnrollResponse{}, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := inttotp.GenerateSecret()
+	if err != nil {
+		return wire.TOTPEnrollResponse{}, ErrMFAFailed
+	}
+	record := &models.TOTPSecret{
+		UserID: user.ID,
+		Secret: secret,
+	}
+	if err := totpSecrets.Set(req.Context, user.ID, record); err != nil {
+		return wire.TOTPEnrollResponse{}, ErrMFAFailed
+	}
+
+	return wire.TOTPEnrollResponse{
+		Secret: secret,
+		URI:    inttotp.URI(mfaCfg.Issuer, user.Email, secret),
+	}, nil
+})).WithSummary("Enroll TOTP").
+	WithDescription("Generates a new TOTP secret and provisioning URI. The enrollment must be confirmed with a code before it takes effect. Requires a session; API tokens are refused.").
+	WithTags("MFA").
+	WithAuthentication().
+	WithSuccessStatus(201).
+	WithErrors(ErrSessionRequired, ErrUserNotFound, ErrMFAAlreadyEnabled, ErrMFAFailed)
+
+// ConfirmTOTP activates a pending TOTP enrollment using a code from the authenticator app.
+// The response carries the account's recovery codes, which are not retrievable later.
+var ConfirmTOTP = rocco.POST("/me/mfa/totp/confirm", requireSession(func(req *rocco.Request[wire.TOTPCodeRequest]) (wire.RecoveryCodesResponse, error) {
+	totpSecrets := sum.MustUse[contracts.TOTPSecrets](req.Context)
+	mfaCfg := sum.MustUse[config.MFA](req.Context)
+
+	userID := req.Identity.ID()
+
+	secret, err := totpSecrets.Get(req.Context, userID)
+	if err != nil || secret == nil {
+		return wire.RecoveryCodesResponse{}, ErrMFANotEnrolled
+	}
+	if secret.IsEnabled() {
+		return wire.RecoveryCodesResponse{}, ErrMFAAlreadyEnabled
+	}
+
+	if !useTOTPCode(req.Context, secret, req.Body.Code, mfaCfg.Skew) {
+		return wire.RecoveryCodesResponse{}, ErrInvalidMFACode
+	}
+
+	codes, err := issueRecoveryCodes(req.Context, userID)
+	if err != nil {
+		return wire.RecoveryCodesResponse{}, ErrMFAFailed
+	}
+
+	now := time.Now()
+	secret.ConfirmedAt = &now
+	if err := totpSecrets.Set(req.Context, userID, secret); err != nil {
+		return wire.RecoveryCodesResponse{}, ErrMFAFailed
+	}
+
+	cookie, err := rotateSessions(req.Context, req.Request, intsession.EventMFAEnabled, userID)
+	if err != nil {
+		return wire.RecoveryCodesResponse{}, ErrMFAFailed
+	}
+	if cookie != nil {
+		intsession.SetResponseCookie(req.Context, cookie)
+	}
+
+	return wire.RecoveryCodesResponse{Codes: codes}, nil
+})).WithSummary("Confirm TOTP").
+	WithDescription("Confirms a pending TOTP enrollment and returns single-use recovery codes. Password logins require a code once confirmed. The current session is rotated to a new token and the user's other sessions are revoked, as configured. Requires a session; API tokens are refused.").
+	WithTags("MFA").
+	WithAuthentication().
+	WithErrors(ErrSessionRequired, ErrMFANotEnrolled, ErrMFAAlreadyEnabled, ErrInvalidMFACode, ErrMFAFailed)
+
+// DisableTOTP removes the authenticated user's TOTP enrollment and recovery codes.
+// A current code is required so a hijacked session alone cannot turn MFA off.
+var DisableTOTP = rocco.POST("/me/mfa/totp/disable", requireSession(func(req *rocco.Request[wire.TOTPCodeRequest]) (rocco.NoBody, error) {
+	totpSecrets := sum.MustUse[contracts.TOTPSecrets](req.Context)
+	recoveryCodes := sum.MustUse[contracts.RecoveryCodes](req.Context)
+	mfaCfg := sum.MustUse[config.MFA](req.Context)
+
+	userID := req.Identity.ID()
+
+	secret, err := totpSecrets.Get(req.Context, userID)
+	if err != nil || secret == nil || !secret.IsEnabled() {
+		return rocco.NoBody{}, ErrMFANotEnrolled
+	}
+
+	if !useTOTPCode(req.Context, secret, req.Body.Code, mfaCfg.Skew) {
+		return rocco.NoBody{}, ErrInvalidMFACode
+	}
+
+	if err := recoveryCodes.DeleteByUser(req.Context, userID); err != nil {
+		return rocco.NoBody{}, ErrMFAFailed
+	}
+	if err := totpSecrets.Delete(req.Context, userID); err != nil {
+		return rocco.NoBody{}, ErrMFAFailed
+	}
+
+	return rocco.NoBody{}, nil
+})).WithSummary("Disable TOTP").
+	WithDescription("Removes the TOTP enrollment and recovery codes after verifying a current code. Requires a session; API tokens are refused.").
+	WithTags("MFA").
+	WithAuthentication().
+	WithSuccessStatus(204).
+	WithErrors(ErrSessionRequired, ErrMFANotEnrolled, ErrInvalidMFACode, ErrMFAFailed)
+
+// RegenerateRecoveryCodes replaces the authenticated user's recovery codes with a new set.
+// A current TOTP code is required; all previously issued codes stop working.
+var RegenerateRecoveryCodes = rocco.POST("/me/mfa/recovery-codes/regenerate", requireSession(func(req *rocco.Request[wire.TOTPCodeRequest]) (wire.RecoveryCodesResponse, error) {
+	totpSecrets := sum.MustUse[contracts.TOTPSecrets](req.Context)
+	mfaCfg := sum.MustUse[config.MFA](req.Context)
+
+	userID := req.Identity.ID()
+
+	secret, err := totpSecrets.Get(req.Context, userID)
+	if err != nil || secret == nil || !secret.IsEnabled() {
+		return wire.RecoveryCodesResponse{}, ErrMFANotEnrolled
+	}
+
+	if !useTOTPCode(req.Context, secret, req.Body.Code, mfaCfg.Skew) {
+		return wire.RecoveryCodesResponse{}, ErrInvalidMFACode
+	}
+
+	codes, err := issueRecoveryCodes(req.Context, userID)
+	if err != nil {
+		return wire.RecoveryCodesResponse{}, ErrMFAFailed
+	}
+
+	return wire.RecoveryCodesResponse{Codes: codes}, nil
+})).WithSummary("Regenerate recovery codes").
+	WithDescription("Invalidates all existing recovery codes and returns a new set. Requires a current TOTP code. Requires a session; API tokens are refused.").
+	WithTags("MFA").
+	WithAuthentication().
+	WithErrors(ErrSessionRequired, ErrMFANotEnrolled, ErrInvalidMFACode, ErrMFAFailed)
+
+// LoginMFA completes a password login for an MFA-enrolled user.
+// It exchanges the challenge cookie set by Login plus a TOTP or recovery code for a session.
+var LoginMFA = rocco.POST("/login/mfa", func(req *rocco.Request[wire.MFALoginRequest]) (rocco.Redirect, error) {
+	verificationTokens := sum.MustUse[contracts.VerificationTokens](req.Context)
+	totpSecrets := sum.MustUse[contracts.TOTPSecrets](req.Context)
+	mfaCfg := sum.MustUse[config.MFA](req.Context)
+	sessionCfg := sum.MustUse[config.Session](req.Context)
+
+	// Validate the challenge token.
+	challenge, err := req.Cookie(mfaChallengeCookieName)
+	if err != nil || challenge.Value == "" {
+		return rocco.Redirect{}, ErrInvalidToken
+	}
+	// Consume the token (single-use) before checking the code. A wrong code
+	// sends the user back to the password step, which bounds guessing to one
+	// attempt per password check; a failed take fails closed.
+	vt, err := verificationTokens.Take(req.Context, challenge.Value)
+	if err != nil || vt == nil {
+		return rocco.Redirect{}, ErrInvalidToken
+	}
+	if vt.Type != models.TokenTypeMFAPending || vt.IsExpired() {
+		return rocco.Redirect{}, ErrInvalidToken
+	}
+
+	secret, err := totpSecrets.Get(req.Context, vt.UserID)
+	if err != nil || secret == nil || !secret.IsEnabled() {
+		return rocco.Redirect{}, ErrInvalidToken
+	}
+
+	if inttotp.IsRecoveryCode(req.Body.Code) {
+		if !consumeRecoveryCode(req.Context, vt.UserID, req.Body.Code) {
+			return rocco.Redirect{}, ErrInvalidMFACode
+		}
+	} else {
+		if !useTOTPCode(req.Context, secret, req.Body.Code, mfaCfg.Skew) {
+			return rocco.Redirect{}, ErrInvalidMFACode
+		}
+	}
+
+	// Create session.
+	cookie, err := startSession(req.Context, req.Request, vt.UserID, models.LoginMethodMFA, vt.Remember)
+	if err != nil {
+		return rocco.Redirect{}, ErrLoginFailed
+	}
+
+	headers := http.Header{}
+	headers.Add("Set-Cookie", cookie.String())
+	headers.Add("Set-Cookie", mfaChallengeCookie(sessionCfg, "", -1).String())
+
+	return rocco.Redirect{
+		URL:     loginRedirectURL(req.Context, vt.ReturnTo),
+		Status:  http.StatusFound,
+		Headers: headers,
+	}, nil
+}).WithSummary("Complete MFA login").
+	WithDescription("Exchanges the MFA challenge cookie set by password login and a TOTP or recovery code for a session. Redirects with session cookie on success.").
+	WithTags("Auth").
+	WithErrors(ErrInvalidToken, ErrInvalidMFACode, ErrLoginFailed)
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/api/contracts"
+	"github.com/zoobzio/sumatra/api/transformers"
+	"github.com/zoobzio/sumatra/api/wire"
+	"github.com/zoobzio/sumatra/config"
+	intpasskey "github.com/zoobzio/sumatra/internal/passkey"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// newRelyingParty builds the WebAuthn relying party from config.
+func newRelyingParty(cfg config.WebAuthn) (*webauthn.WebAuthn, error) {
+	return intpasskey.New(cfg.RPID, cfg.RPDisplayName, cfg.RPOrigins)
+}
+
+// storeChallenge persists ceremony state under a fresh challenge ID and returns the ID.
+func storeChallenge(ctx context.Context, userID string, typ models.ChallengeType, session *webauthn.SessionData, ttl time.Duration) (string, error) {
+	challenges := sum.MustUse[contracts.PasskeyChallenges](ctx)
+
+	data, err := intpasskey.EncodeSession(session)
+	if err != nil {
+		return "", err
+	}
+	id, err := intsession.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	challenge := &models.PasskeyChallenge{
+		ID:        id,
+		UserID:    userID,
+		Type:      typ,
+		Data:      data,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := challenges.Set(ctx, challenge, ttl); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// consumeChallenge loads and deletes a challenge, returning its ceremony state.
+// The challenge must match the expected type and, for registration, the user.
+func consumeChallenge(ctx context.Context, id, userID string, typ models.ChallengeType) (webauthn.SessionData, bool) {
+	challenges := sum.MustUse[contracts.PasskeyChallenges](ctx)
+
+	challenge, err := challenges.Get(ctx, id)
+	if err != nil || challenge == nil {
+		return webauthn.SessionData{}, false
+	}
+	// Single-use regardless of outcome so a challenge cannot be retried.
+	_ = challenges.Delete(ctx, id)
+
+	if challenge.Type != typ || challenge.UserID != userID || challenge.IsExpired() {
+		return webauthn.SessionData{}, false
+	}
+	session, err := intpasskey.DecodeSession(challenge.Data)
+	if err != nil {
+		return webauthn.SessionData{}, false
+	}
+	return session, true
+}
+
+// BeginPasskeyRegistration starts registering a new passkey for the authenticated user.
+// Existing passkeys are excluded so the same authenticator is not registered twice.
+var BeginPasskeyRegistration = rocco.POST("/me/passkeys/register/begin", requireSession(func(req *rocco.Request[rocco.NoBody]) (wire.PasskeyOptionsResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	passkeys := sum.MustUse[contracts.Passkeys](req.Context)
+	webauthnCfg := sum.MustUse[config.WebAuthn](req.Context)
+
+	user, err := users.Get(req.Context, req.Identity.ID())
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrUserNotFound
+	}
+	existing, err := passkeys.ListByUser(req.Context, user.ID)
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+
+	rp, err := newRelyingParty(webauthnCfg)
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+	wu := intpasskey.NewUser(user, existing)
+	creation, session, err := rp.BeginRegistration(wu, webauthn.WithExclusions(wu.Exclusions()))
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+
+	challengeID, err := storeChallenge(req.Context, user.ID, models.ChallengeTypeRegistration, session, webauthnCfg.ChallengeTTL)
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+	options, err := json.Marshal(creation)
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+
+	return wire.PasskeyOptionsResponse{ChallengeID: challengeID, Options: options}, nil
+})).WithSummary("Begin passkey registration").
+	WithDescription("Returns WebAuthn creation options for navigator.credentials.create() and a challenge ID to finish registration with. Requires a session; API tokens are refused.").
+	WithTags("Passkeys").
+	WithAuthentication().
+	WithErrors(ErrSessionRequired, ErrUserNotFound, ErrPasskeyFailed)
+
+// FinishPasskeyRegistration verifies the authenticator's attestation and stores the new passkey.
+var FinishPasskeyRegistration = rocco.POST("/me/passkeys/register/finish", requireSession(func(req *rocco.Request[wire.PasskeyRegisterRequest]) (wire.PasskeyResponse, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	passkeys := sum.MustUse[contracts.Passkeys](req.Context)
+	webauthnCfg := sum.MustUse[config.WebAuthn](req.Context)
+
+	userID := req.Identity.ID()
+
+	session, ok := consumeChallenge(req.Context, req.Body.ChallengeID, userID, models.ChallengeTypeRegistration)
+	if !ok {
+		return wire.PasskeyResponse{}, ErrInvalidChallenge
+	}
+
+	user, err := users.Get(req.Context, userID)
+	if err != nil {
+		return wire.PasskeyResponse{}, ErrUserNotFound
+	}
+	existing, err := passkeys.ListByUser(req.Context, userID)
+	if err != nil {
+		return wire.PasskeyResponse{}, ErrPasskeyFailed
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Body.Credential)
+	if err != nil {
+		return wire.PasskeyResponse{}, ErrPasskeyVerification
+	}
+
+	rp, err := newRelyingParty(webauthnCfg)
+	if err != nil {
+		return wire.PasskeyResponse{}, ErrPasskeyFailed
+	}
+	credential, err := rp.CreateCredential(intpasskey.NewUser(user, existing), session, parsed)
+	if err != nil {
+		return wire.PasskeyResponse{}, ErrPasskeyVerification
+	}
+
+	created, err := passkeys.Create(req.Context, intpasskey.FromCredential(userID, req.Body.Name, credential))
+	if err != nil {
+		return wire.PasskeyResponse{}, ErrPasskeyFailed
+	}
+
+	return transformers.PasskeyToResponse(created), nil
+})).WithSummary("Finish passkey registration").
+	WithDescription("Verifies the credential returned by navigator.credentials.create() and registers it as a passkey. Requires a session; API tokens are refused.").
+	WithTags("Passkeys").
+	WithAuthentication().
+	WithSuccessStatus(201).
+	WithErrors(ErrSessionRequired, ErrInvalidChallenge, ErrUserNotFound, ErrPasskeyVerification, ErrPasskeyFailed)
+
+// ListPasskeys returns all passkeys registered to the authenticated user.
+var ListPasskeys = rocco.GET("/me/passkeys", func(req *rocco.Request[rocco.NoBody]) (wire.PasskeyListResponse, error) {
+	passkeys := sum.MustUse[contracts.Passkeys](req.Context)
+
+	list, err := passkeys.ListByUser(req.Context, req.Identity.ID())
+	if err != nil {
+		return wire.PasskeyListResponse{}, ErrPasskeyFailed
+	}
+
+	return transformers.PasskeysToList(list), nil
+}).WithSummary("List passkeys").
+	WithDescription("Returns all passkeys registered to the authenticated user.").
+	WithTags("Passkeys").
+	WithAuthentication().
+	WithErrors(ErrPasskeyFailed)
+
+// DeletePasskey removes one of the authenticated user's passkeys.
+// At least one other authentication method must remain.
+var DeletePasskey = rocco.DELETE("/me/passkeys/{id}", requireSession(func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	providers := sum.MustUse[contracts.Providers](req.Context)
+	passkeys := sum.MustUse[contracts.Passkeys](req.Context)
+
+	userID := req.Identity.ID()
+
+	id, err := strconv.ParseInt(req.Params.Path["id"], 10, 64)
+	if err != nil {
+		return rocco.NoBody{}, ErrPasskeyNotFound
+	}
+
+	user, err := users.Get(req.Context, userID)
+	if err != nil {
+		return rocco.NoBody{}, ErrUserNotFound
+	}
+	list, err := passkeys.ListByUser(req.Context, userID)
+	if err != nil {
+		return rocco.NoBody{}, ErrPasskeyFailed
+	}
+	found := false
+	for _, p := range list {
+		if p.ID == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return rocco.NoBody{}, ErrPasskeyNotFound
+	}
+
+	// Verify the user has another way to sign in.
+	allProviders, err := providers.ListByUser(req.Context, userID)
+	if err != nil {
+		return rocco.NoBody{}, ErrPasskeyFailed
+	}
+	remainingMethods := len(allProviders) + len(list) - 1
+	if user.PasswordHash != nil {
+		remainingMethods++
+	}
+	if remainingMethods == 0 {
+		return rocco.NoBody{}, ErrLastAuthMethod
+	}
+
+	if err := passkeys.DeleteByUserAndID(req.Context, userID, id); err != nil {
+		return rocco.NoBody{}, ErrPasskeyFailed
+	}
+
+	return rocco.NoBody{}, nil
+})).WithSummary("Delete passkey").
+	WithDescription("Removes a passkey from the authenticated user. Requires at least one other authentication method to remain. Requires a session; API tokens are refused.").
+	WithTags("Passkeys").
+	WithAuthentication().
+	WithPathParams("id").
+	WithSuccessStatus(204).
+	WithErrors(ErrSessionRequired, ErrPasskeyNotFound, ErrUserNotFound, ErrLastAuthMethod, ErrPasskeyFailed)
+
+// BeginPasskeyLogin starts a passkey sign-in.
+// No account is identified up front; the authenticator offers its discoverable credentials.
+var BeginPasskeyLogin = rocco.POST("/login/passkey/begin", func(req *rocco.Request[rocco.NoBody]) (wire.PasskeyOptionsResponse, error) {
+	webauthnCfg := sum.MustUse[config.WebAuthn](req.Context)
+
+	rp, err := newRelyingParty(webauthnCfg)
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+	assertion, session, err := rp.BeginDiscoverableLogin()
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+
+	challengeID, err := storeChallenge(req.Context, "", models.ChallengeTypeLogin, session, webauthnCfg.ChallengeTTL)
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+	options, err := json.Marshal(assertion)
+	if err != nil {
+		return wire.PasskeyOptionsResponse{}, ErrPasskeyFailed
+	}
+
+	return wire.PasskeyOptionsResponse{ChallengeID: challengeID, Options: options}, nil
+}).WithSummary("Begin passkey login").
+	WithDescription("Returns WebAuthn request options for navigator.credentials.get() and a challenge ID to finish sign-in with.").
+	WithTags("Auth").
+	WithErrors(ErrPasskeyFailed)
+
+// LoginPasskey completes a passkey sign-in and creates a session.
+// A user-verified passkey already combines possession and a local factor,
+// so no TOTP step follows.
+var LoginPasskey = rocco.POST("/login/passkey", func(req *rocco.Request[wire.PasskeyLoginRequest]) (rocco.Redirect, error) {
+	users := sum.MustUse[contracts.Users](req.Context)
+	passkeys := sum.MustUse[contracts.Passkeys](req.Context)
+	webauthnCfg := sum.MustUse[config.WebAuthn](req.Context)
+
+	session, ok := consumeChallenge(req.Context, req.Body.ChallengeID, "", models.ChallengeTypeLogin)
+	if !ok {
+		return rocco.Redirect{}, ErrInvalidChallenge
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Body.Credential)
+	if err != nil {
+		return rocco.Redirect{}, ErrPasskeyVerification
+	}
+
+	rp, err := newRelyingParty(webauthnCfg)
+	if err != nil {
+		return rocco.Redirect{}, ErrLoginFailed
+	}
+
+	// Resolve the account from the user handle the authenticator returned.
+	var owner *intpasskey.User
+	handler := func(_, userHandle []byte) (webauthn.User, error) {
+		user, err := users.Get(req.Context, string(userHandle))
+		if err != nil {
+			return nil, err
+		}
+		list, err := passkeys.ListByUser(req.Context, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		owner = intpasskey.NewUser(user, list)
+		return owner, nil
+	}
+
+	_, credential, err := rp.ValidatePasskeyLogin(handler, session, parsed)
+	if err != nil || owner == nil {
+		return rocco.Redirect{}, ErrPasskeyVerification
+	}
+
+	stored := owner.Passkey(credential.ID)
+	if stored == nil {
+		return rocco.Redirect{}, ErrPasskeyVerification
+	}
+	if err := intpasskey.ApplyAssertion(stored, credential, time.Now()); err != nil {
+		return rocco.Redirect{}, ErrPasskeyVerification
+	}
+	if err := passkeys.Set(req.Context, strconv.FormatInt(stored.ID, 10), stored); err != nil {
+		return rocco.Redirect{}, ErrLoginFailed
+	}
+
+	// Create session.
+	cookie, err := startSession(req.Context, req.Request, stored.UserID, models.LoginMethodPasskey, req.Body.Remember)
+	if err != nil {
+		return rocco.Redirect{}, ErrLoginFailed
+	}
+
+	headers := http.Header{}
+	headers.Add("Set-Cookie", cookie.String())
+
+	return rocco.Redirect{
+		URL:     loginRedirectURL(req.Context, req.Body.ReturnTo),
+		Status:  http.StatusFound,
+		Headers: headers,
+	}, nil
+}).WithSummary("Complete passkey login").
+	WithDescription("Verifies the assertion returned by navigator.credentials.get(). Redirects with session cookie on success.").
+	WithTags("Auth").
+	WithErrors(ErrInvalidChallenge, ErrPasskeyVerification, ErrLoginFailed)
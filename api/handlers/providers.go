@@ -1,8 +1,12 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/zoobzio/rocco"
@@ -16,196 +20,164 @@ import (
 	"github.com/zoobzio/sumatra/models"
 )
 
-// InitiateGitHubLink begins the GitHub OAuth flow for linking a provider to an existing account.
-// The user must be authenticated. Generates a state cookie and redirects to GitHub.
-var InitiateGitHubLink = rocco.GET("/providers/github/link", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
-	githubCfg := sum.MustUse[config.GitHub](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
-
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
-	)
-
-	state, stateCookie, err := stateMgr.GenerateState()
-	if err != nil {
-		return rocco.Redirect{}, ErrGitHubOAuthFailed
-	}
-
-	client := intoauth.NewGitHubClient(githubCfg.ClientID, githubCfg.ClientSecret)
-	authURL := client.AuthorizeURL(githubCfg.CallbackURL, state)
-
-	headers := http.Header{}
-	headers.Add("Set-Cookie", stateCookie.String())
-
-	return rocco.Redirect{
-		URL:     authURL,
-		Status:  http.StatusFound,
-		Headers: headers,
-	}, nil
-}).WithSummary("Initiate GitHub link").
-	WithDescription("Begins the GitHub OAuth flow for linking a GitHub account to the authenticated user.").
-	WithTags("Providers").
-	WithAuthentication().
-	WithErrors(ErrGitHubOAuthFailed)
-
-// GitHubLinkCallback completes the GitHub OAuth linking flow.
-// Validates the state, exchanges the code, and creates a Provider record.
-// If the GitHub account is already linked to another user, returns an error.
-var GitHubLinkCallback = rocco.GET("/providers/github/callback", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
-	providers := sum.MustUse[contracts.Providers](req.Context)
-	githubCfg := sum.MustUse[config.GitHub](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
-
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
+// lookupProvider resolves the {provider} path parameter against the OAuth registry.
+func lookupProvider(ctx context.Context, name string) (intoauth.Provider, bool) {
+	registry := sum.MustUse[*intoauth.Registry](ctx)
+	return registry.Get(name)
+}
+
+// newStateManager builds the OAuth state manager from session config.
+func newStateManager(cfg config.Session) *intsession.StateManager {
+	return intsession.NewStateManager(
+		cfg.StateSecret,
+		cfg.CookieDomain,
+		cfg.CookieSecure,
 	)
+}
 
-	stateParam := req.Params.Query["state"]
-	code := req.Params.Query["code"]
+// oauthNonce derives the OpenID Connect nonce from the signed state. The state is
+// already bound to the browser by its cookie, so an ID token carrying this nonce
+// cannot be injected into a different flow. Non-OIDC providers ignore it.
+func oauthNonce(state string) string {
+	h := sha256.Sum256([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
 
-	if err := stateMgr.ValidateState(stateParam, req.Request); err != nil {
-		headers := http.Header{}
-		headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
-		return rocco.Redirect{URL: "/?error=invalid_state", Status: http.StatusFound, Headers: headers}, nil
-	}
+// confirmLinkCode is the /login error code for a provider login held until the
+// user signs in to the matching account and confirms the link.
+const confirmLinkCode = "confirm_link"
 
-	headers := http.Header{}
-	headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
-
-	client := intoauth.NewGitHubClient(githubCfg.ClientID, githubCfg.ClientSecret)
+// provisionProviderUser resolves an account for a provider identity with no
+// existing link. When auto-provisioning is enabled it creates a user from the
+// provider's verified email, or links an existing verified account with that
+// email if config allows. It returns the user ID and whether an existing
+// account was linked, or a /login error code. In confirm mode it returns the
+// matching account's ID with confirmLinkCode and links nothing.
+func provisionProviderUser(ctx context.Context, cfg config.OAuth, providerType models.ProviderType, identity *intoauth.Identity, token *intoauth.Token) (string, bool, string) {
+	users := sum.MustUse[contracts.Users](ctx)
+	providers := sum.MustUse[contracts.Providers](ctx)
 
-	token, err := client.Exchange(req.Context, code, githubCfg.CallbackURL)
-	if err != nil {
-		return rocco.Redirect{URL: "/?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
+	if !cfg.AutoProvision {
+		return "", false, "account_not_linked"
 	}
-
-	ghUser, err := client.GetUser(req.Context, token.AccessToken)
-	if err != nil {
-		return rocco.Redirect{URL: "/?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
+	if identity.Email == "" || !identity.EmailVerified {
+		return "", false, "email_not_verified"
 	}
 
-	providerUserID := fmt.Sprintf("%d", ghUser.ID)
-
-	// Check whether this GitHub account is already linked to a different user.
-	existing, err := providers.GetByProviderUser(req.Context, models.ProviderTypeGitHub, providerUserID)
-	if err == nil && existing != nil && existing.UserID != req.Identity.ID() {
-		return rocco.Redirect{URL: "/?error=provider_already_linked", Status: http.StatusFound, Headers: headers}, nil
+	now := time.Now()
+	user, err := users.GetByEmail(ctx, identity.Email)
+	linked := err == nil && user != nil
+	if linked {
+		// An unverified local account may have been registered by someone who
+		// does not own the address, so it is never linked automatically.
+		if !user.EmailVerified {
+			return "", false, "account_exists"
+		}
+		if cfg.EmailMatch != config.EmailMatchLink {
+			return user.ID, false, confirmLinkCode
+		}
+	} else {
+		userID, err := intsession.GenerateToken()
+		if err != nil {
+			return "", false, "login_failed"
+		}
+		user = &models.User{
+			ID:            userID,
+			Email:         identity.Email,
+			EmailVerified: true,
+			Name:          identity.Name,
+			AvatarURL:     identity.AvatarURL,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := users.Set(ctx, user.ID, user); err != nil {
+			return "", false, "login_failed"
+		}
 	}
 
-	// Create or update the provider record for the current user.
-	now := time.Now()
-	provider := &models.Provider{
-		UserID:         req.Identity.ID(),
-		Type:           models.ProviderTypeGitHub,
-		ProviderUserID: providerUserID,
+	link := &models.Provider{
+		UserID:         user.ID,
+		Type:           providerType,
+		ProviderUserID: identity.ID,
 		AccessToken:    token.AccessToken,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
-	if err := providers.Set(req.Context, "", provider); err != nil {
-		return rocco.Redirect{URL: "/?error=link_failed", Status: http.StatusFound, Headers: headers}, nil
+	if err := providers.Set(ctx, "", link); err != nil {
+		return "", false, "login_failed"
 	}
+	return user.ID, linked, ""
+}
 
-	return rocco.Redirect{
-		URL:     "/?linked=github",
-		Status:  http.StatusFound,
-		Headers: headers,
-	}, nil
-}).WithSummary("GitHub link callback").
-	WithDescription("Completes the GitHub OAuth linking flow. Links the GitHub account to the authenticated user.").
-	WithTags("Providers").
-	WithQueryParams("code", "state").
-	WithAuthentication()
-
-// UnlinkGitHub removes the GitHub provider link for the authenticated user.
-// The user must have at least one other authentication method (password or another provider).
-var UnlinkGitHub = rocco.DELETE("/providers/github", func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
-	users := sum.MustUse[contracts.Users](req.Context)
-	providers := sum.MustUse[contracts.Providers](req.Context)
-
-	// Ensure the provider link exists for this user.
-	_, err := providers.GetByUserAndType(req.Context, req.Identity.ID(), models.ProviderTypeGitHub)
+// holdProviderLink stores link until the account it matched confirms it and
+// returns a state referencing it. Only the link's random ID goes into the
+// signed state cookie; the provider access token stays server-side.
+func holdProviderLink(ctx context.Context, pendingLinks contracts.PendingLinks, stateMgr *intsession.StateManager, link *models.PendingLink) (string, *http.Cookie, error) {
+	id, err := intsession.GenerateToken()
 	if err != nil {
-		return rocco.NoBody{}, ErrProviderNotFound
+		return "", nil, err
 	}
+	now := time.Now()
+	link.ID = id
+	link.CreatedAt = now
+	link.ExpiresAt = now.Add(intsession.StateTTL)
+	if err := pendingLinks.Set(ctx, link, intsession.StateTTL); err != nil {
+		return "", nil, err
+	}
+	return stateMgr.GenerateState(intsession.StateClaims{
+		Purpose:  intsession.StatePurposeConfirmLink,
+		UserID:   link.UserID,
+		Provider: link.Provider,
+		LinkID:   id,
+	})
+}
+
+// confirmLinkURL sends the user to sign in to the account a provider login was
+// held for, passing the state of the pending link so the frontend can confirm
+// it afterwards.
+func confirmLinkURL(provider, state, returnTo string) string {
+	params := url.Values{}
+	params.Set("error", confirmLinkCode)
+	params.Set("provider", provider)
+	params.Set("link_state", state)
+	if returnTo != "" {
+		params.Set("return_to", returnTo)
+	}
+	return "/login?" + params.Encode()
+}
+
+// InitiateProviderLogin begins the OAuth flow for logging in via a linked provider account.
+// No authentication is required — this is a login entry point.
+var InitiateProviderLogin = rocco.GET("/login/{provider}", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
+	oauthCfg := sum.MustUse[config.OAuth](req.Context)
+	sessionCfg := sum.MustUse[config.Session](req.Context)
 
-	// Verify the user retains at least one other authentication method.
-	user, err := users.Get(req.Context, req.Identity.ID())
-	if err != nil || user == nil {
-		return rocco.NoBody{}, ErrUserNotFound
+	provider, ok := lookupProvider(req.Context, req.Params.Path["provider"])
+	if !ok {
+		return rocco.Redirect{}, ErrUnknownProvider
 	}
 
-	allProviders, err := providers.ListByUser(req.Context, req.Identity.ID())
+	verifier, err := intoauth.GenerateVerifier()
 	if err != nil {
-		return rocco.NoBody{}, ErrProviderLinkFailed
-	}
-
-	// Count remaining auth methods: password + every other linked provider.
-	remainingMethods := 0
-	if user.PasswordHash != nil {
-		remainingMethods++
-	}
-	for _, p := range allProviders {
-		if p.Type != models.ProviderTypeGitHub {
-			remainingMethods++
-		}
-	}
-	if remainingMethods == 0 {
-		return rocco.NoBody{}, ErrLastAuthMethod
-	}
-
-	if err := providers.DeleteByUserAndType(req.Context, req.Identity.ID(), models.ProviderTypeGitHub); err != nil {
-		return rocco.NoBody{}, ErrProviderLinkFailed
-	}
-
-	return rocco.NoBody{}, nil
-}).WithSummary("Unlink GitHub").
-	WithDescription("Removes the GitHub provider link for the authenticated user. Requires at least one other authentication method to remain.").
-	WithTags("Providers").
-	WithAuthentication().
-	WithSuccessStatus(204).
-	WithErrors(ErrProviderNotFound, ErrUserNotFound, ErrLastAuthMethod, ErrProviderLinkFailed)
-
-// ListProviders returns all linked OAuth providers for the authenticated user.
-var ListProviders = rocco.GET("/providers", func(req *rocco.Request[rocco.NoBody]) (wire.ProviderListResponse, error) {
-	providers := sum.MustUse[contracts.Providers](req.Context)
-
-	list, err := providers.ListByUser(req.Context, req.Identity.ID())
+		return rocco.Redirect{}, ErrOAuthFailed
+	}
+	remember, _ := strconv.ParseBool(req.Params.Query["remember"])
+	state, stateCookie, err := newStateManager(sessionCfg).GenerateState(intsession.StateClaims{
+		Purpose:      intsession.StatePurposeLogin,
+		CodeVerifier: verifier,
+		ReturnTo:     sanitizeReturnTo(req.Context, req.Params.Query["return_to"]),
+		Remember:     remember,
+	})
 	if err != nil {
-		return wire.ProviderListResponse{}, ErrProviderLinkFailed
+		return rocco.Redirect{}, ErrOAuthFailed
 	}
 
-	return transformers.ProvidersToList(list), nil
-}).WithSummary("List linked providers").
-	WithDescription("Returns all linked OAuth providers for the authenticated user.").
-	WithTags("Providers").
-	WithAuthentication().
-	WithErrors(ErrProviderLinkFailed)
-
-// InitiateGitHubLogin begins the GitHub OAuth flow for logging in via a linked GitHub account.
-// No authentication is required — this is a login entry point.
-var InitiateGitHubLogin = rocco.GET("/login/github", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
-	githubCfg := sum.MustUse[config.GitHub](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
-
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
-	)
-
-	state, stateCookie, err := stateMgr.GenerateState()
-	if err != nil {
-		return rocco.Redirect{}, ErrGitHubOAuthFailed
+	authURL := provider.AuthorizeURL(req.Context, oauthCfg.LoginCallbackURL(provider.Name()), state,
+		intoauth.WithPKCE(verifier), intoauth.WithNonce(oauthNonce(state)))
+	if authURL == "" {
+		return rocco.Redirect{}, ErrOAuthFailed
 	}
 
-	client := intoauth.NewGitHubClient(githubCfg.ClientID, githubCfg.ClientSecret)
-	authURL := client.AuthorizeURL(githubCfg.CallbackURL, state)
-
 	headers := http.Header{}
 	headers.Add("Set-Cookie", stateCookie.String())
 
@@ -214,108 +186,146 @@ var InitiateGitHubLogin = rocco.GET("/login/github", func(req *rocco.Request[roc
 		Status:  http.StatusFound,
 		Headers: headers,
 	}, nil
-}).WithSummary("Login via GitHub").
-	WithDescription("Initiates the GitHub OAuth flow for logging in via a linked GitHub account.").
+}).WithSummary("Login via provider").
+	WithDescription("Initiates the OAuth flow for logging in via a linked provider account. An allowlisted return_to is honoured once the flow completes. With remember=true the session is long-lived; otherwise its cookie ends with the browser session.").
 	WithTags("Auth").
-	WithErrors(ErrGitHubOAuthFailed)
-
-// GitHubLoginCallback completes the GitHub OAuth login flow.
-// Validates the state, exchanges the code, finds the linked Provider, and creates a session.
-var GitHubLoginCallback = rocco.GET("/login/github/callback", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
+	WithPathParams("provider").
+	WithQueryParams("return_to", "remember").
+	WithErrors(ErrUnknownProvider, ErrOAuthFailed)
+
+// ProviderLoginCallback completes the OAuth login flow.
+// Validates the state, exchanges the code, finds the linked Provider (or provisions
+// an account when enabled), and creates a session.
+// Success and error redirects honour the return_to bound into the state.
+var ProviderLoginCallback = rocco.GET("/login/{provider}/callback", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
 	providers := sum.MustUse[contracts.Providers](req.Context)
-	sessions := sum.MustUse[contracts.Sessions](req.Context)
-	githubCfg := sum.MustUse[config.GitHub](req.Context)
+	oauthCfg := sum.MustUse[config.OAuth](req.Context)
 	sessionCfg := sum.MustUse[config.Session](req.Context)
 
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
-	)
-
-	stateParam := req.Params.Query["state"]
-	code := req.Params.Query["code"]
-
-	if err := stateMgr.ValidateState(stateParam, req.Request); err != nil {
-		headers := http.Header{}
-		headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
-		return rocco.Redirect{URL: "/login?error=invalid_state", Status: http.StatusFound, Headers: headers}, nil
+	provider, ok := lookupProvider(req.Context, req.Params.Path["provider"])
+	if !ok {
+		return rocco.Redirect{}, ErrUnknownProvider
 	}
 
+	stateMgr := newStateManager(sessionCfg)
 	headers := http.Header{}
 	headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
 
-	client := intoauth.NewGitHubClient(githubCfg.ClientID, githubCfg.ClientSecret)
+	stateParam := req.Params.Query["state"]
+	claims, err := stateMgr.ValidateState(stateParam, req.Request)
+	if err != nil || claims.Purpose != intsession.StatePurposeLogin {
+		return rocco.Redirect{URL: loginErrorURL("invalid_state", ""), Status: http.StatusFound, Headers: headers}, nil
+	}
+	returnTo := sanitizeReturnTo(req.Context, claims.ReturnTo)
 
-	token, err := client.Exchange(req.Context, code, githubCfg.CallbackURL)
+	token, err := provider.Exchange(req.Context, req.Params.Query["code"], oauthCfg.LoginCallbackURL(provider.Name()),
+		intoauth.WithPKCE(claims.CodeVerifier))
 	if err != nil {
-		return rocco.Redirect{URL: "/login?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
+		return rocco.Redirect{URL: loginErrorURL("oauth_failed", returnTo), Status: http.StatusFound, Headers: headers}, nil
 	}
 
-	ghUser, err := client.GetUser(req.Context, token.AccessToken)
+	identity, err := provider.GetUser(req.Context, token, intoauth.WithNonce(oauthNonce(stateParam)))
 	if err != nil {
-		return rocco.Redirect{URL: "/login?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
+		return rocco.Redirect{URL: loginErrorURL("oauth_failed", returnTo), Status: http.StatusFound, Headers: headers}, nil
+	}
+
+	// Find the account linked to this provider identity, provisioning one if allowed.
+	var userID string
+	var linked bool
+	link, err := providers.GetByProviderUser(req.Context, models.ProviderType(provider.Name()), identity.ID)
+	if err == nil && link != nil {
+		userID = link.UserID
+		// Keep the stored token current; imported links start with a placeholder.
+		if link.AccessToken != token.AccessToken {
+			link.AccessToken = token.AccessToken
+			link.UpdatedAt = time.Now()
+			_ = providers.Set(req.Context, "", link)
+		}
+	} else {
+		var code string
+		userID, linked, code = provisionProviderUser(req.Context, oauthCfg, models.ProviderType(provider.Name()), identity, token)
+		if code == confirmLinkCode {
+			// Hold the link server-side behind a fresh signed state bound to the
+			// matching account; it replaces the cleared login state and is
+			// confirmed once the user has signed in to that account by another method.
+			pending, pendingCookie, err := holdProviderLink(req.Context, sum.MustUse[contracts.PendingLinks](req.Context), stateMgr, &models.PendingLink{
+				UserID:         userID,
+				Provider:       provider.Name(),
+				ProviderUserID: identity.ID,
+				AccessToken:    token.AccessToken,
+			})
+			if err != nil {
+				return rocco.Redirect{URL: loginErrorURL("login_failed", returnTo), Status: http.StatusFound, Headers: headers}, nil
+			}
+			headers.Set("Set-Cookie", pendingCookie.String())
+			return rocco.Redirect{URL: confirmLinkURL(provider.Name(), pending, returnTo), Status: http.StatusFound, Headers: headers}, nil
+		}
+		if code != "" {
+			return rocco.Redirect{URL: loginErrorURL(code, returnTo), Status: http.StatusFound, Headers: headers}, nil
+		}
 	}
 
-	providerUserID := fmt.Sprintf("%d", ghUser.ID)
-
-	// Find the account linked to this GitHub identity.
-	provider, err := providers.GetByProviderUser(req.Context, models.ProviderTypeGitHub, providerUserID)
-	if err != nil || provider == nil {
-		return rocco.Redirect{URL: "/login?error=account_not_linked", Status: http.StatusFound, Headers: headers}, nil
+	// Linking an existing account changes how it can be signed in to, so its
+	// sessions rotate as for an explicit link.
+	var cookie *http.Cookie
+	if linked {
+		cookie, err = rotateSessions(req.Context, req.Request, intsession.EventProviderLinked, userID)
+		if err != nil {
+			return rocco.Redirect{URL: loginErrorURL("login_failed", returnTo), Status: http.StatusFound, Headers: headers}, nil
+		}
 	}
 
 	// Create a session for the linked user.
-	sessionToken, err := intsession.GenerateToken()
-	if err != nil {
-		return rocco.Redirect{URL: "/login?error=login_failed", Status: http.StatusFound, Headers: headers}, nil
-	}
-	now := time.Now()
-	sess := &models.Session{
-		Token:     sessionToken,
-		UserID:    provider.UserID,
-		CreatedAt: now,
-		ExpiresAt: now.Add(sessionCfg.TTL),
-	}
-	if err := sessions.SetWithUserIndex(req.Context, sess, sessionCfg.TTL); err != nil {
-		return rocco.Redirect{URL: "/login?error=login_failed", Status: http.StatusFound, Headers: headers}, nil
+	if cookie == nil {
+		cookie, err = startSession(req.Context, req.Request, userID, models.LoginMethod(provider.Name()), claims.Remember)
+		if err != nil {
+			return rocco.Redirect{URL: loginErrorURL("login_failed", returnTo), Status: http.StatusFound, Headers: headers}, nil
+		}
 	}
 
-	headers.Add("Set-Cookie", buildSessionCookie(sessionCfg, sessionToken).String())
+	headers.Add("Set-Cookie", cookie.String())
 
 	return rocco.Redirect{
-		URL:     "/",
+		URL:     loginRedirectURL(req.Context, returnTo),
 		Status:  http.StatusFound,
 		Headers: headers,
 	}, nil
-}).WithSummary("GitHub login callback").
-	WithDescription("Completes the GitHub OAuth login flow. Finds the linked account and creates a session.").
+}).WithSummary("Provider login callback").
+	WithDescription("Completes the OAuth login flow. Finds the linked account, or provisions one from the provider's verified email when enabled, and creates a session. When the email belongs to an existing account and email matching is in confirm mode, redirects to /login with error=confirm_link, provider, and link_state; after signing in, confirm the link with POST /providers/{provider}/link/confirm.").
 	WithTags("Auth").
-	WithQueryParams("code", "state")
-
-// =============================================================================
-// Google OAuth Handlers
-// =============================================================================
+	WithPathParams("provider").
+	WithQueryParams("code", "state").
+	WithErrors(ErrUnknownProvider)
 
-// InitiateGoogleLink begins the Google OAuth flow for linking a provider to an existing account.
-// The user must be authenticated. Generates a state cookie and redirects to Google.
-var InitiateGoogleLink = rocco.GET("/providers/google/link", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
-	googleCfg := sum.MustUse[config.Google](req.Context)
+// InitiateProviderLink begins the OAuth flow for linking a provider to an existing account.
+// The user must be authenticated. Generates a state cookie and redirects to the provider.
+var InitiateProviderLink = rocco.GET("/providers/{provider}/link", requireSession(func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
+	oauthCfg := sum.MustUse[config.OAuth](req.Context)
 	sessionCfg := sum.MustUse[config.Session](req.Context)
 
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
-	)
+	provider, ok := lookupProvider(req.Context, req.Params.Path["provider"])
+	if !ok {
+		return rocco.Redirect{}, ErrUnknownProvider
+	}
 
-	state, stateCookie, err := stateMgr.GenerateState()
+	verifier, err := intoauth.GenerateVerifier()
 	if err != nil {
-		return rocco.Redirect{}, ErrGoogleOAuthFailed
+		return rocco.Redirect{}, ErrOAuthFailed
+	}
+	state, stateCookie, err := newStateManager(sessionCfg).GenerateState(intsession.StateClaims{
+		Purpose:      intsession.StatePurposeLink,
+		UserID:       req.Identity.ID(),
+		CodeVerifier: verifier,
+	})
+	if err != nil {
+		return rocco.Redirect{}, ErrOAuthFailed
 	}
 
-	client := intoauth.NewGoogleClient(googleCfg.ClientID, googleCfg.ClientSecret)
-	authURL := client.AuthorizeURL(googleCfg.CallbackURL, state)
+	authURL := provider.AuthorizeURL(req.Context, oauthCfg.LinkCallbackURL(provider.Name()), state,
+		intoauth.WithPKCE(verifier), intoauth.WithNonce(oauthNonce(state)))
+	if authURL == "" {
+		return rocco.Redirect{}, ErrOAuthFailed
+	}
 
 	headers := http.Header{}
 	headers.Add("Set-Cookie", stateCookie.String())
@@ -325,89 +335,170 @@ var InitiateGoogleLink = rocco.GET("/providers/google/link", func(req *rocco.Req
 		Status:  http.StatusFound,
 		Headers: headers,
 	}, nil
-}).WithSummary("Initiate Google link").
-	WithDescription("Begins the Google OAuth flow for linking a Google account to the authenticated user.").
+})).WithSummary("Initiate provider link").
+	WithDescription("Begins the OAuth flow for linking a provider account to the authenticated user. Requires a session; API tokens are refused.").
 	WithTags("Providers").
 	WithAuthentication().
-	WithErrors(ErrGoogleOAuthFailed)
+	WithPathParams("provider").
+	WithErrors(ErrSessionRequired, ErrUnknownProvider, ErrOAuthFailed)
 
-// GoogleLinkCallback completes the Google OAuth linking flow.
+// ProviderLinkCallback completes the OAuth linking flow.
 // Validates the state, exchanges the code, and creates a Provider record.
-// If the Google account is already linked to another user, returns an error.
-var GoogleLinkCallback = rocco.GET("/providers/google/callback", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
+// If the provider account is already linked to another user, returns an error.
+var ProviderLinkCallback = rocco.GET("/providers/{provider}/callback", requireSession(func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
 	providers := sum.MustUse[contracts.Providers](req.Context)
-	googleCfg := sum.MustUse[config.Google](req.Context)
+	oauthCfg := sum.MustUse[config.OAuth](req.Context)
 	sessionCfg := sum.MustUse[config.Session](req.Context)
 
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
-	)
-
-	stateParam := req.Params.Query["state"]
-	code := req.Params.Query["code"]
-
-	if err := stateMgr.ValidateState(stateParam, req.Request); err != nil {
-		headers := http.Header{}
-		headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
-		return rocco.Redirect{URL: "/?error=invalid_state", Status: http.StatusFound, Headers: headers}, nil
+	provider, ok := lookupProvider(req.Context, req.Params.Path["provider"])
+	if !ok {
+		return rocco.Redirect{}, ErrUnknownProvider
 	}
+	providerType := models.ProviderType(provider.Name())
 
+	stateMgr := newStateManager(sessionCfg)
 	headers := http.Header{}
 	headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
 
-	client := intoauth.NewGoogleClient(googleCfg.ClientID, googleCfg.ClientSecret)
+	stateParam := req.Params.Query["state"]
+	// The flow must have been started by the same user for linking; otherwise a
+	// victim could be tricked into completing an attacker's link.
+	claims, err := stateMgr.ValidateState(stateParam, req.Request)
+	if err != nil || claims.Purpose != intsession.StatePurposeLink || claims.UserID != req.Identity.ID() {
+		return rocco.Redirect{URL: "/?error=invalid_state", Status: http.StatusFound, Headers: headers}, nil
+	}
 
-	token, err := client.Exchange(req.Context, code, googleCfg.CallbackURL)
+	token, err := provider.Exchange(req.Context, req.Params.Query["code"], oauthCfg.LinkCallbackURL(provider.Name()),
+		intoauth.WithPKCE(claims.CodeVerifier))
 	if err != nil {
 		return rocco.Redirect{URL: "/?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
 	}
 
-	googleUser, err := client.GetUser(req.Context, token.AccessToken)
+	identity, err := provider.GetUser(req.Context, token, intoauth.WithNonce(oauthNonce(stateParam)))
 	if err != nil {
 		return rocco.Redirect{URL: "/?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
 	}
 
-	// Check whether this Google account is already linked to a different user.
-	existing, err := providers.GetByProviderUser(req.Context, models.ProviderTypeGoogle, googleUser.ID)
+	// Check whether this provider account is already linked to a different user.
+	existing, err := providers.GetByProviderUser(req.Context, providerType, identity.ID)
 	if err == nil && existing != nil && existing.UserID != req.Identity.ID() {
 		return rocco.Redirect{URL: "/?error=provider_already_linked", Status: http.StatusFound, Headers: headers}, nil
 	}
 
 	// Create or update the provider record for the current user.
 	now := time.Now()
-	provider := &models.Provider{
+	link := &models.Provider{
 		UserID:         req.Identity.ID(),
-		Type:           models.ProviderTypeGoogle,
-		ProviderUserID: googleUser.ID,
+		Type:           providerType,
+		ProviderUserID: identity.ID,
 		AccessToken:    token.AccessToken,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
-	if err := providers.Set(req.Context, "", provider); err != nil {
+	if err := providers.Set(req.Context, "", link); err != nil {
 		return rocco.Redirect{URL: "/?error=link_failed", Status: http.StatusFound, Headers: headers}, nil
 	}
 
+	cookie, err := rotateSessions(req.Context, req.Request, intsession.EventProviderLinked, req.Identity.ID())
+	if err != nil {
+		return rocco.Redirect{URL: "/?error=session_failed", Status: http.StatusFound, Headers: headers}, nil
+	}
+	if cookie != nil {
+		headers.Add("Set-Cookie", cookie.String())
+	}
+
 	return rocco.Redirect{
-		URL:     "/?linked=google",
+		URL:     "/?linked=" + provider.Name(),
 		Status:  http.StatusFound,
 		Headers: headers,
 	}, nil
-}).WithSummary("Google link callback").
-	WithDescription("Completes the Google OAuth linking flow. Links the Google account to the authenticated user.").
+})).WithSummary("Provider link callback").
+	WithDescription("Completes the OAuth linking flow. Links the provider account to the authenticated user, then rotates the current session to a new token and revokes the user's other sessions, as configured. Requires a session; API tokens are refused.").
 	WithTags("Providers").
+	WithPathParams("provider").
 	WithQueryParams("code", "state").
-	WithAuthentication()
+	WithAuthentication().
+	WithErrors(ErrSessionRequired, ErrUnknownProvider)
+
+// ConfirmProviderLink completes a provider login that was held because its
+// verified email belongs to an existing account. The pending link is held
+// server-side under the key in the signed state cookie set by the login
+// callback and is only accepted from the account it was issued for, so the
+// user proves ownership by signing in before the provider is linked.
+var ConfirmProviderLink = rocco.POST("/providers/{provider}/link/confirm", requireSession(func(req *rocco.Request[wire.ProviderLinkConfirmRequest]) (rocco.NoBody, error) {
+	providers := sum.MustUse[contracts.Providers](req.Context)
+	pendingLinks := sum.MustUse[contracts.PendingLinks](req.Context)
+	sessionCfg := sum.MustUse[config.Session](req.Context)
+
+	provider, ok := lookupProvider(req.Context, req.Params.Path["provider"])
+	if !ok {
+		return rocco.NoBody{}, ErrUnknownProvider
+	}
+	providerType := models.ProviderType(provider.Name())
+
+	stateMgr := newStateManager(sessionCfg)
+	intsession.SetResponseCookie(req.Context, stateMgr.ClearStateCookie())
+
+	claims, err := stateMgr.ValidateState(req.Body.State, req.Request)
+	if err != nil || claims.Purpose != intsession.StatePurposeConfirmLink || claims.LinkID == "" ||
+		claims.UserID != req.Identity.ID() || claims.Provider != provider.Name() {
+		return rocco.NoBody{}, ErrInvalidLinkState
+	}
+	pending, err := pendingLinks.Take(req.Context, claims.LinkID)
+	if err != nil || pending == nil || pending.IsExpired() ||
+		pending.UserID != req.Identity.ID() || pending.Provider != provider.Name() {
+		return rocco.NoBody{}, ErrInvalidLinkState
+	}
+
+	// The provider account may have been linked elsewhere since the login was held.
+	existing, err := providers.GetByProviderUser(req.Context, providerType, pending.ProviderUserID)
+	if err == nil && existing != nil && existing.UserID != req.Identity.ID() {
+		return rocco.NoBody{}, ErrProviderAlreadyLinked
+	}
+
+	now := time.Now()
+	link := &models.Provider{
+		UserID:         req.Identity.ID(),
+		Type:           providerType,
+		ProviderUserID: pending.ProviderUserID,
+		AccessToken:    pending.AccessToken,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := providers.Set(req.Context, "", link); err != nil {
+		return rocco.NoBody{}, ErrProviderLinkFailed
+	}
+
+	cookie, err := rotateSessions(req.Context, req.Request, intsession.EventProviderLinked, req.Identity.ID())
+	if err != nil {
+		return rocco.NoBody{}, ErrProviderLinkFailed
+	}
+	if cookie != nil {
+		intsession.SetResponseCookie(req.Context, cookie)
+	}
 
-// UnlinkGoogle removes the Google provider link for the authenticated user.
-// The user must have at least one other authentication method (password or another provider).
-var UnlinkGoogle = rocco.DELETE("/providers/google", func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	return rocco.NoBody{}, nil
+})).WithSummary("Confirm provider link").
+	WithDescription("Links the provider from a login that was held with error=confirm_link, using its link_state. Must be called by the account the login matched, after signing in to it by another method, and within the state's lifetime. Rotates the current session to a new token and revokes the user's other sessions, as configured. Requires a session; API tokens are refused.").
+	WithTags("Providers").
+	WithAuthentication().
+	WithPathParams("provider").
+	WithSuccessStatus(204).
+	WithErrors(ErrSessionRequired, ErrUnknownProvider, ErrInvalidLinkState, ErrProviderAlreadyLinked, ErrProviderLinkFailed)
+
+// UnlinkProvider removes a provider link for the authenticated user.
+// The user must have at least one other authentication method (password, passkey, or another provider).
+var UnlinkProvider = rocco.DELETE("/providers/{provider}", requireSession(func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
 	users := sum.MustUse[contracts.Users](req.Context)
 	providers := sum.MustUse[contracts.Providers](req.Context)
+	passkeys := sum.MustUse[contracts.Passkeys](req.Context)
+
+	// Links are keyed by type rather than the registry, so a link to a provider
+	// that has since been removed from config can still be cleaned up.
+	providerType := models.ProviderType(req.Params.Path["provider"])
 
 	// Ensure the provider link exists for this user.
-	_, err := providers.GetByUserAndType(req.Context, req.Identity.ID(), models.ProviderTypeGoogle)
+	_, err := providers.GetByUserAndType(req.Context, req.Identity.ID(), providerType)
 	if err != nil {
 		return rocco.NoBody{}, ErrProviderNotFound
 	}
@@ -423,13 +514,18 @@ var UnlinkGoogle = rocco.DELETE("/providers/google", func(req *rocco.Request[roc
 		return rocco.NoBody{}, ErrProviderLinkFailed
 	}
 
-	// Count remaining auth methods: password + every other linked provider.
-	remainingMethods := 0
+	passkeyCount, err := passkeys.CountByUser(req.Context, req.Identity.ID())
+	if err != nil {
+		return rocco.NoBody{}, ErrProviderLinkFailed
+	}
+
+	// Count remaining auth methods: password + passkeys + every other linked provider.
+	remainingMethods := int(passkeyCount)
 	if user.PasswordHash != nil {
 		remainingMethods++
 	}
 	for _, p := range allProviders {
-		if p.Type != models.ProviderTypeGoogle {
+		if p.Type != providerType {
 			remainingMethods++
 		}
 	}
@@ -437,119 +533,31 @@ var UnlinkGoogle = rocco.DELETE("/providers/google", func(req *rocco.Request[roc
 		return rocco.NoBody{}, ErrLastAuthMethod
 	}
 
-	if err := providers.DeleteByUserAndType(req.Context, req.Identity.ID(), models.ProviderTypeGoogle); err != nil {
+	if err := providers.DeleteByUserAndType(req.Context, req.Identity.ID(), providerType); err != nil {
 		return rocco.NoBody{}, ErrProviderLinkFailed
 	}
 
 	return rocco.NoBody{}, nil
-}).WithSummary("Unlink Google").
-	WithDescription("Removes the Google provider link for the authenticated user. Requires at least one other authentication method to remain.").
+})).WithSummary("Unlink provider").
+	WithDescription("Removes a provider link for the authenticated user. Requires at least one other authentication method to remain. Requires a session; API tokens are refused.").
 	WithTags("Providers").
 	WithAuthentication().
+	WithPathParams("provider").
 	WithSuccessStatus(204).
-	WithErrors(ErrProviderNotFound, ErrUserNotFound, ErrLastAuthMethod, ErrProviderLinkFailed)
-
-// InitiateGoogleLogin begins the Google OAuth flow for logging in via a linked Google account.
-// No authentication is required — this is a login entry point.
-var InitiateGoogleLogin = rocco.GET("/login/google", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
-	googleCfg := sum.MustUse[config.Google](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
-
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
-	)
-
-	state, stateCookie, err := stateMgr.GenerateState()
-	if err != nil {
-		return rocco.Redirect{}, ErrGoogleOAuthFailed
-	}
-
-	client := intoauth.NewGoogleClient(googleCfg.ClientID, googleCfg.ClientSecret)
-	authURL := client.AuthorizeURL(googleCfg.CallbackURL, state)
-
-	headers := http.Header{}
-	headers.Add("Set-Cookie", stateCookie.String())
-
-	return rocco.Redirect{
-		URL:     authURL,
-		Status:  http.StatusFound,
-		Headers: headers,
-	}, nil
-}).WithSummary("Login via Google").
-	WithDescription("Initiates the Google OAuth flow for logging in via a linked Google account.").
-	WithTags("Auth").
-	WithErrors(ErrGoogleOAuthFailed)
+	WithErrors(ErrSessionRequired, ErrProviderNotFound, ErrUserNotFound, ErrLastAuthMethod, ErrProviderLinkFailed)
 
-// GoogleLoginCallback completes the Google OAuth login flow.
-// Validates the state, exchanges the code, finds the linked Provider, and creates a session.
-var GoogleLoginCallback = rocco.GET("/login/google/callback", func(req *rocco.Request[rocco.NoBody]) (rocco.Redirect, error) {
+// ListProviders returns all linked OAuth providers for the authenticated user.
+var ListProviders = rocco.GET("/providers", func(req *rocco.Request[rocco.NoBody]) (wire.ProviderListResponse, error) {
 	providers := sum.MustUse[contracts.Providers](req.Context)
-	sessions := sum.MustUse[contracts.Sessions](req.Context)
-	googleCfg := sum.MustUse[config.Google](req.Context)
-	sessionCfg := sum.MustUse[config.Session](req.Context)
 
-	stateMgr := intsession.NewStateManager(
-		sessionCfg.StateSecret,
-		sessionCfg.CookieDomain,
-		sessionCfg.CookieSecure,
-	)
-
-	stateParam := req.Params.Query["state"]
-	code := req.Params.Query["code"]
-
-	if err := stateMgr.ValidateState(stateParam, req.Request); err != nil {
-		headers := http.Header{}
-		headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
-		return rocco.Redirect{URL: "/login?error=invalid_state", Status: http.StatusFound, Headers: headers}, nil
-	}
-
-	headers := http.Header{}
-	headers.Add("Set-Cookie", stateMgr.ClearStateCookie().String())
-
-	client := intoauth.NewGoogleClient(googleCfg.ClientID, googleCfg.ClientSecret)
-
-	token, err := client.Exchange(req.Context, code, googleCfg.CallbackURL)
-	if err != nil {
-		return rocco.Redirect{URL: "/login?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
-	}
-
-	googleUser, err := client.GetUser(req.Context, token.AccessToken)
-	if err != nil {
-		return rocco.Redirect{URL: "/login?error=oauth_failed", Status: http.StatusFound, Headers: headers}, nil
-	}
-
-	// Find the account linked to this Google identity.
-	provider, err := providers.GetByProviderUser(req.Context, models.ProviderTypeGoogle, googleUser.ID)
-	if err != nil || provider == nil {
-		return rocco.Redirect{URL: "/login?error=account_not_linked", Status: http.StatusFound, Headers: headers}, nil
-	}
-
-	// Create a session for the linked user.
-	sessionToken, err := intsession.GenerateToken()
+	list, err := providers.ListByUser(req.Context, req.Identity.ID())
 	if err != nil {
-		return rocco.Redirect{URL: "/login?error=login_failed", Status: http.StatusFound, Headers: headers}, nil
-	}
-	now := time.Now()
-	sess := &models.Session{
-		Token:     sessionToken,
-		UserID:    provider.UserID,
-		CreatedAt: now,
-		ExpiresAt: now.Add(sessionCfg.TTL),
-	}
-	if err := sessions.SetWithUserIndex(req.Context, sess, sessionCfg.TTL); err != nil {
-		return rocco.Redirect{URL: "/login?error=login_failed", Status: http.StatusFound, Headers: headers}, nil
+		return wire.ProviderListResponse{}, ErrProviderLinkFailed
 	}
 
-	headers.Add("Set-Cookie", buildSessionCookie(sessionCfg, sessionToken).String())
-
-	return rocco.Redirect{
-		URL:     "/",
-		Status:  http.StatusFound,
-		Headers: headers,
-	}, nil
-}).WithSummary("Google login callback").
-	WithDescription("Completes the Google OAuth login flow. Finds the linked account and creates a session.").
-	WithTags("Auth").
-	WithQueryParams("code", "state")
+	return transformers.ProvidersToList(list), nil
+}).WithSummary("List linked providers").
+	WithDescription("Returns all linked OAuth providers for the authenticated user.").
+	WithTags("Providers").
+	WithAuthentication().
+	WithErrors(ErrProviderLinkFailed)
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// memoryPendingLinks keeps pending links in a map keyed by ID.
+type memoryPendingLinks map[string]models.PendingLink
+
+func (m memoryPendingLinks) Set(_ context.Context, link *models.PendingLink, _ time.Duration) error {
+	m[link.ID] = *link
+	return nil
+}
+
+func (m memoryPendingLinks) Take(_ context.Context, id string) (*models.PendingLink, error) {
+	link, ok := m[id]
+	if !ok {
+		return nil, ErrInvalidLinkState
+	}
+	delete(m, id)
+	return &link, nil
+}
+
+func TestHoldProviderLink_KeepsAccessTokenOutOfCookie(t *testing.T) {
+	const accessToken = "gho_provider_access_token"
+	links := memoryPendingLinks{}
+	stateMgr := intsession.NewStateManager("a-test-secret-that-is-long-enough!!", "localhost", false)
+
+	state, cookie, err := holdProviderLink(context.Background(), links, stateMgr, &models.PendingLink{
+		UserID:         "user-1",
+		Provider:       "github",
+		ProviderUserID: "12345678",
+		AccessToken:    accessToken,
+	})
+	if err != nil {
+		t.Fatalf("holdProviderLink: %v", err)
+	}
+
+	payload, _, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		t.Fatalf("cookie %q is not payload.signature", cookie.Value)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatalf("decoding cookie payload: %v", err)
+	}
+	if strings.Contains(string(raw), accessToken) || strings.Contains(cookie.Value, accessToken) || strings.Contains(state, accessToken) {
+		t.Fatalf("access token leaked into the state cookie: %s", raw)
+	}
+
+	var claims intsession.StateClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		t.Fatalf("decoding claims: %v", err)
+	}
+	if claims.Purpose != intsession.StatePurposeConfirmLink || claims.UserID != "user-1" || claims.Provider != "github" {
+		t.Errorf("claims = %+v, want a confirm_link state for user-1 and github", claims)
+	}
+	link, err := links.Take(context.Background(), claims.LinkID)
+	if err != nil {
+		t.Fatalf("no pending link stored under the cookie's link ID: %v", err)
+	}
+	if link.AccessToken != accessToken || link.ProviderUserID != "12345678" {
+		t.Errorf("stored link = %+v, want the provider token and user ID", link)
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/api/contracts"
+	"github.com/zoobzio/sumatra/api/transformers"
+	"github.com/zoobzio/sumatra/api/wire"
+	"github.com/zoobzio/sumatra/config"
+	"github.com/zoobzio/sumatra/events"
+	intauthn "github.com/zoobzio/sumatra/internal/authn"
+	intratelimit "github.com/zoobzio/sumatra/internal/ratelimit"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// maxListedSessions bounds the sessions returned by ListSessions.
+const maxListedSessions = 100
+
+// Reasons recorded on events.Session.Revoked.
+const (
+	revokeReasonUser       = "user_revoked"
+	revokeReasonUserOthers = "user_revoked_others"
+)
+
+// startSession creates a session for userID, recording how the user signed in
+// and the client they used, and returns the session cookie to set. Unless the
+// user asked to be remembered, the cookie ends with the browser session.
+func startSession(ctx context.Context, r *http.Request, userID string, method models.LoginMethod, remember bool) (*http.Cookie, error) {
+	sessions := sum.MustUse[contracts.Sessions](ctx)
+	sessionCfg := sum.MustUse[config.Session](ctx)
+
+	token, err := intsession.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := r.UserAgent()
+	if len(userAgent) > intsession.MaxUserAgentLen {
+		userAgent = userAgent[:intsession.MaxUserAgentLen]
+	}
+	client := intsession.ParseUserAgent(userAgent)
+
+	now := time.Now()
+	sess := &models.Session{
+		Token:       token,
+		UserID:      userID,
+		CreatedAt:   now,
+		LastSeenAt:  now,
+		Method:      method,
+		IP:          sum.MustUse[*intratelimit.IPResolver](ctx).Addr(r),
+		UserAgent:   userAgent,
+		Browser:     client.Browser,
+		OS:          client.OS,
+		DeviceClass: client.DeviceClass,
+		Remember:    remember,
+	}
+	sess.ExpiresAt = sum.MustUse[intsession.Lifetime](ctx).Expiry(sess, now)
+	ttl := sess.ExpiresAt.Sub(now)
+	if err := sessions.SetWithUserIndex(ctx, sess, ttl); err != nil {
+		return nil, err
+	}
+	return SessionCookie(sessionCfg, token, intsession.CookieMaxAge(sess, ttl)), nil
+}
+
+// currentSessionID returns the public ID of the session cookie on r, if any.
+func currentSessionID(ctx context.Context, r *http.Request) string {
+	cookie, err := r.Cookie(sum.MustUse[config.Session](ctx).CookieName)
+	if err != nil || cookie.Value == "" {
+		return ""
+	}
+	return sum.MustUse[*intsession.TokenHasher](ctx).Hash(cookie.Value)
+}
+
+// requireSession wraps the handler of an endpoint that changes how the account
+// is secured so that it refuses API tokens. A token, even one with write scope,
+// must not be able to add sign-in methods, weaken MFA, or sign out the owner.
+func requireSession[In, Out any](fn func(*rocco.Request[In]) (Out, error)) func(*rocco.Request[In]) (Out, error) {
+	return func(req *rocco.Request[In]) (Out, error) {
+		if intauthn.ViaAPIToken(req.Identity) {
+			var zero Out
+			return zero, ErrSessionRequired
+		}
+		return fn(req)
+	}
+}
+
+// userSessions returns the active sessions belonging to userID.
+func userSessions(ctx context.Context, userID string) ([]*models.Session, error) {
+	sessions := sum.MustUse[contracts.Sessions](ctx)
+	lifetime := sum.MustUse[intsession.Lifetime](ctx)
+
+	ids, err := sessions.ListByUser(ctx, userID, maxListedSessions)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	records := make([]*models.Session, 0, len(ids))
+	for _, id := range ids {
+		s, err := sessions.GetByID(ctx, id)
+		if err != nil || s == nil || s.UserID != userID || !lifetime.Active(s, now) {
+			continue
+		}
+		records = append(records, s)
+	}
+	return records, nil
+}
+
+// revokeSession deletes s and emits events.Session.Revoked.
+func revokeSession(ctx context.Context, s *models.Session, reason string) error {
+	if err := sum.MustUse[contracts.Sessions](ctx).DeleteWithUserIndex(ctx, s); err != nil {
+		return err
+	}
+	events.Session.Revoked.Emit(ctx, events.SessionEvent{
+		UserID:    s.UserID,
+		SessionID: s.ID(),
+		Reason:    reason,
+	})
+	return nil
+}
+
+// rotateSessions applies the configured rotation policy for event to userID's
+// sessions. It returns the cookie carrying the request's session under its new
+// token, or nil when that session was not rotated.
+func rotateSessions(ctx context.Context, r *http.Request, event intsession.Event, userID string) (*http.Cookie, error) {
+	rotator := sum.MustUse[*intsession.Rotator](ctx)
+	sessionCfg := sum.MustUse[config.Session](ctx)
+
+	current := ""
+	if cookie, err := r.Cookie(sessionCfg.CookieName); err == nil {
+		current = cookie.Value
+	}
+	rotation, err := rotator.Rotate(ctx, event, userID, current)
+	if rotation != nil {
+		for _, id := range rotation.Revoked {
+			events.Session.Revoked.Emit(ctx, events.SessionEvent{
+				UserID:    userID,
+				SessionID: id,
+				Reason:    string(event),
+			})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rotation.Session == nil {
+		return nil, nil
+	}
+	events.Session.Rotated.Emit(ctx, events.SessionEvent{
+		UserID:            userID,
+		SessionID:         rotation.Session.ID(),
+		Reason:            string(event),
+		PreviousSessionID: rotation.Replaced.ID(),
+	})
+	maxAge := intsession.CookieMaxAge(rotation.Session, rotation.TTL(time.Now()))
+	return SessionCookie(sessionCfg, rotation.Session.Token, maxAge), nil
+}
+
+// ListSessions returns the authenticated user's active sessions.
+var ListSessions = rocco.GET("/me/sessions", func(req *rocco.Request[rocco.NoBody]) (wire.SessionListResponse, error) {
+	records, err := userSessions(req.Context, req.Identity.ID())
+	if err != nil {
+		return wire.SessionListResponse{}, ErrSessionsFailed
+	}
+
+	return transformers.SessionsToList(records, currentSessionID(req.Context, req.Request)), nil
+}).WithSummary("List sessions").
+	WithDescription("Returns the authenticated user's active sessions with the client, IP address and sign-in method of each, most recently used first. The session making the request is flagged as current.").
+	WithTags("Sessions").
+	WithAuthentication().
+	WithErrors(ErrSessionsFailed)
+
+// RevokeSession signs out one of the authenticated user's sessions by its ID.
+var RevokeSession = rocco.DELETE("/me/sessions/{id}", requireSession(func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	sessions := sum.MustUse[contracts.Sessions](req.Context)
+
+	// Look the session up directly rather than through the listing, which is
+	// capped, so every session of the user can be revoked.
+	s, err := sessions.GetByID(req.Context, req.Params.Path["id"])
+	if err != nil || s == nil || s.UserID != req.Identity.ID() {
+		return rocco.NoBody{}, ErrSessionNotFound
+	}
+	if err := revokeSession(req.Context, s, revokeReasonUser); err != nil {
+		return rocco.NoBody{}, ErrSessionsFailed
+	}
+	return rocco.NoBody{}, nil
+})).WithSummary("Revoke session").
+	WithDescription("Signs out one of the authenticated user's sessions. Revoking the current session is equivalent to logging out. Requires a session; API tokens are refused.").
+	WithTags("Sessions").
+	WithPathParams("id").
+	WithAuthentication().
+	WithErrors(ErrSessionRequired, ErrSessionNotFound, ErrSessionsFailed).
+	WithSuccessStatus(204)
+
+// RevokeOtherSessions signs out every session of the authenticated user except
+// the one making the request.
+var RevokeOtherSessions = rocco.POST("/me/sessions/revoke-others", requireSession(func(req *rocco.Request[rocco.NoBody]) (rocco.NoBody, error) {
+	sessions := sum.MustUse[contracts.Sessions](req.Context)
+
+	// Without the request's own session every session would be revoked.
+	current := currentSessionID(req.Context, req.Request)
+	if current == "" {
+		return rocco.NoBody{}, ErrSessionRequired
+	}
+	userID := req.Identity.ID()
+	revoked, err := sessions.DeleteByUserExcept(req.Context, userID, current)
+	if err != nil {
+		return rocco.NoBody{}, ErrSessionsFailed
+	}
+	for _, id := range revoked {
+		events.Session.Revoked.Emit(req.Context, events.SessionEvent{
+			UserID:    userID,
+			SessionID: id,
+			Reason:    revokeReasonUserOthers,
+		})
+	}
+	return rocco.NoBody{}, nil
+})).WithSummary("Revoke other sessions").
+	WithDescription("Signs out all of the authenticated user's sessions except the current one, however many there are. Requires a session; API tokens are refused.").
+	WithTags("Sessions").
+	WithAuthentication().
+	WithErrors(ErrSessionRequired, ErrSessionsFailed).
+	WithSuccessStatus(204)
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/api/contracts"
+	"github.com/zoobzio/sumatra/api/transformers"
+	"github.com/zoobzio/sumatra/api/wire"
+	"github.com/zoobzio/sumatra/config"
+	intaccesstoken "github.com/zoobzio/sumatra/internal/accesstoken"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+)
+
+// ExchangeToken mints a short-lived access token for the session cookie.
+var ExchangeToken = rocco.POST("/token", func(req *rocco.Request[rocco.NoBody]) (wire.AccessTokenResponse, error) {
+	sessions := sum.MustUse[contracts.Sessions](req.Context)
+	users := sum.MustUse[contracts.Users](req.Context)
+	issuer := sum.MustUse[*intaccesstoken.Issuer](req.Context)
+
+	cookie, err := req.Cookie(sum.MustUse[config.Session](req.Context).CookieName)
+	if err != nil || cookie.Value == "" {
+		return wire.AccessTokenResponse{}, ErrSessionRequired
+	}
+	session, err := sessions.Get(req.Context, cookie.Value)
+	if err != nil || session == nil {
+		return wire.AccessTokenResponse{}, ErrSessionRequired
+	}
+	now := time.Now()
+	if !sum.MustUse[intsession.Lifetime](req.Context).Active(session, now) {
+		return wire.AccessTokenResponse{}, ErrSessionExpired
+	}
+
+	user, err := users.Get(req.Context, session.UserID)
+	if err != nil {
+		return wire.AccessTokenResponse{}, ErrUserNotFound
+	}
+
+	token, expiresAt, err := issuer.Mint(user, session.ID())
+	if err != nil {
+		return wire.AccessTokenResponse{}, ErrAccessTokenFailed
+	}
+	return transformers.AccessTokenToResponse(token, expiresAt, now), nil
+}).WithSummary("Exchange session for access token").
+	WithDescription("Mints a short-lived JWT for the session cookie, for services that verify requests against the JWKS instead of calling back. The token carries the user ID (sub), email_verified and the session ID (sid), has no audience, and its typ header is session+jwt; reject tokens without it. Exchange again before it expires; signing out does not revoke tokens already issued.").
+	WithTags("Tokens").
+	WithErrors(ErrSessionRequired, ErrSessionExpired, ErrUserNotFound, ErrAccessTokenFailed)
+
+// GetJWKS publishes the keys that verify access tokens.
+var GetJWKS = rocco.GET("/.well-known/jwks.json", func(req *rocco.Request[rocco.NoBody]) (wire.JWKSResponse, error) {
+	keys, err := sum.MustUse[*intaccesstoken.Keyring](req.Context).JWKS()
+	if err != nil {
+		return wire.JWKSResponse{}, ErrAccessTokenFailed
+	}
+	return transformers.JWKSToResponse(keys), nil
+}).WithSummary("Access token signing keys").
+	WithDescription("Returns the JSON Web Key Set that verifies access tokens: the signing key, the next key ahead of rotation, and retired keys whose tokens may still be live. Select the key by the token's kid header and refetch on an unknown kid.").
+	WithTags("Tokens").
+	WithErrors(ErrAccessTokenFailed)
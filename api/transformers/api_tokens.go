@@ -0,0 +1,39 @@
+package transformers
+
+import (
+	"github.com/zoobzio/sumatra/api/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// APITokenToResponse transforms an APIToken model to a public API APITokenResponse.
+func APITokenToResponse(t *models.APIToken) wire.APITokenResponse {
+	scopes := t.ScopeList()
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return wire.APITokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// APITokenToCreatedResponse pairs a newly created token with its raw value.
+func APITokenToCreatedResponse(t *models.APIToken, raw string) wire.APITokenCreatedResponse {
+	return wire.APITokenCreatedResponse{Token: raw, APIToken: APITokenToResponse(t)}
+}
+
+// APITokensToList transforms a slice of APIToken models to a public API APITokenListResponse.
+func APITokensToList(tokens []*models.APIToken) wire.APITokenListResponse {
+	resp := wire.APITokenListResponse{
+		Tokens: make([]wire.APITokenResponse, len(tokens)),
+	}
+	for i, t := range tokens {
+		resp.Tokens[i] = APITokenToResponse(t)
+	}
+	return resp
+}
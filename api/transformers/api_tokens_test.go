@@ -0,0 +1,79 @@
+package transformers
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func newTestAPIToken() *models.APIToken {
+	now := time.Now().UTC().Truncate(time.Second)
+	expires := now.Add(30 * 24 * time.Hour)
+	return &models.APIToken{
+		ID:         3,
+		UserID:     "01942d3a-1234-7abc-8def-0123456789ab",
+		Name:       "CI deploys",
+		Prefix:     "mpat_Yp3bHk2",
+		TokenHash:  "9c56cc51b374c3ba189210d5b6d4bf57790d351c96c47c02190ecf1e430635ab",
+		Scopes:     "read write",
+		ExpiresAt:  &expires,
+		LastUsedAt: &now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// APITokenToResponse
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestAPITokenToResponse_MapsFields(t *testing.T) {
+	tok := newTestAPIToken()
+	resp := APITokenToResponse(tok)
+
+	if resp.ID != tok.ID || resp.Name != tok.Name || resp.Prefix != tok.Prefix {
+		t.Errorf("got %+v", resp)
+	}
+	if !slices.Equal(resp.Scopes, []string{"read", "write"}) {
+		t.Errorf("Scopes: got %v", resp.Scopes)
+	}
+	if resp.ExpiresAt == nil || !resp.ExpiresAt.Equal(*tok.ExpiresAt) {
+		t.Errorf("ExpiresAt: got %v", resp.ExpiresAt)
+	}
+	if resp.LastUsedAt == nil || !resp.LastUsedAt.Equal(*tok.LastUsedAt) {
+		t.Errorf("LastUsedAt: got %v", resp.LastUsedAt)
+	}
+}
+
+func TestAPITokenToResponse_NeverExposesHash(t *testing.T) {
+	tok := newTestAPIToken()
+	b, err := json.Marshal(APITokenToResponse(tok))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), tok.TokenHash) {
+		t.Error("response contains the token hash")
+	}
+}
+
+func TestAPITokenToCreatedResponse_IncludesToken(t *testing.T) {
+	resp := APITokenToCreatedResponse(newTestAPIToken(), "mpat_secret")
+	if resp.Token != "mpat_secret" || resp.APIToken.ID != 3 {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// APITokensToList
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestAPITokensToList_Empty(t *testing.T) {
+	resp := APITokensToList(nil)
+	if resp.Tokens == nil || len(resp.Tokens) != 0 {
+		t.Errorf("expected an empty, non-nil list, got %v", resp.Tokens)
+	}
+}
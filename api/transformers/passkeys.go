@@ -0,0 +1,28 @@
+package transformers
+
+import (
+	"github.com/zoobzio/sumatra/api/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// PasskeyToResponse transforms a Passkey model to a public API PasskeyResponse.
+func PasskeyToResponse(p *models.Passkey) wire.PasskeyResponse {
+	return wire.PasskeyResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		Synced:     p.BackupState,
+		LastUsedAt: p.LastUsedAt,
+		CreatedAt:  p.CreatedAt,
+	}
+}
+
+// PasskeysToList transforms a slice of Passkey models to a public API PasskeyListResponse.
+func PasskeysToList(passkeys []*models.Passkey) wire.PasskeyListResponse {
+	resp := wire.PasskeyListResponse{
+		Passkeys: make([]wire.PasskeyResponse, len(passkeys)),
+	}
+	for i, p := range passkeys {
+		resp.Passkeys[i] = PasskeyToResponse(p)
+	}
+	return resp
+}
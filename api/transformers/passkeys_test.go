@@ -0,0 +1,77 @@
+package transformers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func newTestPasskey() *models.Passkey {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &models.Passkey{
+		ID:           7,
+		UserID:       "01942d3a-1234-7abc-8def-0123456789ab",
+		Name:         "MacBook Touch ID",
+		CredentialID: "AAECAwQFBgcICQ",
+		PublicKey:    "pQECAyYgASFYIA",
+		BackupState:  true,
+		LastUsedAt:   &now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// PasskeyToResponse
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestPasskeyToResponse_MapsFields(t *testing.T) {
+	p := newTestPasskey()
+	resp := PasskeyToResponse(p)
+
+	if resp.ID != p.ID {
+		t.Errorf("ID: got %d, want %d", resp.ID, p.ID)
+	}
+	if resp.Name != p.Name {
+		t.Errorf("Name: got %q, want %q", resp.Name, p.Name)
+	}
+	if !resp.Synced {
+		t.Error("Synced: expected true when backup state is set")
+	}
+	if resp.LastUsedAt == nil || !resp.LastUsedAt.Equal(*p.LastUsedAt) {
+		t.Errorf("LastUsedAt: got %v, want %v", resp.LastUsedAt, p.LastUsedAt)
+	}
+	if !resp.CreatedAt.Equal(p.CreatedAt) {
+		t.Errorf("CreatedAt: got %v, want %v", resp.CreatedAt, p.CreatedAt)
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// PasskeysToList
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestPasskeysToList_Empty(t *testing.T) {
+	resp := PasskeysToList(nil)
+	if resp.Passkeys == nil {
+		t.Fatal("expected non-nil slice so JSON encodes as []")
+	}
+	if len(resp.Passkeys) != 0 {
+		t.Errorf("expected 0 passkeys, got %d", len(resp.Passkeys))
+	}
+}
+
+func TestPasskeysToList_PreservesOrder(t *testing.T) {
+	a := newTestPasskey()
+	b := newTestPasskey()
+	b.ID = 8
+	b.Name = "YubiKey"
+
+	resp := PasskeysToList([]*models.Passkey{a, b})
+	if len(resp.Passkeys) != 2 {
+		t.Fatalf("expected 2 passkeys, got %d", len(resp.Passkeys))
+	}
+	if resp.Passkeys[0].ID != 7 || resp.Passkeys[1].ID != 8 {
+		t.Errorf("unexpected order: %+v", resp.Passkeys)
+	}
+}
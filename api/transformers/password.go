@@ -0,0 +1,17 @@
+package transformers
+
+import (
+	"github.com/zoobzio/sumatra/api/wire"
+	intpassword "github.com/zoobzio/sumatra/internal/password"
+)
+
+// PolicyErrorToDetails transforms a password policy rejection into public API error details.
+func PolicyErrorToDetails(err *intpassword.PolicyError) wire.PasswordPolicyDetails {
+	details := wire.PasswordPolicyDetails{
+		Violations: make([]wire.PasswordViolation, len(err.Violations)),
+	}
+	for i, v := range err.Violations {
+		details.Violations[i] = wire.PasswordViolation{Rule: v.Rule, Message: v.Message}
+	}
+	return details
+}
@@ -0,0 +1,26 @@
+package transformers
+
+import (
+	"testing"
+
+	intpassword "github.com/zoobzio/sumatra/internal/password"
+)
+
+func TestPolicyErrorToDetails_MapsViolations(t *testing.T) {
+	err := &intpassword.PolicyError{Violations: []intpassword.Violation{
+		{Rule: intpassword.RuleMinLength, Message: "must be at least 8 characters"},
+		{Rule: intpassword.RuleBreached, Message: "has appeared in a data breach"},
+	}}
+
+	details := PolicyErrorToDetails(err)
+
+	if len(details.Violations) != 2 {
+		t.Fatalf("Violations: got %d want 2", len(details.Violations))
+	}
+	for i, v := range err.Violations {
+		got := details.Violations[i]
+		if got.Rule != v.Rule || got.Message != v.Message {
+			t.Errorf("Violations[%d]: got %+v want %+v", i, got, v)
+		}
+	}
+}
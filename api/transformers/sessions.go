@@ -0,0 +1,47 @@
+package transformers
+
+import (
+	"sort"
+
+	"github.com/zoobzio/sumatra/api/wire"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// SessionToResponse transforms a Session model to a public API SessionResponse.
+// Sessions created before last-seen tracking report their creation time.
+// current is the ID of the session making the request.
+func SessionToResponse(s *models.Session, current string) wire.SessionResponse {
+	lastSeen := s.LastSeenAt
+	if lastSeen.IsZero() {
+		lastSeen = s.CreatedAt
+	}
+	id := s.ID()
+	return wire.SessionResponse{
+		ID:          id,
+		Current:     current != "" && id == current,
+		Method:      string(s.Method),
+		IP:          s.IP,
+		Browser:     s.Browser,
+		OS:          s.OS,
+		DeviceClass: s.DeviceClass,
+		CreatedAt:   s.CreatedAt,
+		LastSeenAt:  lastSeen,
+		ExpiresAt:   s.ExpiresAt,
+	}
+}
+
+// SessionsToList transforms a slice of Session models to a public API
+// SessionListResponse, ordered by most recent use. The session whose ID is
+// current is flagged.
+func SessionsToList(sessions []*models.Session, current string) wire.SessionListResponse {
+	resp := wire.SessionListResponse{
+		Sessions: make([]wire.SessionResponse, len(sessions)),
+	}
+	for i, s := range sessions {
+		resp.Sessions[i] = SessionToResponse(s, current)
+	}
+	sort.SliceStable(resp.Sessions, func(i, j int) bool {
+		return resp.Sessions[i].LastSeenAt.After(resp.Sessions[j].LastSeenAt)
+	})
+	return resp
+}
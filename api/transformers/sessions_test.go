@@ -0,0 +1,97 @@
+package transformers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func newTestSession() *models.Session {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &models.Session{
+		Token:       "abcdefghijklmnopqrstuvwxyz012345",
+		TokenHash:   "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		UserID:      "01942d3a-1234-7abc-8def-0123456789ab",
+		CreatedAt:   now.Add(-time.Hour),
+		ExpiresAt:   now.Add(167 * time.Hour),
+		LastSeenAt:  now,
+		Method:      models.LoginMethodPassword,
+		IP:          "203.0.113.7",
+		UserAgent:   "Mozilla/5.0 (X11; Linux x86_64; rv:126.0) Gecko/20100101 Firefox/126.0",
+		Browser:     "Firefox 126",
+		OS:          "Linux",
+		DeviceClass: models.DeviceClassDesktop,
+	}
+}
+
+func TestSessionToResponse_MapsFields(t *testing.T) {
+	s := newTestSession()
+	resp := SessionToResponse(s, "")
+
+	if resp.Method != "password" {
+		t.Errorf("Method: got %q want %q", resp.Method, "password")
+	}
+	if resp.IP != s.IP {
+		t.Errorf("IP: got %q want %q", resp.IP, s.IP)
+	}
+	if resp.Browser != s.Browser || resp.OS != s.OS || resp.DeviceClass != s.DeviceClass {
+		t.Errorf("client: got %q/%q/%q", resp.Browser, resp.OS, resp.DeviceClass)
+	}
+	if !resp.CreatedAt.Equal(s.CreatedAt) || !resp.ExpiresAt.Equal(s.ExpiresAt) || !resp.LastSeenAt.Equal(s.LastSeenAt) {
+		t.Error("timestamps not copied")
+	}
+}
+
+func TestSessionToResponse_IDNotToken(t *testing.T) {
+	s := newTestSession()
+	resp := SessionToResponse(s, "")
+
+	if resp.ID != s.ID() {
+		t.Errorf("ID: got %q want %q", resp.ID, s.ID())
+	}
+	if resp.ID == s.Token {
+		t.Error("ID must not be the session token")
+	}
+	if resp.Current {
+		t.Error("Current should be false when no current session is given")
+	}
+}
+
+func TestSessionToResponse_FlagsCurrent(t *testing.T) {
+	s := newTestSession()
+	if resp := SessionToResponse(s, s.ID()); !resp.Current {
+		t.Error("Current should be true for the requesting session")
+	}
+}
+
+func TestSessionToResponse_LastSeenDefaultsToCreatedAt(t *testing.T) {
+	s := newTestSession()
+	s.LastSeenAt = time.Time{}
+
+	if resp := SessionToResponse(s, ""); !resp.LastSeenAt.Equal(s.CreatedAt) {
+		t.Errorf("LastSeenAt: got %v want %v", resp.LastSeenAt, s.CreatedAt)
+	}
+}
+
+func TestSessionsToList_MostRecentFirst(t *testing.T) {
+	older := newTestSession()
+	older.LastSeenAt = older.LastSeenAt.Add(-time.Hour)
+	older.IP = "198.51.100.1"
+	newer := newTestSession()
+
+	resp := SessionsToList([]*models.Session{older, newer}, "")
+	if len(resp.Sessions) != 2 {
+		t.Fatalf("len: got %d want 2", len(resp.Sessions))
+	}
+	if resp.Sessions[0].IP != newer.IP || resp.Sessions[1].IP != older.IP {
+		t.Errorf("order: got %q, %q", resp.Sessions[0].IP, resp.Sessions[1].IP)
+	}
+}
+
+func TestSessionsToList_Empty(t *testing.T) {
+	resp := SessionsToList(nil, "")
+	if resp.Sessions == nil || len(resp.Sessions) != 0 {
+		t.Errorf("expected empty non-nil slice, got %v", resp.Sessions)
+	}
+}
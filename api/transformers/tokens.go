@@ -0,0 +1,36 @@
+package transformers
+
+import (
+	"time"
+
+	"github.com/zoobzio/sumatra/api/wire"
+	intaccesstoken "github.com/zoobzio/sumatra/internal/accesstoken"
+)
+
+// AccessTokenToResponse builds the response for a token minted at now that expires at expiresAt.
+func AccessTokenToResponse(token string, expiresAt, now time.Time) wire.AccessTokenResponse {
+	return wire.AccessTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(expiresAt.Sub(now).Seconds()),
+	}
+}
+
+// JWKSToResponse transforms published signing keys to a public API JWKSResponse.
+func JWKSToResponse(keys []intaccesstoken.JWK) wire.JWKSResponse {
+	resp := wire.JWKSResponse{
+		Keys: make([]wire.JWKResponse, len(keys)),
+	}
+	for i, k := range keys {
+		resp.Keys[i] = wire.JWKResponse{
+			Kty: k.Kty,
+			Crv: k.Crv,
+			X:   k.X,
+			Y:   k.Y,
+			Kid: k.Kid,
+			Use: k.Use,
+			Alg: k.Alg,
+		}
+	}
+	return resp
+}
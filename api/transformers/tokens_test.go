@@ -0,0 +1,41 @@
+package transformers
+
+import (
+	"testing"
+	"time"
+
+	intaccesstoken "github.com/zoobzio/sumatra/internal/accesstoken"
+)
+
+func TestAccessTokenToResponse(t *testing.T) {
+	now := time.Now()
+	resp := AccessTokenToResponse("jwt", now.Add(5*time.Minute), now)
+	if resp.AccessToken != "jwt" || resp.TokenType != "Bearer" || resp.ExpiresIn != 300 {
+		t.Errorf("got %+v", resp)
+	}
+}
+
+func TestJWKSToResponse_MapsKeys(t *testing.T) {
+	keys := []intaccesstoken.JWK{
+		{Kty: "OKP", Crv: "Ed25519", X: "x1", Kid: "k1", Use: "sig", Alg: "EdDSA"},
+		{Kty: "EC", Crv: "P-256", X: "x2", Y: "y2", Kid: "k2", Use: "sig", Alg: "ES256"},
+	}
+
+	resp := JWKSToResponse(keys)
+
+	if len(resp.Keys) != 2 {
+		t.Fatalf("Keys: got %d want 2", len(resp.Keys))
+	}
+	for i, k := range keys {
+		got := resp.Keys[i]
+		if got.Kty != k.Kty || got.Crv != k.Crv || got.X != k.X || got.Y != k.Y || got.Kid != k.Kid || got.Use != k.Use || got.Alg != k.Alg {
+			t.Errorf("Keys[%d]: got %+v want %+v", i, got, k)
+		}
+	}
+}
+
+func TestJWKSToResponse_EmptyIsNotNull(t *testing.T) {
+	if resp := JWKSToResponse(nil); resp.Keys == nil {
+		t.Error("Keys should be an empty slice so it marshals as []")
+	}
+}
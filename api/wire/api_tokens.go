@@ -0,0 +1,96 @@
+package wire
+
+import (
+	"slices"
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// apiTokenScopes are the scopes an API token may be granted.
+var apiTokenScopes = []string{"read", "write"}
+
+// APITokenCreateRequest is the request body for creating a personal access token.
+type APITokenCreateRequest struct {
+	Name      string     `json:"name" description:"Label for the token" example:"CI deploys"`
+	Scopes    []string   `json:"scopes" description:"Scopes: read allows GET requests, write allows every request" example:"[\"read\"]"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" description:"Expiry, omitted for a token that does not expire"`
+}
+
+// Validate validates the APITokenCreateRequest.
+func (r *APITokenCreateRequest) Validate() error {
+	return check.All(
+		check.Str(r.Name, "name").Required().MaxLen(64).V(),
+		check.NotEmpty(r.Scopes, "scopes"),
+		check.Subset(r.Scopes, apiTokenScopes, "scopes"),
+	).Err()
+}
+
+// Clone returns a deep copy of APITokenCreateRequest.
+func (r APITokenCreateRequest) Clone() APITokenCreateRequest {
+	c := r
+	c.Scopes = slices.Clone(r.Scopes)
+	if r.ExpiresAt != nil {
+		e := *r.ExpiresAt
+		c.ExpiresAt = &e
+	}
+	return c
+}
+
+// APITokenResponse is the public API response for a personal access token.
+// The token itself is only ever returned when it is created.
+type APITokenResponse struct {
+	ID         int64      `json:"id" description:"Token ID" example:"1"`
+	Name       string     `json:"name" description:"Label for the token" example:"CI deploys"`
+	Prefix     string     `json:"prefix" description:"First characters of the token" example:"mpat_Yp3bHk2"`
+	Scopes     []string   `json:"scopes" description:"Granted scopes" example:"[\"read\"]"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" description:"Expiry, absent for tokens that do not expire"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" description:"Time the token was last used, to the minute"`
+	CreatedAt  time.Time  `json:"created_at" description:"Time the token was created"`
+}
+
+// Clone returns a deep copy of APITokenResponse.
+func (r APITokenResponse) Clone() APITokenResponse {
+	c := r
+	c.Scopes = slices.Clone(r.Scopes)
+	if r.ExpiresAt != nil {
+		e := *r.ExpiresAt
+		c.ExpiresAt = &e
+	}
+	if r.LastUsedAt != nil {
+		l := *r.LastUsedAt
+		c.LastUsedAt = &l
+	}
+	return c
+}
+
+// APITokenCreatedResponse is the response body for a newly created token,
+// the only time the token is shown.
+type APITokenCreatedResponse struct {
+	Token    string           `json:"token" description:"The token, sent as Authorization: Bearer" example:"mpat_Yp3bHk2xQ0mC8vR1Yp3bHk2xQ0mC8vR1Yp3bHk2xQ0m"`
+	APIToken APITokenResponse `json:"api_token" description:"The stored token"`
+}
+
+// Clone returns a deep copy of APITokenCreatedResponse.
+func (r APITokenCreatedResponse) Clone() APITokenCreatedResponse {
+	c := r
+	c.APIToken = r.APIToken.Clone()
+	return c
+}
+
+// APITokenListResponse is the public API response for listing personal access tokens.
+type APITokenListResponse struct {
+	Tokens []APITokenResponse `json:"tokens" description:"Personal access tokens, newest first"`
+}
+
+// Clone returns a deep copy of APITokenListResponse.
+func (r APITokenListResponse) Clone() APITokenListResponse {
+	c := r
+	if r.Tokens != nil {
+		c.Tokens = make([]APITokenResponse, len(r.Tokens))
+		for i, t := range r.Tokens {
+			c.Tokens[i] = t.Clone()
+		}
+	}
+	return c
+}
@@ -5,14 +5,16 @@ import "github.com/zoobzio/check"
 // RegisterRequest is the request body for creating a new account.
 type RegisterRequest struct {
 	Email    string `json:"email" description:"Email address" example:"user@example.com"`
-	Password string `json:"password" description:"Password (min 8 characters)" example:"correct-horse-battery"`
+	Password string `json:"password" description:"Password; must satisfy the password policy" example:"correct-horse-battery"`
+	ReturnTo string `json:"return_to,omitempty" description:"Post-login redirect; ignored unless it matches the configured allowlist" example:"/app/settings"`
 }
 
 // Validate validates the RegisterRequest.
 func (r *RegisterRequest) Validate() error {
 	return check.All(
 		check.Str(r.Email, "email").Required().Email().V(),
-		check.Str(r.Password, "password").Required().MinLen(8).V(),
+		check.Str(r.Password, "password").Required().V(),
+		check.Str(r.ReturnTo, "return_to").MaxLen(2048).V(),
 	).Err()
 }
 
@@ -25,6 +27,8 @@ func (r RegisterRequest) Clone() RegisterRequest {
 type LoginRequest struct {
 	Email    string `json:"email" description:"Email address" example:"user@example.com"`
 	Password string `json:"password" description:"Password" example:"correct-horse-battery"`
+	ReturnTo string `json:"return_to,omitempty" description:"Post-login redirect; ignored unless it matches the configured allowlist" example:"/app/settings"`
+	Remember bool   `json:"remember,omitempty" description:"Keep the session across browser restarts; otherwise the cookie ends with the browser session" example:"true"`
 }
 
 // Validate validates the LoginRequest.
@@ -32,6 +36,7 @@ func (r *LoginRequest) Validate() error {
 	return check.All(
 		check.Str(r.Email, "email").Required().Email().V(),
 		check.Str(r.Password, "password").Required().V(),
+		check.Str(r.ReturnTo, "return_to").MaxLen(2048).V(),
 	).Err()
 }
 
@@ -42,13 +47,16 @@ func (r LoginRequest) Clone() LoginRequest {
 
 // MagicLinkRequest is the request body for requesting a magic link.
 type MagicLinkRequest struct {
-	Email string `json:"email" description:"Email address" example:"user@example.com"`
+	Email    string `json:"email" description:"Email address" example:"user@example.com"`
+	ReturnTo string `json:"return_to,omitempty" description:"Post-login redirect; ignored unless it matches the configured allowlist" example:"/app/settings"`
+	Remember bool   `json:"remember,omitempty" description:"Keep the session across browser restarts; otherwise the cookie ends with the browser session" example:"true"`
 }
 
 // Validate validates the MagicLinkRequest.
 func (r *MagicLinkRequest) Validate() error {
 	return check.All(
 		check.Str(r.Email, "email").Required().Email().V(),
+		check.Str(r.ReturnTo, "return_to").MaxLen(2048).V(),
 	).Err()
 }
 
@@ -94,14 +102,14 @@ func (r PasswordResetRequest) Clone() PasswordResetRequest {
 // PasswordResetConfirmRequest is the request body for completing a password reset.
 type PasswordResetConfirmRequest struct {
 	Token    string `json:"token" description:"Password reset token" example:"dGhpcyBpcyBhIHRva2Vu"`
-	Password string `json:"password" description:"New password (min 8 characters)" example:"correct-horse-battery"`
+	Password string `json:"password" description:"New password; must satisfy the password policy" example:"correct-horse-battery"`
 }
 
 // Validate validates the PasswordResetConfirmRequest.
 func (r *PasswordResetConfirmRequest) Validate() error {
 	return check.All(
 		check.Str(r.Token, "token").Required().V(),
-		check.Str(r.Password, "password").Required().MinLen(8).V(),
+		check.Str(r.Password, "password").Required().V(),
 	).Err()
 }
 
@@ -109,3 +117,24 @@ func (r *PasswordResetConfirmRequest) Validate() error {
 func (r PasswordResetConfirmRequest) Clone() PasswordResetConfirmRequest {
 	return r
 }
+
+// PasswordViolation is one password policy rule a submitted password failed.
+type PasswordViolation struct {
+	Rule    string `json:"rule" description:"Stable rule identifier" example:"min_length"`
+	Message string `json:"message" description:"Human-readable explanation" example:"must be at least 8 characters"`
+}
+
+// PasswordPolicyDetails are the error details returned when a password is rejected.
+type PasswordPolicyDetails struct {
+	Violations []PasswordViolation `json:"violations" description:"Every rule the password failed"`
+}
+
+// Clone returns a deep copy of PasswordPolicyDetails.
+func (d PasswordPolicyDetails) Clone() PasswordPolicyDetails {
+	c := d
+	if d.Violations != nil {
+		c.Violations = make([]PasswordViolation, len(d.Violations))
+		copy(c.Violations, d.Violations)
+	}
+	return c
+}
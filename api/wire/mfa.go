@@ -0,0 +1,66 @@
+package wire
+
+import "github.com/zoobzio/check"
+
+// TOTPEnrollResponse is the response body for starting TOTP enrollment.
+// The secret is only ever returned here; it is not retrievable once confirmed.
+type TOTPEnrollResponse struct {
+	Secret string `json:"secret" description:"Base32-encoded TOTP secret for manual entry" example:"JBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" description:"otpauth:// provisioning URI for QR code rendering" example:"otpauth://totp/Morpheus:user@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Morpheus"`
+}
+
+// Clone returns a deep copy of TOTPEnrollResponse.
+func (r TOTPEnrollResponse) Clone() TOTPEnrollResponse {
+	return r
+}
+
+// TOTPCodeRequest is the request body for confirming or disabling TOTP.
+type TOTPCodeRequest struct {
+	Code string `json:"code" description:"Current 6-digit code from the authenticator app" example:"123456"`
+}
+
+// Validate validates the TOTPCodeRequest.
+func (r *TOTPCodeRequest) Validate() error {
+	return check.All(
+		check.Str(r.Code, "code").Required().Len(6).Numeric().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of TOTPCodeRequest.
+func (r TOTPCodeRequest) Clone() TOTPCodeRequest {
+	return r
+}
+
+// MFALoginRequest is the request body for completing a login that requires a second factor.
+// The challenge issued by password login travels in a cookie, not the body.
+type MFALoginRequest struct {
+	Code string `json:"code" description:"Current 6-digit code from the authenticator app, or an unused recovery code" example:"123456"`
+}
+
+// Validate validates the MFALoginRequest.
+func (r *MFALoginRequest) Validate() error {
+	return check.All(
+		check.Str(r.Code, "code").Required().MaxLen(32).V(),
+	).Err()
+}
+
+// Clone returns a deep copy of MFALoginRequest.
+func (r MFALoginRequest) Clone() MFALoginRequest {
+	return r
+}
+
+// RecoveryCodesResponse is the response body carrying freshly issued recovery codes.
+// Codes are only returned at issue time; the server keeps hashes only.
+type RecoveryCodesResponse struct {
+	Codes []string `json:"codes" description:"Single-use recovery codes, shown once" example:"abcde-fghjk"`
+}
+
+// Clone returns a deep copy of RecoveryCodesResponse.
+func (r RecoveryCodesResponse) Clone() RecoveryCodesResponse {
+	c := r
+	if r.Codes != nil {
+		c.Codes = make([]string, len(r.Codes))
+		copy(c.Codes, r.Codes)
+	}
+	return c
+}
@@ -0,0 +1,114 @@
+package wire
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// PasskeyOptionsResponse is the response body for starting a WebAuthn ceremony.
+// Options is passed unchanged to navigator.credentials.create() or .get().
+type PasskeyOptionsResponse struct {
+	ChallengeID string          `json:"challenge_id" description:"Opaque ID to echo back when finishing the ceremony" example:"dGhpcyBpcyBhIHRva2Vu"`
+	Options     json.RawMessage `json:"options" description:"WebAuthn PublicKeyCredentialCreationOptions or RequestOptions"`
+}
+
+// Clone returns a deep copy of PasskeyOptionsResponse.
+func (r PasskeyOptionsResponse) Clone() PasskeyOptionsResponse {
+	c := r
+	if r.Options != nil {
+		c.Options = make(json.RawMessage, len(r.Options))
+		copy(c.Options, r.Options)
+	}
+	return c
+}
+
+// PasskeyRegisterRequest is the request body for finishing passkey registration.
+type PasskeyRegisterRequest struct {
+	ChallengeID string          `json:"challenge_id" description:"Challenge ID returned when registration began" example:"dGhpcyBpcyBhIHRva2Vu"`
+	Name        string          `json:"name" description:"Label for the new passkey" example:"MacBook Touch ID"`
+	Credential  json.RawMessage `json:"credential" description:"PublicKeyCredential returned by navigator.credentials.create()"`
+}
+
+// Validate validates the PasskeyRegisterRequest.
+func (r *PasskeyRegisterRequest) Validate() error {
+	return check.All(
+		check.Str(r.ChallengeID, "challenge_id").Required().V(),
+		check.Str(r.Name, "name").Required().MaxLen(64).V(),
+		check.Int(len(r.Credential), "credential").Positive().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of PasskeyRegisterRequest.
+func (r PasskeyRegisterRequest) Clone() PasskeyRegisterRequest {
+	c := r
+	if r.Credential != nil {
+		c.Credential = make(json.RawMessage, len(r.Credential))
+		copy(c.Credential, r.Credential)
+	}
+	return c
+}
+
+// PasskeyLoginRequest is the request body for finishing a passkey sign-in.
+type PasskeyLoginRequest struct {
+	ChallengeID string          `json:"challenge_id" description:"Challenge ID returned when sign-in began" example:"dGhpcyBpcyBhIHRva2Vu"`
+	Credential  json.RawMessage `json:"credential" description:"PublicKeyCredential returned by navigator.credentials.get()"`
+	ReturnTo    string          `json:"return_to,omitempty" description:"Post-login redirect; ignored unless it matches the configured allowlist" example:"/app/settings"`
+	Remember    bool            `json:"remember,omitempty" description:"Keep the session across browser restarts; otherwise the cookie ends with the browser session" example:"true"`
+}
+
+// Validate validates the PasskeyLoginRequest.
+func (r *PasskeyLoginRequest) Validate() error {
+	return check.All(
+		check.Str(r.ChallengeID, "challenge_id").Required().V(),
+		check.Int(len(r.Credential), "credential").Positive().V(),
+		check.Str(r.ReturnTo, "return_to").MaxLen(2048).V(),
+	).Err()
+}
+
+// Clone returns a deep copy of PasskeyLoginRequest.
+func (r PasskeyLoginRequest) Clone() PasskeyLoginRequest {
+	c := r
+	if r.Credential != nil {
+		c.Credential = make(json.RawMessage, len(r.Credential))
+		copy(c.Credential, r.Credential)
+	}
+	return c
+}
+
+// PasskeyResponse is the public API response for a single registered passkey.
+type PasskeyResponse struct {
+	ID         int64      `json:"id" description:"Passkey ID" example:"1"`
+	Name       string     `json:"name" description:"User-chosen label" example:"MacBook Touch ID"`
+	Synced     bool       `json:"synced" description:"Whether the passkey is synced between devices"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" description:"Time of the last successful sign-in"`
+	CreatedAt  time.Time  `json:"created_at" description:"Time the passkey was registered"`
+}
+
+// Clone returns a deep copy of PasskeyResponse.
+func (p PasskeyResponse) Clone() PasskeyResponse {
+	c := p
+	if p.LastUsedAt != nil {
+		t := *p.LastUsedAt
+		c.LastUsedAt = &t
+	}
+	return c
+}
+
+// PasskeyListResponse is the public API response for listing registered passkeys.
+type PasskeyListResponse struct {
+	Passkeys []PasskeyResponse `json:"passkeys" description:"Registered passkeys"`
+}
+
+// Clone returns a deep copy of PasskeyListResponse.
+func (p PasskeyListResponse) Clone() PasskeyListResponse {
+	c := p
+	if p.Passkeys != nil {
+		c.Passkeys = make([]PasskeyResponse, len(p.Passkeys))
+		for i, pk := range p.Passkeys {
+			c.Passkeys[i] = pk.Clone()
+		}
+	}
+	return c
+}
@@ -1,6 +1,10 @@
 package wire
 
-import "time"
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
 
 // ProviderResponse is the public API response for a single linked OAuth provider.
 type ProviderResponse struct {
@@ -28,3 +32,20 @@ func (p ProviderListResponse) Clone() ProviderListResponse {
 	}
 	return c
 }
+
+// ProviderLinkConfirmRequest is the request body for confirming a pending provider link.
+type ProviderLinkConfirmRequest struct {
+	State string `json:"state" description:"link_state passed to /login when the provider login was held for confirmation" example:"dGhpcyBpcyBhIHN0YXRl"`
+}
+
+// Validate validates the ProviderLinkConfirmRequest.
+func (r *ProviderLinkConfirmRequest) Validate() error {
+	return check.All(
+		check.Str(r.State, "state").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of ProviderLinkConfirmRequest.
+func (r ProviderLinkConfirmRequest) Clone() ProviderLinkConfirmRequest {
+	return r
+}
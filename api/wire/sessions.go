@@ -0,0 +1,38 @@
+package wire
+
+import "time"
+
+// SessionResponse is the public API response for one of the user's sessions.
+// The session token is never returned.
+type SessionResponse struct {
+	ID          string    `json:"id" description:"Session identifier, used to revoke the session" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Current     bool      `json:"current" description:"Whether this is the session making the request"`
+	Method      string    `json:"method,omitempty" description:"How the user signed in" example:"password"`
+	IP          string    `json:"ip,omitempty" description:"Client IP address at sign-in" example:"203.0.113.7"`
+	Browser     string    `json:"browser,omitempty" description:"Browser parsed from the user agent" example:"Firefox 126"`
+	OS          string    `json:"os,omitempty" description:"Operating system parsed from the user agent" example:"macOS"`
+	DeviceClass string    `json:"device_class,omitempty" description:"Device class: desktop, mobile, tablet, bot or unknown" example:"desktop"`
+	CreatedAt   time.Time `json:"created_at" description:"Session creation time"`
+	LastSeenAt  time.Time `json:"last_seen_at" description:"Approximate time the session was last used"`
+	ExpiresAt   time.Time `json:"expires_at" description:"Session expiry time"`
+}
+
+// Clone returns a deep copy of SessionResponse.
+func (s SessionResponse) Clone() SessionResponse {
+	return s
+}
+
+// SessionListResponse is the public API response for listing the user's sessions.
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions" description:"Active sessions, most recently used first"`
+}
+
+// Clone returns a deep copy of SessionListResponse.
+func (s SessionListResponse) Clone() SessionListResponse {
+	c := s
+	if s.Sessions != nil {
+		c.Sessions = make([]SessionResponse, len(s.Sessions))
+		copy(c.Sessions, s.Sessions)
+	}
+	return c
+}
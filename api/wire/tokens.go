@@ -0,0 +1,44 @@
+package wire
+
+// AccessTokenResponse is the response body for exchanging a session for an access token.
+type AccessTokenResponse struct {
+	AccessToken string `json:"access_token" description:"Signed JWT carrying sub, email_verified and sid claims" example:"eyJhbGciOiJFZERTQSIsImtpZCI6Ill..."`
+	TokenType   string `json:"token_type" description:"Always Bearer" example:"Bearer"`
+	ExpiresIn   int    `json:"expires_in" description:"Seconds until the token expires" example:"300"`
+}
+
+// Clone returns a deep copy of AccessTokenResponse.
+func (r AccessTokenResponse) Clone() AccessTokenResponse {
+	return r
+}
+
+// JWKResponse is a public signing key as a JSON Web Key.
+type JWKResponse struct {
+	Kty string `json:"kty" description:"Key type: OKP for Ed25519, EC for P-256" example:"OKP"`
+	Crv string `json:"crv" description:"Curve" example:"Ed25519"`
+	X   string `json:"x" description:"Base64url-encoded public key, or X coordinate for EC keys" example:"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"`
+	Y   string `json:"y,omitempty" description:"Base64url-encoded Y coordinate, EC keys only"`
+	Kid string `json:"kid" description:"Key ID, matched against the token's kid header" example:"Yp3bHk2xQ0mC8vR1"`
+	Use string `json:"use" description:"Always sig" example:"sig"`
+	Alg string `json:"alg" description:"JWS algorithm: EdDSA or ES256" example:"EdDSA"`
+}
+
+// Clone returns a deep copy of JWKResponse.
+func (r JWKResponse) Clone() JWKResponse {
+	return r
+}
+
+// JWKSResponse is the JSON Web Key Set that verifies access tokens.
+type JWKSResponse struct {
+	Keys []JWKResponse `json:"keys" description:"Published signing keys, including the next key and recently retired keys"`
+}
+
+// Clone returns a deep copy of JWKSResponse.
+func (r JWKSResponse) Clone() JWKSResponse {
+	c := r
+	if r.Keys != nil {
+		c.Keys = make([]JWKResponse, len(r.Keys))
+		copy(c.Keys, r.Keys)
+	}
+	return c
+}
@@ -18,7 +18,11 @@ import (
 	"github.com/zoobzio/sumatra/admin/handlers"
 	"github.com/zoobzio/sumatra/config"
 	"github.com/zoobzio/sumatra/events"
+	intauthn "github.com/zoobzio/sumatra/internal/authn"
 	intotel "github.com/zoobzio/sumatra/internal/otel"
+	intpassword "github.com/zoobzio/sumatra/internal/password"
+	intratelimit "github.com/zoobzio/sumatra/internal/ratelimit"
+	intsession "github.com/zoobzio/sumatra/internal/session"
 	"github.com/zoobzio/sumatra/stores"
 
 	_ "github.com/lib/pq"
@@ -54,6 +58,15 @@ func run() error {
 	if err := sum.Config[config.Encryption](ctx, k, nil); err != nil {
 		return fmt.Errorf("failed to load encryption config: %w", err)
 	}
+	if err := sum.Config[config.Argon2](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load argon2 config: %w", err)
+	}
+	if err := sum.Config[config.Session](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load session config: %w", err)
+	}
+	if err := sum.Config[config.Admin](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load admin config: %w", err)
+	}
 
 	// =========================================================================
 	// 2. Connect to Infrastructure
@@ -94,7 +107,13 @@ func run() error {
 	renderer := postgres.New()
 
 	// Create all stores
-	allStores, err := stores.New(db, renderer, redisProvider)
+	// Sessions and verification tokens are stored under a keyed hash of the token.
+	tokenHasher, err := intsession.NewTokenHasher(sum.MustUse[config.Encryption](ctx).TokenHashKey())
+	if err != nil {
+		return fmt.Errorf("failed to create token hasher: %w", err)
+	}
+
+	allStores, err := stores.New(db, renderer, redisProvider, redisClient, tokenHasher)
 	if err != nil {
 		return fmt.Errorf("failed to create stores: %w", err)
 	}
@@ -103,8 +122,51 @@ func run() error {
 	sum.Register[contracts.Users](k, allStores.Users)
 	sum.Register[contracts.Sessions](k, allStores.Sessions)
 	sum.Register[contracts.Providers](k, allStores.Providers)
+	sum.Register[contracts.RecoveryCodes](k, allStores.RecoveryCodes)
+	sum.Register[contracts.LoginLockouts](k, allStores.LoginLockouts)
+	sum.Register[contracts.OAuthClients](k, allStores.OAuthClients)
+	sum.Register[contracts.OAuthRefreshTokens](k, allStores.OAuthRefreshTokens)
+	sum.Register[contracts.APITokens](k, allStores.APITokens)
+	sum.Register[contracts.Roles](k, allStores.Roles)
+	sum.Register[*intsession.TokenHasher](k, tokenHasher)
 	log.Println("admin: stores registered")
 
+	// Passwords are hashed with the configured Argon2id parameters.
+	argon2Cfg := sum.MustUse[config.Argon2](ctx)
+	hasher, err := intpassword.NewHasher(intpassword.Parameters{
+		Memory:      uint32(argon2Cfg.Memory),     //nolint:gosec // bounded by config validation
+		Time:        uint32(argon2Cfg.Time),       //nolint:gosec // bounded by config validation
+		Parallelism: uint8(argon2Cfg.Parallelism), //nolint:gosec // bounded by config validation
+		SaltLen:     uint32(argon2Cfg.SaltLength), //nolint:gosec // bounded by config validation
+		KeyLen:      uint32(argon2Cfg.KeyLength),  //nolint:gosec // bounded by config validation
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure password hashing: %w", err)
+	}
+	sum.Register[*intpassword.Hasher](k, hasher)
+
+	// Operators sign in through the public API; their session cookie is read
+	// from the shared session store with the same lifetime rules, and their
+	// roles decide which endpoints they may use.
+	sessionCfg := sum.MustUse[config.Session](ctx)
+	operators := intauthn.NewOperatorAuthenticator(allStores.Sessions, allStores.Users, allStores.Roles, sessionCfg.CookieName, intsession.Lifetime{
+		Idle:        sessionCfg.IdleTimeout,
+		BrowserIdle: sessionCfg.BrowserIdleTimeout,
+		Max:         sessionCfg.MaxLifetime,
+		Interval:    sessionCfg.LastSeenInterval,
+	})
+
+	// Clients outside the allowed networks are turned away before authentication.
+	adminCfg := sum.MustUse[config.Admin](ctx)
+	ipResolver, err := intratelimit.NewIPResolver(adminCfg.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("failed to create ip resolver: %w", err)
+	}
+	allowlist, err := intauthn.NewIPAllowlist(adminCfg.AllowedNetworks, ipResolver.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to parse admin allowed networks: %w", err)
+	}
+
 	// =========================================================================
 	// 4. Register Boundaries
 	// =========================================================================
@@ -160,6 +222,11 @@ func run() error {
 
 	svc.Handle(handlers.All()...)
 
+	// Every admin endpoint requires an operator's session, from an allowed
+	// network when any are configured.
+	svc.Engine().WithMiddleware(intauthn.AdminMiddleware(allowlist, operators)...)
+	svc.Engine().WithAuthenticator(operators.Authenticate)
+
 	appCfg := sum.MustUse[config.App](ctx)
 	capitan.Emit(ctx, events.StartupServerListening, events.StartupPortKey.Field(appCfg.Port))
 	log.Printf("admin: starting server on port %d...", appCfg.Port)
@@ -5,7 +5,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
@@ -14,14 +17,23 @@ import (
 	"github.com/zoobzio/aperture"
 	"github.com/zoobzio/astql/postgres"
 	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/cereal"
 	grubredis "github.com/zoobzio/grub/redis"
 	"github.com/zoobzio/sum"
 	"github.com/zoobzio/sumatra/api/contracts"
 	"github.com/zoobzio/sumatra/api/handlers"
 	"github.com/zoobzio/sumatra/config"
 	"github.com/zoobzio/sumatra/events"
+	intaccesstoken "github.com/zoobzio/sumatra/internal/accesstoken"
+	intauthn "github.com/zoobzio/sumatra/internal/authn"
+	intauthserver "github.com/zoobzio/sumatra/internal/authserver"
 	intidentity "github.com/zoobzio/sumatra/internal/identity"
+	intoauth "github.com/zoobzio/sumatra/internal/oauth"
 	intotel "github.com/zoobzio/sumatra/internal/otel"
+	intpassword "github.com/zoobzio/sumatra/internal/password"
+	intratelimit "github.com/zoobzio/sumatra/internal/ratelimit"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
 	"github.com/zoobzio/sumatra/stores"
 	"google.golang.org/grpc"
 
@@ -56,12 +68,18 @@ func run() error {
 	if err := sum.Config[config.Redis](ctx, k, nil); err != nil {
 		return fmt.Errorf("failed to load redis config: %w", err)
 	}
+	if err := sum.Config[config.OAuth](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load oauth config: %w", err)
+	}
 	if err := sum.Config[config.GitHub](ctx, k, nil); err != nil {
 		return fmt.Errorf("failed to load github config: %w", err)
 	}
 	if err := sum.Config[config.Google](ctx, k, nil); err != nil {
 		return fmt.Errorf("failed to load google config: %w", err)
 	}
+	if err := sum.Config[config.OIDC](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load oidc config: %w", err)
+	}
 	if err := sum.Config[config.Session](ctx, k, nil); err != nil {
 		return fmt.Errorf("failed to load session config: %w", err)
 	}
@@ -77,6 +95,30 @@ func run() error {
 	if err := sum.Config[config.Mesh](ctx, k, nil); err != nil {
 		return fmt.Errorf("failed to load mesh config: %w", err)
 	}
+	if err := sum.Config[config.MFA](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load mfa config: %w", err)
+	}
+	if err := sum.Config[config.WebAuthn](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load webauthn config: %w", err)
+	}
+	if err := sum.Config[config.RateLimit](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load rate limit config: %w", err)
+	}
+	if err := sum.Config[config.Lockout](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load lockout config: %w", err)
+	}
+	if err := sum.Config[config.Password](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load password config: %w", err)
+	}
+	if err := sum.Config[config.Argon2](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load argon2 config: %w", err)
+	}
+	if err := sum.Config[config.AccessToken](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load access token config: %w", err)
+	}
+	if err := sum.Config[config.AuthServer](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load auth server config: %w", err)
+	}
 
 	// =========================================================================
 	// 2. Connect to Infrastructure
@@ -117,7 +159,13 @@ func run() error {
 	renderer := postgres.New()
 
 	// Create all stores
-	allStores, err := stores.New(db, renderer, redisProvider)
+	// Sessions and verification tokens are stored under a keyed hash of the token.
+	tokenHasher, err := intsession.NewTokenHasher(sum.MustUse[config.Encryption](ctx).TokenHashKey())
+	if err != nil {
+		return fmt.Errorf("failed to create token hasher: %w", err)
+	}
+
+	allStores, err := stores.New(db, renderer, redisProvider, redisClient, tokenHasher)
 	if err != nil {
 		return fmt.Errorf("failed to create stores: %w", err)
 	}
@@ -127,15 +175,165 @@ func run() error {
 	sum.Register[contracts.Providers](k, allStores.Providers)
 	sum.Register[contracts.Sessions](k, allStores.Sessions)
 	sum.Register[contracts.VerificationTokens](k, allStores.VerificationTokens)
+	sum.Register[contracts.TOTPSecrets](k, allStores.TOTPSecrets)
+	sum.Register[contracts.RecoveryCodes](k, allStores.RecoveryCodes)
+	sum.Register[contracts.Passkeys](k, allStores.Passkeys)
+	sum.Register[contracts.PasskeyChallenges](k, allStores.PasskeyChallenges)
+	sum.Register[contracts.PendingLinks](k, allStores.PendingLinks)
+	sum.Register[contracts.OAuthRefreshTokens](k, allStores.OAuthRefreshTokens)
+	sum.Register[contracts.LoginLockouts](k, allStores.LoginLockouts)
+	sum.Register[contracts.APITokens](k, allStores.APITokens)
+	sum.Register[*intsession.TokenHasher](k, tokenHasher)
 	log.Println("stores registered")
 
+	// Sessions expire when idle and at an absolute cap; activity slides the
+	// idle expiry at most once per last-seen interval.
+	sessionCfg := sum.MustUse[config.Session](ctx)
+	sessionLifetime := intsession.Lifetime{
+		Idle:        sessionCfg.IdleTimeout,
+		BrowserIdle: sessionCfg.BrowserIdleTimeout,
+		Max:         sessionCfg.MaxLifetime,
+		Interval:    sessionCfg.LastSeenInterval,
+	}
+	sum.Register[intsession.Lifetime](k, sessionLifetime)
+
+	// Post-login return_to targets are checked against the session allowlist.
+	returnToPolicy, err := intsession.NewReturnToPolicy(sessionCfg.ReturnToAllowlist)
+	if err != nil {
+		return fmt.Errorf("failed to parse return_to allowlist: %w", err)
+	}
+	sum.Register[*intsession.ReturnToPolicy](k, returnToPolicy)
+
+	// Changes to a user's auth state rotate the requesting session and revoke
+	// the others as configured per event.
+	rotationPolicies, err := intsession.ParsePolicies(sessionCfg.RotateOn, sessionCfg.RevokeOthersOn)
+	if err != nil {
+		return fmt.Errorf("failed to parse session rotation events: %w", err)
+	}
+	sum.Register[*intsession.Rotator](k, intsession.NewRotator(allStores.Sessions, rotationPolicies))
+
+	// Access tokens are signed by keys shared through the database and
+	// rotated with overlap; keys are loaded once the registry is frozen.
+	accessTokenCfg := sum.MustUse[config.AccessToken](ctx)
+	keyring := intaccesstoken.NewKeyring(allStores.SigningKeys, intaccesstoken.Schedule{
+		Algorithm: accessTokenCfg.Algorithm,
+		Rotation:  accessTokenCfg.KeyRotation,
+		Overlap:   accessTokenCfg.KeyOverlap,
+	})
+	sum.Register[*intaccesstoken.Keyring](k, keyring)
+	issuer := intaccesstoken.NewIssuer(keyring, accessTokenCfg.Issuer, accessTokenCfg.TTL)
+	sum.Register[*intaccesstoken.Issuer](k, issuer)
+
+	// The authorization server signs client tokens with the same keys and
+	// signs users in with their sessions.
+	authServerCfg := sum.MustUse[config.AuthServer](ctx)
+	authServer := intauthserver.New(intauthserver.Stores{
+		Clients:        allStores.OAuthClients,
+		Consents:       allStores.OAuthConsents,
+		Authorizations: allStores.OAuthAuthorizations,
+		RefreshTokens:  allStores.OAuthRefreshTokens,
+		Users:          allStores.Users,
+		Sessions:       allStores.Sessions,
+	}, intauthserver.Options{
+		Issuer:          issuer,
+		SecretHasher:    tokenHasher,
+		Lifetime:        sessionLifetime,
+		CookieName:      sessionCfg.CookieName,
+		LoginURL:        authServerCfg.LoginURL,
+		ConsentURL:      authServerCfg.ConsentURL,
+		RequestTTL:      authServerCfg.RequestTTL,
+		CodeTTL:         authServerCfg.CodeTTL,
+		RefreshTokenTTL: authServerCfg.RefreshTokenTTL,
+	})
+
+	// Client IPs, for rate limits and session records, honour X-Forwarded-For
+	// only from trusted proxies.
+	rlCfg := sum.MustUse[config.RateLimit](ctx)
+	ipResolver, err := intratelimit.NewIPResolver(rlCfg.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("failed to create ip resolver: %w", err)
+	}
+	sum.Register[*intratelimit.IPResolver](k, ipResolver)
+
+	// New passwords are screened against the configured policy and breach corpus.
+	passwordCfg := sum.MustUse[config.Password](ctx)
+	passwordPolicy := &intpassword.Policy{
+		MinLength:     passwordCfg.MinLength,
+		MaxLength:     passwordCfg.MaxLength,
+		RequireLower:  passwordCfg.RequireLower,
+		RequireUpper:  passwordCfg.RequireUpper,
+		RequireDigit:  passwordCfg.RequireDigit,
+		RequireSymbol: passwordCfg.RequireSymbol,
+		MinScore:      passwordCfg.MinScore,
+	}
+	if passwordCfg.BreachCheck {
+		passwordPolicy.Breaches, err = intpassword.OpenBreachChecker(passwordCfg.BreachCorpus)
+		if err != nil {
+			return fmt.Errorf("failed to open breach corpus: %w", err)
+		}
+	}
+	sum.Register[*intpassword.Policy](k, passwordPolicy)
+
+	// Passwords are hashed with the configured Argon2id parameters.
+	argon2Cfg := sum.MustUse[config.Argon2](ctx)
+	hasher, err := intpassword.NewHasher(intpassword.Parameters{
+		Memory:      uint32(argon2Cfg.Memory),     //nolint:gosec // bounded by config validation
+		Time:        uint32(argon2Cfg.Time),       //nolint:gosec // bounded by config validation
+		Parallelism: uint8(argon2Cfg.Parallelism), //nolint:gosec // bounded by config validation
+		SaltLen:     uint32(argon2Cfg.SaltLength), //nolint:gosec // bounded by config validation
+		KeyLen:      uint32(argon2Cfg.KeyLength),  //nolint:gosec // bounded by config validation
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure password hashing: %w", err)
+	}
+	sum.Register[*intpassword.Hasher](k, hasher)
+
+	// OAuth providers are registered only when configured; the generic
+	// /login/{provider} and /providers/{provider} routes resolve them by name.
+	oauthRegistry, err := intoauth.NewRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to create oauth registry: %w", err)
+	}
+	if githubCfg := sum.MustUse[config.GitHub](ctx); githubCfg.Enabled() {
+		if err := oauthRegistry.Register(intoauth.NewGitHubClient(githubCfg.ClientID, githubCfg.ClientSecret)); err != nil {
+			return fmt.Errorf("failed to register github provider: %w", err)
+		}
+	}
+	if googleCfg := sum.MustUse[config.Google](ctx); googleCfg.Enabled() {
+		if err := oauthRegistry.Register(intoauth.NewGoogleClient(googleCfg.ClientID, googleCfg.ClientSecret)); err != nil {
+			return fmt.Errorf("failed to register google provider: %w", err)
+		}
+	}
+	oidcProviders, err := sum.MustUse[config.OIDC](ctx).Parse()
+	if err != nil {
+		return fmt.Errorf("failed to parse oidc providers: %w", err)
+	}
+	for _, p := range oidcProviders {
+		if err := oauthRegistry.Register(intoauth.NewOIDCClient(p.Name, p.Issuer, p.ClientID, p.ClientSecret, p.Scopes)); err != nil {
+			return fmt.Errorf("failed to register oidc provider %q: %w", p.Name, err)
+		}
+	}
+	sum.Register[*intoauth.Registry](k, oauthRegistry)
+	log.Printf("oauth providers registered: %v", oauthRegistry.Names())
+
 	// =========================================================================
 	// 4. Register Boundaries
 	// =========================================================================
 
-	// No model or wire boundaries required for current implementation.
-	// Provider model has encryption boundaries but those are handled via
-	// lifecycle hooks (BeforeSave/AfterLoad) which pull the boundary from context.
+	// Provider, TOTPSecret and SigningKey encrypt fields via lifecycle hooks
+	// (BeforeSave/AfterLoad) which pull the boundary from context, so the
+	// AES encryptor must be set before the boundaries are created.
+	encCfg := sum.MustUse[config.Encryption](ctx)
+	aes, err := cereal.AES(encCfg.Key())
+	if err != nil {
+		return fmt.Errorf("failed to create aes encryptor: %w", err)
+	}
+	svc.WithEncryptor(cereal.EncryptAES, aes)
+
+	if err := models.RegisterBoundaries(k); err != nil {
+		return fmt.Errorf("failed to register model boundaries: %w", err)
+	}
+	log.Println("boundaries registered")
 
 	// =========================================================================
 	// 5. Freeze Registry
@@ -144,6 +342,17 @@ func run() error {
 	sum.Freeze(k)
 	capitan.Emit(ctx, events.StartupServicesReady)
 
+	// Signing keys decrypt through their boundary, so they load after freeze.
+	if err := keyring.Refresh(ctx); err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	keyringCtx, stopKeyring := context.WithCancel(ctx)
+	defer stopKeyring()
+	go keyring.Run(keyringCtx, accessTokenCfg.KeyRefreshInterval, func(err error) {
+		log.Printf("signing key refresh failed: %v", err)
+	})
+	log.Println("signing keys loaded")
+
 	// =========================================================================
 	// 6. Initialize Observability (OTEL + Aperture)
 	// =========================================================================
@@ -195,7 +404,7 @@ func run() error {
 	// =========================================================================
 
 	meshCfg := sum.MustUse[config.Mesh](ctx)
-	identityServer := intidentity.New(allStores.Users, allStores.Sessions, allStores.Providers)
+	identityServer := intidentity.New(allStores.Users, allStores.Sessions, allStores.Providers, sessionLifetime)
 
 	node, err := aegis.NewNodeBuilder().
 		WithID(meshCfg.ID).
@@ -224,6 +433,49 @@ func run() error {
 
 	svc.Handle(handlers.All()...)
 
+	// Authenticate requests by session cookie or, for non-interactive clients,
+	// a personal access token sent as Authorization: Bearer.
+	authenticator := intauthn.NewAuthenticator(allStores.Sessions, allStores.APITokens, sessionCfg.CookieName, sessionLifetime)
+	svc.Engine().WithAuthenticator(authenticator.Authenticate)
+
+	// Rate limit the auth endpoints on the shared Redis connection.
+	if rlCfg.Enabled {
+		limiter := intratelimit.New(intratelimit.NewLimiter(redisClient), intratelimit.Options{
+			Policy: intratelimit.Policy{
+				IP:       intratelimit.Limit{Requests: rlCfg.IPRequests, Window: rlCfg.IPWindow},
+				Email:    intratelimit.Limit{Requests: rlCfg.EmailRequests, Window: rlCfg.EmailWindow},
+				Endpoint: intratelimit.Limit{Requests: rlCfg.EndpointRequests, Window: rlCfg.EndpointWindow},
+			},
+			Rules:    handlers.RateLimited(),
+			ClientIP: ipResolver,
+			OnExceeded: func(ctx context.Context, v intratelimit.Violation) {
+				events.RateLimit.Exceeded.Emit(ctx, events.RateLimitEvent{
+					Scope:             string(v.Scope),
+					Method:            v.Method,
+					Path:              v.Path,
+					ClientIP:          v.ClientIP,
+					RetryAfterSeconds: int(math.Ceil(v.RetryAfter.Seconds())),
+				})
+			},
+		})
+		svc.Engine().WithMiddleware(limiter.Handler)
+	}
+
+	// Record session activity and slide session expiry at a bounded write rate.
+	activity := intsession.NewActivity(allStores.Sessions, sessionCfg.CookieName, sessionLifetime,
+		func(token string, maxAge time.Duration) *http.Cookie {
+			return handlers.SessionCookie(sessionCfg, token, maxAge)
+		})
+	svc.Engine().WithMiddleware(activity.Handler)
+
+	// Handlers returning JSON bodies deliver rotated session cookies through
+	// the response.
+	svc.Engine().WithMiddleware(intsession.ResponseCookies)
+
+	// The authorization server speaks form-encoded OAuth rather than JSON, so
+	// it serves /oauth/* and discovery beside the API.
+	svc.Engine().WithMiddleware(authServer.Mount)
+
 	appCfg := sum.MustUse[config.App](ctx)
 	capitan.Emit(ctx, events.StartupServerListening, events.StartupPortKey.Field(appCfg.Port))
 	log.Printf("starting server on port %d...", appCfg.Port)
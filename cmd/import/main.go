@@ -0,0 +1,176 @@
+// Package main is the entry point for the bulk user import command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/cereal"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/config"
+	intuserimport "github.com/zoobzio/sumatra/internal/userimport"
+	"github.com/zoobzio/sumatra/models"
+	"github.com/zoobzio/sumatra/stores"
+
+	_ "github.com/lib/pq"
+)
+
+// progressEvery is how many records are processed between progress logs.
+const progressEvery = 10000
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	format := flag.String("format", "", "input format: jsonl or csv (default: from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "validate records and check for conflicts without writing")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-format jsonl|csv] [-dry-run] <file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return errors.New("expected exactly one input file")
+	}
+	path := flag.Arg(0)
+
+	log.Println("import: starting...")
+	ctx := context.Background()
+
+	// Initialize sum service and registry.
+	svc := sum.New()
+	k := sum.Start()
+
+	// =========================================================================
+	// 1. Load Configuration
+	// =========================================================================
+
+	if err := sum.Config[config.Database](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load database config: %w", err)
+	}
+	if err := sum.Config[config.Encryption](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load encryption config: %w", err)
+	}
+
+	// =========================================================================
+	// 2. Connect to Infrastructure
+	// =========================================================================
+
+	dbCfg := sum.MustUse[config.Database](ctx)
+	db, err := sqlx.Connect("postgres", dbCfg.DSN())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	log.Println("import: database connected")
+
+	// =========================================================================
+	// 3. Create Stores and Boundaries
+	// =========================================================================
+
+	renderer := postgres.New()
+	users, err := stores.NewUsers(db, renderer)
+	if err != nil {
+		return fmt.Errorf("failed to create users store: %w", err)
+	}
+	providers, err := stores.NewProviders(db, renderer)
+	if err != nil {
+		return fmt.Errorf("failed to create providers store: %w", err)
+	}
+
+	// Provider links encrypt their access token on save.
+	encCfg := sum.MustUse[config.Encryption](ctx)
+	aes, err := cereal.AES(encCfg.Key())
+	if err != nil {
+		return fmt.Errorf("failed to create aes encryptor: %w", err)
+	}
+	svc.WithEncryptor(cereal.EncryptAES, aes)
+	if err := models.RegisterBoundaries(k); err != nil {
+		return fmt.Errorf("failed to register model boundaries: %w", err)
+	}
+
+	sum.Freeze(k)
+
+	// =========================================================================
+	// 4. Import
+	// =========================================================================
+
+	f, err := os.Open(path) //nolint:gosec // path is the operator's command-line argument
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	reader, err := newReader(f, path, *format)
+	if err != nil {
+		return err
+	}
+
+	importer := intuserimport.New(users, providers, *dryRun)
+	var created, skipped, failed int
+	for n := 1; ; n++ {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !errors.Is(err, intuserimport.ErrInvalidRecord) {
+			return fmt.Errorf("line %d: %w", reader.Line(), err)
+		}
+
+		var outcome intuserimport.Outcome
+		if err == nil {
+			outcome, err = importer.Import(ctx, rec)
+		}
+		switch {
+		case err != nil:
+			failed++
+			log.Printf("import: line %d: %v", reader.Line(), err)
+		case outcome == intuserimport.Skipped:
+			skipped++
+		default:
+			created++
+		}
+
+		if n%progressEvery == 0 {
+			log.Printf("import: %d records processed", n)
+		}
+	}
+
+	verb := "created"
+	if *dryRun {
+		verb = "would create"
+	}
+	log.Printf("import: done: %s %d, skipped %d existing, failed %d", verb, created, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d records failed", failed)
+	}
+	return nil
+}
+
+// newReader picks the record reader from format, or from the file extension
+// when format is empty.
+func newReader(r io.Reader, path, format string) (intuserimport.Reader, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format {
+	case "jsonl", "ndjson":
+		return intuserimport.NewJSONLReader(r), nil
+	case "csv":
+		return intuserimport.NewCSVReader(r)
+	}
+	return nil, fmt.Errorf("unknown format %q: use -format jsonl or -format csv", format)
+}
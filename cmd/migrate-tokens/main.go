@@ -0,0 +1,96 @@
+// Package main is the entry point for the one-time token hashing migration.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+	grubredis "github.com/zoobzio/grub/redis"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/config"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/stores"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	log.Println("migrate-tokens: starting...")
+	ctx := context.Background()
+
+	// Initialize sum registry.
+	_ = sum.New()
+	k := sum.Start()
+
+	// =========================================================================
+	// 1. Load Configuration
+	// =========================================================================
+
+	if err := sum.Config[config.Redis](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load redis config: %w", err)
+	}
+	if err := sum.Config[config.Encryption](ctx, k, nil); err != nil {
+		return fmt.Errorf("failed to load encryption config: %w", err)
+	}
+
+	// =========================================================================
+	// 2. Connect to Infrastructure
+	// =========================================================================
+
+	redisCfg := sum.MustUse[config.Redis](ctx)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     redisCfg.Addr(),
+		Password: redisCfg.Password,
+		DB:       redisCfg.DB,
+	})
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	defer func() { _ = redisClient.Close() }()
+	log.Println("migrate-tokens: redis connected")
+
+	// =========================================================================
+	// 3. Create Stores
+	// =========================================================================
+
+	hasher, err := intsession.NewTokenHasher(sum.MustUse[config.Encryption](ctx).TokenHashKey())
+	if err != nil {
+		return fmt.Errorf("failed to create token hasher: %w", err)
+	}
+	provider := grubredis.New(redisClient)
+	sessions, err := stores.NewSessions(provider, redisClient, hasher)
+	if err != nil {
+		return fmt.Errorf("failed to create sessions store: %w", err)
+	}
+	verificationTokens, err := stores.NewVerificationTokens(provider, redisClient, hasher)
+	if err != nil {
+		return fmt.Errorf("failed to create verification tokens store: %w", err)
+	}
+
+	sum.Freeze(k)
+
+	// =========================================================================
+	// 4. Migrate
+	// =========================================================================
+
+	migrated, err := sessions.MigrateLegacy(ctx, intsession.IsTokenHash)
+	if err != nil {
+		return fmt.Errorf("migrating sessions: %w", err)
+	}
+	log.Printf("migrate-tokens: %d sessions migrated", migrated)
+
+	migrated, err = verificationTokens.MigrateLegacy(ctx, intsession.IsTokenHash)
+	if err != nil {
+		return fmt.Errorf("migrating verification tokens: %w", err)
+	}
+	log.Printf("migrate-tokens: %d verification tokens migrated", migrated)
+
+	log.Println("migrate-tokens: done")
+	return nil
+}
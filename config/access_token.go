@@ -0,0 +1,39 @@
+package config
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// AccessToken holds configuration for signed access tokens exchanged for
+// sessions at /token, and for the keys that sign them.
+type AccessToken struct {
+	// Issuer is the iss claim of minted tokens, normally the public base URL.
+	Issuer string `env:"MORPHEUS_ACCESS_TOKEN_ISSUER" default:"http://localhost:8080"`
+	// TTL is how long a minted token is valid.
+	TTL time.Duration `env:"MORPHEUS_ACCESS_TOKEN_TTL" default:"5m"`
+	// Algorithm signs new keys: EdDSA (Ed25519) or ES256 (P-256).
+	Algorithm string `env:"MORPHEUS_ACCESS_TOKEN_ALGORITHM" default:"EdDSA"`
+	// KeyRotation is how long each signing key signs tokens.
+	KeyRotation time.Duration `env:"MORPHEUS_ACCESS_TOKEN_KEY_ROTATION" default:"720h"`
+	// KeyOverlap is how long a key is published before it starts signing and
+	// after it stops.
+	KeyOverlap time.Duration `env:"MORPHEUS_ACCESS_TOKEN_KEY_OVERLAP" default:"24h"`
+	// KeyRefreshInterval is how often keys are reloaded and rotated.
+	KeyRefreshInterval time.Duration `env:"MORPHEUS_ACCESS_TOKEN_KEY_REFRESH_INTERVAL" default:"5m"`
+}
+
+// Validate validates the AccessToken configuration.
+func (c AccessToken) Validate() error {
+	return check.All(
+		check.Str(c.Issuer, "issuer").Required().HTTPOrHTTPS().V(),
+		check.DurationBetween(c.TTL, time.Minute, time.Hour, "ttl"),
+		check.Str(c.Algorithm, "algorithm").Required().OneOf([]string{"EdDSA", "ES256"}).V(),
+		check.DurationMin(c.KeyRefreshInterval, time.Minute, "key_refresh_interval"),
+		// A key must outlive the tokens it signs, and its successor must be
+		// created at least one refresh before it is needed.
+		check.DurationMin(c.KeyOverlap, c.TTL+c.KeyRefreshInterval, "key_overlap"),
+		check.DurationMin(c.KeyRotation, c.KeyOverlap, "key_rotation"),
+	).Err()
+}
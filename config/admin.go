@@ -0,0 +1,30 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/zoobzio/check"
+)
+
+// Admin holds access controls for the admin API binary, on top of requiring
+// a session of a user flagged as an admin.
+type Admin struct {
+	// AllowedNetworks lists the CIDRs admin API clients may connect from. Empty
+	// allows any address.
+	AllowedNetworks []string `env:"MORPHEUS_ADMIN_ALLOWED_NETWORKS"`
+	// TrustedProxies lists the CIDRs whose X-Forwarded-For header is believed
+	// when resolving the client address.
+	TrustedProxies []string `env:"MORPHEUS_ADMIN_TRUSTED_PROXIES"`
+}
+
+// Validate validates the Admin configuration.
+func (c Admin) Validate() error {
+	var validations []*check.Validation
+	for i, cidr := range c.AllowedNetworks {
+		validations = append(validations, check.Str(cidr, "allowed_networks["+strconv.Itoa(i)+"]").CIDR().V())
+	}
+	for i, cidr := range c.TrustedProxies {
+		validations = append(validations, check.Str(cidr, "trusted_proxies["+strconv.Itoa(i)+"]").CIDR().V())
+	}
+	return check.All(validations...).Err()
+}
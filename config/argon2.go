@@ -0,0 +1,26 @@
+package config
+
+import "github.com/zoobzio/check"
+
+// Argon2 holds the Argon2id parameters new password hashes are made with.
+// Existing hashes keep the parameters encoded in them and are upgraded on the
+// user's next successful login.
+type Argon2 struct {
+	// Memory is the memory cost in KiB.
+	Memory      int `env:"MORPHEUS_ARGON2_MEMORY" default:"65536"`
+	Time        int `env:"MORPHEUS_ARGON2_TIME" default:"3"`
+	Parallelism int `env:"MORPHEUS_ARGON2_PARALLELISM" default:"4"`
+	SaltLength  int `env:"MORPHEUS_ARGON2_SALT_LENGTH" default:"16"`
+	KeyLength   int `env:"MORPHEUS_ARGON2_KEY_LENGTH" default:"32"`
+}
+
+// Validate validates the Argon2 configuration.
+func (c Argon2) Validate() error {
+	return check.All(
+		check.Int(c.Memory, "memory").Min(8*c.Parallelism).V(),
+		check.Int(c.Time, "time").Positive().V(),
+		check.Int(c.Parallelism, "parallelism").Positive().Max(255).V(),
+		check.Int(c.SaltLength, "salt_length").Min(8).Max(1024).V(),
+		check.Int(c.KeyLength, "key_length").Min(16).Max(1024).V(),
+	).Err()
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// AuthServer holds configuration for the OAuth 2.0 / OpenID Connect
+// authorization server. Tokens it issues are signed as configured in
+// AccessToken, whose Issuer is also the authorization server's issuer.
+type AuthServer struct {
+	// LoginURL is where a user without a session is sent to sign in, with
+	// return_to set to the authorization request.
+	LoginURL string `env:"MORPHEUS_AUTH_SERVER_LOGIN_URL" default:"/login"`
+	// ConsentURL is the consent screen, opened with the request query
+	// parameter naming the pending request.
+	ConsentURL string `env:"MORPHEUS_AUTH_SERVER_CONSENT_URL" default:"/consent"`
+	// RequestTTL is how long an authorization request awaits consent.
+	RequestTTL time.Duration `env:"MORPHEUS_AUTH_SERVER_REQUEST_TTL" default:"10m"`
+	// CodeTTL is how long an authorization code can be redeemed.
+	CodeTTL time.Duration `env:"MORPHEUS_AUTH_SERVER_CODE_TTL" default:"1m"`
+	// RefreshTokenTTL is how long a refresh token is valid; each use issues
+	// a new one.
+	RefreshTokenTTL time.Duration `env:"MORPHEUS_AUTH_SERVER_REFRESH_TOKEN_TTL" default:"720h"`
+}
+
+// Validate validates the AuthServer configuration.
+func (c AuthServer) Validate() error {
+	return check.All(
+		check.Str(c.LoginURL, "login_url").Required().V(),
+		check.Str(c.ConsentURL, "consent_url").Required().V(),
+		check.DurationBetween(c.RequestTTL, time.Minute, time.Hour, "request_ttl"),
+		// RFC 6749 recommends codes live at most ten minutes.
+		check.DurationBetween(c.CodeTTL, 10*time.Second, 10*time.Minute, "code_ttl"),
+		check.DurationMin(c.RefreshTokenTTL, time.Hour, "refresh_token_ttl"),
+	).Err()
+}
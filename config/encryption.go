@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 
@@ -11,6 +13,10 @@ import (
 type Encryption struct {
 	// AESKey is a 64-character hex-encoded AES-256 key (32 bytes).
 	AESKey string `env:"MORPHEUS_ENCRYPTION_KEY"`
+	// TokenKey is an optional 64-character hex-encoded HMAC key for hashing
+	// session and verification tokens at rest. When empty, a key is derived
+	// from AESKey.
+	TokenKey string `env:"MORPHEUS_ENCRYPTION_TOKEN_KEY"`
 }
 
 // Validate validates the Encryption configuration.
@@ -23,6 +29,16 @@ func (c Encryption) Validate() error {
 	if _, err := hex.DecodeString(c.AESKey); err != nil {
 		return fmt.Errorf("aes_key: must be a valid hex-encoded string: %w", err)
 	}
+	if c.TokenKey != "" {
+		if err := check.All(
+			check.Str(c.TokenKey, "token_key").MinLen(64).MaxLen(64).V(),
+		).Err(); err != nil {
+			return err
+		}
+		if _, err := hex.DecodeString(c.TokenKey); err != nil {
+			return fmt.Errorf("token_key: must be a valid hex-encoded string: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -31,3 +47,16 @@ func (c Encryption) Key() []byte {
 	b, _ := hex.DecodeString(c.AESKey)
 	return b
 }
+
+// TokenHashKey returns the 32-byte HMAC key for hashing tokens at rest:
+// TokenKey if set, otherwise a key derived from the AES key so that neither
+// key reveals the other.
+func (c Encryption) TokenHashKey() []byte {
+	if c.TokenKey != "" {
+		b, _ := hex.DecodeString(c.TokenKey)
+		return b
+	}
+	mac := hmac.New(sha256.New, c.Key())
+	mac.Write([]byte("morpheus token hash key"))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// Lockout holds the failed password attempt policy.
+type Lockout struct {
+	Enabled bool `env:"MORPHEUS_LOCKOUT_ENABLED" default:"true"`
+	// Threshold is the number of consecutive failures that locks the account.
+	Threshold int `env:"MORPHEUS_LOCKOUT_THRESHOLD" default:"5"`
+	// Window is how long a failure counts towards the threshold.
+	Window time.Duration `env:"MORPHEUS_LOCKOUT_WINDOW" default:"15m"`
+	// BaseDuration is the first lockout's length; each repeat lockout doubles it.
+	BaseDuration time.Duration `env:"MORPHEUS_LOCKOUT_BASE_DURATION" default:"5m"`
+	// MaxDuration caps the lockout length.
+	MaxDuration time.Duration `env:"MORPHEUS_LOCKOUT_MAX_DURATION" default:"24h"`
+	// ResetAfter is how long without a failure before the lockout history is forgotten.
+	ResetAfter time.Duration `env:"MORPHEUS_LOCKOUT_RESET_AFTER" default:"24h"`
+}
+
+// Validate validates the Lockout configuration.
+func (c Lockout) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	return check.All(
+		check.Int(c.Threshold, "threshold").Positive().V(),
+		check.DurationMin(c.Window, time.Second, "window"),
+		check.DurationMin(c.BaseDuration, time.Second, "base_duration"),
+		check.DurationMin(c.MaxDuration, c.BaseDuration, "max_duration"),
+		// The record must outlive both the failure window and the longest lockout.
+		check.DurationMin(c.ResetAfter, c.Window, "reset_after"),
+		check.DurationMin(c.ResetAfter, c.MaxDuration, "reset_after"),
+	).Err()
+}
@@ -0,0 +1,19 @@
+package config
+
+import "github.com/zoobzio/check"
+
+// MFA holds configuration for multi-factor authentication.
+type MFA struct {
+	// Issuer is the label shown in authenticator apps alongside the account email.
+	Issuer string `env:"MORPHEUS_MFA_ISSUER" default:"Morpheus"`
+	// Skew is the number of 30-second steps of clock drift tolerated on either side.
+	Skew int `env:"MORPHEUS_MFA_SKEW" default:"1"`
+}
+
+// Validate validates the MFA configuration.
+func (c MFA) Validate() error {
+	return check.All(
+		check.Str(c.Issuer, "issuer").Required().MaxLen(64).V(),
+		check.Int(c.Skew, "skew").NonNegative().Max(3).V(),
+	).Err()
+}
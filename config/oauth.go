@@ -0,0 +1,47 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/zoobzio/check"
+)
+
+// Email match modes for provider logins whose verified email belongs to an existing account.
+const (
+	// EmailMatchLink links the provider to the existing account and signs the user in.
+	EmailMatchLink = "link"
+	// EmailMatchConfirm holds the login until the user signs in to the existing
+	// account by another method and confirms the pending link.
+	EmailMatchConfirm = "confirm"
+)
+
+// OAuth holds configuration shared by all OAuth login and linking providers.
+type OAuth struct {
+	// RedirectBaseURL is the public origin that provider callbacks are served from.
+	// Each provider redirects to {base}/login/{provider}/callback or {base}/providers/{provider}/callback.
+	RedirectBaseURL string `env:"MORPHEUS_OAUTH_REDIRECT_BASE_URL"`
+	// AutoProvision creates an account on provider login when no account is linked,
+	// using the provider's verified email, name, and avatar.
+	AutoProvision bool `env:"MORPHEUS_OAUTH_AUTO_PROVISION"`
+	// EmailMatch decides what auto-provisioning does when a verified local account
+	// already uses the provider's email: EmailMatchLink or EmailMatchConfirm.
+	EmailMatch string `env:"MORPHEUS_OAUTH_EMAIL_MATCH" default:"confirm"`
+}
+
+// Validate validates the OAuth configuration.
+func (c OAuth) Validate() error {
+	return check.All(
+		check.Str(c.RedirectBaseURL, "redirect_base_url").Required().HTTPOrHTTPS().V(),
+		check.Str(c.EmailMatch, "email_match").Required().OneOf([]string{EmailMatchLink, EmailMatchConfirm}).V(),
+	).Err()
+}
+
+// LoginCallbackURL returns the redirect URI for the login flow of the named provider.
+func (c OAuth) LoginCallbackURL(provider string) string {
+	return strings.TrimRight(c.RedirectBaseURL, "/") + "/login/" + provider + "/callback"
+}
+
+// LinkCallbackURL returns the redirect URI for the account-linking flow of the named provider.
+func (c OAuth) LinkCallbackURL(provider string) string {
+	return strings.TrimRight(c.RedirectBaseURL, "/") + "/providers/" + provider + "/callback"
+}
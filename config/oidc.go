@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zoobzio/check"
+)
+
+// OIDC holds the generic OpenID Connect providers, e.g. Okta, Azure AD, or Keycloak.
+// Providers is a JSON array so any number of issuers can be configured from one variable:
+//
+//	[{"name":"okta","issuer":"https://acme.okta.com","client_id":"...","client_secret":"..."}]
+type OIDC struct {
+	Providers string `env:"MORPHEUS_OIDC_PROVIDERS" secret:"morpheus/oidc-providers"`
+}
+
+// OIDCProvider is a single configured OpenID Connect issuer.
+// Name becomes the provider type on linked accounts and the {provider} path segment.
+type OIDCProvider struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+}
+
+// Validate validates the OIDC configuration.
+func (c OIDC) Validate() error {
+	_, err := c.Parse()
+	return err
+}
+
+// Parse decodes and validates the configured providers.
+func (c OIDC) Parse() ([]OIDCProvider, error) {
+	if c.Providers == "" {
+		return nil, nil
+	}
+	var providers []OIDCProvider
+	if err := json.Unmarshal([]byte(c.Providers), &providers); err != nil {
+		return nil, fmt.Errorf("providers: invalid json: %w", err)
+	}
+
+	seen := make(map[string]bool, len(providers))
+	for i, p := range providers {
+		field := fmt.Sprintf("providers[%d]", i)
+		if err := check.All(
+			check.Str(p.Name, field+".name").Required().MaxLen(32).Slug().NotOneOf([]string{"github", "google"}).V(),
+			check.Str(p.Issuer, field+".issuer").Required().URLWithScheme([]string{"https"}).V(),
+			check.Str(p.ClientID, field+".client_id").Required().V(),
+			check.Str(p.ClientSecret, field+".client_secret").Required().V(),
+		).Err(); err != nil {
+			return nil, err
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("%s.name: duplicate provider %q", field, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return providers, nil
+}
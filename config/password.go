@@ -0,0 +1,30 @@
+package config
+
+import "github.com/zoobzio/check"
+
+// Password holds the policy for passwords users choose at registration and reset.
+type Password struct {
+	MinLength int `env:"MORPHEUS_PASSWORD_MIN_LENGTH" default:"8"`
+	// MaxLength bounds hashing and strength-estimation cost.
+	MaxLength     int  `env:"MORPHEUS_PASSWORD_MAX_LENGTH" default:"128"`
+	RequireLower  bool `env:"MORPHEUS_PASSWORD_REQUIRE_LOWER"`
+	RequireUpper  bool `env:"MORPHEUS_PASSWORD_REQUIRE_UPPER"`
+	RequireDigit  bool `env:"MORPHEUS_PASSWORD_REQUIRE_DIGIT"`
+	RequireSymbol bool `env:"MORPHEUS_PASSWORD_REQUIRE_SYMBOL"`
+	// MinScore is the lowest accepted strength score, 0 (off) to 4.
+	MinScore int `env:"MORPHEUS_PASSWORD_MIN_SCORE" default:"2"`
+	// BreachCheck rejects passwords found in the breach corpus.
+	BreachCheck bool `env:"MORPHEUS_PASSWORD_BREACH_CHECK" default:"true"`
+	// BreachCorpus is a file of SHA-1 digests or a directory of SHA-1 prefix
+	// range files. Empty uses the corpus bundled with the binary.
+	BreachCorpus string `env:"MORPHEUS_PASSWORD_BREACH_CORPUS"`
+}
+
+// Validate validates the Password configuration.
+func (c Password) Validate() error {
+	return check.All(
+		check.Int(c.MinLength, "min_length").Positive().V(),
+		check.Int(c.MaxLength, "max_length").Min(c.MinLength).Max(1024).V(),
+		check.Between(c.MinScore, 0, 4, "min_score"),
+	).Err()
+}
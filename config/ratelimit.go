@@ -0,0 +1,48 @@
+package config
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// RateLimit holds sliding-window limits for the unauthenticated auth endpoints.
+// Each limit applies per endpoint; a request count of zero disables that limit.
+type RateLimit struct {
+	Enabled bool `env:"MORPHEUS_RATELIMIT_ENABLED" default:"true"`
+	// IPRequests and IPWindow limit each client IP.
+	IPRequests int           `env:"MORPHEUS_RATELIMIT_IP_REQUESTS" default:"20"`
+	IPWindow   time.Duration `env:"MORPHEUS_RATELIMIT_IP_WINDOW" default:"1m"`
+	// EmailRequests and EmailWindow limit each email address named in a request body.
+	EmailRequests int           `env:"MORPHEUS_RATELIMIT_EMAIL_REQUESTS" default:"5"`
+	EmailWindow   time.Duration `env:"MORPHEUS_RATELIMIT_EMAIL_WINDOW" default:"15m"`
+	// EndpointRequests and EndpointWindow cap all traffic to the endpoint. The
+	// cap is shared by every client, so one client can exhaust it for all; it is
+	// off by default and meant only as an opt-in circuit breaker set well above
+	// peak legitimate traffic.
+	EndpointRequests int           `env:"MORPHEUS_RATELIMIT_ENDPOINT_REQUESTS" default:"0"`
+	EndpointWindow   time.Duration `env:"MORPHEUS_RATELIMIT_ENDPOINT_WINDOW" default:"1m"`
+	// TrustedProxies lists the CIDRs whose X-Forwarded-For header is believed.
+	// Session client IPs are resolved the same way, even when Enabled is false.
+	TrustedProxies []string `env:"MORPHEUS_RATELIMIT_TRUSTED_PROXIES"`
+}
+
+// Validate validates the RateLimit configuration.
+func (c RateLimit) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	validations := []*check.Validation{
+		check.Int(c.IPRequests, "ip_requests").NonNegative().V(),
+		check.DurationMin(c.IPWindow, time.Second, "ip_window"),
+		check.Int(c.EmailRequests, "email_requests").NonNegative().V(),
+		check.DurationMin(c.EmailWindow, time.Second, "email_window"),
+		check.Int(c.EndpointRequests, "endpoint_requests").NonNegative().V(),
+		check.DurationMin(c.EndpointWindow, time.Second, "endpoint_window"),
+	}
+	for i, cidr := range c.TrustedProxies {
+		validations = append(validations, check.Str(cidr, "trusted_proxies["+strconv.Itoa(i)+"]").CIDR().V())
+	}
+	return check.All(validations...).Err()
+}
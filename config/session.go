@@ -8,12 +8,32 @@ import (
 
 // Session holds configuration for session management and cookie settings.
 type Session struct {
-	TTL          time.Duration `env:"MORPHEUS_SESSION_TTL" default:"168h"`
+	// IdleTimeout ends a session that has not been used for this long. Each use
+	// slides the expiry forward, up to MaxLifetime after sign-in.
+	IdleTimeout time.Duration `env:"MORPHEUS_SESSION_IDLE_TIMEOUT" default:"72h"`
+	// BrowserIdleTimeout replaces IdleTimeout for sessions signed in without
+	// "remember me", whose cookie also ends with the browser session.
+	BrowserIdleTimeout time.Duration `env:"MORPHEUS_SESSION_BROWSER_IDLE_TIMEOUT" default:"12h"`
+	// MaxLifetime is the absolute limit on a session's age, however active.
+	MaxLifetime  time.Duration `env:"MORPHEUS_SESSION_MAX_LIFETIME" default:"720h"`
 	CookieName   string        `env:"MORPHEUS_SESSION_COOKIE_NAME" default:"session"`
 	CookieDomain string        `env:"MORPHEUS_SESSION_COOKIE_DOMAIN"`
 	CookieSecure bool          `env:"MORPHEUS_SESSION_COOKIE_SECURE"`
 	CookiePath   string        `env:"MORPHEUS_SESSION_COOKIE_PATH" default:"/"`
 	StateSecret  string        `env:"MORPHEUS_SESSION_STATE_SECRET"`
+	// ReturnToAllowlist lists where users may be sent after login: path prefixes
+	// on this origin ("/app") or absolute origins with an optional path prefix
+	// ("https://app.example.com/dashboard").
+	ReturnToAllowlist []string `env:"MORPHEUS_SESSION_RETURN_TO_ALLOWLIST" default:"/"`
+	// LastSeenInterval is the minimum time between writes of a session's
+	// last-seen timestamp and sliding expiry.
+	LastSeenInterval time.Duration `env:"MORPHEUS_SESSION_LAST_SEEN_INTERVAL" default:"5m"`
+	// RotateOn lists the events after which the requesting session is given a
+	// new token: password_reset, email_verified, provider_linked, mfa_enabled.
+	RotateOn []string `env:"MORPHEUS_SESSION_ROTATE_ON" default:"password_reset,email_verified,provider_linked,mfa_enabled"`
+	// RevokeOthersOn lists the events after which the user's other sessions
+	// are signed out.
+	RevokeOthersOn []string `env:"MORPHEUS_SESSION_REVOKE_OTHERS_ON" default:"password_reset,mfa_enabled"`
 }
 
 // Validate validates the Session configuration.
@@ -22,5 +42,9 @@ func (c Session) Validate() error {
 		check.Str(c.CookieName, "cookie_name").Required().V(),
 		check.Str(c.CookiePath, "cookie_path").Required().V(),
 		check.Str(c.StateSecret, "state_secret").Required().MinLen(32).V(),
+		check.DurationMin(c.LastSeenInterval, time.Second, "last_seen_interval"),
+		check.DurationMin(c.IdleTimeout, c.LastSeenInterval, "idle_timeout"),
+		check.DurationBetween(c.BrowserIdleTimeout, c.LastSeenInterval, c.IdleTimeout, "browser_idle_timeout"),
+		check.DurationMin(c.MaxLifetime, c.IdleTimeout, "max_lifetime"),
 	).Err()
 }
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// WebAuthn holds relying-party configuration for passkey registration and sign-in.
+type WebAuthn struct {
+	// RPID is the relying party ID, normally the registrable domain (e.g. example.com).
+	RPID          string        `env:"MORPHEUS_WEBAUTHN_RP_ID"`
+	RPDisplayName string        `env:"MORPHEUS_WEBAUTHN_RP_DISPLAY_NAME" default:"Morpheus"`
+	RPOrigins     []string      `env:"MORPHEUS_WEBAUTHN_RP_ORIGINS"`
+	ChallengeTTL  time.Duration `env:"MORPHEUS_WEBAUTHN_CHALLENGE_TTL" default:"5m"`
+}
+
+// Validate validates the WebAuthn configuration.
+func (c WebAuthn) Validate() error {
+	return check.All(
+		check.Str(c.RPID, "rp_id").Required().Hostname().V(),
+		check.Str(c.RPDisplayName, "rp_display_name").Required().V(),
+		check.StrSlice(c.RPOrigins, "rp_origins").NotEmpty().V(),
+		check.DurationMin(c.ChallengeTTL, time.Minute, "challenge_ttl"),
+	).Err()
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/sum"
+)
+
+// RateLimitEvent carries details of a request rejected by a rate limit.
+type RateLimitEvent struct {
+	Scope             string `json:"scope"`
+	Method            string `json:"method"`
+	Path              string `json:"path"`
+	ClientIP          string `json:"client_ip"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+}
+
+// Rate limit signals.
+var (
+	RateLimitExceededSignal = capitan.NewSignal("morpheus.ratelimit.exceeded", "Rate limit exceeded")
+)
+
+// RateLimit provides access to rate limiting events.
+var RateLimit = struct {
+	Exceeded sum.Event[RateLimitEvent]
+}{
+	Exceeded: sum.NewWarnEvent[RateLimitEvent](RateLimitExceededSignal),
+}
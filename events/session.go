@@ -7,20 +7,28 @@ import (
 
 // SessionEvent carries session lifecycle data.
 type SessionEvent struct {
-	UserID int64 `json:"user_id"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	// Reason says why a session was revoked or rotated, e.g. "user_revoked".
+	Reason string `json:"reason,omitempty"`
+	// PreviousSessionID is the ID a rotated session had before rotation.
+	PreviousSessionID string `json:"previous_session_id,omitempty"`
 }
 
 // Session signals.
 var (
 	SessionCreatedSignal = capitan.NewSignal("morpheus.session.created", "Session created")
 	SessionRevokedSignal = capitan.NewSignal("morpheus.session.revoked", "Session revoked")
+	SessionRotatedSignal = capitan.NewSignal("morpheus.session.rotated", "Session rotated")
 )
 
 // Session provides access to session lifecycle events.
 var Session = struct {
 	Created sum.Event[SessionEvent]
 	Revoked sum.Event[SessionEvent]
+	Rotated sum.Event[SessionEvent]
 }{
 	Created: sum.NewInfoEvent[SessionEvent](SessionCreatedSignal),
 	Revoked: sum.NewInfoEvent[SessionEvent](SessionRevokedSignal),
+	Rotated: sum.NewInfoEvent[SessionEvent](SessionRotatedSignal),
 }
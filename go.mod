@@ -5,12 +5,15 @@ go 1.24.5
 toolchain go1.25.3
 
 require (
+	github.com/go-webauthn/webauthn v0.15.0
+	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/lib/pq v1.10.9
 	github.com/redis/go-redis/v9 v9.18.0
 	github.com/zoobzio/aperture v1.0.2
 	github.com/zoobzio/astql v1.0.6
 	github.com/zoobzio/capitan v1.0.0
+	github.com/zoobzio/cereal v0.1.1
 	github.com/zoobzio/check v0.0.4
 	github.com/zoobzio/grub v0.1.8
 	github.com/zoobzio/grub/redis v0.1.8
@@ -28,20 +31,25 @@ require (
 	go.opentelemetry.io/otel/sdk/metric v1.38.0
 	go.opentelemetry.io/otel/trace v1.38.0
 	golang.org/x/crypto v0.46.0
+	google.golang.org/grpc v1.75.0
 )
 
 require (
 	github.com/cenkalti/backoff/v5 v5.0.3 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/fxamacker/cbor/v2 v2.9.0 // indirect
 	github.com/go-logr/logr v1.4.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/go-webauthn/x v0.1.26 // indirect
+	github.com/google/go-tpm v0.9.6 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.2 // indirect
 	github.com/testcontainers/testcontainers-go v0.40.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	github.com/zoobzio/aegis v0.0.2 // indirect
 	github.com/zoobzio/atom v1.0.0 // indirect
-	github.com/zoobzio/cereal v0.1.1 // indirect
 	github.com/zoobzio/clockz v1.0.0 // indirect
 	github.com/zoobzio/dbml v1.0.0 // indirect
 	github.com/zoobzio/edamame v1.0.1 // indirect
@@ -62,7 +70,6 @@ require (
 	golang.org/x/text v0.32.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20250825161204-c5933d9347a5 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250825161204-c5933d9347a5 // indirect
-	google.golang.org/grpc v1.75.0 // indirect
 	google.golang.org/protobuf v1.36.8 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
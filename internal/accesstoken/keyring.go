@@ -0,0 +1,191 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ErrNoSigningKey is returned when no loaded key is active.
+var ErrNoSigningKey = errors.New("no active signing key")
+
+// KeyStore is the subset of the signing keys store a Keyring needs.
+type KeyStore interface {
+	ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error)
+	Set(ctx context.Context, key string, signingKey *models.SigningKey) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// Schedule is how signing keys are rotated.
+type Schedule struct {
+	// Algorithm is the algorithm of new keys; keys already stored keep theirs.
+	Algorithm string
+	// Rotation is how long each key signs tokens.
+	Rotation time.Duration
+	// Overlap is how long a key is published before it starts signing, so
+	// verifiers caching the key set learn it in time, and after it stops, so
+	// the tokens it signed verify until they expire. It must be at least the
+	// token TTL.
+	Overlap time.Duration
+}
+
+// Keyring holds the stored signing keys and rotates them on Refresh. Keys are
+// shared through the store, so every instance signs with the same key and
+// publishes the same set.
+type Keyring struct {
+	store    KeyStore
+	schedule Schedule
+	now      func() time.Time
+
+	mu   sync.RWMutex
+	keys []*key
+}
+
+// NewKeyring returns a Keyring rotating keys in store on schedule. It holds no
+// keys until Refresh.
+func NewKeyring(store KeyStore, schedule Schedule) *Keyring {
+	return &Keyring{store: store, schedule: schedule, now: time.Now}
+}
+
+// Refresh loads the unexpired keys, creates the next key when the active one
+// is within Overlap of retiring, and deletes expired keys. The next key
+// starts signing when the active one retires, so it is published for Overlap
+// first. Instances refreshing at the same moment may each create a successor;
+// all are published and they converge on the same signer.
+func (r *Keyring) Refresh(ctx context.Context) error {
+	now := r.now()
+	records, err := r.store.ListUnexpired(ctx, now)
+	if err != nil {
+		return fmt.Errorf("loading signing keys: %w", err)
+	}
+
+	var active, next *models.SigningKey
+	for _, rec := range records {
+		switch {
+		case rec.IsActive(now):
+			if active == nil || !rec.ActivatesAt.Before(active.ActivatesAt) {
+				active = rec
+			}
+		case now.Before(rec.ActivatesAt):
+			next = rec
+		}
+	}
+
+	var created *models.SigningKey
+	switch {
+	case active == nil && next == nil:
+		created, err = r.create(ctx, now)
+	case active != nil && next == nil && !now.Before(active.RetiresAt.Add(-r.schedule.Overlap)):
+		created, err = r.create(ctx, active.RetiresAt)
+	}
+	if err != nil {
+		return err
+	}
+	if created != nil {
+		records = append(records, created)
+	}
+
+	keys := make([]*key, 0, len(records))
+	for _, rec := range records {
+		k, err := parseSigningKey(rec)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+
+	if err := r.store.DeleteExpired(ctx, now); err != nil {
+		return fmt.Errorf("deleting expired signing keys: %w", err)
+	}
+	return nil
+}
+
+// create stores a new key that starts signing at activatesAt.
+func (r *Keyring) create(ctx context.Context, activatesAt time.Time) (*models.SigningKey, error) {
+	retiresAt := activatesAt.Add(r.schedule.Rotation)
+	rec, err := newSigningKey(r.schedule.Algorithm, activatesAt, retiresAt, retiresAt.Add(r.schedule.Overlap))
+	if err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+	if err := r.store.Set(ctx, rec.ID, rec); err != nil {
+		return nil, fmt.Errorf("storing signing key: %w", err)
+	}
+	return rec, nil
+}
+
+// Run calls Refresh every interval until ctx is done, passing failures to
+// onError. Keys already loaded stay in use while refreshes fail.
+func (r *Keyring) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// signer returns the key that signs tokens at now: the most recently activated
+// of the active keys, ties broken by ID so every instance picks the same one.
+func (r *Keyring) signer(now time.Time) (*key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var chosen *key
+	for _, k := range r.keys {
+		if now.Before(k.activatesAt) || !now.Before(k.retiresAt) {
+			continue
+		}
+		if chosen == nil || k.activatesAt.After(chosen.activatesAt) ||
+			(k.activatesAt.Equal(chosen.activatesAt) && k.id < chosen.id) {
+			chosen = k
+		}
+	}
+	if chosen == nil {
+		return nil, ErrNoSigningKey
+	}
+	return chosen, nil
+}
+
+// verifier returns the published key with ID kid at now.
+func (r *Keyring) verifier(kid string, now time.Time) (*key, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, k := range r.keys {
+		if k.id == kid && now.Before(k.expiresAt) {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// JWKS returns the public keys published at the time of the call, including
+// keys not yet signing and retired keys whose tokens may still be live.
+func (r *Keyring) JWKS() ([]JWK, error) {
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	set := make([]JWK, 0, len(r.keys))
+	for _, k := range r.keys {
+		if !now.Before(k.expiresAt) {
+			continue
+		}
+		j, err := k.jwk()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, j)
+	}
+	return set, nil
+}
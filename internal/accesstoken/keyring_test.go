@@ -0,0 +1,147 @@
+package accesstoken
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// fakeKeyStore keeps signing keys in memory.
+type fakeKeyStore struct {
+	keys map[string]*models.SigningKey
+}
+
+func (f *fakeKeyStore) ListUnexpired(_ context.Context, now time.Time) ([]*models.SigningKey, error) {
+	var out []*models.SigningKey
+	for _, k := range f.keys {
+		if !k.IsExpired(now) {
+			c := k.Clone()
+			out = append(out, &c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ActivatesAt.Before(out[j].ActivatesAt) })
+	return out, nil
+}
+
+func (f *fakeKeyStore) Set(_ context.Context, id string, k *models.SigningKey) error {
+	c := k.Clone()
+	f.keys[id] = &c
+	return nil
+}
+
+func (f *fakeKeyStore) DeleteExpired(_ context.Context, now time.Time) error {
+	for id, k := range f.keys {
+		if k.IsExpired(now) {
+			delete(f.keys, id)
+		}
+	}
+	return nil
+}
+
+func TestRefresh_Rotation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	start := now
+	store := &fakeKeyStore{keys: map[string]*models.SigningKey{}}
+	r := NewKeyring(store, Schedule{Algorithm: models.SigningAlgorithmEdDSA, Rotation: 24 * time.Hour, Overlap: time.Hour})
+	r.now = func() time.Time { return now }
+
+	if err := r.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	first, err := r.signer(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.retiresAt.Equal(now.Add(24*time.Hour)) || !first.expiresAt.Equal(now.Add(25*time.Hour)) {
+		t.Errorf("schedule: retires %v expires %v", first.retiresAt, first.expiresAt)
+	}
+
+	steps := []struct {
+		name string
+		// at is the time of the refresh after the first key was created.
+		at        time.Duration
+		stored    int
+		published int
+		// signsFirst and verifiesFirst report whether the first key still
+		// signs new tokens and verifies old ones.
+		signsFirst    bool
+		verifiesFirst bool
+	}{
+		{"outside overlap", 22 * time.Hour, 1, 1, true, true},
+		// The successor is published ahead of signing.
+		{"within overlap", 23*time.Hour + 30*time.Minute, 2, 2, true, true},
+		// A retired key no longer signs but verifies until its tokens expire.
+		{"retired", 24*time.Hour + 30*time.Minute, 2, 2, false, true},
+		{"expired", 25*time.Hour + 30*time.Minute, 1, 1, false, false},
+	}
+	for _, step := range steps {
+		now = start.Add(step.at)
+		if err := r.Refresh(ctx); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if len(store.keys) != step.stored {
+			t.Errorf("%s: stored keys: got %d, want %d", step.name, len(store.keys), step.stored)
+		}
+		if set, _ := r.JWKS(); len(set) != step.published {
+			t.Errorf("%s: published keys: got %d, want %d", step.name, len(set), step.published)
+		}
+		k, err := r.signer(now)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if (k.id == first.id) != step.signsFirst {
+			t.Errorf("%s: signing with the first key: got %v, want %v", step.name, k.id == first.id, step.signsFirst)
+		}
+		if _, ok := r.verifier(first.id, now); ok != step.verifiesFirst {
+			t.Errorf("%s: first key verifies: got %v, want %v", step.name, ok, step.verifiesFirst)
+		}
+	}
+}
+
+func TestRefresh_Errors(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := NewKeyring(&fakeKeyStore{keys: map[string]*models.SigningKey{}}, Schedule{Algorithm: "HS256", Rotation: 24 * time.Hour, Overlap: time.Hour})
+	r.now = func() time.Time { return now }
+
+	if _, err := r.signer(now); err != ErrNoSigningKey {
+		t.Errorf("signer before refresh: got %v, want ErrNoSigningKey", err)
+	}
+	if err := r.Refresh(context.Background()); err == nil {
+		t.Error("expected error for HS256")
+	}
+}
+
+func TestJWKS_Members(t *testing.T) {
+	for _, tc := range []struct {
+		alg, kty, crv string
+		hasY          bool
+	}{
+		{models.SigningAlgorithmEdDSA, "OKP", "Ed25519", false},
+		{models.SigningAlgorithmES256, "EC", "P-256", true},
+	} {
+		t.Run(tc.alg, func(t *testing.T) {
+			r := NewKeyring(&fakeKeyStore{keys: map[string]*models.SigningKey{}}, Schedule{Algorithm: tc.alg, Rotation: 24 * time.Hour, Overlap: time.Hour})
+			if err := r.Refresh(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			set, err := r.JWKS()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(set) != 1 {
+				t.Fatalf("keys: got %d", len(set))
+			}
+			j := set[0]
+			if j.Kty != tc.kty || j.Crv != tc.crv || j.Alg != tc.alg || j.Use != "sig" || j.Kid == "" || j.X == "" {
+				t.Errorf("jwk: %+v", j)
+			}
+			if (j.Y != "") != tc.hasY {
+				t.Errorf("y: %q", j.Y)
+			}
+		})
+	}
+}
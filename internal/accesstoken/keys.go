@@ -0,0 +1,153 @@
+// Package accesstoken mints and verifies short-lived signed access tokens and
+// manages the keys that sign them.
+package accesstoken
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ErrUnsupportedAlgorithm is returned for an algorithm other than EdDSA or ES256.
+var ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+
+// kidBytes is the number of random bytes in a key ID.
+const kidBytes = 12
+
+// key is a parsed SigningKey.
+type key struct {
+	id          string
+	method      jwt.SigningMethod
+	private     crypto.Signer
+	public      crypto.PublicKey
+	activatesAt time.Time
+	retiresAt   time.Time
+	expiresAt   time.Time
+}
+
+// newSigningKey generates a key pair for algorithm, scheduled to sign tokens
+// from activatesAt until retiresAt and published until expiresAt.
+func newSigningKey(algorithm string, activatesAt, retiresAt, expiresAt time.Time) (*models.SigningKey, error) {
+	var private any
+	var public crypto.PublicKey
+	switch algorithm {
+	case models.SigningAlgorithmEdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private, public = priv, pub
+	case models.SigningAlgorithmES256:
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private, public = priv, &priv.PublicKey
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	kid := make([]byte, kidBytes)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+	return &models.SigningKey{
+		ID:          base64.RawURLEncoding.EncodeToString(kid),
+		Algorithm:   algorithm,
+		PrivateKey:  base64.StdEncoding.EncodeToString(privateDER),
+		PublicKey:   base64.StdEncoding.EncodeToString(publicDER),
+		ActivatesAt: activatesAt,
+		RetiresAt:   retiresAt,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// parseSigningKey decodes a stored SigningKey, checking that its key pair
+// matches its algorithm.
+func parseSigningKey(k *models.SigningKey) (*key, error) {
+	privateDER, err := base64.StdEncoding.DecodeString(k.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: decoding private key: %w", k.ID, err)
+	}
+	private, err := x509.ParsePKCS8PrivateKey(privateDER)
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: parsing private key: %w", k.ID, err)
+	}
+
+	parsed := &key{
+		id:          k.ID,
+		activatesAt: k.ActivatesAt,
+		retiresAt:   k.RetiresAt,
+		expiresAt:   k.ExpiresAt,
+	}
+	switch priv := private.(type) {
+	case ed25519.PrivateKey:
+		if k.Algorithm != models.SigningAlgorithmEdDSA {
+			return nil, fmt.Errorf("signing key %s: ed25519 key stored as %s", k.ID, k.Algorithm)
+		}
+		parsed.method = jwt.SigningMethodEdDSA
+		parsed.private, parsed.public = priv, priv.Public()
+	case *ecdsa.PrivateKey:
+		if k.Algorithm != models.SigningAlgorithmES256 || priv.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("signing key %s: ecdsa key stored as %s", k.ID, k.Algorithm)
+		}
+		parsed.method = jwt.SigningMethodES256
+		parsed.private, parsed.public = priv, &priv.PublicKey
+	default:
+		return nil, fmt.Errorf("signing key %s: %w: %T", k.ID, ErrUnsupportedAlgorithm, private)
+	}
+	return parsed, nil
+}
+
+// JWK is the public half of a signing key as a JSON Web Key (RFC 7517).
+// Ed25519 keys have kty OKP (RFC 8037); P-256 keys have kty EC and a Y member.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// jwk returns the public key of k as a JWK.
+func (k *key) jwk() (JWK, error) {
+	j := JWK{Kid: k.id, Use: "sig", Alg: k.method.Alg()}
+	switch pub := k.public.(type) {
+	case ed25519.PublicKey:
+		j.Kty, j.Crv = "OKP", "Ed25519"
+		j.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *ecdsa.PublicKey:
+		point, err := pub.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+		// Uncompressed point: 0x04 || X || Y, each 32 bytes for P-256.
+		b := point.Bytes()
+		j.Kty, j.Crv = "EC", "P-256"
+		j.X = base64.RawURLEncoding.EncodeToString(b[1:33])
+		j.Y = base64.RawURLEncoding.EncodeToString(b[33:])
+	default:
+		return JWK{}, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, k.public)
+	}
+	return j, nil
+}
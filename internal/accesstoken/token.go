@@ -0,0 +1,172 @@
+package accesstoken
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ErrInvalidToken is returned when an access token fails verification.
+var ErrInvalidToken = errors.New("invalid access token")
+
+// clockSkew is the leeway allowed on exp and iat when verifying.
+const clockSkew = 30 * time.Second
+
+// TypSessionToken is the typ header of the access tokens minted for sessions.
+// The same keys sign the ID tokens and access tokens issued to OAuth clients,
+// so Verify requires it to keep those from verifying as session tokens.
+const TypSessionToken = "session+jwt"
+
+// Claims are the claims of an access token.
+type Claims struct {
+	jwt.RegisteredClaims
+	// EmailVerified reports whether the user had verified their email when
+	// the token was minted.
+	EmailVerified bool `json:"email_verified"`
+	// SessionID is the ID of the session the token was exchanged for, so a
+	// resource server can tie the token to a revocable session.
+	SessionID string `json:"sid"`
+}
+
+// Issuer mints access tokens signed by a Keyring and verifies them against
+// the keys it publishes.
+type Issuer struct {
+	keys   *Keyring
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewIssuer returns an Issuer minting tokens with iss set to issuer that
+// expire after ttl.
+func NewIssuer(keys *Keyring, issuer string, ttl time.Duration) *Issuer {
+	return &Issuer{keys: keys, issuer: issuer, ttl: ttl, now: time.Now}
+}
+
+// Name returns the issuer identifier, the iss claim of every token.
+func (i *Issuer) Name() string {
+	return i.issuer
+}
+
+// TTL returns how long minted tokens are valid.
+func (i *Issuer) TTL() time.Duration {
+	return i.ttl
+}
+
+// Mint returns a signed access token for user, bound to the session with ID
+// sessionID, and its expiry.
+func (i *Issuer) Mint(user *models.User, sessionID string) (string, time.Time, error) {
+	now := i.now()
+	jti, err := NewJTI()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := now.Add(i.ttl)
+	signed, err := i.Sign(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.issuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        jti,
+		},
+		EmailVerified: user.EmailVerified,
+		SessionID:     sessionID,
+	}, TypSessionToken)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify checks the signature, issuer, expiry and typ of raw and returns its
+// claims. Session tokens carry no audience; tokens issued to an OAuth client
+// name the client in aud and are rejected.
+func (i *Issuer) Verify(raw string) (*Claims, error) {
+	var claims Claims
+	if err := i.Parse(raw, &claims, TypSessionToken); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	if len(claims.Audience) > 0 {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return &claims, nil
+}
+
+// Sign signs claims with the active key. typ, when set, is the typ header,
+// which keeps a token minted for one purpose from verifying for another.
+func (i *Issuer) Sign(claims jwt.Claims, typ string) (string, error) {
+	k, err := i.keys.signer(i.now())
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.id
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
+	signed, err := token.SignedString(k.private)
+	if err != nil {
+		return "", fmt.Errorf("signing token: %w", err)
+	}
+	return signed, nil
+}
+
+// Parse verifies the signature, issuer, expiry and typ header of raw and
+// decodes it into claims. An empty typ accepts only tokens without a typ
+// other than JWT.
+func (i *Issuer) Parse(raw string, claims jwt.Claims, typ string) error {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{models.SigningAlgorithmEdDSA, models.SigningAlgorithmES256}),
+		jwt.WithIssuer(i.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(i.now),
+	)
+	if _, err := parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		if got, _ := t.Header["typ"].(string); !typMatches(got, typ) {
+			return nil, fmt.Errorf("unexpected typ %q", got)
+		}
+		kid, _ := t.Header["kid"].(string)
+		k, ok := i.keys.verifier(kid, i.now())
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		if t.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("key %q does not sign %s", kid, t.Method.Alg())
+		}
+		return k.public, nil
+	}); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return nil
+}
+
+// typMatches reports whether the typ header got satisfies want, ignoring
+// case and an "application/" prefix as RFC 7515 allows.
+func typMatches(got, want string) bool {
+	got = strings.TrimPrefix(strings.ToLower(got), "application/")
+	if want == "" {
+		return got == "" || got == "jwt"
+	}
+	return got == strings.ToLower(want)
+}
+
+// NewJTI returns a random token ID for the jti claim.
+func NewJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,156 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/zoobzio/sumatra/models"
+)
+
+func TestMintVerify_RoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, alg := range []string{models.SigningAlgorithmEdDSA, models.SigningAlgorithmES256} {
+		t.Run(alg, func(t *testing.T) {
+			r := NewKeyring(&fakeKeyStore{keys: map[string]*models.SigningKey{}}, Schedule{Algorithm: alg, Rotation: 24 * time.Hour, Overlap: time.Hour})
+			r.now = func() time.Time { return now }
+			if err := r.Refresh(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			i := NewIssuer(r, "https://auth.example.com", 5*time.Minute)
+			i.now = func() time.Time { return now }
+			user := &models.User{ID: "u1", EmailVerified: true}
+
+			raw, expiresAt, err := i.Mint(user, "sid1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !expiresAt.Equal(now.Add(5 * time.Minute)) {
+				t.Errorf("expiry: got %v", expiresAt)
+			}
+			claims, err := i.Verify(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Subject != "u1" || !claims.EmailVerified || claims.SessionID != "sid1" || claims.ID == "" {
+				t.Errorf("claims: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	keyring := func() *Keyring {
+		r := NewKeyring(&fakeKeyStore{keys: map[string]*models.SigningKey{}}, Schedule{Algorithm: models.SigningAlgorithmEdDSA, Rotation: 24 * time.Hour, Overlap: time.Hour})
+		r.now = func() time.Time { return now.Add(-time.Hour) }
+		if err := r.Refresh(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	mint := func(r *Keyring, issuer string, at time.Time) string {
+		i := NewIssuer(r, issuer, 5*time.Minute)
+		i.now = func() time.Time { return at }
+		raw, _, err := i.Mint(&models.User{ID: "u1"}, "sid1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+	keys := keyring()
+	i := NewIssuer(keys, "https://auth.example.com", 5*time.Minute)
+	i.now = func() time.Time { return now }
+	tampered := strings.Split(mint(keys, "https://auth.example.com", now), ".")
+	tampered[1] = tampered[1][:len(tampered[1])-2] + "AA"
+	sign := func(claims jwt.RegisteredClaims, typ string) string {
+		raw, err := i.Sign(claims, typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+	claims := jwt.RegisteredClaims{
+		Issuer:    "https://auth.example.com",
+		Subject:   "u1",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}
+	// ID tokens carry a client audience; no typ makes one a session token.
+	idClaims := claims
+	idClaims.Audience = jwt.ClaimStrings{"wiki"}
+
+	for name, raw := range map[string]string{
+		"expired":                   mint(keys, "https://auth.example.com", now.Add(-6*time.Minute)),
+		"wrong issuer":              mint(keys, "https://elsewhere.example.com", now),
+		"unknown key":               mint(keyring(), "https://auth.example.com", now),
+		"tampered":                  strings.Join(tampered, "."),
+		"access token":              sign(claims, "at+jwt"),
+		"id token":                  sign(idClaims, "JWT"),
+		"audience without typ":      sign(idClaims, ""),
+		"audience with session typ": sign(idClaims, TypSessionToken),
+	} {
+		if _, err := i.Verify(raw); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: got %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func TestParse_TypHeader(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := NewKeyring(&fakeKeyStore{keys: map[string]*models.SigningKey{}}, Schedule{Algorithm: models.SigningAlgorithmEdDSA, Rotation: 24 * time.Hour, Overlap: time.Hour})
+	r.now = func() time.Time { return now }
+	if err := r.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	i := NewIssuer(r, "https://auth.example.com", 5*time.Minute)
+	i.now = func() time.Time { return now }
+
+	access, err := i.Sign(jwt.RegisteredClaims{
+		Issuer:    i.Name(),
+		Subject:   "u1",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}, "at+jwt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, _, err := i.Mint(&models.User{ID: "u1"}, "sid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		raw  string
+		ok   bool
+	}{
+		{"access token", access, true},
+		{"session exchange token", session, false},
+	}
+	for _, tt := range tests {
+		if err := i.Parse(tt.raw, &jwt.RegisteredClaims{}, "at+jwt"); (err == nil) != tt.ok {
+			t.Errorf("%s: parsed as at+jwt: got %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestTypMatches(t *testing.T) {
+	for _, tc := range []struct {
+		got, want string
+		ok        bool
+	}{
+		{"", "", true},
+		{"JWT", "", true},
+		{"at+jwt", "", false},
+		{"at+JWT", "at+jwt", true},
+		{"application/at+jwt", "at+jwt", true},
+		{"", "at+jwt", false},
+	} {
+		if got := typMatches(tc.got, tc.want); got != tc.ok {
+			t.Errorf("typMatches(%q, %q) = %v", tc.got, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package authn
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// IPAllowlist is middleware that rejects requests from client IPs outside a
+// set of networks.
+type IPAllowlist struct {
+	networks []*net.IPNet
+	clientIP func(*http.Request) string
+}
+
+// NewIPAllowlist returns an IPAllowlist admitting clients in cidrs, with the
+// client IP of a request resolved by clientIP. An empty cidrs admits every
+// client.
+func NewIPAllowlist(cidrs []string, clientIP func(*http.Request) string) (*IPAllowlist, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing allowed network %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return &IPAllowlist{networks: networks, clientIP: clientIP}, nil
+}
+
+// Allows reports whether a client at ip may connect.
+func (l *IPAllowlist) Allows(ip string) bool {
+	if len(l.networks) == 0 {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range l.networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler responds 403 Forbidden to clients outside the allowlist, before
+// the request is authenticated.
+func (l *IPAllowlist) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allows(l.clientIP(r)) {
+			writeError(w, http.StatusForbidden, `{"code":"FORBIDDEN","message":"client address not allowed"}`)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,71 @@
+package authn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func remoteAddr(r *http.Request) string { return r.RemoteAddr }
+
+func TestIPAllowlist_Allows(t *testing.T) {
+	l, err := NewIPAllowlist([]string{"10.0.0.0/8", "2001:db8::/32"}, remoteAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ip, want := range map[string]bool{
+		"10.1.2.3":    true,
+		"2001:db8::1": true,
+		"192.0.2.1":   false,
+		"2001:db9::1": false,
+		"":            false,
+		"not-an-ip":   false,
+	} {
+		if got := l.Allows(ip); got != want {
+			t.Errorf("%q: got %v want %v", ip, got, want)
+		}
+	}
+}
+
+func TestIPAllowlist_EmptyAllowsAll(t *testing.T) {
+	l, err := NewIPAllowlist(nil, remoteAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Allows("192.0.2.1") {
+		t.Error("an empty allowlist should admit every client")
+	}
+}
+
+func TestIPAllowlist_InvalidCIDR(t *testing.T) {
+	if _, err := NewIPAllowlist([]string{"10.0.0.1"}, remoteAddr); err == nil {
+		t.Error("expected an error for an address without a prefix length")
+	}
+}
+
+func TestIPAllowlist_Handler(t *testing.T) {
+	l, err := NewIPAllowlist([]string{"10.0.0.0/8"}, remoteAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reached := false
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.RemoteAddr = "192.0.2.1"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden || reached {
+		t.Errorf("outside client: status %d, reached %v", w.Code, reached)
+	}
+
+	r.RemoteAddr = "10.0.0.7"
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || !reached {
+		t.Errorf("allowed client: status %d, reached %v", w.Code, reached)
+	}
+}
@@ -0,0 +1,31 @@
+package authn
+
+import (
+	"strings"
+
+	intsession "github.com/zoobzio/sumatra/internal/session"
+)
+
+// APITokenPrefix begins every API token, so secret scanners can recognise a
+// leaked one and requests can tell one from other Bearer tokens.
+const APITokenPrefix = "mpat_"
+
+// displayPrefixLen is how much of a token is stored in the clear to tell
+// tokens apart: the prefix and seven random characters.
+const displayPrefixLen = len(APITokenPrefix) + 7
+
+// GenerateAPIToken returns a new API token and the leading characters of it
+// to store for display.
+func GenerateAPIToken() (token, displayPrefix string, err error) {
+	random, err := intsession.GenerateToken()
+	if err != nil {
+		return "", "", err
+	}
+	token = APITokenPrefix + random
+	return token, token[:displayPrefixLen], nil
+}
+
+// IsAPIToken reports whether s has the form of an API token.
+func IsAPIToken(s string) bool {
+	return strings.HasPrefix(s, APITokenPrefix) && len(s) > displayPrefixLen
+}
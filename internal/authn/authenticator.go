@@ -0,0 +1,109 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/rocco"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Authentication errors.
+var (
+	// ErrUnauthenticated is returned when a request carries no credentials.
+	ErrUnauthenticated = errors.New("authentication required")
+	// ErrInvalidCredentials is returned for an unknown, expired or revoked
+	// session or token.
+	ErrInvalidCredentials = errors.New("invalid or expired credentials")
+	// ErrInsufficientScope is returned when a token's scopes do not allow
+	// the request's method.
+	ErrInsufficientScope = errors.New("token scopes do not allow this request")
+)
+
+// lastUsedInterval bounds how often a token's last-used time is written, so a
+// busy CI job costs one write a minute rather than one per request.
+const lastUsedInterval = time.Minute
+
+// SessionStore is the subset of the sessions store an Authenticator needs.
+type SessionStore interface {
+	Get(ctx context.Context, token string) (*models.Session, error)
+}
+
+// TokenStore is the subset of the API tokens store an Authenticator needs.
+type TokenStore interface {
+	GetByToken(ctx context.Context, raw string) (*models.APIToken, error)
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+// Authenticator authenticates public API requests.
+type Authenticator struct {
+	sessions   SessionStore
+	tokens     TokenStore
+	cookieName string
+	lifetime   intsession.Lifetime
+	now        func() time.Time
+}
+
+// NewAuthenticator returns an Authenticator reading sessions from the cookie
+// cookieName, active under lifetime, and API tokens from tokens.
+func NewAuthenticator(sessions SessionStore, tokens TokenStore, cookieName string, lifetime intsession.Lifetime) *Authenticator {
+	return &Authenticator{sessions: sessions, tokens: tokens, cookieName: cookieName, lifetime: lifetime, now: time.Now}
+}
+
+// Authenticate returns the Identity of r. An Authorization header takes
+// precedence over the session cookie: a request presenting a bad token fails
+// even if it also carries a valid cookie.
+func (a *Authenticator) Authenticate(ctx context.Context, r *http.Request) (rocco.Identity, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		return a.authenticateToken(ctx, r, header)
+	}
+	cookie, err := r.Cookie(a.cookieName)
+	if err != nil || cookie.Value == "" {
+		return nil, ErrUnauthenticated
+	}
+	session, err := a.sessions.Get(ctx, cookie.Value)
+	if err != nil || session == nil || !a.lifetime.Active(session, a.now()) {
+		return nil, ErrInvalidCredentials
+	}
+	return sessionIdentity(session), nil
+}
+
+// authenticateToken authenticates the API token in header and checks its
+// scopes allow r, recording when it was used.
+func (a *Authenticator) authenticateToken(ctx context.Context, r *http.Request, header string) (rocco.Identity, error) {
+	scheme, raw, ok := strings.Cut(header, " ")
+	raw = strings.TrimSpace(raw)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || !IsAPIToken(raw) {
+		return nil, ErrInvalidCredentials
+	}
+	token, err := a.tokens.GetByToken(ctx, raw)
+	now := a.now()
+	if err != nil || token == nil || token.IsExpired(now) {
+		return nil, ErrInvalidCredentials
+	}
+	if !Allows(token, r.Method) {
+		return nil, ErrInsufficientScope
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedInterval {
+		// Best-effort: a failed write only leaves last_used_at stale.
+		_ = a.tokens.TouchLastUsed(ctx, token.ID, now.Truncate(lastUsedInterval))
+	}
+	return tokenIdentity(token), nil
+}
+
+// Allows reports whether token's scopes allow a request with method: read
+// allows safe methods, write allows every method.
+func Allows(token *models.APIToken, method string) bool {
+	if token.HasScope(models.APITokenScopeWrite) {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return token.HasScope(models.APITokenScopeRead)
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+var errNotFound = errors.New("not found")
+
+type fakeSessions map[string]*models.Session
+
+func (f fakeSessions) Get(_ context.Context, token string) (*models.Session, error) {
+	if s, ok := f[token]; ok {
+		return s, nil
+	}
+	return nil, errNotFound
+}
+
+// fakeTokens keys tokens by their raw value.
+type fakeTokens struct {
+	tokens  map[string]*models.APIToken
+	touched []int64
+}
+
+func (f *fakeTokens) GetByToken(_ context.Context, raw string) (*models.APIToken, error) {
+	if t, ok := f.tokens[raw]; ok {
+		c := t.Clone()
+		return &c, nil
+	}
+	return nil, errNotFound
+}
+
+func (f *fakeTokens) TouchLastUsed(_ context.Context, id int64, at time.Time) error {
+	f.touched = append(f.touched, id)
+	for _, t := range f.tokens {
+		if t.ID == id {
+			t.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+const (
+	readToken  = "mpat_read0000000000000000000000000000000000000"
+	writeToken = "mpat_write000000000000000000000000000000000000"
+)
+
+func request(method, cookie, authorization string) *http.Request {
+	r := httptest.NewRequest(method, "/me", nil)
+	if cookie != "" {
+		r.AddCookie(&http.Cookie{Name: "session", Value: cookie})
+	}
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	return r
+}
+
+func TestAuthenticate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	sessions := fakeSessions{
+		"cookie":  {Token: "cookie", TokenHash: "sid1", UserID: "u1", CreatedAt: past, ExpiresAt: now.Add(time.Hour)},
+		"expired": {Token: "expired", TokenHash: "sid2", UserID: "u1", CreatedAt: past, ExpiresAt: past},
+	}
+	tokens := &fakeTokens{tokens: map[string]*models.APIToken{
+		readToken:  {ID: 1, UserID: "u2", Scopes: models.APITokenScopeRead},
+		writeToken: {ID: 2, UserID: "u2", Scopes: models.APITokenScopeWrite},
+		"mpat_expired000000000000000000000000000000000": {ID: 3, UserID: "u2", Scopes: "read", ExpiresAt: &past},
+	}}
+	a := NewAuthenticator(sessions, tokens, "session", intsession.Lifetime{})
+	a.now = func() time.Time { return now }
+
+	tests := []struct {
+		name   string
+		r      *http.Request
+		userID string
+		method Method
+		// tokenID is the API token authenticated, 0 for a session.
+		tokenID int64
+	}{
+		{"session", request(http.MethodPost, "cookie", ""), "u1", MethodSession, 0},
+		{"read token", request(http.MethodGet, "", "Bearer "+readToken), "u2", MethodAPIToken, 1},
+		{"lowercase scheme", request(http.MethodGet, "", "bearer "+readToken), "u2", MethodAPIToken, 1},
+		{"write token on GET", request(http.MethodGet, "", "Bearer "+writeToken), "u2", MethodAPIToken, 2},
+		{"write token on DELETE", request(http.MethodDelete, "", "Bearer "+writeToken), "u2", MethodAPIToken, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := a.Authenticate(context.Background(), tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			identity := id.(*Identity)
+			if identity.ID() != tt.userID || identity.Method() != tt.method || identity.TokenID() != tt.tokenID {
+				t.Errorf("identity: got %+v", identity)
+			}
+			if ViaAPIToken(identity) != (tt.tokenID != 0) {
+				t.Errorf("ViaAPIToken: got %v", ViaAPIToken(identity))
+			}
+			// Sessions have every scope.
+			if tt.tokenID == 0 && (identity.SessionID() != "sid1" || !identity.HasScope(models.APITokenScopeWrite)) {
+				t.Errorf("session identity: got %+v", identity)
+			}
+		})
+	}
+	// Last used is recorded once per interval, not per request.
+	if len(tokens.touched) != 2 {
+		t.Errorf("last used recorded %d times, want once per token", len(tokens.touched))
+	}
+
+	for name, tc := range map[string]struct {
+		r    *http.Request
+		want error
+	}{
+		"no credentials":       {request(http.MethodGet, "", ""), ErrUnauthenticated},
+		"unknown cookie":       {request(http.MethodGet, "nope", ""), ErrInvalidCredentials},
+		"expired session":      {request(http.MethodGet, "expired", ""), ErrInvalidCredentials},
+		"unknown token":        {request(http.MethodGet, "", "Bearer mpat_"+strings.Repeat("x", 43)), ErrInvalidCredentials},
+		"expired token":        {request(http.MethodGet, "", "Bearer mpat_expired000000000000000000000000000000000"), ErrInvalidCredentials},
+		"not a token":          {request(http.MethodGet, "", "Bearer eyJhbGciOiJFZERTQSJ9.e30.sig"), ErrInvalidCredentials},
+		"basic":                {request(http.MethodGet, "", "Basic dTpw"), ErrInvalidCredentials},
+		"read token on DELETE": {request(http.MethodDelete, "", "Bearer "+readToken), ErrInsufficientScope},
+		// A bad token is not rescued by a good cookie.
+		"bad token with cookie": {request(http.MethodGet, "cookie", "Bearer nope"), ErrInvalidCredentials},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := a.Authenticate(context.Background(), tc.r); !errors.Is(err, tc.want) {
+				t.Errorf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_LastUsedAfterInterval(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tokens := &fakeTokens{tokens: map[string]*models.APIToken{
+		readToken: {ID: 1, UserID: "u2", Scopes: models.APITokenScopeRead},
+	}}
+	a := NewAuthenticator(fakeSessions{}, tokens, "session", intsession.Lifetime{})
+	a.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if _, err := a.Authenticate(ctx, request(http.MethodGet, "", "Bearer "+readToken)); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(lastUsedInterval)
+	if _, err := a.Authenticate(ctx, request(http.MethodGet, "", "Bearer "+readToken)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens.touched) != 2 {
+		t.Errorf("last used recorded %d times, want 2", len(tokens.touched))
+	}
+}
+
+func TestGenerateAPIToken(t *testing.T) {
+	token, prefix, err := GenerateAPIToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsAPIToken(token) || !strings.HasPrefix(token, prefix) || len(prefix) != displayPrefixLen {
+		t.Errorf("token %q, prefix %q", token, prefix)
+	}
+	other, _, _ := GenerateAPIToken()
+	if other == token {
+		t.Error("tokens should be random")
+	}
+}
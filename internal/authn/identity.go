@@ -0,0 +1,99 @@
+// Package authn resolves who is making a request: for the public API, the
+// user of the session cookie or of a personal access token sent as a Bearer
+// token; for the admin API, an operator signed in with a session.
+package authn
+
+import (
+	"slices"
+
+	"github.com/zoobzio/rocco"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Method is how a request was authenticated.
+type Method string
+
+// Authentication methods.
+const (
+	MethodSession  Method = "session"
+	MethodAPIToken Method = "api_token"
+)
+
+// Identity is the authenticated user of a request.
+type Identity struct {
+	userID    string
+	method    Method
+	sessionID string
+	tokenID   int64
+	scopes    []string
+	roles     []string
+}
+
+var _ rocco.Identity = (*Identity)(nil)
+
+// ID returns the user ID.
+func (i *Identity) ID() string { return i.userID }
+
+// TenantID returns "": morpheus has a single tenant.
+func (i *Identity) TenantID() string { return "" }
+
+// Email returns "": the user is not loaded to authenticate a request, so
+// handlers that need the email look the user up.
+func (i *Identity) Email() string { return "" }
+
+// Scopes returns the API token scopes the request may use; sessions have all
+// of them. For operators they are the admin permissions their roles grant.
+func (i *Identity) Scopes() []string { return slices.Clone(i.scopes) }
+
+// Roles returns an operator's admin roles. Public API identities have none.
+func (i *Identity) Roles() []string { return slices.Clone(i.roles) }
+
+// HasScope reports whether the request may use scope.
+func (i *Identity) HasScope(scope string) bool { return slices.Contains(i.scopes, scope) }
+
+// HasRole reports whether an operator holds role.
+func (i *Identity) HasRole(role string) bool { return slices.Contains(i.roles, role) }
+
+// Stats returns nil.
+func (i *Identity) Stats() map[string]int { return nil }
+
+// Method returns how the request was authenticated.
+func (i *Identity) Method() Method { return i.method }
+
+// SessionID returns the public ID of the session, for session requests.
+func (i *Identity) SessionID() string { return i.sessionID }
+
+// TokenID returns the ID of the API token, for token requests.
+func (i *Identity) TokenID() int64 { return i.tokenID }
+
+// sessionIdentity returns the Identity of a request made with session s.
+func sessionIdentity(s *models.Session) *Identity {
+	return &Identity{userID: s.UserID, method: MethodSession, sessionID: s.ID(), scopes: models.APITokenScopes}
+}
+
+// operatorIdentity returns the Identity of an operator signed in with session
+// s and holding roles, scoped to the permissions the roles grant.
+func operatorIdentity(s *models.Session, roles []*models.Role) *Identity {
+	i := &Identity{userID: s.UserID, method: MethodSession, sessionID: s.ID(), scopes: []string{}}
+	for _, r := range roles {
+		i.roles = append(i.roles, r.ID)
+		for _, p := range r.PermissionList() {
+			if !slices.Contains(i.scopes, p) {
+				i.scopes = append(i.scopes, p)
+			}
+		}
+	}
+	return i
+}
+
+// tokenIdentity returns the Identity of a request made with API token t.
+func tokenIdentity(t *models.APIToken) *Identity {
+	return &Identity{userID: t.UserID, method: MethodAPIToken, tokenID: t.ID, scopes: t.ScopeList()}
+}
+
+// ViaAPIToken reports whether identity authenticated with an API token.
+// Handlers use it to keep tokens from minting more tokens.
+func ViaAPIToken(identity rocco.Identity) bool {
+	i, ok := identity.(*Identity)
+	return ok && i.method == MethodAPIToken
+}
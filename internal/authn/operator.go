@@ -0,0 +1,122 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zoobzio/rocco"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ErrNotOperator is returned when a signed-in user is not an operator.
+var ErrNotOperator = errors.New("operator access required")
+
+// UserStore is the subset of the users store an OperatorAuthenticator needs.
+type UserStore interface {
+	Get(ctx context.Context, key string) (*models.User, error)
+}
+
+// RoleStore is the subset of the roles store an OperatorAuthenticator needs.
+type RoleStore interface {
+	ListByUser(ctx context.Context, userID string) ([]*models.Role, error)
+}
+
+// OperatorAuthenticator authenticates admin API requests. Operators sign in
+// to the public API like any user and reach the admin API with the same
+// session cookie; only users flagged as admins are let through, with the
+// permissions of their roles as scopes. API tokens are not accepted.
+type OperatorAuthenticator struct {
+	sessions   SessionStore
+	users      UserStore
+	roles      RoleStore
+	cookieName string
+	lifetime   intsession.Lifetime
+	now        func() time.Time
+}
+
+// NewOperatorAuthenticator returns an OperatorAuthenticator reading sessions
+// from the cookie cookieName, active under lifetime.
+func NewOperatorAuthenticator(sessions SessionStore, users UserStore, roles RoleStore, cookieName string, lifetime intsession.Lifetime) *OperatorAuthenticator {
+	return &OperatorAuthenticator{sessions: sessions, users: users, roles: roles, cookieName: cookieName, lifetime: lifetime, now: time.Now}
+}
+
+// operatorKey is the request context key of an operator Identity resolved by
+// OperatorAuthenticator.Handler.
+type operatorKey struct{}
+
+// AdminMiddleware returns the middleware guarding the admin API, in order:
+// clients outside allowlist are refused with 403 before any credentials are
+// read, then requests without an operator session are refused by operators.
+func AdminMiddleware(allowlist *IPAllowlist, operators *OperatorAuthenticator) []func(http.Handler) http.Handler {
+	return []func(http.Handler) http.Handler{allowlist.Handler, operators.Handler}
+}
+
+// Handler authenticates the operator making a request before it reaches the
+// engine, responding 401 Unauthorized without a valid session and 403
+// Forbidden for a signed-in user who is not an operator. The resolved
+// Identity is kept on the request for Authenticate.
+func (a *OperatorAuthenticator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := a.authenticate(r.Context(), r)
+		switch {
+		case errors.Is(err, ErrUnauthenticated), errors.Is(err, ErrInvalidCredentials):
+			writeError(w, http.StatusUnauthorized, `{"code":"UNAUTHORIZED","message":"authentication required"}`)
+		case errors.Is(err, ErrNotOperator):
+			writeError(w, http.StatusForbidden, `{"code":"FORBIDDEN","message":"operator access required"}`)
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, `{"code":"INTERNAL_SERVER_ERROR","message":"internal server error"}`)
+		default:
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), operatorKey{}, id)))
+		}
+	})
+}
+
+// Authenticate returns the Identity of the operator making r, as resolved by
+// Handler when the request passed through it.
+func (a *OperatorAuthenticator) Authenticate(ctx context.Context, r *http.Request) (rocco.Identity, error) {
+	if id, ok := r.Context().Value(operatorKey{}).(*Identity); ok {
+		return id, nil
+	}
+	id, err := a.authenticate(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// authenticate loads the operator making r. The user and their roles are
+// loaded on every request so revoking the admin flag or a role takes effect
+// at once.
+func (a *OperatorAuthenticator) authenticate(ctx context.Context, r *http.Request) (*Identity, error) {
+	cookie, err := r.Cookie(a.cookieName)
+	if err != nil || cookie.Value == "" {
+		return nil, ErrUnauthenticated
+	}
+	session, err := a.sessions.Get(ctx, cookie.Value)
+	if err != nil || session == nil || !a.lifetime.Active(session, a.now()) {
+		return nil, ErrInvalidCredentials
+	}
+	user, err := a.users.Get(ctx, session.UserID)
+	if err != nil || user == nil {
+		return nil, ErrInvalidCredentials
+	}
+	if !user.IsAdmin {
+		return nil, ErrNotOperator
+	}
+	roles, err := a.roles.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("loading roles: %w", err)
+	}
+	return operatorIdentity(session, roles), nil
+}
+
+// writeError writes a JSON error response with status.
+func writeError(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
@@ -0,0 +1,156 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+type fakeUsers map[string]*models.User
+
+func (f fakeUsers) Get(_ context.Context, key string) (*models.User, error) {
+	if u, ok := f[key]; ok {
+		c := u.Clone()
+		return &c, nil
+	}
+	return nil, errNotFound
+}
+
+type fakeRoles map[string][]*models.Role
+
+func (f fakeRoles) ListByUser(_ context.Context, userID string) ([]*models.Role, error) {
+	return f[userID], nil
+}
+
+func TestOperatorAuthenticate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	sessions := fakeSessions{
+		"operator": {Token: "operator", TokenHash: "sid1", UserID: "admin", CreatedAt: past, ExpiresAt: now.Add(time.Hour)},
+		"user":     {Token: "user", TokenHash: "sid2", UserID: "u1", CreatedAt: past, ExpiresAt: now.Add(time.Hour)},
+		"expired":  {Token: "expired", TokenHash: "sid3", UserID: "admin", CreatedAt: past, ExpiresAt: past},
+		"orphan":   {Token: "orphan", TokenHash: "sid4", UserID: "deleted", CreatedAt: past, ExpiresAt: now.Add(time.Hour)},
+		"roleless": {Token: "roleless", TokenHash: "sid5", UserID: "new-admin", CreatedAt: past, ExpiresAt: now.Add(time.Hour)},
+	}
+	users := fakeUsers{
+		"admin":     {ID: "admin", Email: "ops@example.com", IsAdmin: true},
+		"u1":        {ID: "u1", Email: "user@example.com"},
+		"new-admin": {ID: "new-admin", Email: "new-ops@example.com", IsAdmin: true},
+	}
+	roles := fakeRoles{"admin": {
+		{ID: "support", Permissions: "users:read sessions:read sessions:revoke"},
+		{ID: "auditor", Permissions: "users:read"},
+	}}
+	a := NewOperatorAuthenticator(sessions, users, roles, "session", intsession.Lifetime{})
+	a.now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		r         *http.Request
+		want      error
+		sessionID string
+		roles     []string
+		scopes    []string
+	}{
+		{"operator", request(http.MethodDelete, "operator", ""), nil, "sid1", []string{"support", "auditor"},
+			[]string{models.PermissionUsersRead, models.PermissionSessionsRead, models.PermissionSessionsRevoke}},
+		// An operator without roles may sign in but holds no permissions.
+		{"operator without roles", request(http.MethodGet, "roleless", ""), nil, "sid5", nil, nil},
+		{"no credentials", request(http.MethodGet, "", ""), ErrUnauthenticated, "", nil, nil},
+		{"unknown cookie", request(http.MethodGet, "nope", ""), ErrInvalidCredentials, "", nil, nil},
+		{"expired session", request(http.MethodGet, "expired", ""), ErrInvalidCredentials, "", nil, nil},
+		{"deleted user", request(http.MethodGet, "orphan", ""), ErrInvalidCredentials, "", nil, nil},
+		{"not an admin", request(http.MethodGet, "user", ""), ErrNotOperator, "", nil, nil},
+		// API tokens never reach the admin API, even an admin's.
+		{"api token", request(http.MethodGet, "", "Bearer "+writeToken), ErrUnauthenticated, "", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := a.Authenticate(context.Background(), tt.r)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if id != nil {
+					t.Errorf("rejected request got identity %+v", id)
+				}
+				return
+			}
+			if id.(*Identity).SessionID() != tt.sessionID || !slices.Equal(id.Roles(), tt.roles) || !slices.Equal(id.Scopes(), tt.scopes) {
+				t.Errorf("identity: got session %s roles %v permissions %v", id.(*Identity).SessionID(), id.Roles(), id.Scopes())
+			}
+		})
+	}
+
+	// The user is loaded on every request, so revoking the admin flag takes
+	// effect at once.
+	users["admin"].IsAdmin = false
+	if _, err := a.Authenticate(context.Background(), request(http.MethodGet, "operator", "")); !errors.Is(err, ErrNotOperator) {
+		t.Errorf("admin revoked: got %v, want ErrNotOperator", err)
+	}
+}
+
+// TestAdminMiddleware wires the admin API's middleware the way cmd/admin does,
+// in front of a handler authenticating as the engine would.
+func TestAdminMiddleware(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	sessions := fakeSessions{
+		"operator": {Token: "operator", TokenHash: "sid1", UserID: "admin", CreatedAt: past, ExpiresAt: now.Add(time.Hour)},
+		"user":     {Token: "user", TokenHash: "sid2", UserID: "u1", CreatedAt: past, ExpiresAt: now.Add(time.Hour)},
+		"expired":  {Token: "expired", TokenHash: "sid3", UserID: "admin", CreatedAt: past, ExpiresAt: past},
+	}
+	users := fakeUsers{
+		"admin": {ID: "admin", Email: "ops@example.com", IsAdmin: true},
+		"u1":    {ID: "u1", Email: "user@example.com"},
+	}
+	a := NewOperatorAuthenticator(sessions, users, fakeRoles{}, "session", intsession.Lifetime{})
+	a.now = func() time.Time { return now }
+	allowlist, err := NewIPAllowlist([]string{"10.0.0.0/8"}, remoteAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := a.Authenticate(r.Context(), r)
+		if err != nil {
+			t.Errorf("engine authentication after middleware: %v", err)
+			return
+		}
+		_, _ = w.Write([]byte(id.ID()))
+	})
+	mw := AdminMiddleware(allowlist, a)
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+
+	for name, tc := range map[string]struct {
+		addr, cookie string
+		want         int
+	}{
+		"operator":          {"10.0.0.1", "operator", http.StatusOK},
+		"no session":        {"10.0.0.1", "", http.StatusUnauthorized},
+		"expired session":   {"10.0.0.1", "expired", http.StatusUnauthorized},
+		"non-admin session": {"10.0.0.1", "user", http.StatusForbidden},
+		"outside allowlist": {"192.0.2.1", "operator", http.StatusForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := request(http.MethodGet, tc.cookie, "")
+			r.RemoteAddr = tc.addr
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.want {
+				t.Errorf("status: got %d want %d (%s)", w.Code, tc.want, w.Body)
+			}
+			if tc.want == http.StatusOK && w.Body.String() != "admin" {
+				t.Errorf("identity: got %q", w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package authserver
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// codeChallengeLen is the length of a base64url-encoded SHA-256 PKCE challenge.
+const codeChallengeLen = 43
+
+// authorize handles the authorization endpoint. Until the client and redirect
+// URI are verified, errors are returned to the browser; after that they are
+// returned to the client through the redirect URI.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, oauthError(codeInvalidRequest, "malformed request"))
+		return
+	}
+	form := r.Form
+	client, redirectURI, oerr := s.authorizeClient(r, form)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	state := form.Get("state")
+	fail := func(e *Error) {
+		http.Redirect(w, r, errorRedirect(redirectURI, state, s.opts.Issuer.Name(), e), http.StatusFound)
+	}
+
+	request, oerr := s.parseAuthorizeRequest(client, redirectURI, form)
+	if oerr != nil {
+		fail(oerr)
+		return
+	}
+	prompt := strings.Fields(form.Get("prompt"))
+
+	session := s.currentSession(r)
+	if session == nil {
+		if slices.Contains(prompt, "none") {
+			fail(oauthError(codeLoginRequired, "the user is not signed in"))
+			return
+		}
+		http.Redirect(w, r, s.loginURL(form), http.StatusFound)
+		return
+	}
+	request.UserID = session.UserID
+	request.SessionID = session.ID()
+	request.AuthTime = session.CreatedAt
+
+	if !client.SkipConsent && (slices.Contains(prompt, "consent") || !s.hasConsent(r, request)) {
+		if slices.Contains(prompt, "none") {
+			fail(oauthError(codeConsentRequired, "the user has not authorized the client"))
+			return
+		}
+		id, err := intsession.GenerateToken()
+		if err != nil {
+			fail(errServer)
+			return
+		}
+		request.ID = id
+		request.ExpiresAt = request.CreatedAt.Add(s.opts.RequestTTL)
+		if err := s.stores.Authorizations.SetRequest(r.Context(), request, s.opts.RequestTTL); err != nil {
+			fail(errServer)
+			return
+		}
+		http.Redirect(w, r, s.consentURL(id), http.StatusFound)
+		return
+	}
+
+	target, oerr := s.issueCode(r, request)
+	if oerr != nil {
+		fail(oerr)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// authorizeClient resolves the client of an authorization request and the
+// redirect URI to answer it on, which must be registered to the client. It
+// may be omitted when the client has registered only one.
+func (s *Server) authorizeClient(r *http.Request, form url.Values) (*models.OAuthClient, string, *Error) {
+	clientID := form.Get("client_id")
+	if clientID == "" {
+		return nil, "", oauthError(codeInvalidRequest, "client_id is required")
+	}
+	client, err := s.stores.Clients.Get(r.Context(), clientID)
+	if err != nil || client == nil {
+		return nil, "", oauthError(codeInvalidClient, "unknown client")
+	}
+	redirectURI := form.Get("redirect_uri")
+	if redirectURI == "" {
+		if uris := client.RedirectURIList(); len(uris) == 1 {
+			return client, uris[0], nil
+		}
+		return nil, "", oauthError(codeInvalidRequest, "redirect_uri is required")
+	}
+	if !client.AllowsRedirectURI(redirectURI) {
+		return nil, "", oauthError(codeInvalidRequest, "redirect_uri is not registered for the client")
+	}
+	return client, redirectURI, nil
+}
+
+// parseAuthorizeRequest checks an authorization request from a verified
+// client and returns it as an authorization awaiting a user.
+func (s *Server) parseAuthorizeRequest(client *models.OAuthClient, redirectURI string, form url.Values) (*models.OAuthAuthorization, *Error) {
+	if form.Get("response_type") != "code" {
+		return nil, oauthError(codeUnsupportedResponseType, "response_type must be code")
+	}
+	if !client.AllowsGrant(models.GrantTypeAuthorizationCode) {
+		return nil, oauthError(codeUnauthorizedClient, "the client may not use the authorization code grant")
+	}
+	scopes := parseScope(form.Get("scope"))
+	if len(scopes) == 0 {
+		return nil, oauthError(codeInvalidScope, "scope is required")
+	}
+	if !client.AllowsScopes(scopes) {
+		return nil, oauthError(codeInvalidScope, "the client may not request these scopes")
+	}
+	challenge := form.Get("code_challenge")
+	if challenge == "" {
+		return nil, oauthError(codeInvalidRequest, "code_challenge is required")
+	}
+	if form.Get("code_challenge_method") != "S256" {
+		return nil, oauthError(codeInvalidRequest, "code_challenge_method must be S256")
+	}
+	if len(challenge) != codeChallengeLen {
+		return nil, oauthError(codeInvalidRequest, "code_challenge is malformed")
+	}
+	return &models.OAuthAuthorization{
+		ClientID:      client.ID,
+		RedirectURI:   redirectURI,
+		Scope:         strings.Join(scopes, " "),
+		State:         form.Get("state"),
+		Nonce:         form.Get("nonce"),
+		CodeChallenge: challenge,
+		CreatedAt:     s.now(),
+	}, nil
+}
+
+// hasConsent reports whether the user has already granted every scope request asks for.
+func (s *Server) hasConsent(r *http.Request, request *models.OAuthAuthorization) bool {
+	consent, err := s.stores.Consents.GetByUserAndClient(r.Context(), request.UserID, request.ClientID)
+	return err == nil && consent != nil && consent.Covers(strings.Fields(request.Scope))
+}
+
+// issueCode stores an authorization code for request and returns the redirect
+// that delivers it to the client.
+func (s *Server) issueCode(r *http.Request, request *models.OAuthAuthorization) (string, *Error) {
+	code, err := intsession.GenerateToken()
+	if err != nil {
+		return "", errServer
+	}
+	authorization := request.Clone()
+	authorization.ID = ""
+	authorization.Code = code
+	authorization.ExpiresAt = s.now().Add(s.opts.CodeTTL)
+	if err := s.stores.Authorizations.SetCode(r.Context(), &authorization, s.opts.CodeTTL); err != nil {
+		return "", errServer
+	}
+	return withParams(request.RedirectURI, request.State, s.opts.Issuer.Name(), url.Values{"code": {code}}), nil
+}
+
+// loginURL returns the login page, set to return to the authorization
+// request in form once the user has signed in.
+func (s *Server) loginURL(form url.Values) string {
+	return addQuery(s.opts.LoginURL, "return_to", PathAuthorize+"?"+form.Encode())
+}
+
+// consentURL returns the consent screen for the request with ID id.
+func (s *Server) consentURL(id string) string {
+	return addQuery(s.opts.ConsentURL, "request", id)
+}
+
+// addQuery sets key to value in the query of target.
+func addQuery(target, key, value string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// ConsentClient describes the client asking for consent.
+type ConsentClient struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ConsentRequest is the consent API's description of an authorization request.
+type ConsentRequest struct {
+	ID          string        `json:"id"`
+	Client      ConsentClient `json:"client"`
+	Scopes      []string      `json:"scopes"`
+	RedirectURI string        `json:"redirect_uri"`
+}
+
+// ConsentDecision is the body of a consent decision.
+type ConsentDecision struct {
+	Approve bool `json:"approve"`
+}
+
+// ConsentResult is where the browser goes after a consent decision: back to
+// the client with a code or an access_denied error.
+type ConsentResult struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// errRequestNotFound is returned by the consent API for a request that does
+// not exist, has expired, or belongs to another user.
+var errRequestNotFound = &Error{Code: codeInvalidRequest, Description: "authorization request not found", status: http.StatusNotFound}
+
+// pendingRequest returns the authorization request named in the consent API
+// path, provided it belongs to the signed-in user.
+func (s *Server) pendingRequest(r *http.Request) (*models.OAuthAuthorization, *Error) {
+	session := s.currentSession(r)
+	if session == nil {
+		return nil, &Error{Code: codeLoginRequired, Description: "the user is not signed in", status: http.StatusUnauthorized}
+	}
+	request, err := s.stores.Authorizations.GetRequest(r.Context(), r.PathValue("id"))
+	if err != nil || request == nil || request.UserID != session.UserID || !s.now().Before(request.ExpiresAt) {
+		return nil, errRequestNotFound
+	}
+	return request, nil
+}
+
+// getConsent describes an authorization request for the consent screen.
+func (s *Server) getConsent(w http.ResponseWriter, r *http.Request) {
+	request, oerr := s.pendingRequest(r)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	client, err := s.stores.Clients.Get(r.Context(), request.ClientID)
+	if err != nil || client == nil {
+		writeError(w, errRequestNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, ConsentRequest{
+		ID:          request.ID,
+		Client:      ConsentClient{ID: client.ID, Name: client.Name},
+		Scopes:      strings.Fields(request.Scope),
+		RedirectURI: request.RedirectURI,
+	})
+}
+
+// decideConsent records the user's decision on an authorization request and
+// returns where to send the browser. Only JSON bodies are accepted, which a
+// cross-site form cannot send.
+func (s *Server) decideConsent(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeError(w, &Error{Code: codeInvalidRequest, Description: "body must be application/json", status: http.StatusUnsupportedMediaType})
+		return
+	}
+	var decision ConsentDecision
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&decision); err != nil {
+		writeError(w, oauthError(codeInvalidRequest, "malformed body"))
+		return
+	}
+	request, oerr := s.pendingRequest(r)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	if err := s.stores.Authorizations.DeleteRequest(r.Context(), request.ID); err != nil {
+		writeError(w, errServer)
+		return
+	}
+
+	if !decision.Approve {
+		writeJSON(w, http.StatusOK, ConsentResult{RedirectTo: errorRedirect(request.RedirectURI, request.State, s.opts.Issuer.Name(),
+			oauthError(codeAccessDenied, "the user denied the request"))})
+		return
+	}
+	if err := s.stores.Consents.Grant(r.Context(), request.UserID, request.ClientID, strings.Fields(request.Scope)); err != nil {
+		writeError(w, errServer)
+		return
+	}
+	target, oerr := s.issueCode(r, request)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	writeJSON(w, http.StatusOK, ConsentResult{RedirectTo: target})
+}
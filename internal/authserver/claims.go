@@ -0,0 +1,73 @@
+package authserver
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// typAccessToken is the typ header of access tokens issued to clients, as
+// RFC 9068 defines, which keeps them from verifying as ID tokens or as the
+// session access tokens of the public API.
+const typAccessToken = "at+jwt"
+
+// typIDToken is the typ header of ID tokens. OpenID Connect registers no
+// media type for them, so they carry the plain JWT typ; access tokens are kept
+// apart by their own, which an ID token never has.
+const typIDToken = "JWT"
+
+// AccessClaims are the claims of an access token issued to a client.
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope,omitempty"`
+	// AuthTime and SessionID are those of the user's sign-in, absent when the
+	// client acts as itself.
+	AuthTime  *jwt.NumericDate `json:"auth_time,omitempty"`
+	SessionID string           `json:"sid,omitempty"`
+}
+
+// IDClaims are the claims of an ID token.
+type IDClaims struct {
+	jwt.RegisteredClaims
+	AuthTime  *jwt.NumericDate `json:"auth_time,omitempty"`
+	Nonce     string           `json:"nonce,omitempty"`
+	SessionID string           `json:"sid,omitempty"`
+	UserClaims
+}
+
+// UserClaims are the standard claims about a user that the granted scopes
+// release: email and email_verified for email, and name, picture and
+// updated_at for profile.
+type UserClaims struct {
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Picture       string `json:"picture,omitempty"`
+	UpdatedAt     int64  `json:"updated_at,omitempty"`
+}
+
+// UserInfo is the userinfo endpoint response.
+type UserInfo struct {
+	Subject string `json:"sub"`
+	UserClaims
+}
+
+// userClaims returns the claims about user released by scope.
+func userClaims(user *models.User, scope string) UserClaims {
+	var c UserClaims
+	if hasScope(scope, ScopeEmail) {
+		verified := user.EmailVerified
+		c.Email = user.Email
+		c.EmailVerified = &verified
+	}
+	if hasScope(scope, ScopeProfile) {
+		if user.Name != nil {
+			c.Name = *user.Name
+		}
+		if user.AvatarURL != nil {
+			c.Picture = *user.AvatarURL
+		}
+		c.UpdatedAt = user.UpdatedAt.Unix()
+	}
+	return c
+}
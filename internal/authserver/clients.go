@@ -0,0 +1,27 @@
+package authserver
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	intsession "github.com/zoobzio/sumatra/internal/session"
+)
+
+// ClientIDPrefix marks morpheus client IDs.
+const ClientIDPrefix = "mcl_"
+
+// NewClientID returns a random client ID.
+func NewClientID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating client id: %w", err)
+	}
+	return ClientIDPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewClientSecret returns a random client secret. Only its hash is stored,
+// so it is shown once when created.
+func NewClientSecret() (string, error) {
+	return intsession.GenerateToken()
+}
@@ -0,0 +1,68 @@
+package authserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Discovery is the OpenID Provider metadata served at PathDiscovery.
+type Discovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	// AuthorizationResponseIssParameterSupported advertises the iss parameter
+	// of RFC 9207 on authorization responses.
+	AuthorizationResponseIssParameterSupported bool `json:"authorization_response_iss_parameter_supported"`
+}
+
+// Discovery returns the server's provider metadata, with endpoints under the
+// issuer URL.
+func (s *Server) Discovery() Discovery {
+	issuer := s.opts.Issuer.Name()
+	base := strings.TrimSuffix(issuer, "/")
+	return Discovery{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             base + PathAuthorize,
+		TokenEndpoint:                     base + PathToken,
+		UserInfoEndpoint:                  base + PathUserInfo,
+		RevocationEndpoint:                base + PathRevoke,
+		IntrospectionEndpoint:             base + PathIntrospect,
+		JWKSURI:                           base + PathJWKS,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               models.GrantTypes,
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{models.SigningAlgorithmEdDSA, models.SigningAlgorithmES256},
+		ScopesSupported:                   userScopes,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "sid",
+			"email", "email_verified", "name", "picture", "updated_at",
+		},
+		AuthorizationResponseIssParameterSupported: true,
+	}
+}
+
+// discovery serves the provider metadata. Unlike token responses it may be
+// cached.
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(s.Discovery())
+}
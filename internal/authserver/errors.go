@@ -0,0 +1,111 @@
+package authserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// OAuth 2.0 and OpenID Connect error codes.
+const (
+	codeInvalidRequest          = "invalid_request"
+	codeInvalidClient           = "invalid_client"
+	codeInvalidGrant            = "invalid_grant"
+	codeInvalidScope            = "invalid_scope"
+	codeUnauthorizedClient      = "unauthorized_client"
+	codeUnsupportedGrantType    = "unsupported_grant_type"
+	codeUnsupportedResponseType = "unsupported_response_type"
+	codeUnsupportedTokenType    = "unsupported_token_type"
+	codeAccessDenied            = "access_denied"
+	codeServerError             = "server_error"
+	codeInvalidToken            = "invalid_token"
+	codeInsufficientScope       = "insufficient_scope"
+	codeLoginRequired           = "login_required"
+	codeConsentRequired         = "consent_required"
+)
+
+// Error is an OAuth 2.0 error response (RFC 6749 section 5.2), returned in a
+// JSON body or, from the authorization endpoint, in the redirect.
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	status      int
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// oauthError returns an Error with a 400 status.
+func oauthError(code, description string) *Error {
+	return &Error{Code: code, Description: description, status: http.StatusBadRequest}
+}
+
+// errInvalidClient is returned when client authentication fails.
+func errInvalidClient(description string) *Error {
+	return &Error{Code: codeInvalidClient, Description: description, status: http.StatusUnauthorized}
+}
+
+// errServer is returned when a request fails for a reason the client cannot fix.
+var errServer = &Error{Code: codeServerError, Description: "the request could not be completed", status: http.StatusInternalServerError}
+
+// writeJSON writes v with status. Responses carrying tokens must not be
+// cached, so none are.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes e as a JSON error body. A failed client authentication
+// challenges for Basic credentials, as RFC 6749 requires when they were used.
+func writeError(w http.ResponseWriter, e *Error) {
+	if e.status == http.StatusUnauthorized && e.Code == codeInvalidClient {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	writeJSON(w, e.status, e)
+}
+
+// writeBearerError writes e for a protected resource request, with the
+// WWW-Authenticate challenge of RFC 6750.
+func writeBearerError(w http.ResponseWriter, e *Error) {
+	challenge := `Bearer error="` + e.Code + `"`
+	if e.Description != "" {
+		challenge += `, error_description="` + e.Description + `"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	writeJSON(w, e.status, e)
+}
+
+// errorRedirect returns redirectURI with e, state and iss in its query, the
+// authorization endpoint's way of reporting an error to a verified client.
+func errorRedirect(redirectURI, state, issuer string, e *Error) string {
+	params := url.Values{"error": {e.Code}}
+	if e.Description != "" {
+		params.Set("error_description", e.Description)
+	}
+	return withParams(redirectURI, state, issuer, params)
+}
+
+// withParams adds params, state and iss to the query of redirectURI.
+func withParams(redirectURI, state, issuer string, params url.Values) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	if state != "" {
+		q.Set("state", state)
+	}
+	q.Set("iss", issuer)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
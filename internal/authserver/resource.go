@@ -0,0 +1,151 @@
+package authserver
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Introspection is a token introspection response (RFC 7662). Only Active is
+// set for a token that is not active.
+type Introspection struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// bearerToken returns the access token in the Authorization header of r.
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// parseAccessToken verifies an access token issued to a client.
+func (s *Server) parseAccessToken(raw string) (*AccessClaims, bool) {
+	var claims AccessClaims
+	if err := s.opts.Issuer.Parse(raw, &claims, typAccessToken); err != nil || claims.Subject == "" {
+		return nil, false
+	}
+	return &claims, true
+}
+
+// userInfo returns the claims about the user an access token with the openid
+// scope was issued for, as far as its scopes release them.
+func (s *Server) userInfo(w http.ResponseWriter, r *http.Request) {
+	raw := bearerToken(r)
+	if raw == "" {
+		writeBearerError(w, &Error{Code: codeInvalidToken, Description: "an access token is required", status: http.StatusUnauthorized})
+		return
+	}
+	claims, ok := s.parseAccessToken(raw)
+	if !ok {
+		writeBearerError(w, &Error{Code: codeInvalidToken, Description: "the access token is invalid or has expired", status: http.StatusUnauthorized})
+		return
+	}
+	if !hasScope(claims.Scope, ScopeOpenID) {
+		writeBearerError(w, &Error{Code: codeInsufficientScope, Description: "the openid scope is required", status: http.StatusForbidden})
+		return
+	}
+	user, err := s.stores.Users.Get(r.Context(), claims.Subject)
+	if err != nil || user == nil {
+		writeBearerError(w, &Error{Code: codeInvalidToken, Description: "the user no longer exists", status: http.StatusUnauthorized})
+		return
+	}
+	writeJSON(w, http.StatusOK, UserInfo{Subject: user.ID, UserClaims: userClaims(user, claims.Scope)})
+}
+
+// revoke handles token revocation (RFC 7009). Refresh tokens are deleted.
+// Access tokens are short-lived and checked without a lookup, so they cannot
+// be revoked and are answered with unsupported_token_type. Unknown tokens and
+// those of other clients succeed without effect, as the RFC requires.
+func (s *Server) revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, oauthError(codeInvalidRequest, "malformed request"))
+		return
+	}
+	client, oerr := s.authenticateClient(r)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	raw := r.PostForm.Get("token")
+	if raw == "" {
+		writeError(w, oauthError(codeInvalidRequest, "token is required"))
+		return
+	}
+	if _, ok := s.parseAccessToken(raw); ok {
+		writeError(w, oauthError(codeUnsupportedTokenType, "access tokens cannot be revoked"))
+		return
+	}
+	if token, err := s.stores.RefreshTokens.Get(r.Context(), raw); err == nil && token != nil && token.ClientID == client.ID {
+		if err := s.stores.RefreshTokens.Delete(r.Context(), raw); err != nil {
+			writeError(w, errServer)
+			return
+		}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// introspect handles token introspection (RFC 7662) for confidential
+// clients. Any access token can be introspected, so resource servers can
+// check those presented to them; refresh tokens only by their own client.
+func (s *Server) introspect(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, oauthError(codeInvalidRequest, "malformed request"))
+		return
+	}
+	client, oerr := s.authenticateClient(r)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	if client.Public {
+		writeError(w, oauthError(codeUnauthorizedClient, "public clients may not introspect tokens"))
+		return
+	}
+	raw := r.PostForm.Get("token")
+	if raw == "" {
+		writeError(w, oauthError(codeInvalidRequest, "token is required"))
+		return
+	}
+
+	if claims, ok := s.parseAccessToken(raw); ok {
+		writeJSON(w, http.StatusOK, Introspection{
+			Active:    true,
+			Scope:     claims.Scope,
+			ClientID:  claims.ClientID,
+			Subject:   claims.Subject,
+			TokenType: "Bearer",
+			Issuer:    claims.Issuer,
+			Audience:  claims.Audience,
+			IssuedAt:  claims.IssuedAt.Unix(),
+			ExpiresAt: claims.ExpiresAt.Unix(),
+			ID:        claims.ID,
+		})
+		return
+	}
+	token, err := s.stores.RefreshTokens.Get(r.Context(), raw)
+	if err != nil || token == nil || token.ClientID != client.ID || !s.now().Before(token.ExpiresAt) {
+		writeJSON(w, http.StatusOK, Introspection{Active: false})
+		return
+	}
+	writeJSON(w, http.StatusOK, Introspection{
+		Active:    true,
+		Scope:     token.Scope,
+		ClientID:  token.ClientID,
+		Subject:   token.UserID,
+		Issuer:    s.opts.Issuer.Name(),
+		IssuedAt:  token.CreatedAt.Unix(),
+		ExpiresAt: token.ExpiresAt.Unix(),
+	})
+}
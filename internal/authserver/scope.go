@@ -0,0 +1,45 @@
+package authserver
+
+import (
+	"slices"
+	"strings"
+)
+
+// Scopes with meaning to the authorization server itself. Clients may also
+// be allowed scopes of their own, which are passed through to access tokens.
+const (
+	ScopeOpenID        = "openid"
+	ScopeProfile       = "profile"
+	ScopeEmail         = "email"
+	ScopeOfflineAccess = "offline_access"
+)
+
+// userScopes are the scopes that grant access to a user's identity, which a
+// client acting as itself with client_credentials cannot request.
+var userScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopeOfflineAccess}
+
+// parseScope splits a space-separated scope parameter, dropping duplicates.
+func parseScope(raw string) []string {
+	var scopes []string
+	for _, s := range strings.Fields(raw) {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// hasScope reports whether the space-separated scope contains s.
+func hasScope(scope, s string) bool {
+	return slices.Contains(strings.Fields(scope), s)
+}
+
+// isSubset reports whether every scope in scopes is in of.
+func isSubset(scopes, of []string) bool {
+	for _, s := range scopes {
+		if !slices.Contains(of, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+// Package authserver implements an OAuth 2.0 authorization server and OpenID
+// Connect provider, so applications can sign users in with their morpheus
+// accounts. It supports the authorization code grant with PKCE, refresh
+// tokens and client credentials, and serves userinfo, token revocation and
+// introspection, and discovery. Its endpoints speak the form-encoded OAuth
+// protocol directly, so it is mounted as an http.Handler beside the JSON API.
+package authserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/sumatra/internal/accesstoken"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Paths the server handles. Access and ID tokens verify against the JWKS the
+// public API serves at PathJWKS.
+const (
+	PathDiscovery  = "/.well-known/openid-configuration"
+	PathJWKS       = "/.well-known/jwks.json"
+	PathAuthorize  = "/oauth/authorize"
+	PathToken      = "/oauth/token"
+	PathUserInfo   = "/oauth/userinfo"
+	PathRevoke     = "/oauth/revoke"
+	PathIntrospect = "/oauth/introspect"
+	// PathConsent is the consent screen API, followed by the request ID.
+	PathConsent = "/oauth/consent/"
+)
+
+// ClientStore looks up registered clients.
+type ClientStore interface {
+	Get(ctx context.Context, clientID string) (*models.OAuthClient, error)
+}
+
+// ConsentStore records the scopes users have granted clients.
+type ConsentStore interface {
+	GetByUserAndClient(ctx context.Context, userID, clientID string) (*models.OAuthConsent, error)
+	Grant(ctx context.Context, userID, clientID string, scopes []string) error
+}
+
+// AuthorizationStore holds authorization requests awaiting consent and the
+// codes issued for them.
+type AuthorizationStore interface {
+	GetRequest(ctx context.Context, id string) (*models.OAuthAuthorization, error)
+	SetRequest(ctx context.Context, request *models.OAuthAuthorization, ttl time.Duration) error
+	DeleteRequest(ctx context.Context, id string) error
+	SetCode(ctx context.Context, authorization *models.OAuthAuthorization, ttl time.Duration) error
+	// TakeCode returns and deletes the authorization for code atomically.
+	TakeCode(ctx context.Context, code string) (*models.OAuthAuthorization, error)
+}
+
+// RefreshTokenStore holds refresh tokens issued to clients. Tokens of a
+// revoked family are neither returned by Get nor by Take.
+type RefreshTokenStore interface {
+	Get(ctx context.Context, token string) (*models.OAuthRefreshToken, error)
+	Set(ctx context.Context, token *models.OAuthRefreshToken, ttl time.Duration) error
+	// Take returns and deletes a refresh token atomically, remembering its
+	// family for UsedFamily.
+	Take(ctx context.Context, token string) (*models.OAuthRefreshToken, error)
+	// UsedFamily returns the family of a token already taken, or "".
+	UsedFamily(ctx context.Context, token string) (string, error)
+	// RevokeFamily revokes every token of a family, present and future.
+	RevokeFamily(ctx context.Context, family string) error
+	Delete(ctx context.Context, token string) error
+}
+
+// UserStore looks up the users tokens are issued for.
+type UserStore interface {
+	Get(ctx context.Context, key string) (*models.User, error)
+}
+
+// SessionStore looks up the session cookie of the user authorizing a client.
+type SessionStore interface {
+	Get(ctx context.Context, token string) (*models.Session, error)
+}
+
+// SecretHasher derives the at-rest form of a client secret.
+type SecretHasher interface {
+	Hash(secret string) string
+}
+
+// Stores are the stores a Server reads and writes.
+type Stores struct {
+	Clients        ClientStore
+	Consents       ConsentStore
+	Authorizations AuthorizationStore
+	RefreshTokens  RefreshTokenStore
+	Users          UserStore
+	Sessions       SessionStore
+}
+
+// Options configure a Server.
+type Options struct {
+	// Issuer signs access and ID tokens; its name is the issuer identifier.
+	Issuer *accesstoken.Issuer
+	// SecretHasher hashes client secrets to compare with the stored hash.
+	SecretHasher SecretHasher
+	// Lifetime decides whether the user's session is still active.
+	Lifetime intsession.Lifetime
+	// CookieName is the name of the session cookie.
+	CookieName string
+	// LoginURL is where a user without a session is sent, with return_to set
+	// to the authorization request so they come back to it after signing in.
+	LoginURL string
+	// ConsentURL is the consent screen, opened with the request query
+	// parameter naming the request to fetch from the consent API.
+	ConsentURL string
+	// RequestTTL is how long an authorization request awaits consent.
+	RequestTTL time.Duration
+	// CodeTTL is how long an authorization code can be redeemed.
+	CodeTTL time.Duration
+	// RefreshTokenTTL is how long a refresh token is valid. Each use issues a
+	// new token valid for as long again.
+	RefreshTokenTTL time.Duration
+}
+
+// Server is the authorization server.
+type Server struct {
+	stores Stores
+	opts   Options
+	now    func() time.Time
+	mux    *http.ServeMux
+}
+
+// New returns a Server over stores configured by opts.
+func New(stores Stores, opts Options) *Server {
+	s := &Server{stores: stores, opts: opts, now: time.Now}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+PathDiscovery, s.discovery)
+	mux.HandleFunc("GET "+PathAuthorize, s.authorize)
+	mux.HandleFunc("POST "+PathAuthorize, s.authorize)
+	mux.HandleFunc("GET "+PathConsent+"{id}", s.getConsent)
+	mux.HandleFunc("POST "+PathConsent+"{id}", s.decideConsent)
+	mux.HandleFunc("POST "+PathToken, s.token)
+	mux.HandleFunc("GET "+PathUserInfo, s.userInfo)
+	mux.HandleFunc("POST "+PathUserInfo, s.userInfo)
+	mux.HandleFunc("POST "+PathRevoke, s.revoke)
+	mux.HandleFunc("POST "+PathIntrospect, s.introspect)
+	s.mux = mux
+	return s
+}
+
+// ServeHTTP serves the authorization server endpoints.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Mount is middleware that serves the authorization server endpoints and
+// passes every other request to next.
+func (s *Server) Mount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == PathDiscovery || strings.HasPrefix(r.URL.Path, "/oauth/") {
+			s.mux.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// currentSession returns the active session of the cookie on r, or nil.
+func (s *Server) currentSession(r *http.Request) *models.Session {
+	cookie, err := r.Cookie(s.opts.CookieName)
+	if err != nil || cookie.Value == "" {
+		return nil
+	}
+	session, err := s.stores.Sessions.Get(r.Context(), cookie.Value)
+	if err != nil || session == nil || !s.opts.Lifetime.Active(session, s.now()) {
+		return nil
+	}
+	return session
+}
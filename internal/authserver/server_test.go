@@ -0,0 +1,690 @@
+package authserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/internal/accesstoken"
+	intoauth "github.com/zoobzio/sumatra/internal/oauth"
+	"github.com/zoobzio/sumatra/models"
+)
+
+var errNotFound = errors.New("not found")
+
+// memKeys keeps signing keys in memory.
+type memKeys struct {
+	keys map[string]*models.SigningKey
+}
+
+func (m *memKeys) ListUnexpired(_ context.Context, now time.Time) ([]*models.SigningKey, error) {
+	var out []*models.SigningKey
+	for _, k := range m.keys {
+		if !k.IsExpired(now) {
+			c := k.Clone()
+			out = append(out, &c)
+		}
+	}
+	return out, nil
+}
+
+func (m *memKeys) Set(_ context.Context, id string, k *models.SigningKey) error {
+	c := k.Clone()
+	m.keys[id] = &c
+	return nil
+}
+
+func (m *memKeys) DeleteExpired(context.Context, time.Time) error { return nil }
+
+// memStores implements every store the server needs in memory. Codes and
+// refresh tokens are keyed by their raw values.
+type memStores struct {
+	clients   map[string]*models.OAuthClient
+	consents  map[string]*models.OAuthConsent
+	requests  map[string]*models.OAuthAuthorization
+	codes     map[string]*models.OAuthAuthorization
+	refreshes map[string]*models.OAuthRefreshToken
+	used      map[string]string
+	revoked   map[string]bool
+	users     map[string]*models.User
+	sessions  map[string]*models.Session
+}
+
+type (
+	memClients        struct{ *memStores }
+	memConsents       struct{ *memStores }
+	memAuthorizations struct{ *memStores }
+	memRefreshTokens  struct{ *memStores }
+	memUsers          struct{ *memStores }
+	memSessions       struct{ *memStores }
+)
+
+func (m memClients) Get(_ context.Context, id string) (*models.OAuthClient, error) {
+	if c, ok := m.clients[id]; ok {
+		return c, nil
+	}
+	return nil, errNotFound
+}
+
+func (m memConsents) GetByUserAndClient(_ context.Context, userID, clientID string) (*models.OAuthConsent, error) {
+	if c, ok := m.consents[userID+"/"+clientID]; ok {
+		return c, nil
+	}
+	return nil, errNotFound
+}
+
+func (m memConsents) Grant(_ context.Context, userID, clientID string, scopes []string) error {
+	c, ok := m.consents[userID+"/"+clientID]
+	if !ok {
+		c = &models.OAuthConsent{UserID: userID, ClientID: clientID}
+		m.consents[userID+"/"+clientID] = c
+	}
+	c.Grant(scopes)
+	return nil
+}
+
+func (m memAuthorizations) GetRequest(_ context.Context, id string) (*models.OAuthAuthorization, error) {
+	if a, ok := m.requests[id]; ok {
+		c := a.Clone()
+		return &c, nil
+	}
+	return nil, errNotFound
+}
+
+func (m memAuthorizations) SetRequest(_ context.Context, a *models.OAuthAuthorization, _ time.Duration) error {
+	c := a.Clone()
+	m.requests[a.ID] = &c
+	return nil
+}
+
+func (m memAuthorizations) DeleteRequest(_ context.Context, id string) error {
+	delete(m.requests, id)
+	return nil
+}
+
+func (m memAuthorizations) SetCode(_ context.Context, a *models.OAuthAuthorization, _ time.Duration) error {
+	c := a.Clone()
+	m.codes[a.Code] = &c
+	return nil
+}
+
+func (m memAuthorizations) TakeCode(_ context.Context, code string) (*models.OAuthAuthorization, error) {
+	a, ok := m.codes[code]
+	if !ok {
+		return nil, errNotFound
+	}
+	delete(m.codes, code)
+	return a, nil
+}
+
+func (m memRefreshTokens) Get(_ context.Context, token string) (*models.OAuthRefreshToken, error) {
+	if t, ok := m.refreshes[token]; ok && !m.revoked[t.FamilyID] {
+		c := t.Clone()
+		return &c, nil
+	}
+	return nil, errNotFound
+}
+
+func (m memRefreshTokens) Set(_ context.Context, t *models.OAuthRefreshToken, _ time.Duration) error {
+	c := t.Clone()
+	m.refreshes[t.Token] = &c
+	return nil
+}
+
+func (m memRefreshTokens) Take(ctx context.Context, token string) (*models.OAuthRefreshToken, error) {
+	if t, ok := m.refreshes[token]; ok {
+		m.used[token] = t.FamilyID
+	}
+	t, err := m.Get(ctx, token)
+	delete(m.refreshes, token)
+	return t, err
+}
+
+func (m memRefreshTokens) UsedFamily(_ context.Context, token string) (string, error) {
+	return m.used[token], nil
+}
+
+func (m memRefreshTokens) RevokeFamily(_ context.Context, family string) error {
+	m.revoked[family] = true
+	return nil
+}
+
+func (m memRefreshTokens) Delete(_ context.Context, token string) error {
+	delete(m.refreshes, token)
+	return nil
+}
+
+func (m memUsers) Get(_ context.Context, id string) (*models.User, error) {
+	if u, ok := m.users[id]; ok {
+		return u, nil
+	}
+	return nil, errNotFound
+}
+
+func (m memSessions) Get(_ context.Context, token string) (*models.Session, error) {
+	if s, ok := m.sessions[token]; ok {
+		return s, nil
+	}
+	return nil, errNotFound
+}
+
+// prefixHasher stands in for the keyed secret hash.
+type prefixHasher struct{}
+
+func (prefixHasher) Hash(secret string) string { return "hashed:" + secret }
+
+const (
+	testIssuer      = "https://auth.example.com"
+	testRedirectURI = "https://wiki.example.com/callback"
+	testSecret      = "wiki-secret"
+	testSession     = "session-token"
+)
+
+// fixture is a server over in-memory stores with a signed-in user, u1, and
+// two clients: wiki, confidential and allowed every grant, and spa, public.
+type fixture struct {
+	t      *testing.T
+	srv    *httptest.Server
+	mem    *memStores
+	issuer *accesstoken.Issuer
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	keyring := accesstoken.NewKeyring(&memKeys{keys: map[string]*models.SigningKey{}}, accesstoken.Schedule{
+		Algorithm: models.SigningAlgorithmEdDSA, Rotation: 24 * time.Hour, Overlap: time.Hour,
+	})
+	if err := keyring.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	issuer := accesstoken.NewIssuer(keyring, testIssuer, 5*time.Minute)
+
+	name, avatar := "Jane Doe", "https://example.com/jane.png"
+	now := time.Now()
+	mem := &memStores{
+		clients: map[string]*models.OAuthClient{
+			"wiki": {
+				ID: "wiki", Name: "Wiki", SecretHash: "hashed:" + testSecret,
+				RedirectURIs: testRedirectURI,
+				GrantTypes:   "authorization_code refresh_token client_credentials",
+				Scopes:       "openid profile email offline_access wiki:read",
+			},
+			"spa": {
+				ID: "spa", Name: "SPA", Public: true,
+				RedirectURIs: "https://spa.example.com/a https://spa.example.com/b",
+				GrantTypes:   "authorization_code",
+				Scopes:       "openid email",
+			},
+		},
+		consents:  map[string]*models.OAuthConsent{},
+		requests:  map[string]*models.OAuthAuthorization{},
+		codes:     map[string]*models.OAuthAuthorization{},
+		refreshes: map[string]*models.OAuthRefreshToken{},
+		used:      map[string]string{},
+		revoked:   map[string]bool{},
+		users: map[string]*models.User{
+			"u1": {ID: "u1", Email: "jane@example.com", EmailVerified: true, Name: &name, AvatarURL: &avatar, UpdatedAt: now},
+		},
+		sessions: map[string]*models.Session{
+			testSession: {Token: testSession, TokenHash: "sid1", UserID: "u1", CreatedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)},
+		},
+	}
+	server := New(Stores{
+		Clients:        memClients{mem},
+		Consents:       memConsents{mem},
+		Authorizations: memAuthorizations{mem},
+		RefreshTokens:  memRefreshTokens{mem},
+		Users:          memUsers{mem},
+		Sessions:       memSessions{mem},
+	}, Options{
+		Issuer:          issuer,
+		SecretHasher:    prefixHasher{},
+		CookieName:      "session",
+		LoginURL:        "/login",
+		ConsentURL:      "/consent",
+		RequestTTL:      10 * time.Minute,
+		CodeTTL:         time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+	})
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	srv := httptest.NewServer(server.Mount(next))
+	t.Cleanup(srv.Close)
+	return &fixture{t: t, srv: srv, mem: mem, issuer: issuer}
+}
+
+// do sends req without following redirects, with the session cookie when
+// signedIn.
+func (f *fixture) do(req *http.Request, signedIn bool) *http.Response {
+	f.t.Helper()
+	if signedIn {
+		req.AddCookie(&http.Cookie{Name: "session", Value: testSession})
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Do(req)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	f.t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+// authorize sends an authorization request with params over the defaults.
+func (f *fixture) authorize(params url.Values, signedIn bool) *http.Response {
+	f.t.Helper()
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"wiki"},
+		"redirect_uri":          {testRedirectURI},
+		"scope":                 {"openid profile email offline_access"},
+		"state":                 {"xyz"},
+		"nonce":                 {"n-1"},
+		"code_challenge":        {intoauth.CodeChallenge(testVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	for k, v := range params {
+		q[k] = v
+	}
+	req, _ := http.NewRequest(http.MethodGet, f.srv.URL+PathAuthorize+"?"+q.Encode(), nil)
+	return f.do(req, signedIn)
+}
+
+// post sends a form to path, authenticating as wiki with Basic credentials
+// when basic is set.
+func (f *fixture) post(path string, form url.Values, basic bool) *http.Response {
+	f.t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, f.srv.URL+path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if basic {
+		req.SetBasicAuth("wiki", testSecret)
+	}
+	return f.do(req, false)
+}
+
+// consent approves or denies the request a consent redirect names.
+func (f *fixture) consent(location string, approve bool) ConsentResult {
+	f.t.Helper()
+	u, err := url.Parse(location)
+	if err != nil || u.Path != "/consent" {
+		f.t.Fatalf("expected a consent redirect, got %q", location)
+	}
+	id := u.Query().Get("request")
+
+	req, _ := http.NewRequest(http.MethodGet, f.srv.URL+PathConsent+id, nil)
+	resp := f.do(req, true)
+	var described ConsentRequest
+	decode(f.t, resp, http.StatusOK, &described)
+	if described.Client.Name != "Wiki" || len(described.Scopes) != 4 {
+		f.t.Fatalf("consent request: got %+v", described)
+	}
+
+	body, _ := json.Marshal(ConsentDecision{Approve: approve})
+	req, _ = http.NewRequest(http.MethodPost, f.srv.URL+PathConsent+id, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	var result ConsentResult
+	decode(f.t, f.do(req, true), http.StatusOK, &result)
+	return result
+}
+
+// code authorizes wiki, through the consent screen unless the user has
+// already consented, and returns the code.
+func (f *fixture) code() string {
+	f.t.Helper()
+	resp := f.authorize(nil, true)
+	if resp.StatusCode != http.StatusFound {
+		f.t.Fatalf("authorize: got %d", resp.StatusCode)
+	}
+	location := resp.Header.Get("Location")
+	if strings.HasPrefix(location, "/consent?") {
+		location = f.consent(location, true).RedirectTo
+	}
+	return callback(f.t, location).Get("code")
+}
+
+const testVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+func decode(t *testing.T, resp *http.Response, status int, v any) {
+	t.Helper()
+	if resp.StatusCode != status {
+		var body map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		t.Fatalf("status: got %d want %d, body %v", resp.StatusCode, status, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// callback parses a redirect to the wiki's redirect URI.
+func callback(t *testing.T, location string) url.Values {
+	t.Helper()
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme+"://"+u.Host+u.Path != testRedirectURI {
+		t.Fatalf("expected a redirect to the client, got %q", location)
+	}
+	q := u.Query()
+	if q.Get("state") != "xyz" || q.Get("iss") != testIssuer {
+		t.Errorf("state and iss: got %q %q", q.Get("state"), q.Get("iss"))
+	}
+	return q
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	f := newFixture(t)
+	code := f.code()
+	if code == "" {
+		t.Fatal("no code issued")
+	}
+
+	var tokens TokenResponse
+	decode(t, f.post(PathToken, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {testRedirectURI},
+		"code_verifier": {testVerifier},
+	}, true), http.StatusOK, &tokens)
+	if tokens.TokenType != "Bearer" || tokens.ExpiresIn != 300 || tokens.RefreshToken == "" || tokens.IDToken == "" {
+		t.Fatalf("token response: got %+v", tokens)
+	}
+
+	var id IDClaims
+	if err := f.issuer.Parse(tokens.IDToken, &id, typIDToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.issuer.Verify(tokens.IDToken); err == nil {
+		t.Error("ID token verified as a session access token")
+	}
+	if _, err := f.issuer.Verify(tokens.AccessToken); err == nil {
+		t.Error("client access token verified as a session access token")
+	}
+	if id.Subject != "u1" || id.Audience[0] != "wiki" || id.Nonce != "n-1" || id.SessionID != "sid1" ||
+		id.Email != "jane@example.com" || id.Name != "Jane Doe" || id.EmailVerified == nil || !*id.EmailVerified {
+		t.Errorf("ID token claims: got %+v", id)
+	}
+	if err := f.issuer.Parse(tokens.AccessToken, &id, typIDToken); err == nil {
+		t.Error("access token verified as an ID token")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, f.srv.URL+PathUserInfo, nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	var info UserInfo
+	decode(t, f.do(req, false), http.StatusOK, &info)
+	if info.Subject != "u1" || info.Email != "jane@example.com" || info.Picture != "https://example.com/jane.png" {
+		t.Errorf("userinfo: got %+v", info)
+	}
+
+	var refreshed TokenResponse
+	decode(t, f.post(PathToken, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {tokens.RefreshToken},
+		"scope":         {"openid email"},
+	}, true), http.StatusOK, &refreshed)
+	if refreshed.Scope != "openid email" || refreshed.RefreshToken == "" || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatalf("refresh response: got %+v", refreshed)
+	}
+	if got := f.mem.refreshes[refreshed.RefreshToken].Scope; got != "openid profile email offline_access" {
+		t.Errorf("rotated refresh token scope: got %q", got)
+	}
+	if f.mem.refreshes[refreshed.RefreshToken].FamilyID == "" || f.mem.refreshes[refreshed.RefreshToken].FamilyID != f.mem.used[tokens.RefreshToken] {
+		t.Error("rotated refresh token left its family")
+	}
+	resp := f.post(PathToken, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {tokens.RefreshToken}}, true)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("reused refresh token: got %d", resp.StatusCode)
+	}
+
+	var active Introspection
+	decode(t, f.post(PathIntrospect, url.Values{"token": {refreshed.AccessToken}}, true), http.StatusOK, &active)
+	if !active.Active || active.Subject != "u1" || active.ClientID != "wiki" || active.Scope != "openid email" {
+		t.Errorf("introspection: got %+v", active)
+	}
+
+	if resp := f.post(PathRevoke, url.Values{"token": {refreshed.RefreshToken}}, true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("revoke: got %d", resp.StatusCode)
+	}
+	var revoked Introspection
+	decode(t, f.post(PathIntrospect, url.Values{"token": {refreshed.RefreshToken}}, true), http.StatusOK, &revoked)
+	if revoked.Active {
+		t.Error("revoked refresh token is still active")
+	}
+	if resp := f.post(PathRevoke, url.Values{"token": {refreshed.AccessToken}}, true); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("revoking an access token: got %d, want unsupported_token_type", resp.StatusCode)
+	}
+}
+
+func TestToken_RefreshReuseRevokesFamily(t *testing.T) {
+	f := newFixture(t)
+	var tokens TokenResponse
+	decode(t, f.post(PathToken, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {f.code()},
+		"redirect_uri":  {testRedirectURI},
+		"code_verifier": {testVerifier},
+	}, true), http.StatusOK, &tokens)
+
+	var rotated TokenResponse
+	decode(t, f.post(PathToken, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {tokens.RefreshToken}}, true), http.StatusOK, &rotated)
+
+	if resp := f.post(PathToken, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {tokens.RefreshToken}}, true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("replayed refresh token: got %d", resp.StatusCode)
+	}
+	if resp := f.post(PathToken, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {rotated.RefreshToken}}, true); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("live token of a replayed family: got %d, want it revoked", resp.StatusCode)
+	}
+	var introspected Introspection
+	decode(t, f.post(PathIntrospect, url.Values{"token": {rotated.RefreshToken}}, true), http.StatusOK, &introspected)
+	if introspected.Active {
+		t.Error("live token of a replayed family is still active")
+	}
+}
+
+func TestAuthorize_RemembersConsent(t *testing.T) {
+	f := newFixture(t)
+	f.code()
+
+	resp := f.authorize(url.Values{"scope": {"openid email"}}, true)
+	if q := callback(t, resp.Header.Get("Location")); q.Get("code") == "" {
+		t.Errorf("expected a code without consent, got %v", q)
+	}
+	resp = f.authorize(url.Values{"prompt": {"consent"}}, true)
+	if !strings.HasPrefix(resp.Header.Get("Location"), "/consent?") {
+		t.Errorf("prompt=consent: got %q", resp.Header.Get("Location"))
+	}
+}
+
+func TestAuthorize_ConsentDenied(t *testing.T) {
+	f := newFixture(t)
+	resp := f.authorize(nil, true)
+	result := f.consent(resp.Header.Get("Location"), false)
+	if q := callback(t, result.RedirectTo); q.Get("error") != codeAccessDenied {
+		t.Errorf("error: got %q", q.Get("error"))
+	}
+	if len(f.mem.consents) != 0 {
+		t.Error("consent recorded on denial")
+	}
+}
+
+func TestAuthorize_RedirectsToLogin(t *testing.T) {
+	f := newFixture(t)
+	resp := f.authorize(nil, false)
+	u, _ := url.Parse(resp.Header.Get("Location"))
+	if resp.StatusCode != http.StatusFound || u.Path != "/login" || !strings.HasPrefix(u.Query().Get("return_to"), PathAuthorize+"?") {
+		t.Errorf("got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	resp = f.authorize(url.Values{"prompt": {"none"}}, false)
+	if q := callback(t, resp.Header.Get("Location")); q.Get("error") != codeLoginRequired {
+		t.Errorf("prompt=none: got %v", q)
+	}
+}
+
+func TestAuthorize_Errors(t *testing.T) {
+	f := newFixture(t)
+	for name, tc := range map[string]struct {
+		params url.Values
+		status int
+		code   string
+	}{
+		"unknown client":        {url.Values{"client_id": {"nope"}}, http.StatusBadRequest, codeInvalidClient},
+		"unregistered redirect": {url.Values{"redirect_uri": {"https://evil.example.com/cb"}}, http.StatusBadRequest, codeInvalidRequest},
+		"ambiguous redirect":    {url.Values{"client_id": {"spa"}, "redirect_uri": {""}}, http.StatusBadRequest, codeInvalidRequest},
+		"response type":         {url.Values{"response_type": {"token"}}, http.StatusFound, codeUnsupportedResponseType},
+		"scope":                 {url.Values{"scope": {"openid admin"}}, http.StatusFound, codeInvalidScope},
+		"no pkce":               {url.Values{"code_challenge": {""}}, http.StatusFound, codeInvalidRequest},
+		"plain pkce":            {url.Values{"code_challenge_method": {"plain"}}, http.StatusFound, codeInvalidRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := f.authorize(tc.params, true)
+			if resp.StatusCode != tc.status {
+				t.Fatalf("status: got %d want %d", resp.StatusCode, tc.status)
+			}
+			var got string
+			if tc.status == http.StatusFound {
+				got = callback(t, resp.Header.Get("Location")).Get("error")
+			} else {
+				var e Error
+				decode(t, resp, tc.status, &e)
+				got = e.Code
+			}
+			if got != tc.code {
+				t.Errorf("error: got %q want %q", got, tc.code)
+			}
+		})
+	}
+}
+
+func TestToken_CodeErrors(t *testing.T) {
+	f := newFixture(t)
+	exchange := func(code string, params url.Values) *http.Response {
+		form := url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {testRedirectURI},
+			"code_verifier": {testVerifier},
+		}
+		for k, v := range params {
+			form[k] = v
+		}
+		return f.post(PathToken, form, true)
+	}
+	expect := func(t *testing.T, resp *http.Response, status int, code string) {
+		t.Helper()
+		var e Error
+		decode(t, resp, status, &e)
+		if e.Code != code {
+			t.Errorf("error: got %q want %q", e.Code, code)
+		}
+	}
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		expect(t, exchange(f.code(), url.Values{"code_verifier": {strings.Repeat("a", 43)}}), http.StatusBadRequest, codeInvalidGrant)
+	})
+	t.Run("redirect mismatch", func(t *testing.T) {
+		expect(t, exchange(f.code(), url.Values{"redirect_uri": {"https://wiki.example.com/other"}}), http.StatusBadRequest, codeInvalidGrant)
+	})
+	t.Run("reused code", func(t *testing.T) {
+		code := f.code()
+		if resp := exchange(code, nil); resp.StatusCode != http.StatusOK {
+			t.Fatalf("first exchange: got %d", resp.StatusCode)
+		}
+		expect(t, exchange(code, nil), http.StatusBadRequest, codeInvalidGrant)
+	})
+	t.Run("wrong secret", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, f.srv.URL+PathToken, strings.NewReader("grant_type=client_credentials"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("wiki", "guess")
+		resp := f.do(req, false)
+		if resp.Header.Get("WWW-Authenticate") == "" {
+			t.Error("missing Basic challenge")
+		}
+		expect(t, resp, http.StatusUnauthorized, codeInvalidClient)
+	})
+	t.Run("public client with secret", func(t *testing.T) {
+		expect(t, f.post(PathToken, url.Values{"grant_type": {"authorization_code"}, "client_id": {"spa"}, "client_secret": {"x"}}, false),
+			http.StatusUnauthorized, codeInvalidClient)
+	})
+	t.Run("grant not allowed", func(t *testing.T) {
+		expect(t, f.post(PathToken, url.Values{"grant_type": {"client_credentials"}, "client_id": {"spa"}}, false),
+			http.StatusBadRequest, codeUnauthorizedClient)
+	})
+	t.Run("unsupported grant", func(t *testing.T) {
+		expect(t, f.post(PathToken, url.Values{"grant_type": {"password"}}, true), http.StatusBadRequest, codeUnsupportedGrantType)
+	})
+}
+
+func TestToken_ClientCredentials(t *testing.T) {
+	f := newFixture(t)
+	var tokens TokenResponse
+	decode(t, f.post(PathToken, url.Values{
+		"grant_type": {"client_credentials"}, "client_id": {"wiki"}, "client_secret": {testSecret},
+	}, false), http.StatusOK, &tokens)
+	if tokens.Scope != "wiki:read" || tokens.RefreshToken != "" || tokens.IDToken != "" {
+		t.Errorf("token response: got %+v", tokens)
+	}
+	var claims AccessClaims
+	if err := f.issuer.Parse(tokens.AccessToken, &claims, typAccessToken); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "wiki" || claims.ClientID != "wiki" || claims.AuthTime != nil {
+		t.Errorf("claims: got %+v", claims)
+	}
+
+	resp := f.post(PathToken, url.Values{"grant_type": {"client_credentials"}, "scope": {"openid"}}, true)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("user scope: got %d", resp.StatusCode)
+	}
+}
+
+func TestUserInfo_RejectsSessionAccessTokens(t *testing.T) {
+	f := newFixture(t)
+	token, _, err := f.issuer.Mint(f.mem.users["u1"], "sid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, f.srv.URL+PathUserInfo, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if resp := f.do(req, false); resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+		t.Errorf("got %d %q", resp.StatusCode, resp.Header.Get("WWW-Authenticate"))
+	}
+}
+
+func TestConsent_RequiresOwner(t *testing.T) {
+	f := newFixture(t)
+	resp := f.authorize(nil, true)
+	u, _ := url.Parse(resp.Header.Get("Location"))
+	id := u.Query().Get("request")
+
+	f.mem.sessions["other"] = &models.Session{Token: "other", TokenHash: "sid2", UserID: "u2", ExpiresAt: time.Now().Add(time.Hour)}
+	req, _ := http.NewRequest(http.MethodGet, f.srv.URL+PathConsent+id, nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "other"})
+	if resp := f.do(req, false); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("another user's request: got %d", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, f.srv.URL+PathConsent+id, strings.NewReader("approve=true"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if resp := f.do(req, true); resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("form-encoded decision: got %d", resp.StatusCode)
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	f := newFixture(t)
+	req, _ := http.NewRequest(http.MethodGet, f.srv.URL+PathDiscovery, nil)
+	var d Discovery
+	decode(t, f.do(req, false), http.StatusOK, &d)
+	if d.Issuer != testIssuer || d.TokenEndpoint != testIssuer+PathToken || d.JWKSURI != testIssuer+PathJWKS {
+		t.Errorf("discovery: got %+v", d)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, f.srv.URL+"/me", nil)
+	if resp := f.do(req, false); resp.StatusCode != http.StatusTeapot {
+		t.Errorf("other paths should reach the next handler, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,326 @@
+package authserver
+
+import (
+	"crypto/hmac"
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/zoobzio/sumatra/internal/accesstoken"
+	intoauth "github.com/zoobzio/sumatra/internal/oauth"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// TokenResponse is a successful token endpoint response.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// grant is what a client is issued tokens for.
+type grant struct {
+	// user is the user who authorized the client, or nil when the client acts
+	// as itself.
+	user *models.User
+	// scope is the scope of the access token, and refreshScope that of the
+	// refresh token, which a refresh may not narrow.
+	scope        string
+	refreshScope string
+	authTime     time.Time
+	sessionID    string
+	nonce        string
+	// family is the family of the refresh token redeemed, which a new
+	// refresh token joins; empty starts a new family.
+	family string
+}
+
+// token handles the token endpoint.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, oauthError(codeInvalidRequest, "malformed request"))
+		return
+	}
+	client, oerr := s.authenticateClient(r)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+
+	grantType := r.PostForm.Get("grant_type")
+	if grantType == "" {
+		writeError(w, oauthError(codeInvalidRequest, "grant_type is required"))
+		return
+	}
+	if !slices.Contains(models.GrantTypes, grantType) {
+		writeError(w, oauthError(codeUnsupportedGrantType, "unsupported grant_type"))
+		return
+	}
+	if !client.AllowsGrant(grantType) {
+		writeError(w, oauthError(codeUnauthorizedClient, "the client may not use this grant type"))
+		return
+	}
+
+	var g *grant
+	switch grantType {
+	case models.GrantTypeAuthorizationCode:
+		g, oerr = s.redeemCode(r, client)
+	case models.GrantTypeRefreshToken:
+		g, oerr = s.redeemRefreshToken(r, client)
+	case models.GrantTypeClientCredentials:
+		g, oerr = s.clientCredentials(r, client)
+	}
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	resp, oerr := s.issueTokens(r, client, g)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// authenticateClient authenticates the client of a token, revocation or
+// introspection request, by HTTP Basic credentials or client_id and
+// client_secret in the body. Public clients send only their client_id.
+func (s *Server) authenticateClient(r *http.Request) (*models.OAuthClient, *Error) {
+	clientID, secret := r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	if user, pass, ok := r.BasicAuth(); ok {
+		if secret != "" {
+			return nil, oauthError(codeInvalidRequest, "use only one client authentication method")
+		}
+		// RFC 6749 section 2.3.1 form-encodes the credentials before Basic encoding.
+		basicID, err1 := url.QueryUnescape(user)
+		basicSecret, err2 := url.QueryUnescape(pass)
+		if err1 != nil || err2 != nil {
+			return nil, errInvalidClient("malformed client credentials")
+		}
+		if clientID != "" && clientID != basicID {
+			return nil, oauthError(codeInvalidRequest, "client_id does not match the authenticated client")
+		}
+		clientID, secret = basicID, basicSecret
+	}
+	if clientID == "" {
+		return nil, errInvalidClient("client authentication is required")
+	}
+
+	client, err := s.stores.Clients.Get(r.Context(), clientID)
+	if err != nil || client == nil {
+		return nil, errInvalidClient("client authentication failed")
+	}
+	if client.Public {
+		if secret != "" {
+			return nil, errInvalidClient("client authentication failed")
+		}
+		return client, nil
+	}
+	if secret == "" || !hmac.Equal([]byte(s.opts.SecretHasher.Hash(secret)), []byte(client.SecretHash)) {
+		return nil, errInvalidClient("client authentication failed")
+	}
+	return client, nil
+}
+
+// redeemCode handles the authorization_code grant. The code is deleted as it
+// is read, so it is redeemed at most once even if the request fails.
+func (s *Server) redeemCode(r *http.Request, client *models.OAuthClient) (*grant, *Error) {
+	code := r.PostForm.Get("code")
+	if code == "" {
+		return nil, oauthError(codeInvalidRequest, "code is required")
+	}
+	verifier := r.PostForm.Get("code_verifier")
+	if verifier == "" {
+		return nil, oauthError(codeInvalidRequest, "code_verifier is required")
+	}
+	authorization, err := s.stores.Authorizations.TakeCode(r.Context(), code)
+	if err != nil || authorization == nil || authorization.ClientID != client.ID || !s.now().Before(authorization.ExpiresAt) {
+		return nil, oauthError(codeInvalidGrant, "the code is invalid or has expired")
+	}
+	if redirectURI := r.PostForm.Get("redirect_uri"); redirectURI != authorization.RedirectURI {
+		// A redirect URI left out of the authorization request, because the
+		// client has only one, may be left out here too.
+		if redirectURI != "" || len(client.RedirectURIList()) != 1 {
+			return nil, oauthError(codeInvalidGrant, "redirect_uri does not match the authorization request")
+		}
+	}
+	if subtle.ConstantTimeCompare([]byte(intoauth.CodeChallenge(verifier)), []byte(authorization.CodeChallenge)) != 1 {
+		return nil, oauthError(codeInvalidGrant, "code_verifier does not match the code challenge")
+	}
+	user, err := s.stores.Users.Get(r.Context(), authorization.UserID)
+	if err != nil || user == nil {
+		return nil, oauthError(codeInvalidGrant, "the user no longer exists")
+	}
+	return &grant{
+		user:         user,
+		scope:        authorization.Scope,
+		refreshScope: authorization.Scope,
+		authTime:     authorization.AuthTime,
+		sessionID:    authorization.SessionID,
+		nonce:        authorization.Nonce,
+	}, nil
+}
+
+// redeemRefreshToken handles the refresh_token grant. The token is replaced
+// by a new one of the same family on every use, so a stolen token stops
+// working once either party uses it, and a token used again after that
+// revokes the family, cutting off whichever party holds the live token. A
+// narrower scope may be requested for the access token.
+func (s *Server) redeemRefreshToken(r *http.Request, client *models.OAuthClient) (*grant, *Error) {
+	raw := r.PostForm.Get("refresh_token")
+	if raw == "" {
+		return nil, oauthError(codeInvalidRequest, "refresh_token is required")
+	}
+	token, err := s.stores.RefreshTokens.Take(r.Context(), raw)
+	if err != nil || token == nil {
+		if family, err := s.stores.RefreshTokens.UsedFamily(r.Context(), raw); err == nil && family != "" {
+			_ = s.stores.RefreshTokens.RevokeFamily(r.Context(), family)
+		}
+		return nil, oauthError(codeInvalidGrant, "the refresh token is invalid or has expired")
+	}
+	if token.ClientID != client.ID || !s.now().Before(token.ExpiresAt) {
+		return nil, oauthError(codeInvalidGrant, "the refresh token is invalid or has expired")
+	}
+	granted := strings.Fields(token.Scope)
+	if !client.AllowsScopes(granted) {
+		return nil, oauthError(codeInvalidScope, "the client may no longer use these scopes")
+	}
+	scope := token.Scope
+	if requested := parseScope(r.PostForm.Get("scope")); len(requested) > 0 {
+		if !isSubset(requested, granted) {
+			return nil, oauthError(codeInvalidScope, "scope exceeds the scope originally granted")
+		}
+		scope = strings.Join(requested, " ")
+	}
+	user, err := s.stores.Users.Get(r.Context(), token.UserID)
+	if err != nil || user == nil {
+		return nil, oauthError(codeInvalidGrant, "the user no longer exists")
+	}
+	return &grant{user: user, scope: scope, refreshScope: token.Scope, authTime: token.AuthTime, family: token.FamilyID}, nil
+}
+
+// clientCredentials handles the client_credentials grant, in which a
+// confidential client acts as itself and may not request user scopes. An
+// omitted scope grants every scope the client is allowed.
+func (s *Server) clientCredentials(r *http.Request, client *models.OAuthClient) (*grant, *Error) {
+	if client.Public {
+		return nil, oauthError(codeUnauthorizedClient, "public clients may not use client_credentials")
+	}
+	scopes := parseScope(r.PostForm.Get("scope"))
+	if len(scopes) == 0 {
+		for _, scope := range client.ScopeList() {
+			if !slices.Contains(userScopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	for _, scope := range scopes {
+		if slices.Contains(userScopes, scope) {
+			return nil, oauthError(codeInvalidScope, "user scopes require a user")
+		}
+	}
+	if !client.AllowsScopes(scopes) {
+		return nil, oauthError(codeInvalidScope, "the client may not request these scopes")
+	}
+	return &grant{scope: strings.Join(scopes, " ")}, nil
+}
+
+// issueTokens issues an access token for g, an ID token when the openid
+// scope is granted, and a refresh token when offline_access is granted to a
+// client allowed the refresh_token grant.
+func (s *Server) issueTokens(r *http.Request, client *models.OAuthClient, g *grant) (*TokenResponse, *Error) {
+	issuer := s.opts.Issuer
+	now := s.now()
+	expiresAt := now.Add(issuer.TTL())
+
+	jti, err := accesstoken.NewJTI()
+	if err != nil {
+		return nil, errServer
+	}
+	access := AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer.Name(),
+			Subject:   client.ID,
+			Audience:  jwt.ClaimStrings{client.ID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        jti,
+		},
+		ClientID: client.ID,
+		Scope:    g.scope,
+	}
+	if g.user != nil {
+		access.Subject = g.user.ID
+		access.AuthTime = jwt.NewNumericDate(g.authTime)
+		access.SessionID = g.sessionID
+	}
+	signed, err := issuer.Sign(access, typAccessToken)
+	if err != nil {
+		return nil, errServer
+	}
+	resp := &TokenResponse{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(issuer.TTL().Seconds()),
+		Scope:       g.scope,
+	}
+	if g.user == nil {
+		return resp, nil
+	}
+
+	if hasScope(g.scope, ScopeOpenID) {
+		idToken, err := issuer.Sign(IDClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    issuer.Name(),
+				Subject:   g.user.ID,
+				Audience:  jwt.ClaimStrings{client.ID},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+			AuthTime:   jwt.NewNumericDate(g.authTime),
+			Nonce:      g.nonce,
+			SessionID:  g.sessionID,
+			UserClaims: userClaims(g.user, g.scope),
+		}, typIDToken)
+		if err != nil {
+			return nil, errServer
+		}
+		resp.IDToken = idToken
+	}
+
+	if hasScope(g.refreshScope, ScopeOfflineAccess) && client.AllowsGrant(models.GrantTypeRefreshToken) {
+		raw, err := intsession.GenerateToken()
+		if err != nil {
+			return nil, errServer
+		}
+		family := g.family
+		if family == "" {
+			if family, err = intsession.GenerateToken(); err != nil {
+				return nil, errServer
+			}
+		}
+		ttl := s.opts.RefreshTokenTTL
+		if err := s.stores.RefreshTokens.Set(r.Context(), &models.OAuthRefreshToken{
+			Token:     raw,
+			ClientID:  client.ID,
+			UserID:    g.user.ID,
+			Scope:     g.refreshScope,
+			FamilyID:  family,
+			AuthTime:  g.authTime,
+			ExpiresAt: now.Add(ttl),
+			CreatedAt: now,
+		}, ttl); err != nil {
+			return nil, errServer
+		}
+		resp.RefreshToken = raw
+	}
+	return resp, nil
+}
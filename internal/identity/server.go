@@ -3,9 +3,11 @@ package identity
 
 import (
 	"context"
+	"time"
 
 	"github.com/zoobzio/aegis/proto/identity"
 	"github.com/zoobzio/sumatra/api/contracts"
+	intsession "github.com/zoobzio/sumatra/internal/session"
 )
 
 // Server implements identity.IdentityServiceServer.
@@ -14,25 +16,37 @@ type Server struct {
 	users     contracts.Users
 	sessions  contracts.Sessions
 	providers contracts.Providers
+	lifetime  intsession.Lifetime
 }
 
-// New creates a new identity server.
-func New(users contracts.Users, sessions contracts.Sessions, providers contracts.Providers) *Server {
+// New creates a new identity server. Sessions are validated and extended
+// under lifetime, the same rules the public API applies.
+func New(users contracts.Users, sessions contracts.Sessions, providers contracts.Providers, lifetime intsession.Lifetime) *Server {
 	return &Server{
 		users:     users,
 		sessions:  sessions,
 		providers: providers,
+		lifetime:  lifetime,
 	}
 }
 
-// ValidateSession checks if a session token is valid.
+// ValidateSession checks if a session token is valid. A valid session counts
+// as activity: its idle expiry slides forward, at most once per interval.
 func (s *Server) ValidateSession(ctx context.Context, req *identity.ValidateSessionRequest) (*identity.ValidateSessionResponse, error) {
 	session, err := s.sessions.Get(ctx, req.Token)
 	if err != nil || session == nil {
 		return &identity.ValidateSessionResponse{Valid: false}, nil
 	}
+	now := time.Now()
+	if !s.lifetime.Active(session, now) {
+		return &identity.ValidateSessionResponse{Valid: false, UserId: session.UserID, ExpiresAt: session.ExpiresAt.Unix()}, nil
+	}
+	if s.lifetime.Touch(session, now) {
+		// Best-effort: a failed write leaves the previous expiry in place.
+		_ = s.sessions.SetWithUserIndex(ctx, session, session.ExpiresAt.Sub(now))
+	}
 	return &identity.ValidateSessionResponse{
-		Valid:     !session.IsExpired(),
+		Valid:     true,
 		UserId:    session.UserID,
 		ExpiresAt: session.ExpiresAt.Unix(),
 	}, nil
@@ -0,0 +1,45 @@
+// Package lockout implements the failed-login policy: consecutive password
+// failures lock an account temporarily, and each repeat lockout lasts twice as
+// long as the one before, up to a ceiling.
+package lockout
+
+import "time"
+
+// Policy configures when and for how long accounts are locked.
+type Policy struct {
+	// Threshold is the number of failures that triggers a lockout.
+	Threshold int
+	// Window is how long a failure counts towards the threshold.
+	Window time.Duration
+	// BaseDuration is the length of the first lockout.
+	BaseDuration time.Duration
+	// MaxDuration caps the lockout length.
+	MaxDuration time.Duration
+}
+
+// Duration returns the length of a user's nth lockout, counting from 1.
+func (p Policy) Duration(n int) time.Duration {
+	d := p.BaseDuration
+	for i := 1; i < n && d < p.MaxDuration; i++ {
+		d *= 2
+	}
+	if d > p.MaxDuration {
+		d = p.MaxDuration
+	}
+	return d
+}
+
+// Schedule returns the length of each lockout in turn, from the first up to
+// the first that reaches MaxDuration; later lockouts repeat the last entry.
+// Failures are counted atomically where the records are stored, so the
+// schedule is what the store needs to apply the policy.
+func (p Policy) Schedule() []time.Duration {
+	var schedule []time.Duration
+	for n := 1; ; n++ {
+		d := p.Duration(n)
+		schedule = append(schedule, d)
+		if d >= p.MaxDuration || d <= 0 {
+			return schedule
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{
+	Threshold:    3,
+	Window:       15 * time.Minute,
+	BaseDuration: time.Minute,
+	MaxDuration:  10 * time.Minute,
+}
+
+func TestDuration_Doubles(t *testing.T) {
+	cases := []struct {
+		n    int
+		want time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+	for _, tc := range cases {
+		if got := testPolicy.Duration(tc.n); got != tc.want {
+			t.Errorf("Duration(%d): got %v want %v", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestSchedule_DoublesUpToCap(t *testing.T) {
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute}
+
+	got := testPolicy.Schedule()
+	if len(got) != len(want) {
+		t.Fatalf("Schedule: got %v want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Schedule[%d]: got %v want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSchedule_MatchesDuration(t *testing.T) {
+	schedule := testPolicy.Schedule()
+	for n := 1; n <= 50; n++ {
+		i := min(n, len(schedule)) - 1
+		if schedule[i] != testPolicy.Duration(n) {
+			t.Errorf("lockout %d: schedule gives %v, Duration gives %v", n, schedule[i], testPolicy.Duration(n))
+		}
+	}
+}
+
+func TestSchedule_BaseAtCap(t *testing.T) {
+	p := Policy{BaseDuration: time.Hour, MaxDuration: time.Hour}
+
+	got := p.Schedule()
+	if len(got) != 1 || got[0] != time.Hour {
+		t.Errorf("Schedule: got %v want [1h0m0s]", got)
+	}
+}
@@ -1,4 +1,5 @@
-// Package oauth provides GitHub OAuth2 client functionality.
+// Package oauth provides OAuth2 identity provider clients and the registry
+// that the login and account-linking handlers resolve them from.
 package oauth
 
 import (
@@ -7,9 +8,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
+// GitHubProviderName is the registry key for GitHub.
+const GitHubProviderName = "github"
+
 // GitHubUser represents a GitHub user profile.
 type GitHubUser struct {
 	ID        int64   `json:"id"`
@@ -26,6 +31,8 @@ type GitHubClient struct {
 	httpClient   *http.Client
 }
 
+var _ Provider = (*GitHubClient)(nil)
+
 // NewGitHubClient creates a new GitHubClient with the given credentials.
 func NewGitHubClient(clientID, clientSecret string) *GitHubClient {
 	return &GitHubClient{
@@ -35,30 +42,30 @@ func NewGitHubClient(clientID, clientSecret string) *GitHubClient {
 	}
 }
 
+// Name returns the registry key for GitHub.
+func (c *GitHubClient) Name() string {
+	return GitHubProviderName
+}
+
 // AuthorizeURL returns the GitHub OAuth authorization URL with scopes "read:user user:email".
-func (c *GitHubClient) AuthorizeURL(redirectURI, state string) string {
+func (c *GitHubClient) AuthorizeURL(_ context.Context, redirectURI, state string, opts ...AuthOption) string {
 	params := url.Values{}
 	params.Set("client_id", c.clientID)
 	params.Set("redirect_uri", redirectURI)
 	params.Set("scope", "read:user user:email")
 	params.Set("state", state)
+	applyOptions(opts).setChallenge(params)
 	return "https://github.com/login/oauth/authorize?" + params.Encode()
 }
 
-// TokenResponse holds the response from GitHub's token endpoint.
-type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	Scope       string `json:"scope"`
-}
-
 // Exchange exchanges an authorization code for an access token.
-func (c *GitHubClient) Exchange(ctx context.Context, code, redirectURI string) (*TokenResponse, error) {
+func (c *GitHubClient) Exchange(ctx context.Context, code, redirectURI string, opts ...AuthOption) (*Token, error) {
 	params := url.Values{}
 	params.Set("client_id", c.clientID)
 	params.Set("client_secret", c.clientSecret)
 	params.Set("code", code)
 	params.Set("redirect_uri", redirectURI)
+	applyOptions(opts).setVerifier(params)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://github.com/login/oauth/access_token", strings.NewReader(params.Encode()))
 	if err != nil {
@@ -77,7 +84,7 @@ func (c *GitHubClient) Exchange(ctx context.Context, code, redirectURI string) (
 		return nil, fmt.Errorf("token exchange returned status %d", resp.StatusCode)
 	}
 
-	var token TokenResponse
+	var token Token
 	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
 		return nil, fmt.Errorf("decoding token response: %w", err)
 	}
@@ -85,14 +92,16 @@ func (c *GitHubClient) Exchange(ctx context.Context, code, redirectURI string) (
 	return &token, nil
 }
 
-// GetUser retrieves the authenticated user's GitHub profile.
-// If the user's email is private, it falls back to getPrimaryEmail.
-func (c *GitHubClient) GetUser(ctx context.Context, accessToken string) (*GitHubUser, error) {
+// GetUser retrieves the authenticated user's GitHub profile as an Identity.
+// The email and whether it is verified always come from the primary entry of
+// /user/emails; the profile email carries no verification status. Without a
+// primary entry the profile email is reported unverified.
+func (c *GitHubClient) GetUser(ctx context.Context, token *Token, _ ...AuthOption) (*Identity, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating user request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
 	req.Header.Set("Accept", "application/vnd.github+json")
 
 	resp, err := c.httpClient.Do(req)
@@ -110,16 +119,23 @@ func (c *GitHubClient) GetUser(ctx context.Context, accessToken string) (*GitHub
 		return nil, fmt.Errorf("decoding user response: %w", err)
 	}
 
-	// If email is empty (private), fetch it separately.
-	if user.Email == "" {
-		email, err := c.getPrimaryEmail(ctx, accessToken)
-		if err != nil {
-			return nil, fmt.Errorf("fetching primary email: %w", err)
-		}
-		user.Email = email
+	primary, err := c.getPrimaryEmail(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("fetching primary email: %w", err)
 	}
 
-	return &user, nil
+	identity := &Identity{
+		ID:        strconv.FormatInt(user.ID, 10),
+		Username:  user.Login,
+		Email:     user.Email,
+		Name:      user.Name,
+		AvatarURL: user.AvatarURL,
+	}
+	if primary != nil {
+		identity.Email = primary.Email
+		identity.EmailVerified = primary.Verified
+	}
+	return identity, nil
 }
 
 // githubEmail represents a single entry from the GitHub emails endpoint.
@@ -129,42 +145,35 @@ type githubEmail struct {
 	Verified bool   `json:"verified"`
 }
 
-// getPrimaryEmail fetches the user's primary verified email from GitHub.
-func (c *GitHubClient) getPrimaryEmail(ctx context.Context, accessToken string) (string, error) {
+// getPrimaryEmail fetches the user's primary email entry from GitHub, or nil
+// if the account lists none.
+func (c *GitHubClient) getPrimaryEmail(ctx context.Context, accessToken string) (*githubEmail, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user/emails", nil)
 	if err != nil {
-		return "", fmt.Errorf("creating emails request: %w", err)
+		return nil, fmt.Errorf("creating emails request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Accept", "application/vnd.github+json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("fetching emails: %w", err)
+		return nil, fmt.Errorf("fetching emails: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("get emails returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("get emails returned status %d", resp.StatusCode)
 	}
 
 	var emails []githubEmail
 	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
-		return "", fmt.Errorf("decoding emails response: %w", err)
-	}
-
-	for _, e := range emails {
-		if e.Primary && e.Verified {
-			return e.Email, nil
-		}
+		return nil, fmt.Errorf("decoding emails response: %w", err)
 	}
 
-	// Fall back to first verified email.
 	for _, e := range emails {
-		if e.Verified {
-			return e.Email, nil
+		if e.Primary {
+			return &e, nil
 		}
 	}
-
-	return "", fmt.Errorf("no verified email found")
+	return nil, nil
 }
@@ -34,7 +34,7 @@ func newTestClientWithServer(srv *httptest.Server) *GitHubClient {
 
 func TestAuthorizeURL_ContainsClientID(t *testing.T) {
 	c := newTestClient("")
-	u := c.AuthorizeURL("https://example.com/callback", "mystate")
+	u := c.AuthorizeURL(context.Background(), "https://example.com/callback", "mystate")
 	if !strings.Contains(u, "client_id=test-client-id") {
 		t.Errorf("expected client_id in URL: %q", u)
 	}
@@ -43,7 +43,7 @@ func TestAuthorizeURL_ContainsClientID(t *testing.T) {
 func TestAuthorizeURL_ContainsRedirectURI(t *testing.T) {
 	c := newTestClient("")
 	redirect := "https://example.com/callback"
-	u := c.AuthorizeURL(redirect, "mystate")
+	u := c.AuthorizeURL(context.Background(), redirect, "mystate")
 	parsed, err := url.Parse(u)
 	if err != nil {
 		t.Fatalf("failed to parse URL: %v", err)
@@ -56,7 +56,7 @@ func TestAuthorizeURL_ContainsRedirectURI(t *testing.T) {
 
 func TestAuthorizeURL_ContainsState(t *testing.T) {
 	c := newTestClient("")
-	u := c.AuthorizeURL("https://example.com/callback", "mystate")
+	u := c.AuthorizeURL(context.Background(), "https://example.com/callback", "mystate")
 	parsed, err := url.Parse(u)
 	if err != nil {
 		t.Fatalf("failed to parse URL: %v", err)
@@ -69,7 +69,7 @@ func TestAuthorizeURL_ContainsState(t *testing.T) {
 
 func TestAuthorizeURL_ContainsScopes(t *testing.T) {
 	c := newTestClient("")
-	u := c.AuthorizeURL("https://example.com/callback", "mystate")
+	u := c.AuthorizeURL(context.Background(), "https://example.com/callback", "mystate")
 	parsed, err := url.Parse(u)
 	if err != nil {
 		t.Fatalf("failed to parse URL: %v", err)
@@ -85,12 +85,40 @@ func TestAuthorizeURL_ContainsScopes(t *testing.T) {
 
 func TestAuthorizeURL_BaseURL(t *testing.T) {
 	c := newTestClient("")
-	u := c.AuthorizeURL("https://example.com/callback", "mystate")
+	u := c.AuthorizeURL(context.Background(), "https://example.com/callback", "mystate")
 	if !strings.HasPrefix(u, "https://github.com/login/oauth/authorize") {
 		t.Errorf("unexpected base URL: %q", u)
 	}
 }
 
+func TestAuthorizeURL_PKCEChallenge(t *testing.T) {
+	c := newTestClient("")
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	u := c.AuthorizeURL(context.Background(), "https://example.com/callback", "mystate", WithPKCE(verifier))
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+	q := parsed.Query()
+	if got := q.Get("code_challenge"); got != CodeChallenge(verifier) {
+		t.Errorf("code_challenge: got %q want %q", got, CodeChallenge(verifier))
+	}
+	if got := q.Get("code_challenge_method"); got != "S256" {
+		t.Errorf("code_challenge_method: got %q want %q", got, "S256")
+	}
+	if strings.Contains(u, verifier) {
+		t.Errorf("verifier must not appear in authorize URL: %q", u)
+	}
+}
+
+func TestAuthorizeURL_NoPKCEWithoutOption(t *testing.T) {
+	c := newTestClient("")
+	u := c.AuthorizeURL(context.Background(), "https://example.com/callback", "mystate")
+	if strings.Contains(u, "code_challenge") {
+		t.Errorf("unexpected code_challenge in URL: %q", u)
+	}
+}
+
 // ──────────────────────────────────────────────────────────────────────────────
 // Exchange
 // ──────────────────────────────────────────────────────────────────────────────
@@ -104,7 +132,7 @@ func TestExchange_Success(t *testing.T) {
 			t.Errorf("Exchange: missing Accept: application/json header")
 		}
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(TokenResponse{
+		_ = json.NewEncoder(w).Encode(Token{
 			AccessToken: "gho_test_token",
 			TokenType:   "bearer",
 			Scope:       "read:user,user:email",
@@ -131,6 +159,27 @@ func TestExchange_Success(t *testing.T) {
 	}
 }
 
+func TestExchange_SendsCodeVerifier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		if got := r.PostForm.Get("code_verifier"); got != "the-verifier" {
+			t.Errorf("code_verifier: got %q want %q", got, "the-verifier")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Token{AccessToken: "gho_test_token"})
+	}))
+	defer srv.Close()
+
+	c := newTestClientWithServer(srv)
+	c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
+
+	if _, err := c.Exchange(context.Background(), "auth-code", "https://example.com/callback", WithPKCE("the-verifier")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
 func TestExchange_NonOKStatus(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -178,21 +227,28 @@ func TestGetUser_WithEmail(t *testing.T) {
 	name := "The Octocat"
 	avatar := "https://avatars.githubusercontent.com/u/583231"
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/user" {
-			t.Errorf("unexpected path: %q", r.URL.Path)
-		}
 		auth := r.Header.Get("Authorization")
 		if auth != "Bearer gho_test_token" {
 			t.Errorf("Authorization: got %q", auth)
 		}
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(GitHubUser{
-			ID:        583231,
-			Login:     "octocat",
-			Email:     "octocat@github.com",
-			Name:      &name,
-			AvatarURL: &avatar,
-		})
+		switch r.URL.Path {
+		case "/user":
+			_ = json.NewEncoder(w).Encode(GitHubUser{
+				ID:        583231,
+				Login:     "octocat",
+				Email:     "octocat@github.com",
+				Name:      &name,
+				AvatarURL: &avatar,
+			})
+		case "/user/emails":
+			_ = json.NewEncoder(w).Encode([]githubEmail{
+				{Email: "octocat@github.com", Primary: true, Verified: true},
+			})
+		default:
+			t.Errorf("unexpected path: %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
 	}))
 	defer srv.Close()
 
@@ -203,18 +259,21 @@ func TestGetUser_WithEmail(t *testing.T) {
 	}
 	c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
 
-	user, err := c.GetUser(context.Background(), "gho_test_token")
+	user, err := c.GetUser(context.Background(), &Token{AccessToken: "gho_test_token"})
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
-	if user.Login != "octocat" {
-		t.Errorf("Login: got %q want %q", user.Login, "octocat")
+	if user.Username != "octocat" {
+		t.Errorf("Username: got %q want %q", user.Username, "octocat")
 	}
 	if user.Email != "octocat@github.com" {
 		t.Errorf("Email: got %q want %q", user.Email, "octocat@github.com")
 	}
-	if user.ID != 583231 {
-		t.Errorf("ID: got %d want %d", user.ID, 583231)
+	if user.ID != "583231" {
+		t.Errorf("ID: got %q want %q", user.ID, "583231")
+	}
+	if !user.EmailVerified {
+		t.Error("EmailVerified: expected true")
 	}
 }
 
@@ -256,7 +315,7 @@ func TestGetUser_FallbackToEmailEndpoint(t *testing.T) {
 	}
 	c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
 
-	user, err := c.GetUser(context.Background(), "gho_test_token")
+	user, err := c.GetUser(context.Background(), &Token{AccessToken: "gho_test_token"})
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -265,62 +324,92 @@ func TestGetUser_FallbackToEmailEndpoint(t *testing.T) {
 	}
 }
 
-func TestGetUser_FallbackEmailFallsBackToFirstVerified(t *testing.T) {
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		switch r.URL.Path {
-		case "/user":
-			_ = json.NewEncoder(w).Encode(GitHubUser{ID: 1, Login: "user1"})
-		case "/user/emails":
-			// No primary — should fall back to first verified.
-			_ = json.NewEncoder(w).Encode([]githubEmail{
-				{Email: "first@example.com", Primary: false, Verified: true},
-				{Email: "second@example.com", Primary: false, Verified: true},
-			})
-		}
-	}))
-	defer srv.Close()
-
-	c := &GitHubClient{
-		clientID:   "id",
-		clientSecret: "secret",
-		httpClient: srv.Client(),
-	}
-	c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
-
-	user, err := c.GetUser(context.Background(), "tok")
-	if err != nil {
-		t.Fatalf("expected no error, got: %v", err)
-	}
-	if user.Email != "first@example.com" {
-		t.Errorf("Email: got %q want %q", user.Email, "first@example.com")
+func TestGetUser_EmailVerification(t *testing.T) {
+	cases := []struct {
+		name         string
+		profileEmail string
+		emails       []githubEmail
+		wantEmail    string
+		wantVerified bool
+	}{
+		{
+			name:         "verified primary",
+			emails:       []githubEmail{{Email: "primary@example.com", Primary: true, Verified: true}},
+			wantEmail:    "primary@example.com",
+			wantVerified: true,
+		},
+		{
+			name:         "unverified primary shown on profile",
+			profileEmail: "primary@example.com",
+			emails: []githubEmail{
+				{Email: "other@example.com", Primary: false, Verified: true},
+				{Email: "primary@example.com", Primary: true, Verified: false},
+			},
+			wantEmail: "primary@example.com",
+		},
+		{
+			name:         "no primary entry",
+			profileEmail: "public@example.com",
+			emails:       []githubEmail{{Email: "first@example.com", Primary: false, Verified: true}},
+			wantEmail:    "public@example.com",
+		},
+		{
+			name: "no emails",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/user":
+					_ = json.NewEncoder(w).Encode(GitHubUser{ID: 1, Login: "user1", Email: tc.profileEmail})
+				case "/user/emails":
+					_ = json.NewEncoder(w).Encode(tc.emails)
+				}
+			}))
+			defer srv.Close()
+
+			c := &GitHubClient{
+				clientID:     "id",
+				clientSecret: "secret",
+				httpClient:   srv.Client(),
+			}
+			c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
+
+			user, err := c.GetUser(context.Background(), &Token{AccessToken: "tok"})
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if user.Email != tc.wantEmail || user.EmailVerified != tc.wantVerified {
+				t.Errorf("got email %q verified %v, want %q verified %v", user.Email, user.EmailVerified, tc.wantEmail, tc.wantVerified)
+			}
+		})
 	}
 }
 
-func TestGetUser_NoVerifiedEmail(t *testing.T) {
+func TestGetUser_EmailEndpointError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		switch r.URL.Path {
 		case "/user":
-			_ = json.NewEncoder(w).Encode(GitHubUser{ID: 1, Login: "user1"})
+			_ = json.NewEncoder(w).Encode(GitHubUser{ID: 1, Login: "user1", Email: "public@example.com"})
 		case "/user/emails":
-			_ = json.NewEncoder(w).Encode([]githubEmail{
-				{Email: "unverified@example.com", Primary: false, Verified: false},
-			})
+			w.WriteHeader(http.StatusForbidden)
 		}
 	}))
 	defer srv.Close()
 
 	c := &GitHubClient{
-		clientID:   "id",
+		clientID:     "id",
 		clientSecret: "secret",
-		httpClient: srv.Client(),
+		httpClient:   srv.Client(),
 	}
 	c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
 
-	_, err := c.GetUser(context.Background(), "tok")
-	if err == nil {
-		t.Fatal("expected error for no verified email, got nil")
+	if _, err := c.GetUser(context.Background(), &Token{AccessToken: "tok"}); err == nil {
+		t.Fatal("expected error when /user/emails fails, got nil")
 	}
 }
 
@@ -337,7 +426,7 @@ func TestGetUser_NonOKStatus(t *testing.T) {
 	}
 	c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
 
-	_, err := c.GetUser(context.Background(), "bad-token")
+	_, err := c.GetUser(context.Background(), &Token{AccessToken: "bad-token"})
 	if err == nil {
 		t.Fatal("expected error for non-200 user response, got nil")
 	}
@@ -357,7 +446,7 @@ func TestGetUser_InvalidJSON(t *testing.T) {
 	}
 	c.httpClient.Transport = rewriteTransport(srv.URL, c.httpClient.Transport)
 
-	_, err := c.GetUser(context.Background(), "tok")
+	_, err := c.GetUser(context.Background(), &Token{AccessToken: "tok"})
 	if err == nil {
 		t.Fatal("expected error for invalid JSON, got nil")
 	}
@@ -9,6 +9,9 @@ import (
 	"strings"
 )
 
+// GoogleProviderName is the registry key for Google.
+const GoogleProviderName = "google"
+
 // GoogleUser represents a Google user profile from the userinfo endpoint.
 type GoogleUser struct {
 	ID            string  `json:"sub"`
@@ -25,6 +28,8 @@ type GoogleClient struct {
 	httpClient   *http.Client
 }
 
+var _ Provider = (*GoogleClient)(nil)
+
 // NewGoogleClient creates a new GoogleClient with the given credentials.
 func NewGoogleClient(clientID, clientSecret string) *GoogleClient {
 	return &GoogleClient{
@@ -34,8 +39,13 @@ func NewGoogleClient(clientID, clientSecret string) *GoogleClient {
 	}
 }
 
+// Name returns the registry key for Google.
+func (c *GoogleClient) Name() string {
+	return GoogleProviderName
+}
+
 // AuthorizeURL returns the Google OAuth authorization URL with scopes for email and profile.
-func (c *GoogleClient) AuthorizeURL(redirectURI, state string) string {
+func (c *GoogleClient) AuthorizeURL(_ context.Context, redirectURI, state string, opts ...AuthOption) string {
 	params := url.Values{}
 	params.Set("client_id", c.clientID)
 	params.Set("redirect_uri", redirectURI)
@@ -43,27 +53,19 @@ func (c *GoogleClient) AuthorizeURL(redirectURI, state string) string {
 	params.Set("scope", "openid email profile")
 	params.Set("state", state)
 	params.Set("access_type", "offline")
+	applyOptions(opts).setChallenge(params)
 	return "https://accounts.google.com/o/oauth2/v2/auth?" + params.Encode()
 }
 
-// GoogleTokenResponse holds the response from Google's token endpoint.
-type GoogleTokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int    `json:"expires_in"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	Scope        string `json:"scope"`
-	IDToken      string `json:"id_token,omitempty"`
-}
-
 // Exchange exchanges an authorization code for an access token.
-func (c *GoogleClient) Exchange(ctx context.Context, code, redirectURI string) (*GoogleTokenResponse, error) {
+func (c *GoogleClient) Exchange(ctx context.Context, code, redirectURI string, opts ...AuthOption) (*Token, error) {
 	params := url.Values{}
 	params.Set("client_id", c.clientID)
 	params.Set("client_secret", c.clientSecret)
 	params.Set("code", code)
 	params.Set("redirect_uri", redirectURI)
 	params.Set("grant_type", "authorization_code")
+	applyOptions(opts).setVerifier(params)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://oauth2.googleapis.com/token", strings.NewReader(params.Encode()))
 	if err != nil {
@@ -82,7 +84,7 @@ func (c *GoogleClient) Exchange(ctx context.Context, code, redirectURI string) (
 		return nil, fmt.Errorf("token exchange returned status %d", resp.StatusCode)
 	}
 
-	var token GoogleTokenResponse
+	var token Token
 	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
 		return nil, fmt.Errorf("decoding token response: %w", err)
 	}
@@ -90,13 +92,13 @@ func (c *GoogleClient) Exchange(ctx context.Context, code, redirectURI string) (
 	return &token, nil
 }
 
-// GetUser retrieves the authenticated user's Google profile using the userinfo endpoint.
-func (c *GoogleClient) GetUser(ctx context.Context, accessToken string) (*GoogleUser, error) {
+// GetUser retrieves the authenticated user's Google profile as an Identity using the userinfo endpoint.
+func (c *GoogleClient) GetUser(ctx context.Context, token *Token, _ ...AuthOption) (*Identity, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.googleapis.com/oauth2/v3/userinfo", nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating userinfo request: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -118,5 +120,11 @@ func (c *GoogleClient) GetUser(ctx context.Context, accessToken string) (*Google
 		return nil, fmt.Errorf("email not verified")
 	}
 
-	return &user, nil
+	return &Identity{
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Name:          user.Name,
+		AvatarURL:     user.Picture,
+	}, nil
 }
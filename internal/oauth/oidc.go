@@ -0,0 +1,504 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// discoveryTTL is how long a fetched discovery document is reused.
+	discoveryTTL = time.Hour
+	// jwksTTL is how long a fetched key set is reused before a scheduled refresh.
+	jwksTTL = time.Hour
+	// jwksMinRefresh bounds how often an unknown key ID can force a refetch,
+	// so tokens with garbage kids cannot be used to hammer the issuer.
+	jwksMinRefresh = time.Minute
+	// clockSkew is the leeway allowed when checking exp, iat, and nbf.
+	clockSkew = time.Minute
+)
+
+var (
+	// ErrInvalidIDToken is returned when an ID token fails signature or claim verification.
+	ErrInvalidIDToken = errors.New("oauth: invalid id token")
+	// ErrMissingIDToken is returned when the token response has no ID token.
+	ErrMissingIDToken = errors.New("oauth: token response has no id token")
+)
+
+// signingMethods are the JWS algorithms accepted on ID tokens. "none" and the
+// HMAC family are deliberately absent.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// discoveryDocument holds the fields of /.well-known/openid-configuration that the client uses.
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// jwk is a single JSON Web Key. Only the members for RSA and EC signing keys are read.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// idTokenClaims are the ID token claims mapped onto an Identity.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string  `json:"nonce"`
+	AuthorizedParty   string  `json:"azp"`
+	Email             string  `json:"email"`
+	EmailVerified     *bool   `json:"email_verified"`
+	Name              *string `json:"name"`
+	Picture           *string `json:"picture"`
+	PreferredUsername string  `json:"preferred_username"`
+}
+
+// OIDCClient is an OpenID Connect relying party for a single issuer.
+// Endpoints come from the issuer's discovery document and ID tokens are
+// verified against its published keys, which are cached and refreshed on rotation.
+type OIDCClient struct {
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mu          sync.Mutex
+	discovery   *discoveryDocument
+	discoveryAt time.Time
+	keys        map[string]crypto.PublicKey
+	keysAt      time.Time
+}
+
+var _ Provider = (*OIDCClient)(nil)
+
+// NewOIDCClient creates a client for the issuer registered under name.
+// The scopes openid, email, and profile are always requested; extra scopes are appended.
+// Discovery is performed lazily on first use so an unreachable issuer does not block startup.
+func NewOIDCClient(name, issuer, clientID, clientSecret string, scopes []string) *OIDCClient {
+	all := []string{"openid", "email", "profile"}
+	for _, s := range scopes {
+		if s != "openid" && s != "email" && s != "profile" {
+			all = append(all, s)
+		}
+	}
+	return &OIDCClient{
+		name:         name,
+		issuer:       strings.TrimRight(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       all,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		now:          time.Now,
+	}
+}
+
+// Name returns the registry key for this issuer.
+func (c *OIDCClient) Name() string {
+	return c.name
+}
+
+// Issuer returns the configured issuer URL.
+func (c *OIDCClient) Issuer() string {
+	return c.issuer
+}
+
+// AuthorizeURL returns the issuer's authorization URL for the code flow.
+// If discovery fails the empty string is returned; callers treat that as a failed flow.
+func (c *OIDCClient) AuthorizeURL(ctx context.Context, redirectURI, state string, opts ...AuthOption) string {
+	doc, err := c.discover(ctx)
+	if err != nil {
+		return ""
+	}
+	p := applyOptions(opts)
+
+	params := url.Values{}
+	params.Set("client_id", c.clientID)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("response_type", "code")
+	params.Set("scope", strings.Join(c.scopes, " "))
+	params.Set("state", state)
+	if p.nonce != "" {
+		params.Set("nonce", p.nonce)
+	}
+	p.setChallenge(params)
+
+	sep := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return doc.AuthorizationEndpoint + sep + params.Encode()
+}
+
+// Exchange exchanges an authorization code for tokens at the issuer's token endpoint.
+func (c *OIDCClient) Exchange(ctx context.Context, code, redirectURI string, opts ...AuthOption) (*Token, error) {
+	doc, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := applyOptions(opts)
+
+	params := url.Values{}
+	params.Set("client_id", c.clientID)
+	params.Set("client_secret", c.clientSecret)
+	params.Set("code", code)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("grant_type", "authorization_code")
+	p.setVerifier(params)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("exchanging code: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token exchange returned status %d", resp.StatusCode)
+	}
+
+	var token Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("decoding token response: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	return &token, nil
+}
+
+// GetUser verifies the ID token in token and returns the identity it asserts.
+// If the ID token carries no email, the userinfo endpoint is consulted and its
+// subject must match the verified one.
+func (c *OIDCClient) GetUser(ctx context.Context, token *Token, opts ...AuthOption) (*Identity, error) {
+	claims, err := c.verifyIDToken(ctx, token.IDToken, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		ID:        claims.Subject,
+		Username:  claims.PreferredUsername,
+		Email:     claims.Email,
+		Name:      claims.Name,
+		AvatarURL: claims.Picture,
+	}
+	if claims.EmailVerified != nil {
+		identity.EmailVerified = *claims.EmailVerified
+	}
+
+	if identity.Email == "" && token.AccessToken != "" {
+		info, err := c.userinfo(ctx, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		if info.Subject != claims.Subject {
+			return nil, fmt.Errorf("userinfo subject does not match id token")
+		}
+		identity.Email = info.Email
+		if info.EmailVerified != nil {
+			identity.EmailVerified = *info.EmailVerified
+		}
+		if identity.Name == nil {
+			identity.Name = info.Name
+		}
+		if identity.AvatarURL == nil {
+			identity.AvatarURL = info.Picture
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyIDToken checks the ID token's signature against the issuer's keys and
+// validates iss, aud, azp, exp, iat, and (when WithNonce is given) nonce.
+func (c *OIDCClient) verifyIDToken(ctx context.Context, raw string, opts ...AuthOption) (*idTokenClaims, error) {
+	if raw == "" {
+		return nil, ErrMissingIDToken
+	}
+	doc, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := applyOptions(opts)
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(c.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(c.now),
+	)
+
+	var claims idTokenClaims
+	if _, err := parser.ParseWithClaims(raw, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return c.key(ctx, kid)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	// With several audiences the token must name us as the authorized party.
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != c.clientID {
+		return nil, fmt.Errorf("%w: azp does not match client", ErrInvalidIDToken)
+	}
+	if p.nonce != "" && subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(p.nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return &claims, nil
+}
+
+// discover returns the cached discovery document, fetching it when absent or stale.
+// The fetch runs without c.mu held, so a slow issuer only delays the callers
+// that need it; concurrent misses may each fetch and the last one is kept.
+func (c *OIDCClient) discover(ctx context.Context) (*discoveryDocument, error) {
+	c.mu.Lock()
+	cached, fetchedAt := c.discovery, c.discoveryAt
+	c.mu.Unlock()
+
+	if cached != nil && c.now().Sub(fetchedAt) < discoveryTTL {
+		return cached, nil
+	}
+
+	var doc discoveryDocument
+	if err := c.getJSON(ctx, c.issuer+"/.well-known/openid-configuration", "", &doc); err != nil {
+		// Serve a stale document rather than failing logins during an issuer blip.
+		if cached != nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("fetching discovery document: %w", err)
+	}
+	if strings.TrimRight(doc.Issuer, "/") != c.issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match configured issuer %q", doc.Issuer, c.issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document missing required endpoints")
+	}
+
+	c.mu.Lock()
+	c.discovery = &doc
+	c.discoveryAt = c.now()
+	c.mu.Unlock()
+	return &doc, nil
+}
+
+// key returns the public key for kid, refreshing the key set when it is stale
+// or when kid is unknown (the issuer has rotated keys).
+func (c *OIDCClient) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	doc, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	stale := c.keys == nil || now.Sub(c.keysAt) >= jwksTTL
+	if k, ok := c.lookupKey(kid); ok && !stale {
+		c.mu.Unlock()
+		return k, nil
+	}
+	refresh := stale || now.Sub(c.keysAt) >= jwksMinRefresh
+	if refresh {
+		// Record the attempt up front so failures, and callers racing this
+		// one, are also rate limited.
+		c.keysAt = now
+	}
+	c.mu.Unlock()
+
+	if refresh {
+		keys, err := c.fetchKeys(ctx, doc.JWKSURI)
+		c.mu.Lock()
+		if err == nil {
+			c.keys = keys
+		}
+		haveKeys := c.keys != nil
+		c.mu.Unlock()
+		if err != nil && !haveKeys {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if k, ok := c.lookupKey(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("no key for kid %q", kid)
+}
+
+// lookupKey finds kid in the cached key set. An empty kid matches only when the
+// issuer publishes a single key. Callers must hold c.mu.
+func (c *OIDCClient) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, k := range c.keys {
+			return k, true
+		}
+	}
+	k, ok := c.keys[kid]
+	return k, ok
+}
+
+// fetchKeys fetches the issuer's JWKS and returns its signing keys by key ID.
+// Keys that are not for signature use or cannot be parsed are skipped. Callers
+// must not hold c.mu; they publish the result themselves.
+func (c *OIDCClient) fetchKeys(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := c.getJSON(ctx, jwksURI, "", &set); err != nil {
+		return nil, fmt.Errorf("fetching jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+// userinfoResponse holds the userinfo claims used to fill gaps in the ID token.
+type userinfoResponse struct {
+	Subject       string  `json:"sub"`
+	Email         string  `json:"email"`
+	EmailVerified *bool   `json:"email_verified"`
+	Name          *string `json:"name"`
+	Picture       *string `json:"picture"`
+}
+
+// userinfo fetches the userinfo endpoint with the access token.
+func (c *OIDCClient) userinfo(ctx context.Context, accessToken string) (*userinfoResponse, error) {
+	doc, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if doc.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("issuer has no userinfo endpoint")
+	}
+	var info userinfoResponse
+	if err := c.getJSON(ctx, doc.UserinfoEndpoint, accessToken, &info); err != nil {
+		return nil, fmt.Errorf("fetching userinfo: %w", err)
+	}
+	return &info, nil
+}
+
+// getJSON performs a GET and decodes a JSON response, optionally with a bearer token.
+func (c *OIDCClient) getJSON(ctx context.Context, endpoint, bearer string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// publicKey converts an RSA or EC JWK to a crypto.PublicKey.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid rsa key")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) { //nolint:staticcheck // validating untrusted key material
+			return nil, fmt.Errorf("ec point not on curve")
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
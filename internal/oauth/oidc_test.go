@@ -0,0 +1,373 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testIssuer is a local OpenID Connect issuer backed by httptest.
+type testIssuer struct {
+	t          *testing.T
+	srv        *httptest.Server
+	key        *rsa.PrivateKey
+	kid        string
+	jwksHits   atomic.Int32
+	tokenForm  url.Values
+	idToken    string
+	userinfo   map[string]any
+	issuerName string // overrides the advertised issuer when set
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	is := &testIssuer{t: t, key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		issuer := is.srv.URL
+		if is.issuerName != "" {
+			issuer = is.issuerName
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": is.srv.URL + "/authorize",
+			"token_endpoint":         is.srv.URL + "/token",
+			"userinfo_endpoint":      is.srv.URL + "/userinfo",
+			"jwks_uri":               is.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		is.jwksHits.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": is.kid,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(is.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(is.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parsing token form: %v", err)
+		}
+		is.tokenForm = r.PostForm
+		_ = json.NewEncoder(w).Encode(Token{
+			AccessToken: "access-token",
+			TokenType:   "Bearer",
+			IDToken:     is.idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(is.userinfo)
+	})
+	is.srv = httptest.NewServer(mux)
+	t.Cleanup(is.srv.Close)
+	return is
+}
+
+// sign issues an ID token with default valid claims, applying overrides.
+func (is *testIssuer) sign(overrides map[string]any) string {
+	is.t.Helper()
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   is.srv.URL,
+		"sub":   "user-123",
+		"aud":   "client-id",
+		"exp":   now.Add(time.Hour).Unix(),
+		"iat":   now.Unix(),
+		"nonce": "n-0S6_WzA2Mj",
+		"email": "jane@example.com",
+		"name":  "Jane Doe",
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = is.kid
+	raw, err := tok.SignedString(is.key)
+	if err != nil {
+		is.t.Fatalf("signing id token: %v", err)
+	}
+	return raw
+}
+
+func (is *testIssuer) client() *OIDCClient {
+	return NewOIDCClient("acme", is.srv.URL, "client-id", "client-secret", []string{"groups"})
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Discovery and authorization
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestOIDC_AuthorizeURL_UsesDiscoveredEndpoint(t *testing.T) {
+	is := newTestIssuer(t)
+	c := is.client()
+
+	verifier, err := GenerateVerifier()
+	if err != nil {
+		t.Fatalf("GenerateVerifier: %v", err)
+	}
+	raw := c.AuthorizeURL(context.Background(), "https://app.example.com/cb", "state-1", WithPKCE(verifier), WithNonce("nonce-1"))
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if u.Scheme+"://"+u.Host+u.Path != is.srv.URL+"/authorize" {
+		t.Errorf("endpoint: got %q", raw)
+	}
+	q := u.Query()
+	if q.Get("scope") != "openid email profile groups" {
+		t.Errorf("scope: got %q", q.Get("scope"))
+	}
+	if q.Get("nonce") != "nonce-1" {
+		t.Errorf("nonce: got %q", q.Get("nonce"))
+	}
+	if q.Get("code_challenge") != CodeChallenge(verifier) || q.Get("code_challenge_method") != "S256" {
+		t.Errorf("pkce params: got %q / %q", q.Get("code_challenge"), q.Get("code_challenge_method"))
+	}
+}
+
+func TestOIDC_Discovery_IssuerMismatch(t *testing.T) {
+	is := newTestIssuer(t)
+	is.issuerName = "https://evil.example.com"
+
+	if got := is.client().AuthorizeURL(context.Background(), "https://app.example.com/cb", "s"); got != "" {
+		t.Errorf("expected empty URL when discovery issuer mismatches, got %q", got)
+	}
+}
+
+func TestOIDC_Exchange_SendsVerifier(t *testing.T) {
+	is := newTestIssuer(t)
+	is.idToken = is.sign(nil)
+
+	tok, err := is.client().Exchange(context.Background(), "code-1", "https://app.example.com/cb", WithPKCE("verifier-1"))
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if tok.IDToken == "" {
+		t.Error("expected id token in response")
+	}
+	if is.tokenForm.Get("code_verifier") != "verifier-1" {
+		t.Errorf("code_verifier: got %q", is.tokenForm.Get("code_verifier"))
+	}
+	if is.tokenForm.Get("grant_type") != "authorization_code" {
+		t.Errorf("grant_type: got %q", is.tokenForm.Get("grant_type"))
+	}
+}
+
+func TestOIDC_Exchange_MissingIDToken(t *testing.T) {
+	is := newTestIssuer(t)
+
+	_, err := is.client().Exchange(context.Background(), "code-1", "https://app.example.com/cb")
+	if !errors.Is(err, ErrMissingIDToken) {
+		t.Errorf("expected ErrMissingIDToken, got: %v", err)
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// ID token verification
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestOIDC_GetUser_ValidToken(t *testing.T) {
+	is := newTestIssuer(t)
+	tok := &Token{AccessToken: "access-token", IDToken: is.sign(map[string]any{"email_verified": true})}
+
+	id, err := is.client().GetUser(context.Background(), tok, WithNonce("n-0S6_WzA2Mj"))
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if id.ID != "user-123" || id.Email != "jane@example.com" || !id.EmailVerified {
+		t.Errorf("unexpected identity: %+v", id)
+	}
+	if id.Name == nil || *id.Name != "Jane Doe" {
+		t.Errorf("Name: got %v", id.Name)
+	}
+}
+
+func TestOIDC_GetUser_RejectsInvalidClaims(t *testing.T) {
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		token func(is *testIssuer) string
+	}{
+		{"wrong issuer", func(is *testIssuer) string { return is.sign(map[string]any{"iss": "https://evil.example.com"}) }},
+		{"wrong audience", func(is *testIssuer) string { return is.sign(map[string]any{"aud": "other-client"}) }},
+		{"expired", func(is *testIssuer) string {
+			return is.sign(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})
+		}},
+		{"missing exp", func(is *testIssuer) string { return is.sign(map[string]any{"exp": nil}) }},
+		{"nonce mismatch", func(is *testIssuer) string { return is.sign(map[string]any{"nonce": "replayed"}) }},
+		{"missing subject", func(is *testIssuer) string { return is.sign(map[string]any{"sub": nil}) }},
+		{"azp mismatch", func(is *testIssuer) string {
+			return is.sign(map[string]any{"aud": []string{"client-id", "other"}, "azp": "other"})
+		}},
+		{"bad signature", func(is *testIssuer) string {
+			tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+				"iss": is.srv.URL, "sub": "user-123", "aud": "client-id",
+				"exp": time.Now().Add(time.Hour).Unix(), "iat": time.Now().Unix(), "nonce": "n-0S6_WzA2Mj",
+			})
+			tok.Header["kid"] = is.kid
+			raw, _ := tok.SignedString(other)
+			return raw
+		}},
+		{"alg none", func(is *testIssuer) string {
+			tok := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+				"iss": is.srv.URL, "sub": "user-123", "aud": "client-id",
+				"exp": time.Now().Add(time.Hour).Unix(), "iat": time.Now().Unix(), "nonce": "n-0S6_WzA2Mj",
+			})
+			raw, _ := tok.SignedString(jwt.UnsafeAllowNoneSignatureType)
+			return raw
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := newTestIssuer(t)
+			_, err := is.client().GetUser(context.Background(), &Token{IDToken: tc.token(is)}, WithNonce("n-0S6_WzA2Mj"))
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("expected ErrInvalidIDToken, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestOIDC_GetUser_UserinfoFallback(t *testing.T) {
+	is := newTestIssuer(t)
+	is.userinfo = map[string]any{"sub": "user-123", "email": "jane@corp.example.com", "email_verified": true}
+	tok := &Token{AccessToken: "access-token", IDToken: is.sign(map[string]any{"email": nil})}
+
+	id, err := is.client().GetUser(context.Background(), tok)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if id.Email != "jane@corp.example.com" || !id.EmailVerified {
+		t.Errorf("expected email from userinfo, got %+v", id)
+	}
+}
+
+func TestOIDC_GetUser_UserinfoSubjectMismatch(t *testing.T) {
+	is := newTestIssuer(t)
+	is.userinfo = map[string]any{"sub": "someone-else", "email": "mallory@example.com"}
+	tok := &Token{AccessToken: "access-token", IDToken: is.sign(map[string]any{"email": nil})}
+
+	if _, err := is.client().GetUser(context.Background(), tok); err == nil {
+		t.Fatal("expected error when userinfo subject differs from id token")
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────────────────
+// JWKS caching and rotation
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestOIDC_JWKS_CachedAcrossVerifications(t *testing.T) {
+	is := newTestIssuer(t)
+	c := is.client()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetUser(context.Background(), &Token{IDToken: is.sign(nil)}); err != nil {
+			t.Fatalf("GetUser #%d: %v", i, err)
+		}
+	}
+	if hits := is.jwksHits.Load(); hits != 1 {
+		t.Errorf("expected 1 jwks fetch, got %d", hits)
+	}
+}
+
+func TestOIDC_JWKS_RefetchedOnRotation(t *testing.T) {
+	is := newTestIssuer(t)
+	c := is.client()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	if _, err := c.GetUser(context.Background(), &Token{IDToken: is.sign(nil)}); err != nil {
+		t.Fatalf("GetUser before rotation: %v", err)
+	}
+
+	// Rotate to a new key with a new kid.
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	is.key = key
+	is.kid = "key-2"
+
+	// Within the refresh floor an unknown kid does not trigger a refetch.
+	if _, err := c.GetUser(context.Background(), &Token{IDToken: is.sign(nil)}); err == nil {
+		t.Fatal("expected failure before the refresh floor elapses")
+	}
+
+	now = now.Add(jwksMinRefresh)
+	if _, err := c.GetUser(context.Background(), &Token{IDToken: is.sign(nil)}); err != nil {
+		t.Fatalf("GetUser after rotation: %v", err)
+	}
+	if hits := is.jwksHits.Load(); hits != 2 {
+		t.Errorf("expected 2 jwks fetches, got %d", hits)
+	}
+}
+
+func TestJWK_ECPublicKey(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	k := jwk{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(priv.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(priv.Y.Bytes()),
+	}
+	pub, err := k.publicKey()
+	if err != nil {
+		t.Fatalf("publicKey: %v", err)
+	}
+	if !priv.PublicKey.Equal(pub) {
+		t.Error("expected decoded key to equal the original")
+	}
+
+	k.Y = base64.RawURLEncoding.EncodeToString([]byte{1, 2, 3})
+	if _, err := k.publicKey(); err == nil {
+		t.Error("expected error for point not on curve")
+	}
+}
+
+func TestCodeChallenge_RFC7636Vector(t *testing.T) {
+	// Appendix B of RFC 7636.
+	got := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Token holds the fields of an OAuth2 token response that morpheus uses.
+// Providers that do not issue a field leave it empty.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// Identity is a provider account normalised to the fields morpheus stores.
+type Identity struct {
+	// ID is the provider's stable identifier for the account.
+	ID string
+	// Username is the provider-side handle, if the provider has one.
+	Username string
+	// Email is the account's email address.
+	Email string
+	// EmailVerified reports whether the provider has verified Email.
+	EmailVerified bool
+	// Name is the display name, if available.
+	Name *string
+	// AvatarURL is the profile picture URL, if available.
+	AvatarURL *string
+}
+
+// Provider is an OAuth2 identity provider usable for login and account linking.
+type Provider interface {
+	// Name returns the provider's registry key, stored as the provider type on links.
+	Name() string
+	// AuthorizeURL returns the URL to send the user to for consent. ctx bounds
+	// any lookup needed to build it, such as OpenID Connect discovery.
+	// An empty string means the URL could not be built (e.g. discovery failed).
+	AuthorizeURL(ctx context.Context, redirectURI, state string, opts ...AuthOption) string
+	// Exchange exchanges an authorization code for a token.
+	Exchange(ctx context.Context, code, redirectURI string, opts ...AuthOption) (*Token, error)
+	// GetUser fetches the identity of the account that granted token.
+	GetUser(ctx context.Context, token *Token, opts ...AuthOption) (*Identity, error)
+}
+
+// Registry holds the configured providers keyed by name.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+// NewRegistry creates a registry containing the given providers.
+// It returns an error if two providers share a name.
+func NewRegistry(providers ...Provider) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		if err := r.Register(p); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds a provider to the registry.
+func (r *Registry) Register(p Provider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name := p.Name()
+	if name == "" {
+		return fmt.Errorf("oauth: provider has no name")
+	}
+	if _, ok := r.providers[name]; ok {
+		return fmt.Errorf("oauth: provider %q already registered", name)
+	}
+	r.providers[name] = p
+	return nil
+}
+
+// Get returns the provider registered under name.
+func (r *Registry) Get(name string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names returns the registered provider names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
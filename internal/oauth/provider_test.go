@@ -0,0 +1,65 @@
+package oauth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// stubProvider is a minimal Provider for registry tests.
+type stubProvider struct{ name string }
+
+func (p stubProvider) Name() string { return p.name }
+func (p stubProvider) AuthorizeURL(context.Context, string, string, ...AuthOption) string {
+	return ""
+}
+func (p stubProvider) Exchange(context.Context, string, string, ...AuthOption) (*Token, error) {
+	return &Token{}, nil
+}
+func (p stubProvider) GetUser(context.Context, *Token, ...AuthOption) (*Identity, error) {
+	return &Identity{}, nil
+}
+
+func TestNewRegistry_RegistersProviders(t *testing.T) {
+	r, err := NewRegistry(NewGitHubClient("id", "secret"), NewGoogleClient("id", "secret"))
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	if _, ok := r.Get(GitHubProviderName); !ok {
+		t.Error("expected github to be registered")
+	}
+	if _, ok := r.Get(GoogleProviderName); !ok {
+		t.Error("expected google to be registered")
+	}
+	if _, ok := r.Get("gitlab"); ok {
+		t.Error("expected unknown provider lookup to fail")
+	}
+}
+
+func TestNewRegistry_DuplicateName(t *testing.T) {
+	_, err := NewRegistry(stubProvider{name: "acme"}, stubProvider{name: "acme"})
+	if err == nil {
+		t.Fatal("expected error for duplicate provider name")
+	}
+}
+
+func TestRegister_EmptyName(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	if err := r.Register(stubProvider{}); err == nil {
+		t.Fatal("expected error for provider without a name")
+	}
+}
+
+func TestNames_Sorted(t *testing.T) {
+	r, err := NewRegistry(stubProvider{name: "okta"}, stubProvider{name: "acme"}, stubProvider{name: "github"})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	want := []string{"acme", "github", "okta"}
+	if got := r.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names: got %v want %v", got, want)
+	}
+}
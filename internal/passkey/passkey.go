@@ -0,0 +1,186 @@
+// Package passkey adapts morpheus users and stored credentials to the
+// go-webauthn relying-party library.
+package passkey
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ErrCloneWarning is returned when an authenticator's signature counter did not
+// advance, which indicates the credential may have been cloned.
+var ErrCloneWarning = errors.New("passkey: signature counter did not increase")
+
+// encoding is the unpadded base64url encoding used for credential IDs and keys.
+var encoding = base64.RawURLEncoding
+
+// New creates a WebAuthn relying party from config values.
+// Passkeys require resident keys and user verification, so both are set as defaults.
+func New(rpID, rpDisplayName string, rpOrigins []string) (*webauthn.WebAuthn, error) {
+	return webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpDisplayName,
+		RPOrigins:     rpOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+	})
+}
+
+// User adapts a models.User and its passkeys to the webauthn.User interface.
+type User struct {
+	user     *models.User
+	passkeys []*models.Passkey
+}
+
+// NewUser wraps a user and their registered passkeys.
+func NewUser(user *models.User, passkeys []*models.Passkey) *User {
+	return &User{user: user, passkeys: passkeys}
+}
+
+// WebAuthnID returns the user handle, which is the morpheus user ID.
+func (u *User) WebAuthnID() []byte {
+	return []byte(u.user.ID)
+}
+
+// WebAuthnName returns the account name shown by authenticators.
+func (u *User) WebAuthnName() string {
+	return u.user.Email
+}
+
+// WebAuthnDisplayName returns the display name, falling back to the email.
+func (u *User) WebAuthnDisplayName() string {
+	if u.user.Name != nil && *u.user.Name != "" {
+		return *u.user.Name
+	}
+	return u.user.Email
+}
+
+// WebAuthnCredentials returns the user's stored passkeys as library credentials.
+// Records that fail to decode are skipped rather than failing the ceremony.
+func (u *User) WebAuthnCredentials() []webauthn.Credential {
+	creds := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, p := range u.passkeys {
+		c, err := ToCredential(p)
+		if err != nil {
+			continue
+		}
+		creds = append(creds, c)
+	}
+	return creds
+}
+
+// Passkey returns the stored passkey matching a library credential ID.
+func (u *User) Passkey(credentialID []byte) *models.Passkey {
+	id := encoding.EncodeToString(credentialID)
+	for _, p := range u.passkeys {
+		if p.CredentialID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// Exclusions returns descriptors for the user's existing passkeys so that an
+// authenticator does not register a second credential for the same account.
+func (u *User) Exclusions() []protocol.CredentialDescriptor {
+	return webauthn.Credentials(u.WebAuthnCredentials()).CredentialDescriptors()
+}
+
+// ToCredential converts a stored passkey to a library credential.
+func ToCredential(p *models.Passkey) (webauthn.Credential, error) {
+	id, err := encoding.DecodeString(p.CredentialID)
+	if err != nil {
+		return webauthn.Credential{}, fmt.Errorf("passkey: decoding credential id: %w", err)
+	}
+	key, err := encoding.DecodeString(p.PublicKey)
+	if err != nil {
+		return webauthn.Credential{}, fmt.Errorf("passkey: decoding public key: %w", err)
+	}
+	aaguid, err := encoding.DecodeString(p.AAGUID)
+	if err != nil {
+		return webauthn.Credential{}, fmt.Errorf("passkey: decoding aaguid: %w", err)
+	}
+
+	var transports []protocol.AuthenticatorTransport
+	for _, t := range strings.Split(p.Transports, ",") {
+		if t != "" {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+	}
+
+	return webauthn.Credential{
+		ID:              id,
+		PublicKey:       key,
+		AttestationType: p.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: p.BackupEligible,
+			BackupState:    p.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    aaguid,
+			SignCount: uint32(p.SignCount), //nolint:gosec // stored from a uint32 counter
+		},
+	}, nil
+}
+
+// FromCredential builds a new passkey record from a freshly registered credential.
+func FromCredential(userID, name string, c *webauthn.Credential) *models.Passkey {
+	transports := make([]string, len(c.Transport))
+	for i, t := range c.Transport {
+		transports[i] = string(t)
+	}
+	return &models.Passkey{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    encoding.EncodeToString(c.ID),
+		PublicKey:       encoding.EncodeToString(c.PublicKey),
+		AttestationType: c.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          encoding.EncodeToString(c.Authenticator.AAGUID),
+		SignCount:       int64(c.Authenticator.SignCount),
+		BackupEligible:  c.Flags.BackupEligible,
+		BackupState:     c.Flags.BackupState,
+	}
+}
+
+// ApplyAssertion records the result of a successful sign-in on the stored passkey.
+// It returns ErrCloneWarning if the authenticator's counter indicates cloning.
+func ApplyAssertion(p *models.Passkey, c *webauthn.Credential, now time.Time) error {
+	if c.Authenticator.CloneWarning {
+		return ErrCloneWarning
+	}
+	p.SignCount = int64(c.Authenticator.SignCount)
+	p.BackupState = c.Flags.BackupState
+	p.LastUsedAt = &now
+	p.UpdatedAt = now
+	return nil
+}
+
+// EncodeSession serialises ceremony state for storage in a PasskeyChallenge.
+func EncodeSession(s *webauthn.SessionData) (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("passkey: encoding session: %w", err)
+	}
+	return string(b), nil
+}
+
+// DecodeSession restores ceremony state from a PasskeyChallenge.
+func DecodeSession(data string) (webauthn.SessionData, error) {
+	var s webauthn.SessionData
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return webauthn.SessionData{}, fmt.Errorf("passkey: decoding session: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,180 @@
+package passkey
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/zoobzio/sumatra/models"
+)
+
+func testCredential() *webauthn.Credential {
+	return &webauthn.Credential{
+		ID:              []byte{0x01, 0x02, 0x03, 0x04},
+		PublicKey:       []byte{0xa5, 0x01, 0x02},
+		AttestationType: "none",
+		Transport:       []protocol.AuthenticatorTransport{protocol.Internal, protocol.Hybrid},
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: true,
+			BackupState:    true,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    []byte{0xaa, 0xbb},
+			SignCount: 7,
+		},
+	}
+}
+
+func TestNew_Valid(t *testing.T) {
+	rp, err := New("example.com", "Morpheus", []string{"https://example.com"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if rp.Config.AuthenticatorSelection.ResidentKey != protocol.ResidentKeyRequirementRequired {
+		t.Error("expected resident keys to be required for passkeys")
+	}
+}
+
+func TestNew_MissingOrigins(t *testing.T) {
+	if _, err := New("example.com", "Morpheus", nil); err == nil {
+		t.Fatal("expected error when no origins are configured")
+	}
+}
+
+func TestFromCredential_RoundTrip(t *testing.T) {
+	orig := testCredential()
+	p := FromCredential("uid", "Laptop", orig)
+
+	if p.UserID != "uid" || p.Name != "Laptop" {
+		t.Errorf("unexpected owner fields: %+v", p)
+	}
+	if p.Transports != "internal,hybrid" {
+		t.Errorf("transports: got %q", p.Transports)
+	}
+	if p.SignCount != 7 {
+		t.Errorf("sign count: got %d", p.SignCount)
+	}
+
+	back, err := ToCredential(p)
+	if err != nil {
+		t.Fatalf("ToCredential: %v", err)
+	}
+	if !bytes.Equal(back.ID, orig.ID) {
+		t.Errorf("credential id: got %x want %x", back.ID, orig.ID)
+	}
+	if !bytes.Equal(back.PublicKey, orig.PublicKey) {
+		t.Errorf("public key: got %x want %x", back.PublicKey, orig.PublicKey)
+	}
+	if !bytes.Equal(back.Authenticator.AAGUID, orig.Authenticator.AAGUID) {
+		t.Errorf("aaguid: got %x want %x", back.Authenticator.AAGUID, orig.Authenticator.AAGUID)
+	}
+	if len(back.Transport) != 2 {
+		t.Errorf("expected 2 transports, got %d", len(back.Transport))
+	}
+	if !back.Flags.BackupEligible || !back.Flags.BackupState {
+		t.Error("expected backup flags to round-trip")
+	}
+}
+
+func TestToCredential_InvalidEncoding(t *testing.T) {
+	p := &models.Passkey{CredentialID: "!!!", PublicKey: "AA"}
+	if _, err := ToCredential(p); err == nil {
+		t.Fatal("expected error for invalid credential id encoding")
+	}
+}
+
+func TestUser_Adapter(t *testing.T) {
+	name := "Jane Doe"
+	u := &models.User{ID: "uid-123", Email: "jane@example.com", Name: &name}
+	good := FromCredential(u.ID, "Laptop", testCredential())
+	bad := &models.Passkey{CredentialID: "!!!"}
+
+	wu := NewUser(u, []*models.Passkey{good, bad})
+	if string(wu.WebAuthnID()) != "uid-123" {
+		t.Errorf("WebAuthnID: got %q", wu.WebAuthnID())
+	}
+	if wu.WebAuthnName() != "jane@example.com" {
+		t.Errorf("WebAuthnName: got %q", wu.WebAuthnName())
+	}
+	if wu.WebAuthnDisplayName() != "Jane Doe" {
+		t.Errorf("WebAuthnDisplayName: got %q", wu.WebAuthnDisplayName())
+	}
+	if n := len(wu.WebAuthnCredentials()); n != 1 {
+		t.Errorf("expected undecodable passkey to be skipped, got %d credentials", n)
+	}
+	if len(wu.Exclusions()) != 1 {
+		t.Errorf("expected 1 exclusion, got %d", len(wu.Exclusions()))
+	}
+	if wu.Passkey(testCredential().ID) != good {
+		t.Error("expected Passkey lookup by credential ID to find the stored record")
+	}
+	if wu.Passkey([]byte{0xff}) != nil {
+		t.Error("expected Passkey lookup for unknown ID to return nil")
+	}
+}
+
+func TestUser_DisplayNameFallsBackToEmail(t *testing.T) {
+	u := &models.User{ID: "uid", Email: "jane@example.com"}
+	if got := NewUser(u, nil).WebAuthnDisplayName(); got != "jane@example.com" {
+		t.Errorf("WebAuthnDisplayName: got %q", got)
+	}
+}
+
+func TestApplyAssertion_UpdatesCounter(t *testing.T) {
+	p := FromCredential("uid", "Laptop", testCredential())
+	c := testCredential()
+	c.Authenticator.SignCount = 9
+	c.Flags.BackupState = false
+	now := time.Now()
+
+	if err := ApplyAssertion(p, c, now); err != nil {
+		t.Fatalf("ApplyAssertion: %v", err)
+	}
+	if p.SignCount != 9 {
+		t.Errorf("sign count: got %d want 9", p.SignCount)
+	}
+	if p.BackupState {
+		t.Error("expected backup state to be updated")
+	}
+	if p.LastUsedAt == nil || !p.LastUsedAt.Equal(now) {
+		t.Error("expected LastUsedAt to be set")
+	}
+}
+
+func TestApplyAssertion_CloneWarning(t *testing.T) {
+	p := FromCredential("uid", "Laptop", testCredential())
+	c := testCredential()
+	c.Authenticator.CloneWarning = true
+
+	if err := ApplyAssertion(p, c, time.Now()); err != ErrCloneWarning {
+		t.Errorf("expected ErrCloneWarning, got: %v", err)
+	}
+}
+
+func TestSession_RoundTrip(t *testing.T) {
+	orig := &webauthn.SessionData{
+		Challenge:        "challenge-value",
+		RelyingPartyID:   "example.com",
+		UserID:           []byte("uid"),
+		UserVerification: protocol.VerificationRequired,
+	}
+	encoded, err := EncodeSession(orig)
+	if err != nil {
+		t.Fatalf("EncodeSession: %v", err)
+	}
+	decoded, err := DecodeSession(encoded)
+	if err != nil {
+		t.Fatalf("DecodeSession: %v", err)
+	}
+	if decoded.Challenge != orig.Challenge || string(decoded.UserID) != "uid" {
+		t.Errorf("session did not round-trip: %+v", decoded)
+	}
+}
+
+func TestDecodeSession_Invalid(t *testing.T) {
+	if _, err := DecodeSession("not json"); err == nil {
+		t.Fatal("expected error for invalid session data")
+	}
+}
@@ -1,4 +1,7 @@
-// Package password provides Argon2id password hashing and verification.
+// Package password provides Argon2id password hashing and verification, and
+// the policy that decides which passwords users may choose. Hashes imported
+// from other systems (bcrypt, scrypt, Django and Werkzeug PBKDF2) verify too,
+// and are upgraded to Argon2id on the user's next login.
 package password
 
 import (
@@ -30,16 +33,75 @@ var defaults = Parameters{
 	KeyLen:      32,
 }
 
+// DefaultParameters returns the parameters used by Hash.
+func DefaultParameters() Parameters {
+	return defaults
+}
+
+// Validate reports whether p is usable for hashing. The minimums follow RFC 9106.
+func (p Parameters) Validate() error {
+	switch {
+	case p.Time < 1:
+		return fmt.Errorf("%w: time must be at least 1", ErrInvalidParameters)
+	case p.Parallelism < 1:
+		return fmt.Errorf("%w: parallelism must be at least 1", ErrInvalidParameters)
+	case p.Memory < 8*uint32(p.Parallelism):
+		return fmt.Errorf("%w: memory must be at least 8 KiB per lane", ErrInvalidParameters)
+	case p.SaltLen < 8:
+		return fmt.Errorf("%w: salt length must be at least 8 bytes", ErrInvalidParameters)
+	case p.KeyLen < 16:
+		return fmt.Errorf("%w: key length must be at least 16 bytes", ErrInvalidParameters)
+	}
+	return nil
+}
+
+// String formats p as a parameter set label, e.g. "argon2id v=19 m=65536 t=3 p=4 s=16 k=32".
+func (p Parameters) String() string {
+	return fmt.Sprintf("argon2id v=%d m=%d t=%d p=%d s=%d k=%d",
+		argon2.Version, p.Memory, p.Time, p.Parallelism, p.SaltLen, p.KeyLen)
+}
+
 // ErrMalformedHash is returned by Verify when the encoded hash string is invalid.
 var ErrMalformedHash = errors.New("password: malformed hash")
 
+// ErrInvalidParameters is returned when Argon2id parameters are below the safe minimums.
+var ErrInvalidParameters = errors.New("password: invalid argon2id parameters")
+
+// Hasher hashes passwords with a configured set of Argon2id parameters and
+// recognises hashes made with any other set.
+type Hasher struct {
+	params Parameters
+}
+
+// NewHasher returns a Hasher using p.
+func NewHasher(p Parameters) (*Hasher, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &Hasher{params: p}, nil
+}
+
+// defaultHasher backs the package-level Hash.
+var defaultHasher = &Hasher{params: defaults}
+
+// Parameters returns the parameters new hashes are made with.
+func (h *Hasher) Parameters() Parameters {
+	return h.params
+}
+
 // Hash hashes password using Argon2id with secure defaults.
 // The returned string is a self-contained encoded hash that includes the salt
 // and parameters, formatted as:
 //
 //	$argon2id$v=19$m=65536,t=3,p=4$<base64-salt>$<base64-hash>
 func Hash(password string) (string, error) {
-	salt := make([]byte, defaults.SaltLen)
+	return defaultHasher.Hash(password)
+}
+
+// Hash hashes password using the Hasher's parameters, in the same encoding as
+// the package-level Hash.
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return "", fmt.Errorf("password: generating salt: %w", err)
 	}
@@ -47,10 +109,10 @@ func Hash(password string) (string, error) {
 	hash := argon2.IDKey(
 		[]byte(password),
 		salt,
-		defaults.Time,
-		defaults.Memory,
-		defaults.Parallelism,
-		defaults.KeyLen,
+		h.params.Time,
+		h.params.Memory,
+		h.params.Parallelism,
+		h.params.KeyLen,
 	)
 
 	encodedSalt := base64.RawStdEncoding.EncodeToString(salt)
@@ -59,9 +121,9 @@ func Hash(password string) (string, error) {
 	encoded := fmt.Sprintf(
 		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
 		argon2.Version,
-		defaults.Memory,
-		defaults.Time,
-		defaults.Parallelism,
+		h.params.Memory,
+		h.params.Time,
+		h.params.Parallelism,
 		encodedSalt,
 		encodedHash,
 	)
@@ -69,10 +131,40 @@ func Hash(password string) (string, error) {
 	return encoded, nil
 }
 
-// Verify compares password against an encoded hash string produced by Hash.
+// NeedsRehash reports whether hash was made with parameters other than the
+// Hasher's, so it should be replaced the next time the plaintext is known.
+// Legacy encodings and malformed hashes also report true.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	p, _, _, err := parse(hash)
+	return err != nil || p != h.params
+}
+
+// Describe returns the parameter set label of an encoded hash, as formatted by
+// Parameters.String for Argon2id (or "bcrypt cost=12" and the like for legacy
+// encodings), or "unknown" if the hash cannot be parsed.
+func Describe(hash string) string {
+	if p, _, _, err := parse(hash); err == nil {
+		return p.String()
+	}
+	if legacy, err := parseLegacy(hash); err == nil {
+		return legacy.label()
+	}
+	return "unknown"
+}
+
+// Verify compares password against an encoded hash string produced by Hash or
+// imported in one of the legacy encodings described in legacy.go.
 // Returns true if the password matches, false if it does not.
 // Returns ErrMalformedHash if the encoded string cannot be parsed.
 func Verify(password, hash string) (bool, error) {
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		legacy, err := parseLegacy(hash)
+		if err != nil {
+			return false, err
+		}
+		return legacy.verify(password), nil
+	}
+
 	p, salt, hashBytes, err := parse(hash)
 	if err != nil {
 		return false, err
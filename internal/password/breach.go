@@ -0,0 +1,210 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // breach corpora are keyed by SHA-1 digests
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// prefixLen is the number of hex characters of the SHA-1 digest used to select
+// a range, as in the Have I Been Pwned range API.
+const prefixLen = 5
+
+// ErrMalformedCorpus is returned when a breach corpus line is not a SHA-1
+// digest or range suffix.
+var ErrMalformedCorpus = errors.New("malformed breach corpus")
+
+// BreachChecker reports whether a password appears in a breach corpus.
+type BreachChecker interface {
+	Breached(password string) (bool, error)
+}
+
+// rangeKey splits a password's uppercase hex SHA-1 digest into the range
+// prefix and the suffix looked up within that range.
+func rangeKey(password string) (prefix, suffix string) {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // corpus lookup key, not a password hash
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	return digest[:prefixLen], digest[prefixLen:]
+}
+
+// Corpus is an in-memory breach corpus indexed by SHA-1 prefix. Only digests
+// are held, never plaintext passwords.
+type Corpus struct {
+	ranges map[string][]string
+}
+
+// LoadCorpus reads a corpus of SHA-1 digests, one per line in uppercase or
+// lowercase hex, each optionally followed by ":count" as in the Have I Been
+// Pwned ordered-by-hash download. Blank lines and lines starting with '#' are
+// skipped.
+func LoadCorpus(r io.Reader) (*Corpus, error) {
+	c := &Corpus{ranges: make(map[string][]string)}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		digest, ok, err := parseCorpusLine(scanner.Text(), sha1.Size*2)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ok {
+			c.ranges[digest[:prefixLen]] = append(c.ranges[digest[:prefixLen]], digest[prefixLen:])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for _, suffixes := range c.ranges {
+		sort.Strings(suffixes)
+	}
+	return c, nil
+}
+
+// LoadCorpusFile loads a corpus from path; see LoadCorpus for the format.
+func LoadCorpusFile(path string) (*Corpus, error) {
+	f, err := os.Open(path) //nolint:gosec // path comes from operator configuration
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCorpus(f)
+}
+
+// Breached reports whether password's digest is in the corpus.
+func (c *Corpus) Breached(password string) (bool, error) {
+	prefix, suffix := rangeKey(password)
+	suffixes := c.ranges[prefix]
+	i := sort.SearchStrings(suffixes, suffix)
+	return i < len(suffixes) && suffixes[i] == suffix, nil
+}
+
+// Len returns the number of digests in the corpus.
+func (c *Corpus) Len() int {
+	n := 0
+	for _, suffixes := range c.ranges {
+		n += len(suffixes)
+	}
+	return n
+}
+
+//go:embed data/breached.txt
+var bundledCorpus string
+
+var (
+	bundledOnce sync.Once
+	bundled     *Corpus
+)
+
+// BundledCorpus returns the corpus shipped with the binary: digests of the most
+// common passwords and their trivial variations. It is small enough to keep in
+// memory and works offline; point RangeDir at a full range download for wider
+// coverage.
+func BundledCorpus() *Corpus {
+	bundledOnce.Do(func() {
+		c, err := LoadCorpus(strings.NewReader(bundledCorpus))
+		if err != nil {
+			panic("password: bundled breach corpus: " + err.Error())
+		}
+		bundled = c
+	})
+	return bundled
+}
+
+// RangeDir checks passwords against a directory of range files, one per SHA-1
+// prefix, named after the five-character uppercase prefix (optionally with a
+// ".txt" extension) and holding "SUFFIX:COUNT" lines, the layout produced by the
+// Have I Been Pwned downloader. Only the single range file for a password is
+// read per check, so the full corpus can be used offline without loading it
+// into memory.
+type RangeDir struct {
+	dir string
+}
+
+// NewRangeDir returns a RangeDir reading from dir, which must exist.
+func NewRangeDir(dir string) (*RangeDir, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s: not a directory", dir)
+	}
+	return &RangeDir{dir: dir}, nil
+}
+
+// Breached reports whether password's digest suffix is in its range file. A
+// missing range file means no breached password has that prefix.
+func (d *RangeDir) Breached(password string) (bool, error) {
+	prefix, suffix := rangeKey(password)
+
+	f, err := os.Open(filepath.Join(d.dir, prefix))
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(filepath.Join(d.dir, prefix+".txt"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		entry, ok, err := parseCorpusLine(scanner.Text(), sha1.Size*2-prefixLen)
+		if err != nil {
+			return false, fmt.Errorf("%s line %d: %w", prefix, line, err)
+		}
+		if ok && entry == suffix {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// OpenBreachChecker returns a checker for path: the bundled corpus when path is
+// empty, a RangeDir when it is a directory, and a loaded Corpus otherwise.
+func OpenBreachChecker(path string) (BreachChecker, error) {
+	if path == "" {
+		return BundledCorpus(), nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return NewRangeDir(path)
+	}
+	return LoadCorpusFile(path)
+}
+
+const hexDigits = "0123456789abcdefABCDEF"
+
+// parseCorpusLine returns the uppercase hex digest (or suffix) of wantLen
+// characters on line, dropping any ":count". ok is false for blank and
+// comment lines.
+func parseCorpusLine(line string, wantLen int) (digest string, ok bool, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false, nil
+	}
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	if len(line) != wantLen {
+		return "", false, ErrMalformedCorpus
+	}
+	for _, r := range line {
+		if !strings.ContainsRune(hexDigits, r) {
+			return "", false, ErrMalformedCorpus
+		}
+	}
+	return strings.ToUpper(line), true, nil
+}
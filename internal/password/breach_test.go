@@ -0,0 +1,97 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sha1("hunter2") = F3BBBD66A63D4BF1747940578EC3D0103530E21D
+const hunter2Digest = "F3BBBD66A63D4BF1747940578EC3D0103530E21D"
+
+func TestLoadCorpus(t *testing.T) {
+	input := "# comment\n\n" + strings.ToLower(hunter2Digest) + ":17\n" +
+		"0000000000000000000000000000000000000000\n"
+	c, err := LoadCorpus(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadCorpus: %v", err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len: got %d want 2", c.Len())
+	}
+	if ok, _ := c.Breached("hunter2"); !ok {
+		t.Error("expected hunter2 to be breached")
+	}
+	if ok, _ := c.Breached("hunter3"); ok {
+		t.Error("expected hunter3 not to be breached")
+	}
+}
+
+func TestLoadCorpus_Malformed(t *testing.T) {
+	for _, input := range []string{"F3BBBD\n", strings.Repeat("Z", 40) + "\n"} {
+		if _, err := LoadCorpus(strings.NewReader(input)); err == nil {
+			t.Errorf("LoadCorpus(%q): expected error", input)
+		}
+	}
+}
+
+func TestBundledCorpus(t *testing.T) {
+	c := BundledCorpus()
+	for _, pw := range []string{"password", "Password1", "qwerty123", "letmein!"} {
+		if ok, _ := c.Breached(pw); !ok {
+			t.Errorf("expected %q in bundled corpus", pw)
+		}
+	}
+	if ok, _ := c.Breached("tangerine-orbit-walnut-42"); ok {
+		t.Error("expected random passphrase not to be in bundled corpus")
+	}
+}
+
+func TestRangeDir(t *testing.T) {
+	dir := t.TempDir()
+	body := "0018A45C4D1DEF81644B54AB7F969B88D65:1\n" + hunter2Digest[prefixLen:] + ":17\n"
+	if err := os.WriteFile(filepath.Join(dir, hunter2Digest[:prefixLen]+".txt"), []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewRangeDir(dir)
+	if err != nil {
+		t.Fatalf("NewRangeDir: %v", err)
+	}
+	if ok, err := d.Breached("hunter2"); err != nil || !ok {
+		t.Errorf("Breached(hunter2): got %v, %v want true", ok, err)
+	}
+	if ok, err := d.Breached("not-in-any-range"); err != nil || ok {
+		t.Errorf("Breached without range file: got %v, %v want false", ok, err)
+	}
+}
+
+func TestOpenBreachChecker(t *testing.T) {
+	if c, err := OpenBreachChecker(""); err != nil || c != BundledCorpus() {
+		t.Errorf("empty path: got %v, %v want bundled corpus", c, err)
+	}
+
+	dir := t.TempDir()
+	if c, err := OpenBreachChecker(dir); err != nil {
+		t.Errorf("directory: %v", err)
+	} else if _, ok := c.(*RangeDir); !ok {
+		t.Errorf("directory: got %T want *RangeDir", c)
+	}
+
+	file := filepath.Join(dir, "corpus.txt")
+	if err := os.WriteFile(file, []byte(hunter2Digest+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := OpenBreachChecker(file)
+	if err != nil {
+		t.Fatalf("file: %v", err)
+	}
+	if ok, _ := c.Breached("hunter2"); !ok {
+		t.Error("file: expected hunter2 to be breached")
+	}
+
+	if _, err := OpenBreachChecker(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing path: expected error")
+	}
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed data/common.txt
+var commonPasswords string
+
+// commonPasswordRanks maps each bundled common password to its 1-based rank,
+// most common first.
+var commonPasswordRanks = parseRanks(commonPasswords)
+
+func parseRanks(list string) map[string]int {
+	ranks := make(map[string]int)
+	for _, line := range strings.Split(list, "\n") {
+		word := strings.ToLower(strings.TrimSpace(line))
+		if word == "" {
+			continue
+		}
+		if _, ok := ranks[word]; !ok {
+			ranks[word] = len(ranks) + 1
+		}
+	}
+	return ranks
+}
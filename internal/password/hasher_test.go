@@ -0,0 +1,87 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// cheap keeps the tests fast; it is still a valid parameter set.
+var cheap = Parameters{Memory: 64, Time: 1, Parallelism: 1, SaltLen: 16, KeyLen: 32}
+
+func TestNewHasher_RejectsWeakParameters(t *testing.T) {
+	cases := map[string]Parameters{
+		"time":        {Memory: 64, Time: 0, Parallelism: 1, SaltLen: 16, KeyLen: 32},
+		"parallelism": {Memory: 64, Time: 1, Parallelism: 0, SaltLen: 16, KeyLen: 32},
+		"memory":      {Memory: 15, Time: 1, Parallelism: 2, SaltLen: 16, KeyLen: 32},
+		"salt":        {Memory: 64, Time: 1, Parallelism: 1, SaltLen: 4, KeyLen: 32},
+		"key":         {Memory: 64, Time: 1, Parallelism: 1, SaltLen: 16, KeyLen: 8},
+	}
+	for name, p := range cases {
+		if _, err := NewHasher(p); !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("%s: expected ErrInvalidParameters, got %v", name, err)
+		}
+	}
+}
+
+func TestHasher_HashUsesParameters(t *testing.T) {
+	h, err := NewHasher(cheap)
+	if err != nil {
+		t.Fatalf("NewHasher: %v", err)
+	}
+	encoded, err := h.Hash("hunter2")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("unexpected encoded hash %q", encoded)
+	}
+	if ok, err := Verify("hunter2", encoded); err != nil || !ok {
+		t.Errorf("Verify: got %v, %v want true", ok, err)
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	h, _ := NewHasher(cheap)
+	current, _ := h.Hash("hunter2")
+	if h.NeedsRehash(current) {
+		t.Error("hash with current parameters should not need rehash")
+	}
+
+	for name, p := range map[string]Parameters{
+		"memory": {Memory: 128, Time: 1, Parallelism: 1, SaltLen: 16, KeyLen: 32},
+		"time":   {Memory: 64, Time: 2, Parallelism: 1, SaltLen: 16, KeyLen: 32},
+		"salt":   {Memory: 64, Time: 1, Parallelism: 1, SaltLen: 32, KeyLen: 32},
+		"key":    {Memory: 64, Time: 1, Parallelism: 1, SaltLen: 16, KeyLen: 64},
+	} {
+		other, _ := NewHasher(p)
+		old, _ := other.Hash("hunter2")
+		if !h.NeedsRehash(old) {
+			t.Errorf("%s: hash with outdated parameters should need rehash", name)
+		}
+	}
+
+	if !h.NeedsRehash("not-a-hash") {
+		t.Error("malformed hash should need rehash")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	h, _ := NewHasher(cheap)
+	encoded, _ := h.Hash("hunter2")
+	if got, want := Describe(encoded), "argon2id v=19 m=64 t=1 p=1 s=16 k=32"; got != want {
+		t.Errorf("Describe: got %q want %q", got, want)
+	}
+	if got := Describe(encoded); got != cheap.String() {
+		t.Errorf("Describe should match Parameters.String: got %q want %q", got, cheap.String())
+	}
+	if got := Describe("$2a$10$abcdefghijklmnopqrstuv"); got != "unknown" {
+		t.Errorf("Describe(unknown): got %q", got)
+	}
+}
+
+func TestDefaultParameters(t *testing.T) {
+	if err := DefaultParameters().Validate(); err != nil {
+		t.Errorf("defaults should be valid: %v", err)
+	}
+}
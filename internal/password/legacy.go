@@ -0,0 +1,252 @@
+package password
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha1" //nolint:gosec // Django and Werkzeug support PBKDF2-SHA1 hashes
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Hashes imported from other systems are verified in their original encoding
+// and never produced. Every one of them reports NeedsRehash, so a user's first
+// successful login replaces it with Argon2id. The supported encodings are:
+//
+//	$2a$10$<salt+hash>                          bcrypt ($2a$, $2b$, $2y$)
+//	$scrypt$ln=15,r=8,p=1$<b64-salt>$<b64-hash>  scrypt, PHC / passlib
+//	pbkdf2_sha256$<iter>$<salt>$<b64-hash>      Django (pbkdf2_sha1 too)
+//	pbkdf2:sha256:<iter>$<salt>$<hex-hash>      Werkzeug (sha1, sha512 too)
+//	scrypt:<n>:<r>:<p>$<salt>$<hex-hash>        Werkzeug scrypt
+
+// Bounds on legacy work factors, so a corrupt or hostile import cannot make a
+// single login take minutes.
+const (
+	maxPBKDF2Iterations = 10_000_000
+	maxScryptLogN       = 20
+	maxScryptR          = 32
+	maxScryptP          = 16
+	maxLegacyKeyLen     = 128
+)
+
+// bcryptMaxPassword is the number of password bytes bcrypt uses. Other
+// implementations silently truncate longer passwords, so verification does too.
+const bcryptMaxPassword = 72
+
+// legacyHash is a parsed hash in one of the imported encodings.
+type legacyHash interface {
+	verify(password string) bool
+	label() string
+}
+
+// parseLegacy parses hash in any supported imported encoding.
+func parseLegacy(encoded string) (legacyHash, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return parseBcrypt(encoded)
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		return parsePHCScrypt(encoded)
+	case strings.HasPrefix(encoded, "pbkdf2_"):
+		return parseDjango(encoded)
+	case strings.HasPrefix(encoded, "pbkdf2:"):
+		return parseWerkzeugPBKDF2(encoded)
+	case strings.HasPrefix(encoded, "scrypt:"):
+		return parseWerkzeugScrypt(encoded)
+	}
+	return nil, ErrMalformedHash
+}
+
+// Supported reports whether hash is in an encoding Verify understands:
+// Argon2id or one of the imported legacy encodings.
+func Supported(hash string) bool {
+	if _, _, _, err := parse(hash); err == nil {
+		return true
+	}
+	_, err := parseLegacy(hash)
+	return err == nil
+}
+
+type bcryptHash struct {
+	encoded []byte
+	cost    int
+}
+
+func parseBcrypt(encoded string) (legacyHash, error) {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return nil, ErrMalformedHash
+	}
+	return bcryptHash{encoded: []byte(encoded), cost: cost}, nil
+}
+
+func (h bcryptHash) verify(password string) bool {
+	pw := []byte(password)
+	if len(pw) > bcryptMaxPassword {
+		pw = pw[:bcryptMaxPassword]
+	}
+	return bcrypt.CompareHashAndPassword(h.encoded, pw) == nil
+}
+
+func (h bcryptHash) label() string {
+	return fmt.Sprintf("bcrypt cost=%d", h.cost)
+}
+
+type scryptHash struct {
+	logN, r, p int
+	salt, key  []byte
+}
+
+// parsePHCScrypt parses "$scrypt$ln=15,r=8,p=1$<salt>$<hash>". Salt and hash
+// are unpadded base64; passlib's "." in place of "+" is accepted.
+func parsePHCScrypt(encoded string) (legacyHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 {
+		return nil, ErrMalformedHash
+	}
+	var h scryptHash
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &h.logN, &h.r, &h.p); err != nil {
+		return nil, ErrMalformedHash
+	}
+	var err error
+	if h.salt, err = decodeAdaptedBase64(parts[3]); err != nil {
+		return nil, ErrMalformedHash
+	}
+	if h.key, err = decodeAdaptedBase64(parts[4]); err != nil {
+		return nil, ErrMalformedHash
+	}
+	return h, h.check()
+}
+
+// parseWerkzeugScrypt parses "scrypt:<n>:<r>:<p>$<salt>$<hex-hash>", where the
+// salt is used as text.
+func parseWerkzeugScrypt(encoded string) (legacyHash, error) {
+	method, salt, digest, err := splitWerkzeug(encoded)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(method, ":")
+	if len(fields) != 4 {
+		return nil, ErrMalformedHash
+	}
+	n, err1 := strconv.Atoi(fields[1])
+	r, err2 := strconv.Atoi(fields[2])
+	p, err3 := strconv.Atoi(fields[3])
+	if err1 != nil || err2 != nil || err3 != nil || n < 2 || n&(n-1) != 0 {
+		return nil, ErrMalformedHash
+	}
+	h := scryptHash{r: r, p: p, salt: []byte(salt), key: digest}
+	for ; n > 1; n >>= 1 {
+		h.logN++
+	}
+	return h, h.check()
+}
+
+func (h scryptHash) check() error {
+	if h.logN < 1 || h.logN > maxScryptLogN || h.r < 1 || h.r > maxScryptR || h.p < 1 || h.p > maxScryptP ||
+		len(h.key) == 0 || len(h.key) > maxLegacyKeyLen {
+		return ErrMalformedHash
+	}
+	return nil
+}
+
+func (h scryptHash) verify(password string) bool {
+	candidate, err := scrypt.Key([]byte(password), h.salt, 1<<h.logN, h.r, h.p, len(h.key))
+	return err == nil && subtle.ConstantTimeCompare(candidate, h.key) == 1
+}
+
+func (h scryptHash) label() string {
+	return fmt.Sprintf("scrypt ln=%d r=%d p=%d", h.logN, h.r, h.p)
+}
+
+type pbkdf2Hash struct {
+	digest     string
+	newHash    func() hash.Hash
+	iterations int
+	salt, key  []byte
+}
+
+// pbkdf2Digests maps the digest names used by Django and Werkzeug to their hash functions.
+var pbkdf2Digests = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// parseDjango parses "pbkdf2_sha256$<iter>$<salt>$<b64-hash>".
+func parseDjango(encoded string) (legacyHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 {
+		return nil, ErrMalformedHash
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, ErrMalformedHash
+	}
+	key, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrMalformedHash
+	}
+	return newPBKDF2Hash(strings.TrimPrefix(parts[0], "pbkdf2_"), iterations, []byte(parts[2]), key)
+}
+
+// parseWerkzeugPBKDF2 parses "pbkdf2:sha256:<iter>$<salt>$<hex-hash>".
+func parseWerkzeugPBKDF2(encoded string) (legacyHash, error) {
+	method, salt, key, err := splitWerkzeug(encoded)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(method, ":")
+	if len(fields) != 3 {
+		return nil, ErrMalformedHash
+	}
+	iterations, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, ErrMalformedHash
+	}
+	return newPBKDF2Hash(fields[1], iterations, []byte(salt), key)
+}
+
+func newPBKDF2Hash(digest string, iterations int, salt, key []byte) (legacyHash, error) {
+	newHash, ok := pbkdf2Digests[digest]
+	if !ok || iterations < 1 || iterations > maxPBKDF2Iterations || len(key) == 0 || len(key) > maxLegacyKeyLen {
+		return nil, ErrMalformedHash
+	}
+	return pbkdf2Hash{digest: digest, newHash: newHash, iterations: iterations, salt: salt, key: key}, nil
+}
+
+func (h pbkdf2Hash) verify(password string) bool {
+	candidate, err := pbkdf2.Key(h.newHash, password, h.salt, h.iterations, len(h.key))
+	return err == nil && subtle.ConstantTimeCompare(candidate, h.key) == 1
+}
+
+func (h pbkdf2Hash) label() string {
+	return fmt.Sprintf("pbkdf2-%s i=%d", h.digest, h.iterations)
+}
+
+// splitWerkzeug splits "<method>$<salt>$<hex-hash>".
+func splitWerkzeug(encoded string) (method, salt string, key []byte, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 3 || parts[1] == "" {
+		return "", "", nil, ErrMalformedHash
+	}
+	key, err = hex.DecodeString(parts[2])
+	if err != nil {
+		return "", "", nil, ErrMalformedHash
+	}
+	return parts[0], parts[1], key, nil
+}
+
+// decodeAdaptedBase64 decodes standard base64 with or without padding,
+// accepting passlib's "." for "+".
+func decodeAdaptedBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.ReplaceAll(s, ".", "+"), "=")
+	return base64.RawStdEncoding.DecodeString(s)
+}
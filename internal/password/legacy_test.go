@@ -0,0 +1,122 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Vectors for "hunter2", generated with Python's hashlib at low work factors.
+var legacyVectors = map[string]struct {
+	hash  string
+	label string
+}{
+	"django sha256": {
+		"pbkdf2_sha256$1000$seasalt123$Z8W6THQ7/q7YZax54QizjDcL3C+cgOKb60X7lQWjODc=",
+		"pbkdf2-sha256 i=1000",
+	},
+	"django sha1": {
+		"pbkdf2_sha1$1000$seasalt123$4QzbsllGbK2jlHACY/yjnPDNWls=",
+		"pbkdf2-sha1 i=1000",
+	},
+	"werkzeug sha256": {
+		"pbkdf2:sha256:1000$wzsalt$899c2ef175336bcb16a9b34d1d12fdfb079b29b73f724518c04a965ba57415be",
+		"pbkdf2-sha256 i=1000",
+	},
+	"werkzeug sha512": {
+		"pbkdf2:sha512:1000$wzsalt$a5d3c05e1cf142c2ec0ec7304c61b168662361a86d9b1ff019a284d8214209c2541807fad701d8581ca87541c334a4405a0bd78057e7770f97876dc7e7cb0b12",
+		"pbkdf2-sha512 i=1000",
+	},
+	"werkzeug scrypt": {
+		"scrypt:1024:8:1$wzsalt$d1142e98cf1b21302bbcbc78521d325cce67089fc9ac3e39cd707ebd842cfbf6728e96789d118b16c158d021c9d1cd36dddae75f1ab82f99b05f33281f9756a4",
+		"scrypt ln=10 r=8 p=1",
+	},
+	"phc scrypt": {
+		"$scrypt$ln=10,r=8,p=1$MDEyMzQ1Njc4OWFiY2RlZg$xhygCB++/lnqkJuXyqpuqIwyXp1fZuC+q3d168khIUA",
+		"scrypt ln=10 r=8 p=1",
+	},
+	"passlib scrypt": {
+		"$scrypt$ln=10,r=8,p=1$MDEyMzQ1Njc4OWFiY2RlZg$xhygCB../lnqkJuXyqpuqIwyXp1fZuC.q3d168khIUA",
+		"scrypt ln=10 r=8 p=1",
+	},
+}
+
+func TestVerify_LegacyEncodings(t *testing.T) {
+	for name, v := range legacyVectors {
+		if ok, err := Verify("hunter2", v.hash); err != nil || !ok {
+			t.Errorf("%s: Verify(correct) got %v, %v want true", name, ok, err)
+		}
+		if ok, err := Verify("hunter3", v.hash); err != nil || ok {
+			t.Errorf("%s: Verify(wrong) got %v, %v want false", name, ok, err)
+		}
+		if got := Describe(v.hash); got != v.label {
+			t.Errorf("%s: Describe got %q want %q", name, got, v.label)
+		}
+		if !Supported(v.hash) {
+			t.Errorf("%s: expected Supported", name)
+		}
+	}
+}
+
+func TestVerify_Bcrypt(t *testing.T) {
+	encoded, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// PHP writes $2y$ for the same algorithm.
+	for _, h := range []string{string(encoded), "$2y$" + string(encoded[4:])} {
+		if ok, err := Verify("hunter2", h); err != nil || !ok {
+			t.Errorf("Verify(%q): got %v, %v want true", h, ok, err)
+		}
+		if ok, _ := Verify("hunter3", h); ok {
+			t.Errorf("Verify(%q, wrong): got true", h)
+		}
+		if got := Describe(h); got != "bcrypt cost=4" {
+			t.Errorf("Describe: got %q", got)
+		}
+	}
+}
+
+func TestVerify_BcryptTruncatesLongPasswords(t *testing.T) {
+	long := strings.Repeat("a", bcryptMaxPassword)
+	encoded, err := bcrypt.GenerateFromPassword([]byte(long), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := Verify(long+"ignored", string(encoded)); err != nil || !ok {
+		t.Errorf("expected bytes past 72 to be ignored, got %v, %v", ok, err)
+	}
+}
+
+func TestVerify_MalformedLegacy(t *testing.T) {
+	cases := []string{
+		"$2a$04$short",
+		"$scrypt$ln=10,r=8$c2FsdA$aGFzaA",
+		"$scrypt$ln=40,r=8,p=1$c2FsdA$aGFzaA", // work factor too high
+		"pbkdf2_md5$1000$salt$aGFzaA==",       // unsupported digest
+		"pbkdf2_sha256$notanumber$salt$aGFzaA==",
+		"pbkdf2_sha256$99999999$salt$aGFzaA==", // too many iterations
+		"pbkdf2:sha256$wzsalt$00ff",            // missing iterations
+		"pbkdf2:sha256:1000$wzsalt$nothex",
+		"scrypt:1000:8:1$wzsalt$00ff", // n not a power of two
+	}
+	for _, tc := range cases {
+		if ok, err := Verify("hunter2", tc); !errors.Is(err, ErrMalformedHash) || ok {
+			t.Errorf("Verify(%q): got %v, %v want ErrMalformedHash", tc, ok, err)
+		}
+		if Supported(tc) {
+			t.Errorf("Supported(%q): got true", tc)
+		}
+	}
+}
+
+func TestHasher_NeedsRehashLegacy(t *testing.T) {
+	h, _ := NewHasher(cheap)
+	for name, v := range legacyVectors {
+		if !h.NeedsRehash(v.hash) {
+			t.Errorf("%s: legacy hash should need rehash", name)
+		}
+	}
+}
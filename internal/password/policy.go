@@ -0,0 +1,156 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rules a password can violate. They are stable identifiers clients can map to
+// their own copy.
+const (
+	RuleMinLength     = "min_length"
+	RuleMaxLength     = "max_length"
+	RuleLowercase     = "lowercase"
+	RuleUppercase     = "uppercase"
+	RuleDigit         = "digit"
+	RuleSymbol        = "symbol"
+	RuleContainsEmail = "contains_email"
+	RuleBreached      = "breached"
+	RuleTooWeak       = "too_weak"
+)
+
+// Violation describes one rule a password failed.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PolicyError is returned by Policy.Check when a password violates one or more
+// rules.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return "password rejected: " + strings.Join(msgs, "; ")
+}
+
+// Policy decides which passwords users may choose.
+type Policy struct {
+	// MinLength and MaxLength bound the length in characters. MaxLength also
+	// caps the work done by the strength estimate and hashing.
+	MinLength int
+	MaxLength int
+	// RequireLower, RequireUpper, RequireDigit and RequireSymbol demand at
+	// least one character of each enabled class.
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MinScore is the lowest acceptable Estimate score, 0 to 4. Zero disables
+	// the strength check.
+	MinScore int
+	// Breaches, if set, rejects passwords found in a breach corpus.
+	Breaches BreachChecker
+}
+
+// Check validates password against the policy for the account with the given
+// email. It returns a *PolicyError listing every violated rule, or another
+// error if the breach corpus could not be read.
+func (p *Policy) Check(password, email string) error {
+	length := utf8.RuneCountInString(password)
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &PolicyError{Violations: []Violation{{
+			Rule:    RuleMaxLength,
+			Message: fmt.Sprintf("must be at most %d characters", p.MaxLength),
+		}}}
+	}
+
+	var violations []Violation
+	add := func(rule, msg string) {
+		violations = append(violations, Violation{Rule: rule, Message: msg})
+	}
+
+	if length < p.MinLength {
+		add(RuleMinLength, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireLower && !lower {
+		add(RuleLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireUpper && !upper {
+		add(RuleUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireDigit && !digit {
+		add(RuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		add(RuleSymbol, "must contain a symbol")
+	}
+
+	userInputs := emailInputs(email)
+	if containsAny(strings.ToLower(password), userInputs) {
+		add(RuleContainsEmail, "must not contain your email address")
+	}
+
+	if p.Breaches != nil {
+		breached, err := p.Breaches.Breached(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			add(RuleBreached, "has appeared in a data breach; choose a different password")
+		}
+	}
+
+	if p.MinScore > 0 && Estimate(password, userInputs...).Score < p.MinScore {
+		add(RuleTooWeak, "is too easy to guess; try a longer passphrase")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// emailInputs returns the lowercased email and its local part, the parts of an
+// address an attacker would try inside the password.
+func emailInputs(email string) []string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil
+	}
+	inputs := []string{email}
+	if at := strings.LastIndexByte(email, '@'); at >= minPatternLen {
+		inputs = append(inputs, email[:at])
+	}
+	return inputs
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if sub != "" && strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func rules(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var pe *PolicyError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *PolicyError, got %T: %v", err, err)
+	}
+	out := make([]string, len(pe.Violations))
+	for i, v := range pe.Violations {
+		out[i] = v.Rule
+	}
+	return out
+}
+
+func hasRule(rs []string, rule string) bool {
+	for _, r := range rs {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPolicy_AcceptsStrongPassword(t *testing.T) {
+	p := &Policy{MinLength: 8, MaxLength: 128, MinScore: 3, Breaches: BundledCorpus()}
+	if err := p.Check("tangerine-orbit-walnut-42", "jane@example.com"); err != nil {
+		t.Errorf("expected strong password to pass, got %v", err)
+	}
+}
+
+func TestPolicy_Length(t *testing.T) {
+	p := &Policy{MinLength: 10, MaxLength: 20}
+	if rs := rules(t, p.Check("short", "")); !hasRule(rs, RuleMinLength) {
+		t.Errorf("expected %s, got %v", RuleMinLength, rs)
+	}
+	rs := rules(t, p.Check(strings.Repeat("x", 21), ""))
+	if len(rs) != 1 || rs[0] != RuleMaxLength {
+		t.Errorf("expected only %s, got %v", RuleMaxLength, rs)
+	}
+	// Length counts characters, not bytes.
+	if err := p.Check(strings.Repeat("é", 10), ""); err != nil {
+		t.Errorf("expected 10 multi-byte characters to pass, got %v", err)
+	}
+}
+
+func TestPolicy_CharacterClasses(t *testing.T) {
+	p := &Policy{RequireLower: true, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+	rs := rules(t, p.Check("abcdefgh", ""))
+	for _, want := range []string{RuleUppercase, RuleDigit, RuleSymbol} {
+		if !hasRule(rs, want) {
+			t.Errorf("expected %s, got %v", want, rs)
+		}
+	}
+	if hasRule(rs, RuleLowercase) {
+		t.Errorf("unexpected %s in %v", RuleLowercase, rs)
+	}
+	if err := p.Check("aB3$", ""); err != nil {
+		t.Errorf("expected all classes to pass, got %v", err)
+	}
+}
+
+func TestPolicy_ContainsEmail(t *testing.T) {
+	p := &Policy{}
+	for _, pw := range []string{"Jane.Doe@Example.com!", "xx-jane.doe-xx"} {
+		if rs := rules(t, p.Check(pw, "jane.doe@example.com")); !hasRule(rs, RuleContainsEmail) {
+			t.Errorf("Check(%q): expected %s, got %v", pw, RuleContainsEmail, rs)
+		}
+	}
+	if err := p.Check("unrelated-passphrase", "jane.doe@example.com"); err != nil {
+		t.Errorf("expected unrelated password to pass, got %v", err)
+	}
+}
+
+func TestPolicy_Breached(t *testing.T) {
+	p := &Policy{Breaches: BundledCorpus()}
+	if rs := rules(t, p.Check("iloveyou", "")); !hasRule(rs, RuleBreached) {
+		t.Errorf("expected %s, got %v", RuleBreached, rs)
+	}
+}
+
+type failingChecker struct{}
+
+func (failingChecker) Breached(string) (bool, error) { return false, errors.New("corpus unavailable") }
+
+func TestPolicy_BreachCheckerError(t *testing.T) {
+	p := &Policy{Breaches: failingChecker{}}
+	err := p.Check("anything-at-all", "")
+	var pe *PolicyError
+	if err == nil || errors.As(err, &pe) {
+		t.Errorf("expected corpus error, got %v", err)
+	}
+}
+
+func TestPolicy_TooWeak(t *testing.T) {
+	p := &Policy{MinScore: 3}
+	if rs := rules(t, p.Check("abcdefgh", "")); !hasRule(rs, RuleTooWeak) {
+		t.Errorf("expected %s, got %v", RuleTooWeak, rs)
+	}
+}
+
+func TestPolicyError_Error(t *testing.T) {
+	err := &PolicyError{Violations: []Violation{
+		{Rule: RuleMinLength, Message: "must be at least 8 characters"},
+		{Rule: RuleBreached, Message: "has appeared in a data breach"},
+	}}
+	want := "password rejected: must be at least 8 characters; has appeared in a data breach"
+	if err.Error() != want {
+		t.Errorf("Error: got %q want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,244 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Strength is a zxcvbn-style estimate of how hard a password is to guess.
+type Strength struct {
+	// Guesses is the estimated number of attempts an attacker needs.
+	Guesses float64
+	// Score buckets Guesses from 0 (trivially guessable) to 4 (very strong).
+	Score int
+}
+
+// Score thresholds on log10(guesses), matching zxcvbn's buckets.
+var scoreThresholds = [...]float64{3, 6, 8, 10}
+
+// bruteforceCardinality is the per-character guess multiplier for characters
+// not covered by any recognised pattern.
+const bruteforceCardinality = 10
+
+// minPatternLen is the shortest run treated as a sequence, repeat or keyboard walk.
+const minPatternLen = 3
+
+// Estimate scores a password the way zxcvbn does: it finds the cheapest way to
+// cover the password with recognised patterns (common passwords, words in
+// userInputs, sequences, repeats, keyboard walks, years) and bruteforced
+// characters, and takes the product of each segment's guesses. Matching is
+// case-insensitive and undoes common l33t substitutions.
+func Estimate(password string, userInputs ...string) Strength {
+	runes := []rune(password)
+	n := len(runes)
+	if n == 0 {
+		return Strength{Guesses: 1}
+	}
+
+	lower := []rune(strings.ToLower(password))
+	dict := rankedDictionary(userInputs)
+
+	// best[i] is the fewest guesses needed to cover runes[:i].
+	best := make([]float64, n+1)
+	best[0] = 1
+	for i := 1; i <= n; i++ {
+		best[i] = math.Inf(1)
+		for j := 0; j < i; j++ {
+			g := best[j] * segmentGuesses(runes[j:i], lower[j:i], dict)
+			if g < best[i] {
+				best[i] = g
+			}
+		}
+	}
+
+	guesses := math.Max(best[n], 1)
+	return Strength{Guesses: guesses, Score: scoreFor(guesses)}
+}
+
+func scoreFor(guesses float64) int {
+	l := math.Log10(guesses)
+	for score, threshold := range scoreThresholds {
+		if l < threshold {
+			return score
+		}
+	}
+	return len(scoreThresholds)
+}
+
+// segmentGuesses returns the cheapest estimate for a single segment: the
+// bruteforce cost, or less if the segment matches a pattern.
+func segmentGuesses(orig, lower []rune, dict map[string]int) float64 {
+	g := math.Pow(bruteforceCardinality, float64(len(orig)))
+	if len(orig) == 1 {
+		return g
+	}
+	if d, ok := dictionaryGuesses(orig, lower, dict); ok && d < g {
+		g = d
+	}
+	if len(orig) < minPatternLen {
+		return g
+	}
+	for _, match := range []func([]rune) (float64, bool){repeatGuesses, sequenceGuesses, keyboardGuesses, yearGuesses} {
+		if p, ok := match(lower); ok && p < g {
+			g = p
+		}
+	}
+	return g
+}
+
+// dictionaryGuesses matches the segment, forwards or reversed and with l33t
+// substitutions undone, against the ranked dictionary. Rank is the base guess
+// count, with multipliers for the variations an attacker would also try.
+func dictionaryGuesses(orig, lower []rune, dict map[string]int) (float64, bool) {
+	word := string(lower)
+	multiplier := 1.0
+	if hasUpper(orig) {
+		multiplier *= 2
+	}
+
+	if rank, ok := dict[word]; ok {
+		return float64(rank) * multiplier, true
+	}
+	if rank, ok := dict[reverse(word)]; ok {
+		return float64(rank) * multiplier * 2, true
+	}
+	if plain := unleet(word); plain != word {
+		if rank, ok := dict[plain]; ok {
+			return float64(rank) * multiplier * 2, true
+		}
+	}
+	return 0, false
+}
+
+// repeatGuesses matches a segment made of one unit repeated ("aaaa", "abcabc").
+func repeatGuesses(s []rune) (float64, bool) {
+	for unit := 1; unit <= len(s)/2; unit++ {
+		if len(s)%unit != 0 {
+			continue
+		}
+		repeated := true
+		for i := unit; i < len(s); i++ {
+			if s[i] != s[i-unit] {
+				repeated = false
+				break
+			}
+		}
+		if repeated {
+			base := math.Pow(bruteforceCardinality, float64(unit))
+			return base * float64(len(s)/unit), true
+		}
+	}
+	return 0, false
+}
+
+// sequenceGuesses matches runs with a constant step of ±1 ("abcd", "9876").
+func sequenceGuesses(s []rune) (float64, bool) {
+	delta := s[1] - s[0]
+	if delta != 1 && delta != -1 {
+		return 0, false
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i]-s[i-1] != delta {
+			return 0, false
+		}
+	}
+
+	var base float64
+	switch {
+	case s[0] == 'a' || s[0] == 'z' || s[0] == '0' || s[0] == '1' || s[0] == '9':
+		base = 4
+	case unicode.IsDigit(s[0]):
+		base = 10
+	default:
+		base = 26
+	}
+	if delta < 0 {
+		base *= 2
+	}
+	return base * float64(len(s)), true
+}
+
+// keyboardRows lists the rows of a QWERTY layout, unshifted and shifted.
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"~!@#$%^&*()_+", "qwertyuiop{}|", "asdfghjkl:\"", "zxcvbnm<>?",
+}
+
+// keyboardGuesses matches straight walks along a keyboard row ("qwerty", "lkjh", "!@#$").
+func keyboardGuesses(s []rune) (float64, bool) {
+	walk := string(s)
+	for _, row := range keyboardRows {
+		if strings.Contains(row, walk) || strings.Contains(reverse(row), walk) {
+			return 40 * float64(len(s)), true
+		}
+	}
+	return 0, false
+}
+
+// yearGuesses matches four-digit years between 1900 and 2099.
+func yearGuesses(s []rune) (float64, bool) {
+	if len(s) != 4 || (string(s[:2]) != "19" && string(s[:2]) != "20") {
+		return 0, false
+	}
+	for _, r := range s[2:] {
+		if !unicode.IsDigit(r) {
+			return 0, false
+		}
+	}
+	return 200, true
+}
+
+// leetSubstitutions maps common l33t characters back to the letters they replace.
+var leetSubstitutions = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+func unleet(s string) string {
+	return strings.Map(func(r rune) rune {
+		if sub, ok := leetSubstitutions[r]; ok {
+			return sub
+		}
+		return r
+	}, s)
+}
+
+func hasUpper(s []rune) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// rankedDictionary merges the user's own inputs, which an attacker targeting
+// them would try first, with the common password list.
+func rankedDictionary(userInputs []string) map[string]int {
+	if len(userInputs) == 0 {
+		return commonPasswordRanks
+	}
+	dict := make(map[string]int, len(commonPasswordRanks)+len(userInputs))
+	for word, rank := range commonPasswordRanks {
+		dict[word] = rank
+	}
+	for i, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if len([]rune(input)) < minPatternLen {
+			continue
+		}
+		if rank, ok := dict[input]; !ok || rank > i+1 {
+			dict[input] = i + 1
+		}
+	}
+	return dict
+}
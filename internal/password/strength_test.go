@@ -0,0 +1,54 @@
+package password
+
+import "testing"
+
+func TestEstimate_WeakPasswords(t *testing.T) {
+	for _, pw := range []string{
+		"password", "Password1", "p@ssw0rd", "drowssap", "qwertyuiop",
+		"abcdefgh", "98765432", "aaaaaaaaaa", "abcabcabc", "summer2024",
+	} {
+		if s := Estimate(pw); s.Score > 1 {
+			t.Errorf("Estimate(%q): score %d (guesses %.0f), want <= 1", pw, s.Score, s.Guesses)
+		}
+	}
+}
+
+func TestEstimate_StrongPasswords(t *testing.T) {
+	for _, pw := range []string{
+		"correct horse battery staple",
+		"v9#Lq2!mZx8@Rt",
+		"tangerine-orbit-walnut-42",
+	} {
+		if s := Estimate(pw); s.Score < 3 {
+			t.Errorf("Estimate(%q): score %d (guesses %.0f), want >= 3", pw, s.Score, s.Guesses)
+		}
+	}
+}
+
+func TestEstimate_UserInputs(t *testing.T) {
+	without := Estimate("jdoe-example")
+	with := Estimate("jdoe-example", "jdoe", "example")
+	if with.Guesses >= without.Guesses {
+		t.Errorf("user inputs should lower guesses: with=%.0f without=%.0f", with.Guesses, without.Guesses)
+	}
+}
+
+func TestEstimate_Empty(t *testing.T) {
+	if s := Estimate(""); s.Score != 0 {
+		t.Errorf("Estimate(\"\"): score %d want 0", s.Score)
+	}
+}
+
+func TestEstimate_ScoreBuckets(t *testing.T) {
+	cases := []struct {
+		guesses float64
+		want    int
+	}{
+		{1, 0}, {999, 0}, {1e3, 1}, {1e6, 2}, {1e8, 3}, {1e10, 4}, {1e20, 4},
+	}
+	for _, tc := range cases {
+		if got := scoreFor(tc.guesses); got != tc.want {
+			t.Errorf("scoreFor(%g): got %d want %d", tc.guesses, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPResolver determines the client address of a request. X-Forwarded-For is
+// only consulted when the connection comes from a trusted proxy, so callers
+// cannot spoof their address to dodge per-IP limits.
+type IPResolver struct {
+	trusted []*net.IPNet
+}
+
+// NewIPResolver creates a resolver trusting the given proxy CIDRs.
+func NewIPResolver(trustedProxies []string) (*IPResolver, error) {
+	r := &IPResolver{}
+	for _, cidr := range trustedProxies {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("ratelimit: invalid trusted proxy %q: %w", cidr, err)
+		}
+		r.trusted = append(r.trusted, network)
+	}
+	return r, nil
+}
+
+// ClientIP returns the client address for r. IPv6 addresses are reduced to
+// their /64 network, since a single host is usually handed a whole /64.
+func (r *IPResolver) ClientIP(req *http.Request) string {
+	ip := r.resolve(req)
+	if ip == nil {
+		return ""
+	}
+	if ip.To4() == nil {
+		return ip.Mask(net.CIDRMask(64, 128)).String() + "/64"
+	}
+	return ip.String()
+}
+
+// Addr returns the full client address for r, for display and audit records.
+func (r *IPResolver) Addr(req *http.Request) string {
+	ip := r.resolve(req)
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// resolve returns the client address, following X-Forwarded-For through trusted proxies.
+func (r *IPResolver) resolve(req *http.Request) net.IP {
+	ip := remoteIP(req.RemoteAddr)
+	if ip != nil && r.isTrusted(ip) {
+		// Walk X-Forwarded-For from the nearest hop, skipping our own proxies.
+		hops := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				break
+			}
+			ip = hop
+			if !r.isTrusted(hop) {
+				break
+			}
+		}
+	}
+	return ip
+}
+
+func (r *IPResolver) isTrusted(ip net.IP) bool {
+	for _, network := range r.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP parses the host part of an http.Request RemoteAddr.
+func remoteIP(addr string) net.IP {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP_RemoteAddr(t *testing.T) {
+	r := &IPResolver{}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	// Untrusted peers cannot choose their address via X-Forwarded-For.
+	if got := r.ClientIP(req); got != "203.0.113.7" {
+		t.Errorf("got %q want %q", got, "203.0.113.7")
+	}
+}
+
+func TestClientIP_TrustedProxy(t *testing.T) {
+	r, err := NewIPResolver([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("NewIPResolver: %v", err)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.5:4321"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 198.51.100.1, 10.0.0.9")
+
+	// The spoofable leftmost entry is ignored; the nearest untrusted hop wins.
+	if got := r.ClientIP(req); got != "198.51.100.1" {
+		t.Errorf("got %q want %q", got, "198.51.100.1")
+	}
+}
+
+func TestClientIP_IPv6Slash64(t *testing.T) {
+	r := &IPResolver{}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "[2001:db8:1:2:aaaa::1]:4321"
+
+	if got := r.ClientIP(req); got != "2001:db8:1:2::/64" {
+		t.Errorf("got %q want %q", got, "2001:db8:1:2::/64")
+	}
+}
+
+func TestAddr_KeepsFullIPv6(t *testing.T) {
+	r := &IPResolver{}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "[2001:db8:1:2:aaaa::1]:4321"
+
+	if got := r.Addr(req); got != "2001:db8:1:2:aaaa::1" {
+		t.Errorf("got %q want %q", got, "2001:db8:1:2:aaaa::1")
+	}
+}
+
+func TestNewIPResolver_InvalidCIDR(t *testing.T) {
+	if _, err := NewIPResolver([]string{"10.0.0.0"}); err == nil {
+		t.Error("expected error for address without prefix length")
+	}
+}
@@ -0,0 +1,109 @@
+// Package ratelimit implements sliding-window rate limiting for HTTP endpoints,
+// backed by Redis so limits hold across every instance of the service.
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces rate limit windows in Redis.
+const keyPrefix = "ratelimit:"
+
+// Decision is the outcome of recording a hit against a window.
+type Decision struct {
+	// Allowed reports whether the hit fit within the limit.
+	Allowed bool
+	// Remaining is the number of further hits allowed in the current window.
+	Remaining int
+	// RetryAfter is how long until a rejected caller may try again.
+	RetryAfter time.Duration
+}
+
+// Counter records hits against a named sliding window.
+type Counter interface {
+	// Allow records a hit against key if fewer than limit hits fall within the
+	// trailing window. Rejected hits are not recorded.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (Decision, error)
+}
+
+// slidingWindow keeps one sorted-set member per accepted hit, scored by its
+// timestamp in milliseconds. Expired members are trimmed before counting, and
+// the whole check-and-add runs atomically on the Redis server.
+//
+// KEYS[1] window key; ARGV[1] now (ms); ARGV[2] window (ms); ARGV[3] limit; ARGV[4] member.
+// Returns {allowed, remaining, retry_after_ms}.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return {1, limit - count - 1, 0}
+end
+
+local retry = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	retry = tonumber(oldest[2]) + window - now
+end
+return {0, 0, retry}
+`)
+
+// Limiter is a Counter backed by Redis sorted sets.
+type Limiter struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+// NewLimiter creates a Limiter on the given Redis client, normally the same
+// connection the session and token stores use.
+func NewLimiter(client redis.Scripter) *Limiter {
+	return &Limiter{client: client, now: time.Now}
+}
+
+// Allow implements Counter.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Decision, error) {
+	member, err := hitID()
+	if err != nil {
+		return Decision{}, err
+	}
+	now := l.now().UnixMilli()
+
+	res, err := slidingWindow.Run(ctx, l.client, []string{keyPrefix + key},
+		now, window.Milliseconds(), limit, strconv.FormatInt(now, 10)+"-"+member,
+	).Int64Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("ratelimit: evaluating window: %w", err)
+	}
+	if len(res) != 3 {
+		return Decision{}, fmt.Errorf("ratelimit: unexpected script result %v", res)
+	}
+
+	return Decision{
+		Allowed:    res[0] == 1,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
+
+// hitID returns a random suffix so concurrent hits in the same millisecond
+// are stored as distinct sorted-set members.
+func hitID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("ratelimit: generating hit id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
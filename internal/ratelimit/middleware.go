@@ -0,0 +1,198 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBodyPeek bounds how much of a request body is buffered to find the email field.
+const maxBodyPeek = 64 << 10
+
+// Scope identifies which dimension of a request a limit applies to.
+type Scope string
+
+const (
+	// ScopeIP limits requests from one client IP to one endpoint.
+	ScopeIP Scope = "ip"
+	// ScopeEmail limits requests naming one email address at one endpoint.
+	ScopeEmail Scope = "email"
+	// ScopeEndpoint limits all requests to one endpoint.
+	ScopeEndpoint Scope = "endpoint"
+)
+
+// Limit allows Requests hits per Window. A zero Requests disables the limit.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Policy holds the limit for each scope.
+type Policy struct {
+	IP       Limit
+	Email    Limit
+	Endpoint Limit
+}
+
+// Rule selects an endpoint to rate limit.
+type Rule struct {
+	Method string
+	Path   string
+	// EmailField names the JSON body field holding the account email, if the
+	// endpoint takes one. Requests are then also limited per email address.
+	EmailField string
+}
+
+// Violation describes a request rejected by a limit.
+type Violation struct {
+	Scope      Scope
+	Method     string
+	Path       string
+	ClientIP   string
+	RetryAfter time.Duration
+}
+
+// Options configures the middleware.
+type Options struct {
+	Policy Policy
+	Rules  []Rule
+	// ClientIP resolves the caller's address. Defaults to the connection's remote address.
+	ClientIP *IPResolver
+	// OnExceeded is called for every rejected request.
+	OnExceeded func(ctx context.Context, v Violation)
+}
+
+// Middleware enforces a Policy on the endpoints selected by its rules.
+type Middleware struct {
+	counter    Counter
+	policy     Policy
+	rules      map[string]Rule
+	clientIP   *IPResolver
+	onExceeded func(ctx context.Context, v Violation)
+}
+
+// New creates rate limiting middleware that records hits with counter.
+func New(counter Counter, opts Options) *Middleware {
+	rules := make(map[string]Rule, len(opts.Rules))
+	for _, r := range opts.Rules {
+		rules[r.Method+" "+r.Path] = r
+	}
+	resolver := opts.ClientIP
+	if resolver == nil {
+		resolver = &IPResolver{}
+	}
+	return &Middleware{
+		counter:    counter,
+		policy:     opts.Policy,
+		rules:      rules,
+		clientIP:   resolver,
+		onExceeded: opts.OnExceeded,
+	}
+}
+
+// Handler wraps next, answering 429 with Retry-After once a limit is exceeded.
+// Requests to endpoints without a rule pass straight through. If the counter
+// fails the request is allowed, so a Redis outage does not take logins down.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := m.rules[r.Method+" "+r.URL.Path]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := m.clientIP.ClientIP(r)
+		endpoint := rule.Method + " " + rule.Path
+		checks := []struct {
+			scope Scope
+			limit Limit
+			key   string
+		}{
+			{ScopeIP, m.policy.IP, ip},
+			{ScopeEmail, m.policy.Email, ""},
+			{ScopeEndpoint, m.policy.Endpoint, "*"},
+		}
+		if rule.EmailField != "" && m.policy.Email.Requests > 0 {
+			checks[1].key = hashKey(peekEmail(r, rule.EmailField))
+		}
+
+		for _, c := range checks {
+			if c.limit.Requests <= 0 || c.key == "" {
+				continue
+			}
+			d, err := m.counter.Allow(r.Context(), string(c.scope)+":"+endpoint+":"+c.key, c.limit.Requests, c.limit.Window)
+			if err != nil || d.Allowed {
+				continue
+			}
+			if m.onExceeded != nil {
+				m.onExceeded(r.Context(), Violation{
+					Scope:      c.scope,
+					Method:     rule.Method,
+					Path:       rule.Path,
+					ClientIP:   ip,
+					RetryAfter: d.RetryAfter,
+				})
+			}
+			writeTooManyRequests(w, d.RetryAfter)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// peekEmail reads the named field from a JSON body and restores the body for
+// the handler. It returns "" when the body is not JSON or lacks the field.
+func peekEmail(r *http.Request, field string) string {
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+	buf, err := io.ReadAll(io.LimitReader(r.Body, maxBodyPeek))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+	if err != nil {
+		return ""
+	}
+
+	var body map[string]json.RawMessage
+	if json.Unmarshal(buf, &body) != nil {
+		return ""
+	}
+	var email string
+	if json.Unmarshal(body[field], &email) != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// hashKey keeps raw email addresses out of Redis key names.
+func hashKey(v string) string {
+	if v == "" {
+		return ""
+	}
+	h := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(h[:16])
+}
+
+// writeTooManyRequests writes a 429 response. Retry-After is rounded up to
+// whole seconds so clients never retry before the window has moved on.
+func writeTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_, _ = w.Write([]byte(`{"code":"TOO_MANY_REQUESTS","message":"too many requests"}`))
+}
@@ -0,0 +1,216 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memCounter is an in-memory Counter with the same semantics as Limiter.
+type memCounter struct {
+	hits map[string][]time.Time
+	now  time.Time
+	err  error
+	keys []string
+}
+
+func newMemCounter() *memCounter {
+	return &memCounter{hits: map[string][]time.Time{}, now: time.Unix(1700000000, 0)}
+}
+
+func (c *memCounter) Allow(_ context.Context, key string, limit int, window time.Duration) (Decision, error) {
+	c.keys = append(c.keys, key)
+	if c.err != nil {
+		return Decision{}, c.err
+	}
+	var live []time.Time
+	for _, t := range c.hits[key] {
+		if c.now.Sub(t) < window {
+			live = append(live, t)
+		}
+	}
+	if len(live) >= limit {
+		c.hits[key] = live
+		return Decision{RetryAfter: live[0].Add(window).Sub(c.now)}, nil
+	}
+	c.hits[key] = append(live, c.now)
+	return Decision{Allowed: true, Remaining: limit - len(live) - 1}, nil
+}
+
+func okHandler(t *testing.T, wantBody string) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wantBody != "" {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != wantBody {
+				t.Errorf("handler body: got %q want %q", body, wantBody)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func loginRequest(ip, email string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"`+email+`","password":"x"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.RemoteAddr = ip + ":1234"
+	return r
+}
+
+var loginRule = Rule{Method: http.MethodPost, Path: "/login", EmailField: "email"}
+
+func TestMiddleware_IPLimit(t *testing.T) {
+	counter := newMemCounter()
+	var violations []Violation
+	m := New(counter, Options{
+		Policy: Policy{IP: Limit{Requests: 2, Window: time.Minute}},
+		Rules:  []Rule{loginRule},
+		OnExceeded: func(_ context.Context, v Violation) {
+			violations = append(violations, v)
+		},
+	})
+	h := m.Handler(okHandler(t, ""))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, loginRequest("203.0.113.7", "a@example.com"))
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: got %d want 204", i, w.Code)
+		}
+	}
+
+	counter.now = counter.now.Add(20 * time.Second)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, loginRequest("203.0.113.7", "b@example.com"))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("got %d want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "40" {
+		t.Errorf("Retry-After: got %q want %q", got, "40")
+	}
+	if len(violations) != 1 || violations[0].Scope != ScopeIP || violations[0].ClientIP != "203.0.113.7" {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+
+	// Another client is unaffected.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, loginRequest("198.51.100.1", "a@example.com"))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("other IP: got %d want 204", w.Code)
+	}
+}
+
+func TestMiddleware_EmailLimitAcrossIPs(t *testing.T) {
+	counter := newMemCounter()
+	m := New(counter, Options{
+		Policy: Policy{Email: Limit{Requests: 1, Window: time.Minute}},
+		Rules:  []Rule{loginRule},
+	})
+	h := m.Handler(okHandler(t, ""))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, loginRequest("203.0.113.7", "Victim@Example.com"))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("first: got %d want 204", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, loginRequest("198.51.100.1", " victim@example.com"))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second: got %d want 429", w.Code)
+	}
+
+	for _, k := range counter.keys {
+		if strings.Contains(k, "victim") {
+			t.Errorf("email leaked into key %q", k)
+		}
+	}
+}
+
+func TestMiddleware_EndpointLimit(t *testing.T) {
+	counter := newMemCounter()
+	m := New(counter, Options{
+		Policy: Policy{Endpoint: Limit{Requests: 1, Window: time.Minute}},
+		Rules:  []Rule{loginRule},
+	})
+	h := m.Handler(okHandler(t, ""))
+
+	h.ServeHTTP(httptest.NewRecorder(), loginRequest("203.0.113.7", "a@example.com"))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, loginRequest("198.51.100.1", "b@example.com"))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("got %d want 429", w.Code)
+	}
+}
+
+func TestMiddleware_BodyRestored(t *testing.T) {
+	m := New(newMemCounter(), Options{
+		Policy: Policy{Email: Limit{Requests: 5, Window: time.Minute}},
+		Rules:  []Rule{loginRule},
+	})
+	h := m.Handler(okHandler(t, `{"email":"a@example.com","password":"x"}`))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, loginRequest("203.0.113.7", "a@example.com"))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("got %d want 204", w.Code)
+	}
+}
+
+func TestMiddleware_UnmatchedRoutePassesThrough(t *testing.T) {
+	counter := newMemCounter()
+	m := New(counter, Options{
+		Policy: Policy{IP: Limit{Requests: 1, Window: time.Minute}},
+		Rules:  []Rule{loginRule},
+	})
+	h := m.Handler(okHandler(t, ""))
+
+	for i := 0; i < 3; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/login", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: got %d want 204", i, w.Code)
+		}
+	}
+	if len(counter.keys) != 0 {
+		t.Errorf("expected no counter calls, got %v", counter.keys)
+	}
+}
+
+func TestMiddleware_FailsOpen(t *testing.T) {
+	counter := newMemCounter()
+	counter.err = errors.New("redis down")
+	m := New(counter, Options{
+		Policy: Policy{IP: Limit{Requests: 1, Window: time.Minute}},
+		Rules:  []Rule{loginRule},
+	})
+	h := m.Handler(okHandler(t, ""))
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, loginRequest("203.0.113.7", "a@example.com"))
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: got %d want 204", i, w.Code)
+		}
+	}
+}
+
+func TestWriteTooManyRequests_RoundsUp(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeTooManyRequests(w, 1500*time.Millisecond)
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After: got %q want %q", got, "2")
+	}
+
+	w = httptest.NewRecorder()
+	writeTooManyRequests(w, 0)
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After: got %q want %q", got, "1")
+	}
+}
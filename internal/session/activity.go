@@ -0,0 +1,74 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ActivityStore is the subset of the sessions store the activity tracker needs.
+type ActivityStore interface {
+	Get(ctx context.Context, token string) (*models.Session, error)
+	SetWithUserIndex(ctx context.Context, session *models.Session, ttl time.Duration) error
+}
+
+// CookieFunc builds the session cookie for token, expiring after maxAge, or
+// with the browser session when maxAge is 0.
+type CookieFunc func(token string, maxAge time.Duration) *http.Cookie
+
+// Activity is middleware that records session use and slides session expiry.
+// A session is rewritten at most once per Lifetime.Interval, so a busy client
+// costs one Redis write per interval rather than one per request.
+type Activity struct {
+	store      ActivityStore
+	cookieName string
+	lifetime   Lifetime
+	cookie     CookieFunc
+	now        func() time.Time
+}
+
+// NewActivity returns an Activity reading the session token from cookieName.
+// When a session is extended, the cookie built by cookie is re-issued so the
+// browser keeps it as long as the server does.
+func NewActivity(store ActivityStore, cookieName string, lifetime Lifetime, cookie CookieFunc) *Activity {
+	return &Activity{store: store, cookieName: cookieName, lifetime: lifetime, cookie: cookie, now: time.Now}
+}
+
+// Handler wraps next, touching the session named by the request's cookie
+// before the request is served. Failures are ignored: activity tracking never
+// blocks a request.
+func (a *Activity) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(a.cookieName); err == nil && cookie.Value != "" {
+			if extended := a.touch(r.Context(), cookie.Value); extended != nil {
+				http.SetCookie(w, extended)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// touch records activity on the session, returning the cookie to re-issue
+// when its expiry was extended.
+func (a *Activity) touch(ctx context.Context, token string) *http.Cookie {
+	s, err := a.store.Get(ctx, token)
+	if err != nil || s == nil {
+		return nil
+	}
+	now := a.now()
+	if !a.lifetime.Active(s, now) || !a.lifetime.Touch(s, now) {
+		return nil
+	}
+	ttl := s.ExpiresAt.Sub(now)
+	if ttl <= 0 {
+		return nil
+	}
+	// The session and its user index entry are rewritten together so both
+	// keys carry the new TTL.
+	if err := a.store.SetWithUserIndex(ctx, s, ttl); err != nil {
+		return nil
+	}
+	return a.cookie(token, CookieMaxAge(s, ttl))
+}
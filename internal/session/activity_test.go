@@ -0,0 +1,168 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+type fakeActivityStore struct {
+	sessions map[string]*models.Session
+	writes   int
+	lastTTL  time.Duration
+}
+
+func (f *fakeActivityStore) Get(_ context.Context, token string) (*models.Session, error) {
+	s, ok := f.sessions[token]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	c := *s
+	return &c, nil
+}
+
+func (f *fakeActivityStore) SetWithUserIndex(_ context.Context, s *models.Session, ttl time.Duration) error {
+	f.writes++
+	f.lastTTL = ttl
+	c := *s
+	f.sessions[s.Token] = &c
+	return nil
+}
+
+// serveWithCookie runs a request through a and reports whether the next
+// handler ran and the cookie re-issued, if any.
+func serveWithCookie(a *Activity, value string) (bool, *http.Cookie) {
+	called := false
+	h := a.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	r := httptest.NewRequest(http.MethodGet, "/me", nil)
+	if value != "" {
+		r.AddCookie(&http.Cookie{Name: "session", Value: value})
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	var reissued *http.Cookie
+	if cookies := w.Result().Cookies(); len(cookies) > 0 {
+		reissued = cookies[0]
+	}
+	return called, reissued
+}
+
+func TestActivity_Handler(t *testing.T) {
+	lifetime := Lifetime{Idle: 2 * time.Hour, Max: 24 * time.Hour, Interval: 5 * time.Minute}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		cookie  string
+		session models.Session
+		// expiresAt is the session's new expiry, or zero when the request
+		// should not write it; maxAge is the MaxAge of the re-issued cookie.
+		expiresAt time.Time
+		maxAge    int
+	}{
+		{
+			name:    "stale session",
+			cookie:  "tok",
+			session: models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour), Remember: true},
+			// The idle timeout slides forward from now.
+			expiresAt: now.Add(2 * time.Hour),
+			maxAge:    int((2 * time.Hour).Seconds()),
+		},
+		{
+			name:      "capped at max lifetime",
+			cookie:    "tok",
+			session:   models.Session{CreatedAt: now.Add(-23 * time.Hour), ExpiresAt: now.Add(time.Hour), Remember: true},
+			expiresAt: now.Add(time.Hour),
+			maxAge:    int(time.Hour.Seconds()),
+		},
+		{
+			name:      "browser session",
+			cookie:    "tok",
+			session:   models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+			expiresAt: now.Add(2 * time.Hour),
+			maxAge:    0,
+		},
+		{
+			name:    "recently seen",
+			cookie:  "tok",
+			session: models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour), LastSeenAt: now.Add(-time.Minute)},
+		},
+		{
+			name:    "idle expired",
+			cookie:  "tok",
+			session: models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Second)},
+		},
+		{
+			name:    "past max lifetime",
+			cookie:  "tok",
+			session: models.Session{CreatedAt: now.Add(-25 * time.Hour), ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "unknown session",
+			cookie:  "unknown",
+			session: models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "no cookie",
+			session: models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.session
+			s.Token, s.UserID = "tok", "u1"
+			store := &fakeActivityStore{sessions: map[string]*models.Session{"tok": &s}}
+			a := NewActivity(store, "session", lifetime, func(token string, maxAge time.Duration) *http.Cookie {
+				return &http.Cookie{Name: "session", Value: token, MaxAge: int(maxAge.Seconds())}
+			})
+			a.now = func() time.Time { return now }
+
+			called, cookie := serveWithCookie(a, tt.cookie)
+			if !called {
+				t.Fatal("next handler not called")
+			}
+			if tt.expiresAt.IsZero() {
+				if store.writes != 0 || cookie != nil {
+					t.Errorf("writes = %d, cookie = %v; want neither", store.writes, cookie)
+				}
+				return
+			}
+			got := store.sessions["tok"]
+			if store.writes != 1 || !got.LastSeenAt.Equal(now) || !got.ExpiresAt.Equal(tt.expiresAt) {
+				t.Errorf("writes = %d, LastSeenAt = %v, ExpiresAt = %v; want ExpiresAt %v", store.writes, got.LastSeenAt, got.ExpiresAt, tt.expiresAt)
+			}
+			if want := tt.expiresAt.Sub(now); store.lastTTL != want {
+				t.Errorf("ttl = %v, want %v", store.lastTTL, want)
+			}
+			if cookie == nil || cookie.Value != "tok" || cookie.MaxAge != tt.maxAge {
+				t.Errorf("re-issued cookie = %+v, want MaxAge %d", cookie, tt.maxAge)
+			}
+		})
+	}
+}
+
+func TestActivity_BoundsWrites(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeActivityStore{sessions: map[string]*models.Session{
+		"tok": {Token: "tok", UserID: "u1", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+	}}
+	lifetime := Lifetime{Idle: 2 * time.Hour, Max: 24 * time.Hour, Interval: 5 * time.Minute}
+	a := NewActivity(store, "session", lifetime, func(token string, _ time.Duration) *http.Cookie {
+		return &http.Cookie{Name: "session", Value: token}
+	})
+	a.now = func() time.Time { return now }
+
+	for range 10 {
+		serveWithCookie(a, "tok")
+	}
+	now = now.Add(5 * time.Minute)
+	serveWithCookie(a, "tok")
+	if store.writes != 2 {
+		t.Errorf("writes = %d, want 2", store.writes)
+	}
+}
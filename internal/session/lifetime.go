@@ -0,0 +1,68 @@
+package session
+
+import (
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Lifetime decides when sessions expire: after Idle without use, and never
+// later than Max after sign-in. Sessions the user did not ask to remember use
+// BrowserIdle instead of Idle when it is set. Activity slides a session's
+// expiry forward at most once per Interval, which also bounds the writes
+// activity causes.
+type Lifetime struct {
+	Idle        time.Duration
+	BrowserIdle time.Duration
+	Max         time.Duration
+	Interval    time.Duration
+}
+
+// idle returns the idle timeout that applies to s.
+func (l Lifetime) idle(s *models.Session) time.Duration {
+	if !s.Remember && l.BrowserIdle > 0 {
+		return l.BrowserIdle
+	}
+	return l.Idle
+}
+
+// CookieMaxAge returns the Max-Age for the cookie of s when the server keeps it
+// for ttl: ttl for remembered sessions, and 0, a cookie that ends with the
+// browser session, for the rest.
+func CookieMaxAge(s *models.Session, ttl time.Duration) time.Duration {
+	if !s.Remember {
+		return 0
+	}
+	return ttl
+}
+
+// Expiry returns the expiry of s after activity at now: the idle timeout from
+// now, capped at the absolute lifetime.
+func (l Lifetime) Expiry(s *models.Session, now time.Time) time.Time {
+	expiry := now.Add(l.idle(s))
+	if limit := s.CreatedAt.Add(l.Max); l.Max > 0 && expiry.After(limit) {
+		return limit
+	}
+	return expiry
+}
+
+// Active reports whether s may be used at now. Sessions created under a longer
+// lifetime are cut off once they pass the current cap.
+func (l Lifetime) Active(s *models.Session, now time.Time) bool {
+	if now.After(s.ExpiresAt) {
+		return false
+	}
+	return l.Max <= 0 || now.Before(s.CreatedAt.Add(l.Max))
+}
+
+// Touch records activity on s at now and slides its expiry. It returns false,
+// leaving s unchanged, when s was touched within the last Interval; callers
+// save s only when it returns true.
+func (l Lifetime) Touch(s *models.Session, now time.Time) bool {
+	if now.Sub(s.LastSeenAt) < l.Interval {
+		return false
+	}
+	s.LastSeenAt = now
+	s.ExpiresAt = l.Expiry(s, now)
+	return true
+}
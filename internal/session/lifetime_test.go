@@ -0,0 +1,100 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func TestLifetime_Expiry(t *testing.T) {
+	l := Lifetime{Idle: 2 * time.Hour, Max: 24 * time.Hour}
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	s := &models.Session{CreatedAt: created}
+
+	if got, want := l.Expiry(s, created), created.Add(2*time.Hour); !got.Equal(want) {
+		t.Errorf("new session: got %v want %v", got, want)
+	}
+	if got, want := l.Expiry(s, created.Add(23*time.Hour)), created.Add(24*time.Hour); !got.Equal(want) {
+		t.Errorf("near cap: got %v want %v", got, want)
+	}
+}
+
+func TestLifetime_ExpiryWithoutCap(t *testing.T) {
+	l := Lifetime{Idle: 2 * time.Hour}
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(100 * time.Hour)
+
+	if got := l.Expiry(&models.Session{CreatedAt: created}, now); !got.Equal(now.Add(2 * time.Hour)) {
+		t.Errorf("got %v want %v", got, now.Add(2*time.Hour))
+	}
+}
+
+func TestLifetime_Active(t *testing.T) {
+	l := Lifetime{Idle: 2 * time.Hour, Max: 24 * time.Hour}
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		s    models.Session
+		want bool
+	}{
+		{"active", models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}, true},
+		{"idle expired", models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Second)}, false},
+		{"past cap", models.Session{CreatedAt: now.Add(-25 * time.Hour), ExpiresAt: now.Add(time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		if got := l.Active(&tt.s, now); got != tt.want {
+			t.Errorf("%s: got %v want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLifetime_Touch(t *testing.T) {
+	l := Lifetime{Idle: 2 * time.Hour, Max: 24 * time.Hour, Interval: 5 * time.Minute}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := &models.Session{CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour), LastSeenAt: now.Add(-time.Minute)}
+
+	if l.Touch(s, now) {
+		t.Fatal("touch within the interval should not change the session")
+	}
+	if !s.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("ExpiresAt changed to %v", s.ExpiresAt)
+	}
+
+	later := now.Add(10 * time.Minute)
+	if !l.Touch(s, later) {
+		t.Fatal("touch after the interval should change the session")
+	}
+	if !s.LastSeenAt.Equal(later) || !s.ExpiresAt.Equal(later.Add(2*time.Hour)) {
+		t.Errorf("got LastSeenAt %v ExpiresAt %v", s.LastSeenAt, s.ExpiresAt)
+	}
+}
+
+func TestLifetime_BrowserIdle(t *testing.T) {
+	l := Lifetime{Idle: 72 * time.Hour, BrowserIdle: 2 * time.Hour, Max: 720 * time.Hour}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	browser := &models.Session{CreatedAt: now}
+	if got, want := l.Expiry(browser, now), now.Add(2*time.Hour); !got.Equal(want) {
+		t.Errorf("browser session: got %v want %v", got, want)
+	}
+	remembered := &models.Session{CreatedAt: now, Remember: true}
+	if got, want := l.Expiry(remembered, now), now.Add(72*time.Hour); !got.Equal(want) {
+		t.Errorf("remembered session: got %v want %v", got, want)
+	}
+
+	l.BrowserIdle = 0
+	if got, want := l.Expiry(browser, now), now.Add(72*time.Hour); !got.Equal(want) {
+		t.Errorf("without BrowserIdle: got %v want %v", got, want)
+	}
+}
+
+func TestCookieMaxAge(t *testing.T) {
+	if got := CookieMaxAge(&models.Session{Remember: true}, time.Hour); got != time.Hour {
+		t.Errorf("remembered: got %v want 1h", got)
+	}
+	if got := CookieMaxAge(&models.Session{}, time.Hour); got != 0 {
+		t.Errorf("browser session: got %v want 0", got)
+	}
+}
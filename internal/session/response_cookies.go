@@ -0,0 +1,74 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// responseCookiesKey is the context key of the cookies queued for a response.
+type responseCookiesKey struct{}
+
+// responseCookies holds the cookies queued by a handler until the response
+// headers are written.
+type responseCookies struct {
+	mu      sync.Mutex
+	cookies []*http.Cookie
+}
+
+// ResponseCookies is middleware that lets handlers which do not control their
+// response headers, such as those returning JSON bodies, set cookies on the
+// response with SetResponseCookie. A rotated session cookie is delivered this
+// way.
+func ResponseCookies(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queued := &responseCookies{}
+		ctx := context.WithValue(r.Context(), responseCookiesKey{}, queued)
+		next.ServeHTTP(&cookieWriter{ResponseWriter: w, queued: queued}, r.WithContext(ctx))
+	})
+}
+
+// SetResponseCookie queues c to be set on the response of the request ctx
+// belongs to. It reports false when the request was not served through
+// ResponseCookies.
+func SetResponseCookie(ctx context.Context, c *http.Cookie) bool {
+	queued, ok := ctx.Value(responseCookiesKey{}).(*responseCookies)
+	if !ok {
+		return false
+	}
+	queued.mu.Lock()
+	queued.cookies = append(queued.cookies, c)
+	queued.mu.Unlock()
+	return true
+}
+
+// cookieWriter adds the queued cookies to the headers when they are written.
+type cookieWriter struct {
+	http.ResponseWriter
+	queued      *responseCookies
+	wroteHeader bool
+}
+
+func (w *cookieWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.queued.mu.Lock()
+		for _, c := range w.queued.cookies {
+			http.SetCookie(w.ResponseWriter, c)
+		}
+		w.queued.mu.Unlock()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cookieWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *cookieWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
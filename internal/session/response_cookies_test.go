@@ -0,0 +1,46 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseCookies_SetsQueuedCookies(t *testing.T) {
+	h := ResponseCookies(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !SetResponseCookie(r.Context(), &http.Cookie{Name: "session", Value: "rotated"}) {
+			t.Error("SetResponseCookie reported no middleware")
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/me/mfa/totp/confirm", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].Value != "rotated" {
+		t.Fatalf("cookies: got %+v", cookies)
+	}
+}
+
+func TestResponseCookies_ExplicitStatus(t *testing.T) {
+	h := ResponseCookies(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetResponseCookie(r.Context(), &http.Cookie{Name: "session", Value: "rotated"})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/password/reset/confirm", nil))
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status: got %d", w.Code)
+	}
+	if len(w.Result().Cookies()) != 1 {
+		t.Error("cookie not set with an explicit status")
+	}
+}
+
+func TestSetResponseCookie_WithoutMiddleware(t *testing.T) {
+	if SetResponseCookie(context.Background(), &http.Cookie{Name: "session"}) {
+		t.Error("expected false without the middleware")
+	}
+}
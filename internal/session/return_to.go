@@ -0,0 +1,136 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// maxReturnToLen bounds the length of an accepted return_to value so it stays
+// well within cookie and token payload limits.
+const maxReturnToLen = 2048
+
+// ErrInvalidReturnToRule is returned when an allowlist entry cannot be parsed.
+var ErrInvalidReturnToRule = errors.New("invalid return_to allowlist entry")
+
+// ReturnToPolicy decides which post-login redirect targets may be honoured.
+// Each allowlist entry is either a path prefix on this origin ("/app") or an
+// absolute origin with an optional path prefix ("https://app.example.com/dashboard").
+// Anything not matched is rejected so a crafted return_to cannot redirect users
+// to another site.
+type ReturnToPolicy struct {
+	rules []returnToRule
+}
+
+// returnToRule is a parsed allowlist entry. An empty origin means same-origin.
+type returnToRule struct {
+	origin string
+	path   string
+}
+
+// NewReturnToPolicy parses the allowlist entries.
+func NewReturnToPolicy(allowlist []string) (*ReturnToPolicy, error) {
+	p := &ReturnToPolicy{}
+	for _, entry := range allowlist {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rule, err := parseReturnToRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		p.rules = append(p.rules, rule)
+	}
+	return p, nil
+}
+
+func parseReturnToRule(entry string) (returnToRule, error) {
+	if strings.HasPrefix(entry, "/") {
+		if strings.HasPrefix(entry, "//") || hasDotSegment(entry) {
+			return returnToRule{}, fmt.Errorf("%w: %q", ErrInvalidReturnToRule, entry)
+		}
+		return returnToRule{path: entry}, nil
+	}
+
+	u, err := url.Parse(entry)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" ||
+		u.User != nil || u.RawQuery != "" || u.Fragment != "" || hasDotSegment(u.Path) {
+		return returnToRule{}, fmt.Errorf("%w: %q", ErrInvalidReturnToRule, entry)
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	return returnToRule{origin: u.Scheme + "://" + strings.ToLower(u.Host), path: path}, nil
+}
+
+// Sanitize returns raw if it is an allowed redirect target, or "" otherwise.
+func (p *ReturnToPolicy) Sanitize(raw string) string {
+	if p == nil || raw == "" || len(raw) > maxReturnToLen {
+		return ""
+	}
+	// Browsers treat backslashes as slashes and strip control characters, so
+	// "/\evil.com" or "/\t/evil.com" would otherwise escape the origin.
+	for _, c := range raw {
+		if c == '\\' || c < 0x20 || c == 0x7f {
+			return ""
+		}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.User != nil || u.Opaque != "" {
+		return ""
+	}
+
+	var origin string
+	switch {
+	case u.Scheme == "" && u.Host == "":
+		if !strings.HasPrefix(raw, "/") || strings.HasPrefix(raw, "//") {
+			return ""
+		}
+	case u.Scheme == "https" || u.Scheme == "http":
+		if u.Host == "" {
+			return ""
+		}
+		origin = u.Scheme + "://" + strings.ToLower(u.Host)
+	default:
+		return ""
+	}
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	if hasDotSegment(path) {
+		return ""
+	}
+
+	for _, rule := range p.rules {
+		if rule.origin == origin && pathHasPrefix(path, rule.path) {
+			return raw
+		}
+	}
+	return ""
+}
+
+// pathHasPrefix reports whether path is prefix or lies beneath it, matching on
+// segment boundaries so "/app" does not admit "/apple".
+func pathHasPrefix(path, prefix string) bool {
+	if prefix == "/" || path == prefix {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+// hasDotSegment reports whether path contains a "." or ".." segment, which
+// could walk out of an allowed prefix once the browser normalises it.
+func hasDotSegment(path string) bool {
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestPolicy(t *testing.T, allowlist ...string) *ReturnToPolicy {
+	t.Helper()
+	p, err := NewReturnToPolicy(allowlist)
+	if err != nil {
+		t.Fatalf("NewReturnToPolicy: %v", err)
+	}
+	return p
+}
+
+func TestReturnToPolicy_Allowed(t *testing.T) {
+	p := newTestPolicy(t, "/app", "https://spa.example.com")
+
+	cases := []string{
+		"/app",
+		"/app/",
+		"/app/settings?tab=security",
+		"/app#billing",
+		"https://spa.example.com",
+		"https://spa.example.com/",
+		"https://SPA.example.com/anything?x=1",
+	}
+	for _, raw := range cases {
+		if got := p.Sanitize(raw); got != raw {
+			t.Errorf("Sanitize(%q) = %q, want allowed", raw, got)
+		}
+	}
+}
+
+func TestReturnToPolicy_Rejected(t *testing.T) {
+	p := newTestPolicy(t, "/app", "https://spa.example.com/dash")
+
+	cases := []string{
+		"",
+		"/apple",
+		"/",
+		"//evil.com/app",
+		"/\\evil.com",
+		"/app\t/x",
+		"/app/../admin",
+		"/app/%2e%2e/admin",
+		"app/settings",
+		"https://evil.com/app",
+		"https://spa.example.com/other",
+		"https://spa.example.com.evil.com/dash",
+		"http://spa.example.com/dash",
+		"https://user@spa.example.com/dash",
+		"javascript:alert(1)",
+		"https:/spa.example.com/dash",
+	}
+	for _, raw := range cases {
+		if got := p.Sanitize(raw); got != "" {
+			t.Errorf("Sanitize(%q) = %q, want rejected", raw, got)
+		}
+	}
+}
+
+func TestReturnToPolicy_RootAllowsSameOrigin(t *testing.T) {
+	p := newTestPolicy(t, "/")
+
+	if got := p.Sanitize("/anything/here"); got != "/anything/here" {
+		t.Errorf("expected same-origin path to be allowed, got %q", got)
+	}
+	if got := p.Sanitize("https://evil.com/"); got != "" {
+		t.Errorf("expected absolute URL to be rejected, got %q", got)
+	}
+}
+
+func TestReturnToPolicy_EmptyAllowlist(t *testing.T) {
+	p := newTestPolicy(t)
+
+	if got := p.Sanitize("/app"); got != "" {
+		t.Errorf("expected empty allowlist to reject everything, got %q", got)
+	}
+}
+
+func TestReturnToPolicy_TooLong(t *testing.T) {
+	p := newTestPolicy(t, "/")
+
+	raw := "/" + string(make([]byte, maxReturnToLen))
+	if got := p.Sanitize(raw); got != "" {
+		t.Error("expected over-long return_to to be rejected")
+	}
+}
+
+func TestNewReturnToPolicy_InvalidEntries(t *testing.T) {
+	cases := []string{
+		"//evil.com",
+		"/app/../admin",
+		"spa.example.com",
+		"ftp://spa.example.com",
+		"https://",
+		"https://spa.example.com/?q=1",
+	}
+	for _, entry := range cases {
+		if _, err := NewReturnToPolicy([]string{entry}); !errors.Is(err, ErrInvalidReturnToRule) {
+			t.Errorf("NewReturnToPolicy(%q): expected ErrInvalidReturnToRule, got %v", entry, err)
+		}
+	}
+}
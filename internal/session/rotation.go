@@ -0,0 +1,185 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Event is a change to a user's authentication state after which sessions
+// issued before it should not be trusted as they were.
+type Event string
+
+// Events that rotate sessions.
+const (
+	EventPasswordReset  Event = "password_reset"
+	EventEmailVerified  Event = "email_verified"
+	EventProviderLinked Event = "provider_linked"
+	EventMFAEnabled     Event = "mfa_enabled"
+)
+
+// Events lists every Event, in the order they are documented.
+var Events = []Event{EventPasswordReset, EventEmailVerified, EventProviderLinked, EventMFAEnabled}
+
+// ErrUnknownEvent is returned when configuration names an event that does not exist.
+var ErrUnknownEvent = errors.New("unknown session rotation event")
+
+// Policy is what happens to a user's sessions on an Event.
+type Policy struct {
+	// Rotate replaces the token of the session making the request, so a token
+	// captured before the event stops working.
+	Rotate bool
+	// RevokeOthers signs out every other session of the user.
+	RevokeOthers bool
+}
+
+// ParsePolicies builds per-event policies from the events that rotate the
+// current session and the events that revoke the others. Events in neither
+// list leave sessions alone.
+func ParsePolicies(rotateOn, revokeOthersOn []string) (map[Event]Policy, error) {
+	policies := make(map[Event]Policy, len(Events))
+	set := func(names []string, apply func(*Policy)) error {
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			event, ok := lookupEvent(name)
+			if !ok {
+				return fmt.Errorf("%w: %q", ErrUnknownEvent, name)
+			}
+			p := policies[event]
+			apply(&p)
+			policies[event] = p
+		}
+		return nil
+	}
+	if err := set(rotateOn, func(p *Policy) { p.Rotate = true }); err != nil {
+		return nil, err
+	}
+	if err := set(revokeOthersOn, func(p *Policy) { p.RevokeOthers = true }); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func lookupEvent(name string) (Event, bool) {
+	for _, e := range Events {
+		if string(e) == name {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+// RotationStore is the subset of the sessions store a Rotator needs.
+type RotationStore interface {
+	Get(ctx context.Context, token string) (*models.Session, error)
+	SetWithUserIndex(ctx context.Context, session *models.Session, ttl time.Duration) error
+	DeleteWithUserIndex(ctx context.Context, session *models.Session) error
+	DeleteByUserExcept(ctx context.Context, userID, keep string) ([]string, error)
+}
+
+// Rotation is the outcome of Rotator.Rotate.
+type Rotation struct {
+	// Session is the current session under its new token, or nil when no
+	// session was rotated. Its Token is the value for the new cookie.
+	Session *models.Session
+	// Replaced is the current session as it was before rotation, or nil.
+	Replaced *models.Session
+	// Revoked are the public IDs of the user's other sessions that were signed out.
+	Revoked []string
+}
+
+// TTL returns how long the rotated session has left at now.
+func (r *Rotation) TTL(now time.Time) time.Duration {
+	if r.Session == nil {
+		return 0
+	}
+	return r.Session.ExpiresAt.Sub(now)
+}
+
+// Rotator applies the configured Policy to a user's sessions when their
+// authentication state changes.
+type Rotator struct {
+	store    RotationStore
+	policies map[Event]Policy
+	now      func() time.Time
+}
+
+// NewRotator returns a Rotator applying policies, as built by ParsePolicies.
+func NewRotator(store RotationStore, policies map[Event]Policy) *Rotator {
+	return &Rotator{store: store, policies: policies, now: time.Now}
+}
+
+// Rotate applies the policy for event to userID's sessions. currentToken is
+// the session cookie of the request, if any; it is rotated only if it belongs
+// to userID, so an unauthenticated request carrying someone else's cookie
+// leaves that session alone. The session under the new token keeps the
+// metadata and expiry of the one it replaces.
+func (r *Rotator) Rotate(ctx context.Context, event Event, userID, currentToken string) (*Rotation, error) {
+	policy := r.policies[event]
+	result := &Rotation{}
+	if !policy.Rotate && !policy.RevokeOthers {
+		return result, nil
+	}
+
+	var current *models.Session
+	if currentToken != "" {
+		s, err := r.store.Get(ctx, currentToken)
+		if err == nil && s != nil && s.UserID == userID && r.now().Before(s.ExpiresAt) {
+			current = s
+		}
+	}
+
+	if policy.Rotate && current != nil {
+		rotated, err := r.replace(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		result.Session = rotated
+		result.Replaced = current
+	}
+
+	if policy.RevokeOthers {
+		keep := ""
+		switch {
+		case result.Session != nil:
+			keep = result.Session.ID()
+		case current != nil:
+			keep = current.ID()
+		}
+		revoked, err := r.store.DeleteByUserExcept(ctx, userID, keep)
+		if err != nil {
+			return result, fmt.Errorf("revoking sessions: %w", err)
+		}
+		result.Revoked = revoked
+	}
+	return result, nil
+}
+
+// replace stores s under a new token and deletes it under the old one.
+func (r *Rotator) replace(ctx context.Context, s *models.Session) (*models.Session, error) {
+	token, err := GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	ttl := s.ExpiresAt.Sub(r.now())
+	if ttl <= 0 {
+		return nil, errors.New("rotating expired session")
+	}
+	rotated := s.Clone()
+	rotated.Token = token
+	rotated.TokenHash = ""
+	if err := r.store.SetWithUserIndex(ctx, &rotated, ttl); err != nil {
+		return nil, fmt.Errorf("storing rotated session: %w", err)
+	}
+	if err := r.store.DeleteWithUserIndex(ctx, s); err != nil {
+		return nil, fmt.Errorf("deleting replaced session: %w", err)
+	}
+	return &rotated, nil
+}
@@ -0,0 +1,184 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// fakeRotationStore keys sessions by ID, using the token itself as the hash.
+type fakeRotationStore struct {
+	sessions map[string]*models.Session
+	lastTTL  time.Duration
+}
+
+func (f *fakeRotationStore) Get(_ context.Context, token string) (*models.Session, error) {
+	s, ok := f.sessions[token]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	c := s.Clone()
+	return &c, nil
+}
+
+func (f *fakeRotationStore) SetWithUserIndex(_ context.Context, s *models.Session, ttl time.Duration) error {
+	if s.TokenHash == "" {
+		s.TokenHash = s.Token
+	}
+	f.lastTTL = ttl
+	c := s.Clone()
+	f.sessions[s.TokenHash] = &c
+	return nil
+}
+
+func (f *fakeRotationStore) DeleteWithUserIndex(_ context.Context, s *models.Session) error {
+	delete(f.sessions, s.TokenHash)
+	return nil
+}
+
+func (f *fakeRotationStore) DeleteByUserExcept(_ context.Context, userID, keep string) ([]string, error) {
+	var revoked []string
+	for id, s := range f.sessions {
+		if s.UserID == userID && id != keep {
+			delete(f.sessions, id)
+			revoked = append(revoked, id)
+		}
+	}
+	return revoked, nil
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies(
+		[]string{"password_reset", " mfa_enabled ", ""},
+		[]string{"password_reset", "provider_linked"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[Event]Policy{
+		EventPasswordReset:  {Rotate: true, RevokeOthers: true},
+		EventMFAEnabled:     {Rotate: true},
+		EventProviderLinked: {RevokeOthers: true},
+	}
+	for _, e := range Events {
+		if policies[e] != want[e] {
+			t.Errorf("%s: got %+v want %+v", e, policies[e], want[e])
+		}
+	}
+}
+
+func TestParsePolicies_UnknownEvent(t *testing.T) {
+	if _, err := ParsePolicies([]string{"password_changed"}, nil); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("got %v, want ErrUnknownEvent", err)
+	}
+	if _, err := ParsePolicies(nil, []string{"login"}); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("got %v, want ErrUnknownEvent", err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		event  Event
+		policy map[Event]Policy
+		cookie string
+		// rotated reports whether the cookie's session is replaced; revoked
+		// and kept are the original sessions removed and left behind.
+		rotated bool
+		revoked []string
+		kept    []string
+	}{
+		{"rotate", EventMFAEnabled, map[Event]Policy{EventMFAEnabled: {Rotate: true}}, "current",
+			true, nil, []string{"foreign", "other"}},
+		{"rotate and revoke others", EventPasswordReset, map[Event]Policy{EventPasswordReset: {Rotate: true, RevokeOthers: true}}, "current",
+			true, []string{"other"}, []string{"foreign"}},
+		{"revoke others without rotate", EventProviderLinked, map[Event]Policy{EventProviderLinked: {RevokeOthers: true}}, "current",
+			false, []string{"other"}, []string{"current", "foreign"}},
+		// A cookie for another user's session is not the caller's to keep.
+		{"another user's cookie", EventPasswordReset, map[Event]Policy{EventPasswordReset: {Rotate: true, RevokeOthers: true}}, "foreign",
+			false, []string{"current", "other"}, []string{"foreign"}},
+		{"no policy", EventEmailVerified, nil, "current",
+			false, nil, []string{"current", "foreign", "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeRotationStore{sessions: map[string]*models.Session{}}
+			for token, userID := range map[string]string{"current": "u1", "other": "u1", "foreign": "u2"} {
+				store.sessions[token] = &models.Session{
+					Token: token, TokenHash: token, UserID: userID, Method: models.LoginMethodPassword,
+					CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour),
+				}
+			}
+			r := NewRotator(store, tt.policy)
+			r.now = func() time.Time { return now }
+
+			rot, err := r.Rotate(context.Background(), tt.event, "u1", tt.cookie)
+			if err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(rot.Revoked)
+			if !slices.Equal(rot.Revoked, tt.revoked) {
+				t.Errorf("revoked: got %v want %v", rot.Revoked, tt.revoked)
+			}
+			for _, id := range tt.kept {
+				if _, ok := store.sessions[id]; !ok {
+					t.Errorf("session %q revoked", id)
+				}
+			}
+			if !tt.rotated {
+				if rot.Session != nil || len(store.sessions) != len(tt.kept) {
+					t.Errorf("session rotated: got %+v, %d sessions stored", rot.Session, len(store.sessions))
+				}
+				return
+			}
+			if rot.Session == nil || rot.Session.Token == "" || rot.Session.Token == tt.cookie {
+				t.Fatalf("expected a new token, got %+v", rot.Session)
+			}
+			if _, ok := store.sessions[tt.cookie]; ok {
+				t.Error("old token still valid")
+			}
+			stored, ok := store.sessions[rot.Session.ID()]
+			if !ok {
+				t.Fatal("rotated session not stored")
+			}
+			if stored.Method != models.LoginMethodPassword || !stored.CreatedAt.Equal(now.Add(-time.Hour)) {
+				t.Error("rotated session lost its metadata")
+			}
+			if got := rot.TTL(now); got != time.Hour || store.lastTTL != time.Hour {
+				t.Errorf("TTL: got %v stored %v, want 1h", got, store.lastTTL)
+			}
+			if rot.Replaced == nil || rot.Replaced.Token != tt.cookie {
+				t.Error("Replaced should be the old session")
+			}
+		})
+	}
+}
+
+func TestRotate_RevokesBeyondListingLimit(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeRotationStore{sessions: map[string]*models.Session{}}
+	for i := 0; i < 151; i++ {
+		id := fmt.Sprintf("session-%d", i)
+		store.sessions[id] = &models.Session{Token: id, TokenHash: id, UserID: "u1", ExpiresAt: now.Add(time.Hour)}
+	}
+	r := NewRotator(store, map[Event]Policy{EventPasswordReset: {RevokeOthers: true}})
+	r.now = func() time.Time { return now }
+
+	rot, err := r.Rotate(context.Background(), EventPasswordReset, "u1", "session-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rot.Revoked) != 150 {
+		t.Errorf("Revoked: got %d sessions want 150", len(rot.Revoked))
+	}
+	if _, ok := store.sessions["session-0"]; !ok || len(store.sessions) != 1 {
+		t.Errorf("expected only the current session to remain, %d sessions left", len(store.sessions))
+	}
+}
@@ -3,23 +3,62 @@ package session
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 )
 
-const (
-	stateCookieName = "oauth_state"
-	stateTTL        = 10 * time.Minute
-)
+const stateCookieName = "oauth_state"
+
+// StateTTL is how long an OAuth state is accepted. Server-side records a state
+// refers to, such as a pending provider link, are kept for as long.
+const StateTTL = 10 * time.Minute
 
 // ErrInvalidState is returned when the OAuth state parameter fails validation.
 var ErrInvalidState = errors.New("invalid oauth state")
 
+// StatePurpose identifies which OAuth flow a state was issued for.
+type StatePurpose string
+
+const (
+	// StatePurposeLogin marks a state issued by a login flow.
+	StatePurposeLogin StatePurpose = "login"
+	// StatePurposeLink marks a state issued by an account-linking flow.
+	StatePurposeLink StatePurpose = "link"
+	// StatePurposeConfirmLink marks a state carrying a provider link that waits
+	// for the user to sign in to the matching account and confirm it.
+	StatePurposeConfirmLink StatePurpose = "confirm_link"
+)
+
+// StateClaims is the flow context bound to an OAuth state.
+type StateClaims struct {
+	// Nonce is the random value sent to the provider as the state parameter.
+	Nonce string `json:"n"`
+	// Purpose is the flow the state was issued for.
+	Purpose StatePurpose `json:"p"`
+	// UserID is the user who initiated a link flow, or the account a pending
+	// link must be confirmed by; empty for logins.
+	UserID string `json:"u,omitempty"`
+	// Provider is the provider of a pending link.
+	Provider string `json:"pt,omitempty"`
+	// LinkID is the key of a pending link held server-side. The link itself,
+	// including the provider access token, never enters the cookie.
+	LinkID string `json:"l,omitempty"`
+	// CodeVerifier is the PKCE verifier sent with the token exchange.
+	CodeVerifier string `json:"v,omitempty"`
+	// ReturnTo is where to send the user once the flow completes.
+	ReturnTo string `json:"r,omitempty"`
+	// Remember carries the login's "remember me" choice through the flow.
+	Remember bool `json:"m,omitempty"`
+	// ExpiresAt is the Unix time after which the state is rejected.
+	ExpiresAt int64 `json:"e"`
+}
+
 // StateManager handles generation and validation of OAuth state parameters.
 type StateManager struct {
 	secret       []byte
@@ -36,73 +75,81 @@ func NewStateManager(secret, cookieDomain string, cookieSecure bool) *StateManag
 	}
 }
 
-// GenerateState creates a signed state string and its corresponding cookie.
-// The state encodes a nonce and expiry, signed with HMAC-SHA256.
-func (m *StateManager) GenerateState() (string, *http.Cookie, error) {
+// GenerateState issues a state for a new OAuth flow carrying claims.
+// Nonce and ExpiresAt are set here; the caller supplies the rest.
+//
+// The returned state is only the random nonce, because it travels through the
+// provider and appears in URLs. The full claims — including the PKCE verifier,
+// which must never be visible alongside the authorization code — are signed with
+// HMAC-SHA256 and kept in an HttpOnly cookie.
+func (m *StateManager) GenerateState(claims StateClaims) (string, *http.Cookie, error) {
 	nonce, err := GenerateToken()
 	if err != nil {
 		return "", nil, fmt.Errorf("generating nonce: %w", err)
 	}
+	claims.Nonce = nonce
+	claims.ExpiresAt = time.Now().Add(StateTTL).Unix()
 
-	expiry := time.Now().Add(stateTTL).Unix()
-	payload := nonce + "|" + strconv.FormatInt(expiry, 10)
-
-	sig := m.sign(payload)
-	state := payload + "." + sig
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, fmt.Errorf("encoding state claims: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
 
 	cookie := &http.Cookie{
 		Name:     stateCookieName,
-		Value:    state,
+		Value:    payload + "." + m.sign(payload),
 		Path:     "/",
 		Domain:   m.cookieDomain,
-		MaxAge:   int(stateTTL.Seconds()),
+		MaxAge:   int(StateTTL.Seconds()),
 		Secure:   m.cookieSecure,
 		HttpOnly: true,
 		SameSite: http.SameSiteLaxMode,
 	}
 
-	return state, cookie, nil
+	return nonce, cookie, nil
 }
 
-// ValidateState verifies the state parameter against the cookie stored in the request.
-// It checks that the state matches the cookie, verifies the HMAC signature,
-// and ensures the state has not expired.
-func (m *StateManager) ValidateState(stateParam string, r *http.Request) error {
+// ValidateState verifies the state parameter against the cookie stored in the request
+// and returns the claims the flow was started with.
+// It verifies the cookie's HMAC signature, checks the state matches the signed
+// nonce, and ensures the state has not expired. Callers must still check that
+// Purpose (and UserID for link flows) match the callback being served.
+func (m *StateManager) ValidateState(stateParam string, r *http.Request) (*StateClaims, error) {
 	cookie, err := r.Cookie(stateCookieName)
 	if err != nil {
-		return ErrInvalidState
-	}
-
-	if cookie.Value != stateParam {
-		return ErrInvalidState
+		return nil, ErrInvalidState
 	}
 
 	// Split into payload and signature.
-	lastDot := strings.LastIndex(stateParam, ".")
+	lastDot := strings.LastIndex(cookie.Value, ".")
 	if lastDot < 0 {
-		return ErrInvalidState
+		return nil, ErrInvalidState
 	}
-	payload := stateParam[:lastDot]
-	sig := stateParam[lastDot+1:]
+	payload := cookie.Value[:lastDot]
+	sig := cookie.Value[lastDot+1:]
 
 	if !hmac.Equal([]byte(sig), []byte(m.sign(payload))) {
-		return ErrInvalidState
+		return nil, ErrInvalidState
 	}
 
-	// Verify expiry from payload: nonce|expiry
-	parts := strings.SplitN(payload, "|", 2)
-	if len(parts) != 2 {
-		return ErrInvalidState
-	}
-	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
 	if err != nil {
-		return ErrInvalidState
+		return nil, ErrInvalidState
+	}
+	var claims StateClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, ErrInvalidState
+	}
+
+	if claims.Nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(stateParam)) != 1 {
+		return nil, ErrInvalidState
 	}
-	if time.Now().Unix() > expiry {
-		return ErrInvalidState
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrInvalidState
 	}
 
-	return nil
+	return &claims, nil
 }
 
 // ClearStateCookie returns an expired cookie that clears the state cookie in the browser.
@@ -1,6 +1,8 @@
 package session
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -21,10 +23,32 @@ func requestWithCookie(name, value string) *http.Request {
 	return r
 }
 
+// signClaims builds a cookie value for claims signed by m, bypassing GenerateState
+// so tests can craft expired or modified claims.
+func signClaims(t *testing.T, m *StateManager, claims StateClaims) string {
+	t.Helper()
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + m.sign(payload)
+}
+
+func testClaims() StateClaims {
+	return StateClaims{
+		Purpose:      StatePurposeLink,
+		UserID:       "user-123",
+		CodeVerifier: "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+		ReturnTo:     "/settings",
+		Remember:     true,
+	}
+}
+
 func TestGenerateState_ReturnsStateAndCookie(t *testing.T) {
 	m := newTestManager(t)
 
-	state, cookie, err := m.GenerateState()
+	state, cookie, err := m.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -37,15 +61,15 @@ func TestGenerateState_ReturnsStateAndCookie(t *testing.T) {
 	if cookie.Name != stateCookieName {
 		t.Errorf("cookie name: got %q want %q", cookie.Name, stateCookieName)
 	}
-	if cookie.Value != state {
-		t.Errorf("cookie value does not match state: got %q want %q", cookie.Value, state)
+	if !strings.Contains(cookie.Value, ".") {
+		t.Errorf("cookie value should be payload.signature: %q", cookie.Value)
 	}
 }
 
 func TestGenerateState_CookieAttributes(t *testing.T) {
 	m := NewStateManager("a-test-secret-that-is-long-enough!!", "example.com", true)
 
-	_, cookie, err := m.GenerateState()
+	_, cookie, err := m.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -61,56 +85,89 @@ func TestGenerateState_CookieAttributes(t *testing.T) {
 	if cookie.Domain != "example.com" {
 		t.Errorf("Domain: got %q want %q", cookie.Domain, "example.com")
 	}
-	if cookie.MaxAge != int(stateTTL.Seconds()) {
-		t.Errorf("MaxAge: got %d want %d", cookie.MaxAge, int(stateTTL.Seconds()))
+	if cookie.MaxAge != int(StateTTL.Seconds()) {
+		t.Errorf("MaxAge: got %d want %d", cookie.MaxAge, int(StateTTL.Seconds()))
 	}
 	if cookie.Path != "/" {
 		t.Errorf("Path: got %q want %q", cookie.Path, "/")
 	}
 }
 
-func TestGenerateState_HasThreeParts(t *testing.T) {
-	// State format: nonce|expiry.signature
+func TestGenerateState_StateOmitsClaims(t *testing.T) {
+	// The state travels through the provider; the PKCE verifier must not.
 	m := newTestManager(t)
-	state, _, err := m.GenerateState()
+	claims := testClaims()
+
+	state, _, err := m.GenerateState(claims)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
+	for _, secret := range []string{claims.CodeVerifier, claims.UserID} {
+		if strings.Contains(state, secret) {
+			t.Errorf("state %q leaks claim %q", state, secret)
+		}
+	}
+}
+
+func TestValidateState_RoundTrip(t *testing.T) {
+	m := newTestManager(t)
+	want := testClaims()
+
+	state, cookie, err := m.GenerateState(want)
+	if err != nil {
+		t.Fatalf("GenerateState: %v", err)
+	}
 
-	lastDot := strings.LastIndex(state, ".")
-	if lastDot < 0 {
-		t.Fatalf("state has no '.': %q", state)
+	r := requestWithCookie(stateCookieName, cookie.Value)
+	got, err := m.ValidateState(state, r)
+	if err != nil {
+		t.Fatalf("expected valid state, got: %v", err)
+	}
+	if got.Nonce != state {
+		t.Errorf("Nonce: got %q want %q", got.Nonce, state)
 	}
-	payload := state[:lastDot]
-	if !strings.Contains(payload, "|") {
-		t.Errorf("payload has no '|': %q", payload)
+	if got.Purpose != want.Purpose || got.UserID != want.UserID ||
+		got.CodeVerifier != want.CodeVerifier || got.ReturnTo != want.ReturnTo || got.Remember != want.Remember {
+		t.Errorf("claims did not round-trip: got %+v want %+v", got, want)
 	}
 }
 
-func TestValidateState_RoundTrip(t *testing.T) {
+func TestValidateState_PendingLinkRoundTrip(t *testing.T) {
 	m := newTestManager(t)
+	want := StateClaims{
+		Purpose:  StatePurposeConfirmLink,
+		UserID:   "user-123",
+		Provider: "github",
+		LinkID:   "link-key",
+	}
 
-	state, _, err := m.GenerateState()
+	state, cookie, err := m.GenerateState(want)
 	if err != nil {
 		t.Fatalf("GenerateState: %v", err)
 	}
+	if strings.Contains(state, want.LinkID) {
+		t.Errorf("state %q leaks the link key", state)
+	}
 
-	r := requestWithCookie(stateCookieName, state)
-	if err := m.ValidateState(state, r); err != nil {
+	got, err := m.ValidateState(state, requestWithCookie(stateCookieName, cookie.Value))
+	if err != nil {
 		t.Fatalf("expected valid state, got: %v", err)
 	}
+	if got.Purpose != want.Purpose || got.UserID != want.UserID || got.Provider != want.Provider || got.LinkID != want.LinkID {
+		t.Errorf("pending link did not round-trip: got %+v want %+v", got, want)
+	}
 }
 
 func TestValidateState_NoCookie(t *testing.T) {
 	m := newTestManager(t)
 
-	state, _, err := m.GenerateState()
+	state, _, err := m.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("GenerateState: %v", err)
 	}
 
 	r := httptest.NewRequest(http.MethodGet, "/callback", nil) // no cookie
-	if err := m.ValidateState(state, r); err != ErrInvalidState {
+	if _, err := m.ValidateState(state, r); err != ErrInvalidState {
 		t.Errorf("expected ErrInvalidState, got: %v", err)
 	}
 }
@@ -118,19 +175,19 @@ func TestValidateState_NoCookie(t *testing.T) {
 func TestValidateState_CookieMismatch(t *testing.T) {
 	m := newTestManager(t)
 
-	state, _, err := m.GenerateState()
+	_, cookie, err := m.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("GenerateState: %v", err)
 	}
 
-	other, _, err := m.GenerateState()
+	other, _, err := m.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("GenerateState (other): %v", err)
 	}
 
-	// Cookie holds `state`, but param is `other` — mismatch.
-	r := requestWithCookie(stateCookieName, state)
-	if err := m.ValidateState(other, r); err != ErrInvalidState {
+	// Cookie belongs to the first flow, but param is from `other` — mismatch.
+	r := requestWithCookie(stateCookieName, cookie.Value)
+	if _, err := m.ValidateState(other, r); err != ErrInvalidState {
 		t.Errorf("expected ErrInvalidState on mismatch, got: %v", err)
 	}
 }
@@ -138,19 +195,20 @@ func TestValidateState_CookieMismatch(t *testing.T) {
 func TestValidateState_TamperedSignature(t *testing.T) {
 	m := newTestManager(t)
 
-	state, _, err := m.GenerateState()
+	state, cookie, err := m.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("GenerateState: %v", err)
 	}
 
 	// Flip the last character of the signature.
-	tampered := state[:len(state)-1] + "X"
-	if tampered[len(tampered)-1] == state[len(state)-1] {
-		tampered = state[:len(state)-1] + "Y"
+	value := cookie.Value
+	tampered := value[:len(value)-1] + "X"
+	if tampered[len(tampered)-1] == value[len(value)-1] {
+		tampered = value[:len(value)-1] + "Y"
 	}
 
 	r := requestWithCookie(stateCookieName, tampered)
-	if err := m.ValidateState(tampered, r); err != ErrInvalidState {
+	if _, err := m.ValidateState(state, r); err != ErrInvalidState {
 		t.Errorf("expected ErrInvalidState for tampered signature, got: %v", err)
 	}
 }
@@ -158,19 +216,22 @@ func TestValidateState_TamperedSignature(t *testing.T) {
 func TestValidateState_TamperedPayload(t *testing.T) {
 	m := newTestManager(t)
 
-	state, _, err := m.GenerateState()
+	state, cookie, err := m.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("GenerateState: %v", err)
 	}
 
-	// Replace the nonce portion of the payload while keeping the original sig.
-	lastDot := strings.LastIndex(state, ".")
-	sig := state[lastDot:]
-	payload := "fakefakenonce|" + strings.Split(state[:lastDot], "|")[1]
-	tampered := payload + sig
+	// Swap in claims for a different user while keeping the original signature.
+	sig := cookie.Value[strings.LastIndex(cookie.Value, "."):]
+	forged := testClaims()
+	forged.Nonce = state
+	forged.UserID = "attacker"
+	forged.ExpiresAt = time.Now().Add(time.Minute).Unix()
+	raw, _ := json.Marshal(forged)
+	tampered := base64.RawURLEncoding.EncodeToString(raw) + sig
 
 	r := requestWithCookie(stateCookieName, tampered)
-	if err := m.ValidateState(tampered, r); err != ErrInvalidState {
+	if _, err := m.ValidateState(state, r); err != ErrInvalidState {
 		t.Errorf("expected ErrInvalidState for tampered payload, got: %v", err)
 	}
 }
@@ -178,36 +239,36 @@ func TestValidateState_TamperedPayload(t *testing.T) {
 func TestValidateState_Expired(t *testing.T) {
 	m := newTestManager(t)
 
-	state, _, err := m.GenerateState()
-	if err != nil {
-		t.Fatalf("GenerateState: %v", err)
+	// Re-sign with the same manager to isolate the expiry check.
+	claims := testClaims()
+	claims.Nonce = "expired-nonce"
+	claims.ExpiresAt = time.Now().Add(-time.Second).Unix()
+
+	r := requestWithCookie(stateCookieName, signClaims(t, m, claims))
+	if _, err := m.ValidateState(claims.Nonce, r); err != ErrInvalidState {
+		t.Errorf("expected ErrInvalidState for expired state, got: %v", err)
 	}
+}
 
-	// Reconstruct the state with an expiry in the past.
-	// We re-sign it with the same manager to isolate the expiry check.
-	lastDot := strings.LastIndex(state, ".")
-	payload := state[:lastDot]
-	parts := strings.SplitN(payload, "|", 2)
-	nonce := parts[0]
+func TestValidateState_EmptyNonce(t *testing.T) {
+	m := newTestManager(t)
 
-	expiredExpiry := time.Now().Add(-time.Second).Unix()
-	expiredPayload := nonce + "|" + itoa64(expiredExpiry)
-	expiredSig := m.sign(expiredPayload)
-	expiredState := expiredPayload + "." + expiredSig
+	// A validly signed cookie without a nonce must not match an empty state param.
+	claims := testClaims()
+	claims.ExpiresAt = time.Now().Add(time.Minute).Unix()
 
-	r := requestWithCookie(stateCookieName, expiredState)
-	if err := m.ValidateState(expiredState, r); err != ErrInvalidState {
-		t.Errorf("expected ErrInvalidState for expired state, got: %v", err)
+	r := requestWithCookie(stateCookieName, signClaims(t, m, claims))
+	if _, err := m.ValidateState("", r); err != ErrInvalidState {
+		t.Errorf("expected ErrInvalidState for empty nonce, got: %v", err)
 	}
 }
 
 func TestValidateState_NoDelimiter(t *testing.T) {
 	m := newTestManager(t)
 
-	// A state string without the '.' delimiter is malformed.
-	malformed := "nodotinhere"
-	r := requestWithCookie(stateCookieName, malformed)
-	if err := m.ValidateState(malformed, r); err != ErrInvalidState {
+	// A cookie without the '.' delimiter is malformed.
+	r := requestWithCookie(stateCookieName, "nodotinhere")
+	if _, err := m.ValidateState("nodotinhere", r); err != ErrInvalidState {
 		t.Errorf("expected ErrInvalidState for malformed state, got: %v", err)
 	}
 }
@@ -216,14 +277,14 @@ func TestValidateState_DifferentManager(t *testing.T) {
 	m1 := NewStateManager("secret-one-that-is-long-enough!!!!!", "localhost", false)
 	m2 := NewStateManager("secret-two-that-is-long-enough!!!!!", "localhost", false)
 
-	state, _, err := m1.GenerateState()
+	state, cookie, err := m1.GenerateState(testClaims())
 	if err != nil {
 		t.Fatalf("GenerateState: %v", err)
 	}
 
 	// m2 has a different secret; signature should fail.
-	r := requestWithCookie(stateCookieName, state)
-	if err := m2.ValidateState(state, r); err != ErrInvalidState {
+	r := requestWithCookie(stateCookieName, cookie.Value)
+	if _, err := m2.ValidateState(state, r); err != ErrInvalidState {
 		t.Errorf("expected ErrInvalidState when validating with different secret, got: %v", err)
 	}
 }
@@ -257,7 +318,7 @@ func TestGenerateState_Unique(t *testing.T) {
 	const n = 20
 	seen := make(map[string]struct{}, n)
 	for i := 0; i < n; i++ {
-		state, _, err := m.GenerateState()
+		state, _, err := m.GenerateState(testClaims())
 		if err != nil {
 			t.Fatalf("iteration %d: %v", i, err)
 		}
@@ -267,23 +328,3 @@ func TestGenerateState_Unique(t *testing.T) {
 		seen[state] = struct{}{}
 	}
 }
-
-// itoa64 converts an int64 to its decimal string representation.
-func itoa64(n int64) string {
-	if n == 0 {
-		return "0"
-	}
-	neg := n < 0
-	if neg {
-		n = -n
-	}
-	buf := make([]byte, 0, 20)
-	for n > 0 {
-		buf = append([]byte{byte('0' + n%10)}, buf...)
-		n /= 10
-	}
-	if neg {
-		buf = append([]byte{'-'}, buf...)
-	}
-	return string(buf)
-}
@@ -0,0 +1,50 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// minTokenHashKeyLen is the shortest accepted HMAC key, in bytes.
+const minTokenHashKeyLen = 32
+
+// ErrTokenHashKeyTooShort is returned for HMAC keys shorter than 32 bytes.
+var ErrTokenHashKeyTooShort = errors.New("token hash key must be at least 32 bytes")
+
+// TokenHasher derives the at-rest form of bearer tokens. Sessions and
+// verification tokens are stored under the keyed hash of their token, so a
+// copy of Redis cannot be replayed as credentials.
+type TokenHasher struct {
+	key []byte
+}
+
+// NewTokenHasher returns a TokenHasher using key for HMAC-SHA256.
+func NewTokenHasher(key []byte) (*TokenHasher, error) {
+	if len(key) < minTokenHashKeyLen {
+		return nil, ErrTokenHashKeyTooShort
+	}
+	return &TokenHasher{key: append([]byte(nil), key...)}, nil
+}
+
+// Hash returns the hex-encoded HMAC-SHA256 of token.
+func (h *TokenHasher) Hash(token string) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsTokenHash reports whether s has the form of a Hash result, which is how
+// hashed Redis keys are told apart from the raw tokens used before hashing.
+func IsTokenHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestNewTokenHasher_RejectsShortKey(t *testing.T) {
+	if _, err := NewTokenHasher(make([]byte, 16)); !errors.Is(err, ErrTokenHashKeyTooShort) {
+		t.Errorf("got %v, want ErrTokenHashKeyTooShort", err)
+	}
+}
+
+func TestTokenHasher_Hash(t *testing.T) {
+	h, err := NewTokenHasher(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewTokenHasher: %v", err)
+	}
+	other, err := NewTokenHasher(bytes.Repeat([]byte{2}, 32))
+	if err != nil {
+		t.Fatalf("NewTokenHasher: %v", err)
+	}
+
+	token, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	hash := h.Hash(token)
+	if hash != h.Hash(token) {
+		t.Error("hash should be deterministic")
+	}
+	if !IsTokenHash(hash) {
+		t.Errorf("IsTokenHash(%q) = false", hash)
+	}
+	if hash == other.Hash(token) {
+		t.Error("different keys should produce different hashes")
+	}
+	if hash == h.Hash(token+"x") {
+		t.Error("different tokens should produce different hashes")
+	}
+}
+
+func TestIsTokenHash_RawToken(t *testing.T) {
+	token, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if IsTokenHash(token) {
+		t.Errorf("raw token %q mistaken for a hash", token)
+	}
+}
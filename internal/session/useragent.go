@@ -0,0 +1,122 @@
+package session
+
+import (
+	"strings"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+// MaxUserAgentLen bounds the raw User-Agent stored with a session.
+const MaxUserAgentLen = 512
+
+// UserAgent is the coarse description of a client shown in session lists.
+type UserAgent struct {
+	// Browser is the browser family and major version, e.g. "Firefox 126".
+	Browser string
+	// OS is the operating system family, e.g. "macOS".
+	OS string
+	// DeviceClass is one of the models.DeviceClass* values.
+	DeviceClass string
+}
+
+// browserTokens are matched in order, so browsers built on Chrome or Safari
+// are found before the engine they also advertise.
+var browserTokens = []struct{ token, name string }{
+	{"EdgiOS/", "Edge"},
+	{"EdgA/", "Edge"},
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"FxiOS/", "Firefox"},
+	{"Firefox/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chromium/", "Chromium"},
+	{"Chrome/", "Chrome"},
+	{"Version/", "Safari"},
+	{"MSIE ", "Internet Explorer"},
+	{"Trident/", "Internet Explorer"},
+}
+
+// osTokens are matched in order; Android and iOS user agents also mention
+// Linux and Mac OS X.
+var osTokens = []struct{ token, name string }{
+	{"Windows", "Windows"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"iPod", "iOS"},
+	{"Android", "Android"},
+	{"CrOS", "ChromeOS"},
+	{"Macintosh", "macOS"},
+	{"Mac OS X", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// botMarkers identify crawlers and scripted clients (matched lower-case).
+var botMarkers = []string{"bot", "crawl", "spider", "slurp", "curl/", "wget/", "python-", "go-http-client", "okhttp"}
+
+// ParseUserAgent derives the browser, OS and device class from a User-Agent
+// header. It recognises the common browsers only; anything else is reported as
+// "Other", and an empty header as an unknown device.
+func ParseUserAgent(header string) UserAgent {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return UserAgent{DeviceClass: models.DeviceClassUnknown}
+	}
+
+	ua := UserAgent{Browser: "Other", OS: "Other"}
+	for _, b := range browserTokens {
+		if version, ok := tokenVersion(header, b.token); ok {
+			ua.Browser = b.name
+			if b.name == "Internet Explorer" && b.token == "Trident/" {
+				// Trident/7.0 is IE 11; its own version is not the browser's.
+				version = ""
+			}
+			if version != "" {
+				ua.Browser += " " + version
+			}
+			break
+		}
+	}
+	for _, o := range osTokens {
+		if strings.Contains(header, o.token) {
+			ua.OS = o.name
+			break
+		}
+	}
+	ua.DeviceClass = deviceClass(header, ua.OS)
+	return ua
+}
+
+// tokenVersion reports whether token occurs in header and returns the major
+// version number that follows it, if any.
+func tokenVersion(header, token string) (string, bool) {
+	i := strings.Index(header, token)
+	if i < 0 {
+		return "", false
+	}
+	rest := header[i+len(token):]
+	end := 0
+	for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+		end++
+	}
+	return rest[:end], true
+}
+
+func deviceClass(header, os string) string {
+	lower := strings.ToLower(header)
+	for _, marker := range botMarkers {
+		if strings.Contains(lower, marker) {
+			return models.DeviceClassBot
+		}
+	}
+	switch {
+	case os == "iPadOS", strings.Contains(header, "Tablet"),
+		os == "Android" && !strings.Contains(header, "Mobile"):
+		return models.DeviceClassTablet
+	case os == "iOS", os == "Android", strings.Contains(header, "Mobi"):
+		return models.DeviceClassMobile
+	case os == "Windows", os == "macOS", os == "Linux", os == "ChromeOS":
+		return models.DeviceClassDesktop
+	}
+	return models.DeviceClassUnknown
+}
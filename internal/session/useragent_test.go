@@ -0,0 +1,93 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   UserAgent
+	}{
+		{
+			name:   "chrome on windows",
+			header: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want:   UserAgent{Browser: "Chrome 124", OS: "Windows", DeviceClass: models.DeviceClassDesktop},
+		},
+		{
+			name:   "edge on windows",
+			header: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.80",
+			want:   UserAgent{Browser: "Edge 124", OS: "Windows", DeviceClass: models.DeviceClassDesktop},
+		},
+		{
+			name:   "firefox on linux",
+			header: "Mozilla/5.0 (X11; Linux x86_64; rv:126.0) Gecko/20100101 Firefox/126.0",
+			want:   UserAgent{Browser: "Firefox 126", OS: "Linux", DeviceClass: models.DeviceClassDesktop},
+		},
+		{
+			name:   "safari on macos",
+			header: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Safari/605.1.15",
+			want:   UserAgent{Browser: "Safari 17", OS: "macOS", DeviceClass: models.DeviceClassDesktop},
+		},
+		{
+			name:   "safari on iphone",
+			header: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want:   UserAgent{Browser: "Safari 17", OS: "iOS", DeviceClass: models.DeviceClassMobile},
+		},
+		{
+			name:   "chrome on iphone",
+			header: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0.6367.88 Mobile/15E148 Safari/604.1",
+			want:   UserAgent{Browser: "Chrome 124", OS: "iOS", DeviceClass: models.DeviceClassMobile},
+		},
+		{
+			name:   "safari on ipad",
+			header: "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want:   UserAgent{Browser: "Safari 17", OS: "iPadOS", DeviceClass: models.DeviceClassTablet},
+		},
+		{
+			name:   "chrome on android phone",
+			header: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.6367.82 Mobile Safari/537.36",
+			want:   UserAgent{Browser: "Chrome 124", OS: "Android", DeviceClass: models.DeviceClassMobile},
+		},
+		{
+			name:   "samsung internet on android tablet",
+			header: "Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Safari/537.36",
+			want:   UserAgent{Browser: "Samsung Internet 24", OS: "Android", DeviceClass: models.DeviceClassTablet},
+		},
+		{
+			name:   "internet explorer 11",
+			header: "Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			want:   UserAgent{Browser: "Internet Explorer", OS: "Windows", DeviceClass: models.DeviceClassDesktop},
+		},
+		{
+			name:   "crawler",
+			header: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want:   UserAgent{Browser: "Other", OS: "Other", DeviceClass: models.DeviceClassBot},
+		},
+		{
+			name:   "curl",
+			header: "curl/8.7.1",
+			want:   UserAgent{Browser: "Other", OS: "Other", DeviceClass: models.DeviceClassBot},
+		},
+		{
+			name:   "unrecognised",
+			header: "SomeClient/1.0",
+			want:   UserAgent{Browser: "Other", OS: "Other", DeviceClass: models.DeviceClassUnknown},
+		},
+		{
+			name:   "empty",
+			header: "  ",
+			want:   UserAgent{DeviceClass: models.DeviceClassUnknown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseUserAgent(tt.header); got != tt.want {
+				t.Errorf("ParseUserAgent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
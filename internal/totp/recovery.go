@@ -0,0 +1,81 @@
+package totp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+const (
+	// RecoveryCodeCount is the number of recovery codes issued per enrollment.
+	RecoveryCodeCount = 10
+	// recoveryCodeLen is the number of characters in a recovery code, excluding the separator.
+	recoveryCodeLen = 10
+	// recoveryAlphabet omits characters that are easily confused when read aloud or handwritten.
+	recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// GenerateRecoveryCodes returns n random recovery codes formatted as "xxxxx-xxxxx".
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode canonicalises user input so that case, whitespace,
+// and a missing separator do not cause a valid code to be rejected.
+func NormalizeRecoveryCode(code string) string {
+	s := strings.ToLower(code)
+	s = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) != recoveryCodeLen {
+		return s
+	}
+	return s[:recoveryCodeLen/2] + "-" + s[recoveryCodeLen/2:]
+}
+
+// IsRecoveryCode reports whether code has the shape of a recovery code rather
+// than a numeric TOTP code.
+func IsRecoveryCode(code string) bool {
+	s := NormalizeRecoveryCode(code)
+	if len(s) != recoveryCodeLen+1 {
+		return false
+	}
+	for i, r := range s {
+		if i == recoveryCodeLen/2 {
+			if r != '-' {
+				return false
+			}
+			continue
+		}
+		if !strings.ContainsRune(recoveryAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// generateRecoveryCode returns a single random recovery code.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("totp: generating recovery code: %w", err)
+	}
+	out := make([]byte, recoveryCodeLen)
+	for i, v := range b {
+		// 256 is not a multiple of the alphabet size; the resulting bias is
+		// negligible against 10 characters of entropy.
+		out[i] = recoveryAlphabet[int(v)%len(recoveryAlphabet)]
+	}
+	return NormalizeRecoveryCode(string(out)), nil
+}
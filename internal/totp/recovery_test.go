@@ -0,0 +1,67 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateRecoveryCodes_CountAndFormat(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes: %v", err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("expected %d codes, got %d", RecoveryCodeCount, len(codes))
+	}
+	for _, c := range codes {
+		if !IsRecoveryCode(c) {
+			t.Errorf("generated code %q does not look like a recovery code", c)
+		}
+		if len(c) != 11 || c[5] != '-' {
+			t.Errorf("expected xxxxx-xxxxx format, got %q", c)
+		}
+	}
+}
+
+func TestGenerateRecoveryCodes_Unique(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes: %v", err)
+	}
+	seen := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		if seen[c] {
+			t.Errorf("duplicate code %q", c)
+		}
+		seen[c] = true
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	cases := map[string]string{
+		"abcde-fghjk":   "abcde-fghjk",
+		"ABCDE-FGHJK":   "abcde-fghjk",
+		"abcdefghjk":    "abcde-fghjk",
+		" abcde fghjk ": "abcde-fghjk",
+	}
+	for in, want := range cases {
+		if got := NormalizeRecoveryCode(in); got != want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsRecoveryCode_RejectsTOTPCode(t *testing.T) {
+	if IsRecoveryCode("123456") {
+		t.Error("expected 6-digit TOTP code not to be treated as a recovery code")
+	}
+}
+
+func TestIsRecoveryCode_RejectsAmbiguousCharacters(t *testing.T) {
+	if IsRecoveryCode("abcde-fghi1") {
+		t.Error("expected code containing excluded characters to be rejected")
+	}
+	if IsRecoveryCode(strings.Repeat("a", 12)) {
+		t.Error("expected wrong-length code to be rejected")
+	}
+}
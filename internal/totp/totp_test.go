@@ -0,0 +1,199 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed from RFC 6238 Appendix B, base32-encoded.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestHOTP_RFC6238Vectors(t *testing.T) {
+	// 8-digit SHA-1 test vectors from RFC 6238 Appendix B.
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+	}
+
+	key := []byte("12345678901234567890")
+	for _, tc := range cases {
+		got := hotp(key, tc.unix/Period, 8)
+		if got != tc.want {
+			t.Errorf("T=%d: got %q want %q", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestCode_SixDigits(t *testing.T) {
+	code, err := Code(rfcSecret, 59/Period)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	// Last six digits of the RFC vector 94287082.
+	if code != "287082" {
+		t.Errorf("got %q want %q", code, "287082")
+	}
+}
+
+func TestGenerateSecret_Decodable(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	key, err := decode(secret)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(key) != secretLen {
+		t.Errorf("expected %d-byte key, got %d", secretLen, len(key))
+	}
+	if strings.Contains(secret, "=") {
+		t.Errorf("secret should be unpadded: %q", secret)
+	}
+}
+
+func TestGenerateSecret_Unique(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	b, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	if a == b {
+		t.Error("expected distinct secrets")
+	}
+}
+
+func TestValidate_CurrentStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := Code(rfcSecret, Step(now))
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	step, ok, err := Validate(rfcSecret, code, now, 1)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected code to validate")
+	}
+	if step != Step(now) {
+		t.Errorf("step: got %d want %d", step, Step(now))
+	}
+}
+
+func TestValidate_WithinSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := Code(rfcSecret, Step(now)-1)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	step, ok, err := Validate(rfcSecret, code, now, 1)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected previous-step code to validate within skew")
+	}
+	if step != Step(now)-1 {
+		t.Errorf("step: got %d want %d", step, Step(now)-1)
+	}
+}
+
+func TestValidate_OutsideSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := Code(rfcSecret, Step(now)-2)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+
+	_, ok, err := Validate(rfcSecret, code, now, 1)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if ok {
+		t.Error("expected code two steps old to be rejected with skew 1")
+	}
+}
+
+func TestValidate_WrongCode(t *testing.T) {
+	_, ok, err := Validate(rfcSecret, "000000", time.Unix(59, 0), 0)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if ok {
+		t.Error("expected wrong code to be rejected")
+	}
+}
+
+func TestValidate_WrongLength(t *testing.T) {
+	_, ok, err := Validate(rfcSecret, "12345", time.Unix(59, 0), 1)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if ok {
+		t.Error("expected short code to be rejected")
+	}
+}
+
+func TestValidate_InvalidSecret(t *testing.T) {
+	_, _, err := Validate("not base32!!", "123456", time.Now(), 1)
+	if err != ErrInvalidSecret {
+		t.Errorf("expected ErrInvalidSecret, got: %v", err)
+	}
+}
+
+func TestValidate_LowercaseSecret(t *testing.T) {
+	now := time.Unix(59, 0)
+	step, ok, err := Validate(strings.ToLower(rfcSecret), "287082", now, 0)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if !ok || step != 1 {
+		t.Errorf("expected lowercase secret to validate at step 1, got ok=%v step=%d", ok, step)
+	}
+}
+
+func TestURI_Format(t *testing.T) {
+	uri := URI("Morpheus", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("failed to parse URI: %v", err)
+	}
+	if parsed.Scheme != "otpauth" {
+		t.Errorf("scheme: got %q want %q", parsed.Scheme, "otpauth")
+	}
+	if parsed.Host != "totp" {
+		t.Errorf("host: got %q want %q", parsed.Host, "totp")
+	}
+	if parsed.Path != "/Morpheus:user@example.com" {
+		t.Errorf("label: got %q", parsed.Path)
+	}
+	q := parsed.Query()
+	if q.Get("secret") != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("secret: got %q", q.Get("secret"))
+	}
+	if q.Get("issuer") != "Morpheus" {
+		t.Errorf("issuer: got %q", q.Get("issuer"))
+	}
+	if q.Get("digits") != "6" {
+		t.Errorf("digits: got %q", q.Get("digits"))
+	}
+	if q.Get("period") != "30" {
+		t.Errorf("period: got %q", q.Get("period"))
+	}
+}
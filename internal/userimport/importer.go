@@ -0,0 +1,123 @@
+package userimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// ImportedAccessToken is stored on imported provider links in place of an
+// OAuth access token, which is not portable between systems. The next login
+// through the provider replaces it.
+const ImportedAccessToken = "imported"
+
+// ErrProviderLinked is returned when a record's provider identity is already
+// linked to an account.
+var ErrProviderLinked = errors.New("provider identity already linked")
+
+// Users is the subset of the users store the importer needs.
+type Users interface {
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Set(ctx context.Context, key string, user *models.User) error
+}
+
+// Providers is the subset of the providers store the importer needs.
+type Providers interface {
+	GetByProviderUser(ctx context.Context, providerType models.ProviderType, providerUserID string) (*models.Provider, error)
+	Set(ctx context.Context, key string, provider *models.Provider) error
+}
+
+// Outcome is the result of importing one record.
+type Outcome int
+
+const (
+	// Created means the user (and any provider links) were written.
+	Created Outcome = iota
+	// Skipped means a user with the same email already exists, so re-running an
+	// import is safe.
+	Skipped
+)
+
+// Importer writes records to the users and providers stores.
+type Importer struct {
+	users     Users
+	providers Providers
+	dryRun    bool
+	now       func() time.Time
+}
+
+// New returns an Importer. With dryRun set, records are validated and checked
+// against existing data but nothing is written.
+func New(users Users, providers Providers, dryRun bool) *Importer {
+	return &Importer{users: users, providers: providers, dryRun: dryRun, now: time.Now}
+}
+
+// Import validates rec and creates its user and provider links.
+func (im *Importer) Import(ctx context.Context, rec *Record) (Outcome, error) {
+	if err := rec.Validate(); err != nil {
+		return 0, err
+	}
+
+	if existing, err := im.users.GetByEmail(ctx, rec.Email); err == nil && existing != nil {
+		return Skipped, nil
+	}
+	for _, p := range rec.Providers {
+		link, err := im.providers.GetByProviderUser(ctx, models.ProviderType(p.Type), p.ProviderUserID)
+		if err == nil && link != nil {
+			return 0, fmt.Errorf("%w: %s %s", ErrProviderLinked, p.Type, p.ProviderUserID)
+		}
+	}
+	if im.dryRun {
+		return Created, nil
+	}
+
+	userID, err := intsession.GenerateToken()
+	if err != nil {
+		return 0, err
+	}
+	now := im.now()
+	user := &models.User{
+		ID:            userID,
+		Email:         rec.Email,
+		EmailVerified: rec.EmailVerified,
+		CreatedAt:     rec.CreatedAt,
+		UpdatedAt:     now,
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if rec.PasswordHash != "" {
+		hash := rec.PasswordHash
+		user.PasswordHash = &hash
+	}
+	if rec.Name != "" {
+		name := rec.Name
+		user.Name = &name
+	}
+	if rec.AvatarURL != "" {
+		avatar := rec.AvatarURL
+		user.AvatarURL = &avatar
+	}
+	if err := im.users.Set(ctx, user.ID, user); err != nil {
+		return 0, fmt.Errorf("saving user %s: %w", rec.Email, err)
+	}
+
+	for _, p := range rec.Providers {
+		link := &models.Provider{
+			UserID:         user.ID,
+			Type:           models.ProviderType(p.Type),
+			ProviderUserID: p.ProviderUserID,
+			AccessToken:    ImportedAccessToken,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := im.providers.Set(ctx, "", link); err != nil {
+			return 0, fmt.Errorf("saving %s link for %s: %w", p.Type, rec.Email, err)
+		}
+	}
+	return Created, nil
+}
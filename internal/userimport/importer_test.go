@@ -0,0 +1,138 @@
+package userimport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/sumatra/models"
+)
+
+const djangoHash = "pbkdf2_sha256$1000$seasalt123$Z8W6THQ7/q7YZax54QizjDcL3C+cgOKb60X7lQWjODc="
+
+type fakeUsers struct {
+	byEmail map[string]*models.User
+}
+
+func (f *fakeUsers) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	if u, ok := f.byEmail[email]; ok {
+		return u, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeUsers) Set(_ context.Context, _ string, u *models.User) error {
+	f.byEmail[u.Email] = u
+	return nil
+}
+
+type fakeProviders struct {
+	links []*models.Provider
+}
+
+func (f *fakeProviders) GetByProviderUser(_ context.Context, typ models.ProviderType, id string) (*models.Provider, error) {
+	for _, l := range f.links {
+		if l.Type == typ && l.ProviderUserID == id {
+			return l, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeProviders) Set(_ context.Context, _ string, p *models.Provider) error {
+	f.links = append(f.links, p)
+	return nil
+}
+
+func newFakes() (*fakeUsers, *fakeProviders) {
+	return &fakeUsers{byEmail: map[string]*models.User{}}, &fakeProviders{}
+}
+
+func TestImport_CreatesUserAndLinks(t *testing.T) {
+	users, providers := newFakes()
+	im := New(users, providers, false)
+	created := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	out, err := im.Import(context.Background(), &Record{
+		Email:         " jane@example.com ",
+		PasswordHash:  djangoHash,
+		EmailVerified: true,
+		Name:          "Jane",
+		CreatedAt:     created,
+		Providers:     []ProviderLink{{Type: "GitHub", ProviderUserID: "42"}},
+	})
+	if err != nil || out != Created {
+		t.Fatalf("Import: got %v, %v", out, err)
+	}
+
+	u := users.byEmail["jane@example.com"]
+	if u == nil || u.ID == "" {
+		t.Fatalf("user not saved: %+v", users.byEmail)
+	}
+	if u.PasswordHash == nil || *u.PasswordHash != djangoHash || !u.EmailVerified || *u.Name != "Jane" || !u.CreatedAt.Equal(created) {
+		t.Errorf("user fields: got %+v", u)
+	}
+	if len(providers.links) != 1 {
+		t.Fatalf("links: got %d want 1", len(providers.links))
+	}
+	l := providers.links[0]
+	if l.UserID != u.ID || l.Type != "github" || l.ProviderUserID != "42" || l.AccessToken != ImportedAccessToken {
+		t.Errorf("link: got %+v", l)
+	}
+}
+
+func TestImport_SkipsExistingEmail(t *testing.T) {
+	users, providers := newFakes()
+	users.byEmail["jane@example.com"] = &models.User{ID: "existing", Email: "jane@example.com"}
+
+	out, err := New(users, providers, false).Import(context.Background(), &Record{Email: "jane@example.com", PasswordHash: djangoHash})
+	if err != nil || out != Skipped {
+		t.Errorf("got %v, %v want Skipped", out, err)
+	}
+	if users.byEmail["jane@example.com"].ID != "existing" {
+		t.Error("existing user was overwritten")
+	}
+}
+
+func TestImport_RejectsLinkedProvider(t *testing.T) {
+	users, providers := newFakes()
+	providers.links = append(providers.links, &models.Provider{UserID: "other", Type: "github", ProviderUserID: "42"})
+
+	_, err := New(users, providers, false).Import(context.Background(), &Record{
+		Email:     "jane@example.com",
+		Providers: []ProviderLink{{Type: "github", ProviderUserID: "42"}},
+	})
+	if !errors.Is(err, ErrProviderLinked) {
+		t.Errorf("got %v want ErrProviderLinked", err)
+	}
+	if len(users.byEmail) != 0 {
+		t.Error("user should not be created")
+	}
+}
+
+func TestImport_DryRunWritesNothing(t *testing.T) {
+	users, providers := newFakes()
+	out, err := New(users, providers, true).Import(context.Background(), &Record{Email: "jane@example.com", PasswordHash: djangoHash})
+	if err != nil || out != Created {
+		t.Errorf("got %v, %v want Created", out, err)
+	}
+	if len(users.byEmail) != 0 || len(providers.links) != 0 {
+		t.Error("dry run wrote records")
+	}
+}
+
+func TestImport_InvalidRecords(t *testing.T) {
+	cases := map[string]*Record{
+		"no email":         {PasswordHash: djangoHash},
+		"unsupported hash": {Email: "jane@example.com", PasswordHash: "md5$abc"},
+		"no credentials":   {Email: "jane@example.com"},
+		"incomplete link":  {Email: "jane@example.com", Providers: []ProviderLink{{Type: "github"}}},
+	}
+	for name, rec := range cases {
+		users, providers := newFakes()
+		if _, err := New(users, providers, false).Import(context.Background(), rec); !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("%s: got %v want ErrInvalidRecord", name, err)
+		}
+	}
+}
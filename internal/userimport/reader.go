@@ -0,0 +1,155 @@
+package userimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reader yields records from an export. Next returns io.EOF after the last
+// record. A malformed record returns an error wrapping ErrInvalidRecord, and
+// reading can continue with the next call; any other error is fatal.
+type Reader interface {
+	Next() (*Record, error)
+	// Line is the input line of the record last returned, for error reports.
+	Line() int
+}
+
+// maxJSONLLine bounds a single JSONL record.
+const maxJSONLLine = 1 << 20
+
+// JSONLReader reads one JSON Record per line. Blank lines are skipped.
+type JSONLReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewJSONLReader returns a JSONLReader reading from r.
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJSONLLine)
+	return &JSONLReader{scanner: scanner}
+}
+
+// Next returns the next record.
+func (r *JSONLReader) Next() (*Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+		return &rec, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Line returns the line number of the last record.
+func (r *JSONLReader) Line() int {
+	return r.line
+}
+
+// CSV columns. The header row names the columns present, in any order; only
+// email is required. providers holds "type:provider_user_id" pairs separated
+// by ";", e.g. "github:12345;google:1098".
+const (
+	colEmail         = "email"
+	colPasswordHash  = "password_hash"
+	colEmailVerified = "email_verified"
+	colName          = "name"
+	colAvatarURL     = "avatar_url"
+	colCreatedAt     = "created_at"
+	colProviders     = "providers"
+)
+
+// CSVReader reads records from a CSV export with a header row.
+type CSVReader struct {
+	r       *csv.Reader
+	columns map[string]int
+	line    int
+}
+
+// NewCSVReader returns a CSVReader reading from r. It reads the header row
+// immediately and fails if it has no email column.
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns[colEmail]; !ok {
+		return nil, fmt.Errorf("csv header has no %q column", colEmail)
+	}
+	return &CSVReader{r: cr, columns: columns, line: 1}, nil
+}
+
+// Next returns the next record.
+func (r *CSVReader) Next() (*Record, error) {
+	row, err := r.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		r.line = parseErr.StartLine
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.line, _ = r.r.FieldPos(0)
+
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	rec := &Record{
+		Email:        field(colEmail),
+		PasswordHash: field(colPasswordHash),
+		Name:         field(colName),
+		AvatarURL:    field(colAvatarURL),
+	}
+	if v := field(colEmailVerified); v != "" {
+		if rec.EmailVerified, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("%w: %s %q", ErrInvalidRecord, colEmailVerified, v)
+		}
+	}
+	if v := field(colCreatedAt); v != "" {
+		if rec.CreatedAt, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("%w: %s %q", ErrInvalidRecord, colCreatedAt, v)
+		}
+	}
+	if v := field(colProviders); v != "" {
+		for _, pair := range strings.Split(v, ";") {
+			typ, id, ok := strings.Cut(strings.TrimSpace(pair), ":")
+			if !ok {
+				return nil, fmt.Errorf("%w: %s %q", ErrInvalidRecord, colProviders, pair)
+			}
+			rec.Providers = append(rec.Providers, ProviderLink{Type: typ, ProviderUserID: id})
+		}
+	}
+	return rec, nil
+}
+
+// Line returns the line number of the last record.
+func (r *CSVReader) Line() int {
+	return r.line
+}
@@ -0,0 +1,92 @@
+package userimport
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLReader(t *testing.T) {
+	input := `{"email":"a@example.com","password_hash":"$2a$10$x","email_verified":true,"providers":[{"type":"github","provider_user_id":"42"}]}
+
+not json
+{"email":"b@example.com","created_at":"2020-01-02T03:04:05Z"}
+`
+	r := NewJSONLReader(strings.NewReader(input))
+
+	rec, err := r.Next()
+	if err != nil {
+		t.Fatalf("record 1: %v", err)
+	}
+	if rec.Email != "a@example.com" || !rec.EmailVerified || len(rec.Providers) != 1 || rec.Providers[0].ProviderUserID != "42" {
+		t.Errorf("record 1: got %+v", rec)
+	}
+	if r.Line() != 1 {
+		t.Errorf("record 1 line: got %d", r.Line())
+	}
+
+	if _, err := r.Next(); !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("malformed line: got %v want ErrInvalidRecord", err)
+	}
+	if r.Line() != 3 {
+		t.Errorf("malformed line number: got %d want 3", r.Line())
+	}
+
+	rec, err = r.Next()
+	if err != nil {
+		t.Fatalf("record 2: %v", err)
+	}
+	if !rec.CreatedAt.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("record 2 created_at: got %v", rec.CreatedAt)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("end: got %v want io.EOF", err)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "Email,password_hash,email_verified,name,created_at,providers\n" +
+		"a@example.com,$2a$10$x,true,Ann,2020-01-02T03:04:05Z,github:42;google:7\n" +
+		"b@example.com,,,,,\n" +
+		"c@example.com,,maybe,,,\n"
+	r, err := NewCSVReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("NewCSVReader: %v", err)
+	}
+
+	rec, err := r.Next()
+	if err != nil {
+		t.Fatalf("row 1: %v", err)
+	}
+	if rec.Email != "a@example.com" || rec.PasswordHash != "$2a$10$x" || !rec.EmailVerified || rec.Name != "Ann" {
+		t.Errorf("row 1: got %+v", rec)
+	}
+	if len(rec.Providers) != 2 || rec.Providers[1] != (ProviderLink{Type: "google", ProviderUserID: "7"}) {
+		t.Errorf("row 1 providers: got %+v", rec.Providers)
+	}
+	if r.Line() != 2 {
+		t.Errorf("row 1 line: got %d want 2", r.Line())
+	}
+
+	rec, err = r.Next()
+	if err != nil || rec.Email != "b@example.com" || rec.EmailVerified || len(rec.Providers) != 0 {
+		t.Errorf("row 2: got %+v, %v", rec, err)
+	}
+
+	if _, err := r.Next(); !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("row 3: got %v want ErrInvalidRecord", err)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("end: got %v want io.EOF", err)
+	}
+}
+
+func TestCSVReader_RequiresEmailColumn(t *testing.T) {
+	if _, err := NewCSVReader(strings.NewReader("name,password_hash\n")); err == nil {
+		t.Error("expected error for header without email")
+	}
+}
@@ -0,0 +1,59 @@
+// Package userimport reads user exports from legacy systems and writes them to
+// the users and providers tables. Password hashes are imported as-is in any
+// encoding internal/password verifies, and upgraded on each user's first login.
+package userimport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	intpassword "github.com/zoobzio/sumatra/internal/password"
+)
+
+// Record is one exported user.
+type Record struct {
+	Email         string         `json:"email"`
+	PasswordHash  string         `json:"password_hash,omitempty"`
+	EmailVerified bool           `json:"email_verified"`
+	Name          string         `json:"name,omitempty"`
+	AvatarURL     string         `json:"avatar_url,omitempty"`
+	CreatedAt     time.Time      `json:"created_at,omitempty"`
+	Providers     []ProviderLink `json:"providers,omitempty"`
+}
+
+// ProviderLink is an OAuth identity linked to an exported user.
+type ProviderLink struct {
+	Type           string `json:"type"`
+	ProviderUserID string `json:"provider_user_id"`
+}
+
+// ErrInvalidRecord is returned for records that cannot be imported.
+var ErrInvalidRecord = errors.New("invalid record")
+
+// Validate trims the record's fields and reports whether it can be imported.
+// A record needs a password hash, a provider link, or both, so the user has
+// some way to sign in.
+func (r *Record) Validate() error {
+	r.Email = strings.TrimSpace(r.Email)
+	r.PasswordHash = strings.TrimSpace(r.PasswordHash)
+	if r.Email == "" || !strings.Contains(r.Email, "@") {
+		return fmt.Errorf("%w: email %q", ErrInvalidRecord, r.Email)
+	}
+	if r.PasswordHash != "" && !intpassword.Supported(r.PasswordHash) {
+		return fmt.Errorf("%w: %s: unsupported password hash encoding", ErrInvalidRecord, r.Email)
+	}
+	if r.PasswordHash == "" && len(r.Providers) == 0 {
+		return fmt.Errorf("%w: %s: no password hash or provider link", ErrInvalidRecord, r.Email)
+	}
+	for i := range r.Providers {
+		p := &r.Providers[i]
+		p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+		p.ProviderUserID = strings.TrimSpace(p.ProviderUserID)
+		if p.Type == "" || p.ProviderUserID == "" {
+			return fmt.Errorf("%w: %s: provider link needs type and provider_user_id", ErrInvalidRecord, r.Email)
+		}
+	}
+	return nil
+}
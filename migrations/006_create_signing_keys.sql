@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE signing_keys (
+    id TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    activates_at TIMESTAMPTZ NOT NULL,
+    retires_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_signing_keys_expires_at ON signing_keys(expires_at);
+
+-- +goose Down
+DROP TABLE signing_keys;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Operators of the admin API. There is no endpoint to grant the flag; set it
+-- directly, e.g. UPDATE users SET is_admin = true WHERE email = '...'.
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN is_admin;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Admin roles and their assignment to operators. Operators still need
+-- users.is_admin to reach the admin API; roles decide what they may do there.
+CREATE TABLE roles (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    permissions TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_roles (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id TEXT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, role_id)
+);
+
+CREATE INDEX idx_user_roles_user_id ON user_roles(user_id);
+
+-- Roles are assigned through the admin API by an operator holding
+-- roles:manage; assign the first security engineer directly, e.g.
+-- INSERT INTO user_roles (user_id, role_id) VALUES ('...', 'security').
+INSERT INTO roles (id, name, description, permissions) VALUES
+    ('support', 'Support', 'Views users and sessions, signs users out and clears lockouts.',
+        'users:read sessions:read sessions:revoke lockouts:manage'),
+    ('security', 'Security engineer', 'Everything support can do, plus deleting users, revoking API tokens, managing OAuth clients, reading reports and assigning roles.',
+        'users:read users:delete sessions:read sessions:revoke lockouts:manage tokens:revoke reports:read oauth_clients:read oauth_clients:manage roles:manage');
+
+-- +goose Down
+DROP TABLE user_roles;
+DROP TABLE roles;
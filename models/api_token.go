@@ -0,0 +1,77 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// Scopes an APIToken may be granted. A read token may only make safe (GET,
+// HEAD and OPTIONS) requests; a write token may make any request.
+const (
+	APITokenScopeRead  = "read"
+	APITokenScopeWrite = "write"
+)
+
+// APITokenScopes lists every API token scope.
+var APITokenScopes = []string{APITokenScopeRead, APITokenScopeWrite}
+
+// APIToken is a personal access token a user creates for non-interactive
+// clients such as the CLI and CI. The token is shown once and stored as a
+// keyed hash; Prefix keeps its first characters so users can tell tokens apart.
+type APIToken struct {
+	ID         int64      `json:"id" db:"id" constraints:"primarykey" description:"Auto-increment primary key" example:"1"`
+	UserID     string     `json:"user_id" db:"user_id" constraints:"notnull" references:"users(id)" description:"FK to users.id" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	Name       string     `json:"name" db:"name" constraints:"notnull" description:"User-chosen label for the token" example:"CI deploys"`
+	Prefix     string     `json:"prefix" db:"prefix" constraints:"notnull" description:"First characters of the token" example:"mpat_Yp3bHk2"`
+	TokenHash  string     `json:"-" db:"token_hash" constraints:"notnull,unique" description:"Keyed hash of the token"`
+	Scopes     string     `json:"scopes" db:"scopes" constraints:"notnull" description:"Space-separated scopes" example:"read write"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at" description:"Expiry, null for tokens that do not expire"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at" description:"Time the token last authenticated a request, to the minute"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at" constraints:"notnull" default:"now()" description:"Last update time"`
+}
+
+// ScopeList returns the scopes granted to the token.
+func (t APIToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+// HasScope reports whether the token was granted scope.
+func (t APIToken) HasScope(scope string) bool {
+	return slices.Contains(t.ScopeList(), scope)
+}
+
+// IsExpired reports whether the token has expired at now.
+func (t APIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// Validate validates the APIToken model.
+func (t APIToken) Validate() error {
+	scopes := t.ScopeList()
+	return check.All(
+		check.Str(t.UserID, "user_id").Required().V(),
+		check.Str(t.Name, "name").Required().MaxLen(64).V(),
+		check.Str(t.Prefix, "prefix").Required().V(),
+		check.Str(t.TokenHash, "token_hash").Required().V(),
+		check.NotEmpty(scopes, "scopes"),
+		check.Subset(scopes, APITokenScopes, "scopes"),
+	).Err()
+}
+
+// Clone returns a deep copy of the APIToken.
+func (t APIToken) Clone() APIToken {
+	c := t
+	if t.ExpiresAt != nil {
+		e := *t.ExpiresAt
+		c.ExpiresAt = &e
+	}
+	if t.LastUsedAt != nil {
+		l := *t.LastUsedAt
+		c.LastUsedAt = &l
+	}
+	return c
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func validAPIToken() APIToken {
+	return APIToken{UserID: "u1", Name: "CI", Prefix: "mpat_abcdefg", TokenHash: "hash", Scopes: "read"}
+}
+
+func TestAPIToken_Validate(t *testing.T) {
+	if err := validAPIToken().Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAPIToken_Validate_UnknownScope(t *testing.T) {
+	tok := validAPIToken()
+	tok.Scopes = "read admin"
+	if err := tok.Validate(); err == nil {
+		t.Fatal("expected error for unknown scope, got nil")
+	}
+}
+
+func TestAPIToken_Validate_NoScopes(t *testing.T) {
+	tok := validAPIToken()
+	tok.Scopes = " "
+	if err := tok.Validate(); err == nil {
+		t.Fatal("expected error for empty scopes, got nil")
+	}
+}
+
+func TestAPIToken_IsExpired(t *testing.T) {
+	now := time.Now()
+	tok := validAPIToken()
+	if tok.IsExpired(now) {
+		t.Error("a token without expiry never expires")
+	}
+	past := now.Add(-time.Second)
+	tok.ExpiresAt = &past
+	if !tok.IsExpired(now) {
+		t.Error("expected expired")
+	}
+}
+
+func TestAPIToken_Clone_DeepCopiesTimes(t *testing.T) {
+	now := time.Now()
+	tok := validAPIToken()
+	tok.ExpiresAt, tok.LastUsedAt = &now, &now
+	c := tok.Clone()
+	if c.ExpiresAt == tok.ExpiresAt || c.LastUsedAt == tok.LastUsedAt {
+		t.Error("Clone shares time pointers")
+	}
+}
@@ -8,5 +8,11 @@ func RegisterBoundaries(k sum.Key) error {
 	if _, err := sum.NewBoundary[Provider](k); err != nil {
 		return err
 	}
+	if _, err := sum.NewBoundary[TOTPSecret](k); err != nil {
+		return err
+	}
+	if _, err := sum.NewBoundary[SigningKey](k); err != nil {
+		return err
+	}
 	return nil
 }
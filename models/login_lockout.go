@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// LoginLockout tracks failed password attempts for a user and any resulting
+// temporary lockout. Records are stored in Redis and expire once the user has
+// gone long enough without a failure.
+type LoginLockout struct {
+	// UserID is the subject the failures count against: the user, or for an
+	// email with no account, a key derived from the address.
+	UserID string `json:"user_id"`
+	// Failures counts consecutive failed attempts since the last lockout or success.
+	Failures int `json:"failures"`
+	// Lockouts counts lockouts since the record was created; each one doubles the next lockout.
+	Lockouts      int        `json:"lockouts"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
+
+// IsLocked reports whether the user is locked out at t.
+func (l LoginLockout) IsLocked(t time.Time) bool {
+	return l.LockedUntil != nil && t.Before(*l.LockedUntil)
+}
+
+// Validate validates the LoginLockout model.
+func (l LoginLockout) Validate() error {
+	return check.All(
+		check.Str(l.UserID, "user_id").Required().V(),
+		check.Int(l.Failures, "failures").NonNegative().V(),
+		check.Int(l.Lockouts, "lockouts").NonNegative().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the LoginLockout.
+func (l LoginLockout) Clone() LoginLockout {
+	c := l
+	if l.LockedUntil != nil {
+		t := *l.LockedUntil
+		c.LockedUntil = &t
+	}
+	return c
+}
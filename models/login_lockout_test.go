@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginLockout_Validate(t *testing.T) {
+	l := LoginLockout{UserID: "user_id", Failures: 3}
+	if err := l.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestLoginLockout_Validate_MissingUserID(t *testing.T) {
+	l := LoginLockout{Failures: 1}
+	if err := l.Validate(); err == nil {
+		t.Fatal("expected error for missing UserID, got nil")
+	}
+}
+
+func TestLoginLockout_Validate_NegativeFailures(t *testing.T) {
+	l := LoginLockout{UserID: "user_id", Failures: -1}
+	if err := l.Validate(); err == nil {
+		t.Fatal("expected error for negative Failures, got nil")
+	}
+}
+
+func TestLoginLockout_IsLocked(t *testing.T) {
+	now := time.Now()
+	l := LoginLockout{UserID: "user_id"}
+	if l.IsLocked(now) {
+		t.Error("expected record without LockedUntil not to be locked")
+	}
+
+	until := now.Add(time.Minute)
+	l.LockedUntil = &until
+	if !l.IsLocked(now) {
+		t.Error("expected record to be locked before LockedUntil")
+	}
+	if l.IsLocked(until) {
+		t.Error("expected lock to lapse at LockedUntil")
+	}
+}
+
+func TestLoginLockout_Clone(t *testing.T) {
+	until := time.Now()
+	l := LoginLockout{UserID: "user_id", LockedUntil: &until}
+	c := l.Clone()
+	*c.LockedUntil = until.Add(time.Hour)
+	if !l.LockedUntil.Equal(until) {
+		t.Error("Clone should deep-copy LockedUntil")
+	}
+}
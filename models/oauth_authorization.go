@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// OAuthAuthorization is an authorization request in flight. It is stored in
+// Redis under its ID while it awaits the user's consent, then under the hash
+// of Code until the client redeems the code.
+type OAuthAuthorization struct {
+	ID   string `json:"id"`
+	Code string `json:"code,omitempty"`
+	// ClientID, RedirectURI, Scope, State and Nonce are as requested by the
+	// client; the code is redeemed only with the same client and redirect URI.
+	ClientID    string `json:"client_id"`
+	RedirectURI string `json:"redirect_uri"`
+	Scope       string `json:"scope"`
+	State       string `json:"state,omitempty"`
+	Nonce       string `json:"nonce,omitempty"`
+	// CodeChallenge is the S256 PKCE challenge the code verifier must match.
+	CodeChallenge string `json:"code_challenge"`
+	UserID        string `json:"user_id"`
+	// SessionID is the ID of the session the user authorized from.
+	SessionID string `json:"session_id"`
+	// AuthTime is when that session signed in, the ID token's auth_time.
+	AuthTime  time.Time `json:"auth_time"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate validates the OAuthAuthorization model.
+func (a OAuthAuthorization) Validate() error {
+	return check.All(
+		check.Str(a.ClientID, "client_id").Required().V(),
+		check.Str(a.RedirectURI, "redirect_uri").Required().V(),
+		check.Str(a.CodeChallenge, "code_challenge").Required().V(),
+		check.Str(a.UserID, "user_id").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the OAuthAuthorization.
+func (a OAuthAuthorization) Clone() OAuthAuthorization {
+	return a
+}
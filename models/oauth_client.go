@@ -0,0 +1,101 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// Grant types an OAuthClient may be allowed to use at the token endpoint.
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// GrantTypes lists every grant type the authorization server supports.
+var GrantTypes = []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken, GrantTypeClientCredentials}
+
+// OAuthClient is an application registered to sign users in through the
+// authorization server. Redirect URIs, grant types and scopes are stored
+// space-separated. Public clients, such as single-page and native apps, have
+// no secret and must use PKCE; confidential clients authenticate with a
+// secret stored as a keyed hash.
+type OAuthClient struct {
+	ID           string    `json:"id" db:"id" constraints:"primarykey" description:"Client ID" example:"mcl_Yp3bHk2xQ0mC8vR1"`
+	Name         string    `json:"name" db:"name" constraints:"notnull" description:"Name shown on the consent screen" example:"Wiki"`
+	Public       bool      `json:"public" db:"public" constraints:"notnull" default:"false" description:"Whether the client has no secret"`
+	SecretHash   string    `json:"-" db:"secret_hash" description:"Keyed hash of the client secret, empty for public clients"`
+	RedirectURIs string    `json:"redirect_uris" db:"redirect_uris" description:"Space-separated redirect URIs, matched exactly" example:"https://wiki.example.com/callback"`
+	GrantTypes   string    `json:"grant_types" db:"grant_types" constraints:"notnull" description:"Space-separated grant types the client may use" example:"authorization_code refresh_token"`
+	Scopes       string    `json:"scopes" db:"scopes" constraints:"notnull" description:"Space-separated scopes the client may request" example:"openid profile email offline_access"`
+	SkipConsent  bool      `json:"skip_consent" db:"skip_consent" constraints:"notnull" default:"false" description:"Whether users are signed in without a consent screen, for first-party apps"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" constraints:"notnull" default:"now()" description:"Last update time"`
+}
+
+// RedirectURIList returns the registered redirect URIs.
+func (c OAuthClient) RedirectURIList() []string {
+	return strings.Fields(c.RedirectURIs)
+}
+
+// GrantTypeList returns the grant types the client may use.
+func (c OAuthClient) GrantTypeList() []string {
+	return strings.Fields(c.GrantTypes)
+}
+
+// ScopeList returns the scopes the client may request.
+func (c OAuthClient) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// AllowsGrant reports whether the client may use grantType.
+func (c OAuthClient) AllowsGrant(grantType string) bool {
+	return slices.Contains(c.GrantTypeList(), grantType)
+}
+
+// AllowsRedirectURI reports whether uri exactly matches a registered redirect URI.
+func (c OAuthClient) AllowsRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIList(), uri)
+}
+
+// AllowsScopes reports whether the client may request every scope in scopes.
+func (c OAuthClient) AllowsScopes(scopes []string) bool {
+	allowed := c.ScopeList()
+	for _, s := range scopes {
+		if !slices.Contains(allowed, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate validates the OAuthClient model.
+func (c OAuthClient) Validate() error {
+	grants := c.GrantTypeList()
+	checks := []*check.Validation{
+		check.Str(c.ID, "id").Required().V(),
+		check.Str(c.Name, "name").Required().MaxLen(64).V(),
+		check.NotEmpty(grants, "grant_types"),
+		check.Subset(grants, GrantTypes, "grant_types"),
+		check.NotEmpty(c.ScopeList(), "scopes"),
+		check.Str(c.SecretHash, "secret_hash").When(!c.Public, func(b *check.StrBuilder) { b.Required() }).V(),
+	}
+	if c.Public {
+		checks = append(checks, check.SliceNotContains(grants, GrantTypeClientCredentials, "grant_types"))
+	}
+	if slices.Contains(grants, GrantTypeAuthorizationCode) {
+		checks = append(checks, check.NotEmpty(c.RedirectURIList(), "redirect_uris"))
+	}
+	for _, uri := range c.RedirectURIList() {
+		checks = append(checks, check.Str(uri, "redirect_uris").URL().NotContains("#").V())
+	}
+	return check.All(checks...).Err()
+}
+
+// Clone returns a deep copy of the OAuthClient.
+func (c OAuthClient) Clone() OAuthClient {
+	return c
+}
@@ -0,0 +1,82 @@
+package models
+
+import "testing"
+
+func validOAuthClient() OAuthClient {
+	return OAuthClient{
+		ID:           "mcl_client",
+		Name:         "Wiki",
+		SecretHash:   "hash",
+		RedirectURIs: "https://wiki.example.com/callback http://localhost:3000/callback",
+		GrantTypes:   "authorization_code refresh_token",
+		Scopes:       "openid profile email offline_access",
+	}
+}
+
+func TestOAuthClient_Validate_Success(t *testing.T) {
+	if err := validOAuthClient().Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestOAuthClient_Validate_UnknownGrantType(t *testing.T) {
+	c := validOAuthClient()
+	c.GrantTypes = "authorization_code password"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for password grant, got nil")
+	}
+}
+
+func TestOAuthClient_Validate_ConfidentialNeedsSecret(t *testing.T) {
+	c := validOAuthClient()
+	c.SecretHash = ""
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for confidential client without secret, got nil")
+	}
+	c.Public = true
+	if err := c.Validate(); err != nil {
+		t.Fatalf("public client without secret: %v", err)
+	}
+}
+
+func TestOAuthClient_Validate_PublicClientCredentials(t *testing.T) {
+	c := validOAuthClient()
+	c.Public, c.SecretHash = true, ""
+	c.GrantTypes = "client_credentials"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for public client_credentials client, got nil")
+	}
+}
+
+func TestOAuthClient_Validate_CodeNeedsRedirectURI(t *testing.T) {
+	c := validOAuthClient()
+	c.RedirectURIs = ""
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for missing redirect URIs, got nil")
+	}
+	c.GrantTypes = "client_credentials"
+	if err := c.Validate(); err != nil {
+		t.Fatalf("client_credentials client without redirect URIs: %v", err)
+	}
+}
+
+func TestOAuthClient_Validate_RedirectURIFragment(t *testing.T) {
+	c := validOAuthClient()
+	c.RedirectURIs = "https://wiki.example.com/callback#frag"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for redirect URI with fragment, got nil")
+	}
+}
+
+func TestOAuthClient_Allows(t *testing.T) {
+	c := validOAuthClient()
+	if !c.AllowsGrant(GrantTypeRefreshToken) || c.AllowsGrant(GrantTypeClientCredentials) {
+		t.Error("AllowsGrant")
+	}
+	if !c.AllowsRedirectURI("http://localhost:3000/callback") || c.AllowsRedirectURI("http://localhost:3000/callback/") {
+		t.Error("AllowsRedirectURI must match exactly")
+	}
+	if !c.AllowsScopes([]string{"openid", "email"}) || c.AllowsScopes([]string{"openid", "admin"}) {
+		t.Error("AllowsScopes")
+	}
+}
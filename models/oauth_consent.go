@@ -0,0 +1,55 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// OAuthConsent records the scopes a user has granted an OAuthClient, so the
+// consent screen is shown again only when the client asks for more.
+type OAuthConsent struct {
+	ID        int64     `json:"id" db:"id" constraints:"primarykey" description:"Auto-increment primary key" example:"1"`
+	UserID    string    `json:"user_id" db:"user_id" constraints:"notnull" references:"users(id)" description:"FK to users.id" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	ClientID  string    `json:"client_id" db:"client_id" constraints:"notnull" references:"oauth_clients(id)" description:"FK to oauth_clients.id" example:"mcl_Yp3bHk2xQ0mC8vR1"`
+	Scopes    string    `json:"scopes" db:"scopes" constraints:"notnull" description:"Space-separated granted scopes" example:"openid profile email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" constraints:"notnull" default:"now()" description:"Last update time"`
+}
+
+// Covers reports whether every scope in scopes has been granted.
+func (c OAuthConsent) Covers(scopes []string) bool {
+	granted := strings.Fields(c.Scopes)
+	for _, s := range scopes {
+		if !slices.Contains(granted, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Grant adds scopes to those granted.
+func (c *OAuthConsent) Grant(scopes []string) {
+	granted := strings.Fields(c.Scopes)
+	for _, s := range scopes {
+		if !slices.Contains(granted, s) {
+			granted = append(granted, s)
+		}
+	}
+	c.Scopes = strings.Join(granted, " ")
+}
+
+// Validate validates the OAuthConsent model.
+func (c OAuthConsent) Validate() error {
+	return check.All(
+		check.Str(c.UserID, "user_id").Required().V(),
+		check.Str(c.ClientID, "client_id").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the OAuthConsent.
+func (c OAuthConsent) Clone() OAuthConsent {
+	return c
+}
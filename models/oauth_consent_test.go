@@ -0,0 +1,27 @@
+package models
+
+import "testing"
+
+func TestOAuthConsent_Covers(t *testing.T) {
+	c := OAuthConsent{Scopes: "openid email"}
+	if !c.Covers([]string{"email"}) || !c.Covers(nil) {
+		t.Error("expected granted scopes to be covered")
+	}
+	if c.Covers([]string{"openid", "profile"}) {
+		t.Error("profile was not granted")
+	}
+}
+
+func TestOAuthConsent_Grant_Merges(t *testing.T) {
+	c := OAuthConsent{Scopes: "openid email"}
+	c.Grant([]string{"email", "profile"})
+	if c.Scopes != "openid email profile" {
+		t.Errorf("Scopes: got %q", c.Scopes)
+	}
+}
+
+func TestOAuthConsent_Validate_MissingClientID(t *testing.T) {
+	if err := (OAuthConsent{UserID: "u1"}).Validate(); err == nil {
+		t.Fatal("expected error for missing ClientID, got nil")
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// OAuthRefreshToken is a refresh token issued to an OAuthClient, stored in
+// Redis under the keyed hash of Token. Each use replaces it with a new one.
+type OAuthRefreshToken struct {
+	Token    string `json:"token,omitempty"`
+	ClientID string `json:"client_id"`
+	UserID   string `json:"user_id"`
+	Scope    string `json:"scope"`
+	// FamilyID is shared by a token and every token rotated from it. Using a
+	// token that has already been rotated revokes the whole family.
+	FamilyID string `json:"family_id,omitempty"`
+	// AuthTime is when the user signed in to authorize the client, carried
+	// into ID tokens issued on refresh.
+	AuthTime  time.Time `json:"auth_time"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate validates the OAuthRefreshToken model.
+func (t OAuthRefreshToken) Validate() error {
+	return check.All(
+		check.Str(t.Token, "token").Required().V(),
+		check.Str(t.ClientID, "client_id").Required().V(),
+		check.Str(t.UserID, "user_id").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the OAuthRefreshToken.
+func (t OAuthRefreshToken) Clone() OAuthRefreshToken {
+	return t
+}
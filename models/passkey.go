@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// Passkey is a WebAuthn credential registered to a user account.
+// Credential IDs and public keys are stored base64url-encoded; neither is secret.
+type Passkey struct {
+	ID              int64      `json:"id" db:"id" constraints:"primarykey" description:"Auto-increment primary key" example:"1"`
+	UserID          string     `json:"user_id" db:"user_id" constraints:"notnull" references:"users(id)" description:"FK to users.id" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	Name            string     `json:"name" db:"name" constraints:"notnull" description:"User-chosen label for the passkey" example:"MacBook Touch ID"`
+	CredentialID    string     `json:"credential_id" db:"credential_id" constraints:"notnull,unique" description:"Base64url-encoded WebAuthn credential ID" example:"AAECAwQFBgcICQ"`
+	PublicKey       string     `json:"-" db:"public_key" constraints:"notnull" description:"Base64url-encoded COSE public key"`
+	AttestationType string     `json:"attestation_type" db:"attestation_type" description:"Attestation format reported at registration" example:"none"`
+	Transports      string     `json:"transports" db:"transports" description:"Comma-separated authenticator transports" example:"internal,hybrid"`
+	AAGUID          string     `json:"aaguid" db:"aaguid" description:"Base64url-encoded authenticator model identifier"`
+	SignCount       int64      `json:"sign_count" db:"sign_count" constraints:"notnull" default:"0" description:"Last observed signature counter"`
+	BackupEligible  bool       `json:"backup_eligible" db:"backup_eligible" constraints:"notnull" default:"false" description:"Whether the credential can be synced between devices"`
+	BackupState     bool       `json:"backup_state" db:"backup_state" constraints:"notnull" default:"false" description:"Whether the credential is currently synced"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty" db:"last_used_at" description:"Time of the last successful sign-in"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at" constraints:"notnull" default:"now()" description:"Last update time"`
+}
+
+// Validate validates the Passkey model.
+func (p Passkey) Validate() error {
+	return check.All(
+		check.Str(p.UserID, "user_id").Required().V(),
+		check.Str(p.Name, "name").Required().MaxLen(64).V(),
+		check.Str(p.CredentialID, "credential_id").Required().V(),
+		check.Str(p.PublicKey, "public_key").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the Passkey.
+func (p Passkey) Clone() Passkey {
+	c := p
+	if p.LastUsedAt != nil {
+		t := *p.LastUsedAt
+		c.LastUsedAt = &t
+	}
+	return c
+}
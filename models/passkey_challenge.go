@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// ChallengeType identifies the WebAuthn ceremony a challenge was issued for.
+type ChallengeType string
+
+const (
+	// ChallengeTypeRegistration is issued when an authenticated user adds a passkey.
+	ChallengeTypeRegistration ChallengeType = "registration"
+	// ChallengeTypeLogin is issued for an unauthenticated passkey sign-in.
+	ChallengeTypeLogin ChallengeType = "login"
+)
+
+// PasskeyChallenge holds the server-side state of an in-flight WebAuthn ceremony.
+// Challenges are stored in Redis with a short TTL and consumed on first use.
+type PasskeyChallenge struct {
+	ID        string        `json:"id"`
+	UserID    string        `json:"user_id,omitempty"`
+	Type      ChallengeType `json:"type"`
+	Data      string        `json:"data"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// IsExpired reports whether the challenge has passed its expiry time.
+func (c PasskeyChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// Validate validates the PasskeyChallenge model.
+func (c PasskeyChallenge) Validate() error {
+	return check.All(
+		check.Str(c.ID, "id").Required().V(),
+		check.Str(string(c.Type), "type").Required().OneOf([]string{
+			string(ChallengeTypeRegistration),
+			string(ChallengeTypeLogin),
+		}).V(),
+		check.Str(c.Data, "data").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the PasskeyChallenge.
+func (c PasskeyChallenge) Clone() PasskeyChallenge {
+	return c
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPasskeyChallenge_Validate_AllTypes(t *testing.T) {
+	types := []ChallengeType{ChallengeTypeRegistration, ChallengeTypeLogin}
+	for _, ct := range types {
+		c := PasskeyChallenge{
+			ID:   "challenge_id",
+			Type: ct,
+			Data: "{}",
+		}
+		if err := c.Validate(); err != nil {
+			t.Errorf("expected no error for type %q, got: %v", ct, err)
+		}
+	}
+}
+
+func TestPasskeyChallenge_Validate_InvalidType(t *testing.T) {
+	c := PasskeyChallenge{ID: "challenge_id", Type: "assertion", Data: "{}"}
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for unknown challenge type, got nil")
+	}
+}
+
+func TestPasskeyChallenge_Validate_MissingData(t *testing.T) {
+	c := PasskeyChallenge{ID: "challenge_id", Type: ChallengeTypeLogin}
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for missing Data, got nil")
+	}
+}
+
+func TestPasskeyChallenge_IsExpired(t *testing.T) {
+	c := PasskeyChallenge{ExpiresAt: time.Now().Add(time.Minute)}
+	if c.IsExpired() {
+		t.Error("expected future challenge not to be expired")
+	}
+	c.ExpiresAt = time.Now().Add(-time.Minute)
+	if !c.IsExpired() {
+		t.Error("expected past challenge to be expired")
+	}
+}
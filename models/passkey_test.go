@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validPasskey() Passkey {
+	return Passkey{
+		UserID:       "01942d3a-1234-7abc-8def-0123456789ab",
+		Name:         "MacBook Touch ID",
+		CredentialID: "AAECAwQFBgcICQ",
+		PublicKey:    "pQECAyYgASFYIA",
+	}
+}
+
+func TestPasskey_Validate_Success(t *testing.T) {
+	if err := validPasskey().Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestPasskey_Validate_MissingUserID(t *testing.T) {
+	p := validPasskey()
+	p.UserID = ""
+	if err := p.Validate(); err == nil {
+		t.Fatal("expected error for missing UserID, got nil")
+	}
+}
+
+func TestPasskey_Validate_MissingCredentialID(t *testing.T) {
+	p := validPasskey()
+	p.CredentialID = ""
+	if err := p.Validate(); err == nil {
+		t.Fatal("expected error for missing CredentialID, got nil")
+	}
+}
+
+func TestPasskey_Validate_MissingPublicKey(t *testing.T) {
+	p := validPasskey()
+	p.PublicKey = ""
+	if err := p.Validate(); err == nil {
+		t.Fatal("expected error for missing PublicKey, got nil")
+	}
+}
+
+func TestPasskey_Validate_NameTooLong(t *testing.T) {
+	p := validPasskey()
+	p.Name = strings.Repeat("a", 65)
+	if err := p.Validate(); err == nil {
+		t.Fatal("expected error for overlong Name, got nil")
+	}
+}
+
+func TestPasskey_Clone_DeepCopiesLastUsedAt(t *testing.T) {
+	now := time.Now()
+	orig := validPasskey()
+	orig.LastUsedAt = &now
+	c := orig.Clone()
+
+	*c.LastUsedAt = now.Add(time.Hour)
+	if !orig.LastUsedAt.Equal(now) {
+		t.Error("mutating clone's LastUsedAt should not affect original")
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// PendingLink is a provider login held because its verified email belongs to
+// an existing account. It waits in Redis, for the lifetime of the OAuth state
+// that references it, until that account signs in and confirms the link, so
+// the provider access token never travels to the browser.
+type PendingLink struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	Provider       string    `json:"provider"`
+	ProviderUserID string    `json:"provider_user_id"`
+	AccessToken    string    `json:"access_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// IsExpired reports whether the pending link has passed its expiry time.
+func (l PendingLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
+
+// Validate validates the PendingLink model.
+func (l PendingLink) Validate() error {
+	return check.All(
+		check.Str(l.ID, "id").Required().V(),
+		check.Str(l.UserID, "user_id").Required().V(),
+		check.Str(l.Provider, "provider").Required().V(),
+		check.Str(l.ProviderUserID, "provider_user_id").Required().V(),
+		check.Str(l.AccessToken, "access_token").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the PendingLink.
+func (l PendingLink) Clone() PendingLink {
+	return l
+}
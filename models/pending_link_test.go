@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPendingLink_Validate(t *testing.T) {
+	valid := PendingLink{
+		ID:             "link_id",
+		UserID:         "user_id",
+		Provider:       "github",
+		ProviderUserID: "12345",
+		AccessToken:    "gho_token",
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	missing := map[string]func(*PendingLink){
+		"id":               func(l *PendingLink) { l.ID = "" },
+		"user_id":          func(l *PendingLink) { l.UserID = "" },
+		"provider":         func(l *PendingLink) { l.Provider = "" },
+		"provider_user_id": func(l *PendingLink) { l.ProviderUserID = "" },
+		"access_token":     func(l *PendingLink) { l.AccessToken = "" },
+	}
+	for field, clear := range missing {
+		l := valid
+		clear(&l)
+		if err := l.Validate(); err == nil {
+			t.Errorf("expected error for missing %s, got nil", field)
+		}
+	}
+}
+
+func TestPendingLink_IsExpired(t *testing.T) {
+	l := PendingLink{ExpiresAt: time.Now().Add(time.Minute)}
+	if l.IsExpired() {
+		t.Error("expected future link not to be expired")
+	}
+	l.ExpiresAt = time.Now().Add(-time.Minute)
+	if !l.IsExpired() {
+		t.Error("expected past link to be expired")
+	}
+}
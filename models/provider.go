@@ -8,7 +8,9 @@ import (
 	"github.com/zoobzio/sum"
 )
 
-// ProviderType represents the type of OAuth provider.
+// ProviderType identifies the OAuth provider a link belongs to.
+// It matches the provider's name in the internal/oauth registry, so new
+// providers do not need a constant here.
 type ProviderType string
 
 const (
@@ -55,7 +57,7 @@ func (p *Provider) AfterLoad(ctx context.Context) error {
 func (p Provider) Validate() error {
 	return check.All(
 		check.Str(p.UserID, "user_id").Required().V(),
-		check.Str(string(p.Type), "type").Required().OneOf([]string{"github", "google"}).V(),
+		check.Str(string(p.Type), "type").Required().MaxLen(32).Slug().V(),
 		check.Str(p.ProviderUserID, "provider_user_id").Required().V(),
 		check.Str(p.AccessToken, "access_token").Required().V(),
 	).Err()
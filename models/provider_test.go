@@ -41,12 +41,24 @@ func TestProvider_Validate_MissingType(t *testing.T) {
 func TestProvider_Validate_InvalidType(t *testing.T) {
 	p := Provider{
 		UserID:         "01942d3a-1234-7abc-8def-0123456789ab",
-		Type:           ProviderType("gitlab"),
+		Type:           ProviderType("Git Lab"),
 		ProviderUserID: "583231",
 		AccessToken:    "gho_test_access_token",
 	}
 	if err := p.Validate(); err == nil {
-		t.Fatal("expected error for unsupported provider type, got nil")
+		t.Fatal("expected error for malformed provider type, got nil")
+	}
+}
+
+func TestProvider_Validate_RegisteredType(t *testing.T) {
+	p := Provider{
+		UserID:         "01942d3a-1234-7abc-8def-0123456789ab",
+		Type:           ProviderType("gitlab"),
+		ProviderUserID: "583231",
+		AccessToken:    "glpat_test_access_token",
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("expected provider types outside the built-in constants to validate, got: %v", err)
 	}
 }
 
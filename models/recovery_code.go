@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// RecoveryCode is a single-use backup code that can stand in for a TOTP code.
+// Only the argon2id hash is stored; the plaintext is shown to the user once.
+type RecoveryCode struct {
+	ID        int64      `json:"id" db:"id" constraints:"primarykey" description:"Auto-increment primary key" example:"1"`
+	UserID    string     `json:"user_id" db:"user_id" constraints:"notnull" references:"users(id)" description:"FK to users.id" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	CodeHash  string     `json:"-" db:"code_hash" constraints:"notnull" description:"argon2id hash of the recovery code"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at" description:"Time the code was consumed, null while unused"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+}
+
+// IsUsed reports whether the code has already been consumed.
+func (r RecoveryCode) IsUsed() bool {
+	return r.UsedAt != nil
+}
+
+// Validate validates the RecoveryCode model.
+func (r RecoveryCode) Validate() error {
+	return check.All(
+		check.Str(r.UserID, "user_id").Required().V(),
+		check.Str(r.CodeHash, "code_hash").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the RecoveryCode.
+func (r RecoveryCode) Clone() RecoveryCode {
+	c := r
+	if r.UsedAt != nil {
+		t := *r.UsedAt
+		c.UsedAt = &t
+	}
+	return c
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecoveryCode_Validate_Success(t *testing.T) {
+	r := RecoveryCode{
+		UserID:   "01942d3a-1234-7abc-8def-0123456789ab",
+		CodeHash: "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA",
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestRecoveryCode_Validate_MissingUserID(t *testing.T) {
+	r := RecoveryCode{CodeHash: "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA"}
+	if err := r.Validate(); err == nil {
+		t.Fatal("expected error for missing UserID, got nil")
+	}
+}
+
+func TestRecoveryCode_Validate_MissingCodeHash(t *testing.T) {
+	r := RecoveryCode{UserID: "01942d3a-1234-7abc-8def-0123456789ab"}
+	if err := r.Validate(); err == nil {
+		t.Fatal("expected error for missing CodeHash, got nil")
+	}
+}
+
+func TestRecoveryCode_IsUsed(t *testing.T) {
+	r := RecoveryCode{}
+	if r.IsUsed() {
+		t.Error("expected new code to be unused")
+	}
+	now := time.Now()
+	r.UsedAt = &now
+	if !r.IsUsed() {
+		t.Error("expected code with UsedAt to be used")
+	}
+}
+
+func TestRecoveryCode_Clone_DeepCopiesUsedAt(t *testing.T) {
+	now := time.Now()
+	orig := RecoveryCode{UserID: "uid", CodeHash: "h", UsedAt: &now}
+	c := orig.Clone()
+
+	*c.UsedAt = now.Add(time.Hour)
+	if !orig.UsedAt.Equal(now) {
+		t.Error("mutating clone's UsedAt should not affect original")
+	}
+}
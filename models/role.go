@@ -0,0 +1,93 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/check"
+)
+
+// Permissions an admin Role may grant. Each names an admin API operation.
+const (
+	PermissionUsersRead          = "users:read"
+	PermissionUsersDelete        = "users:delete"
+	PermissionSessionsRead       = "sessions:read"
+	PermissionSessionsRevoke     = "sessions:revoke"
+	PermissionRolesManage        = "roles:manage"
+	PermissionTokensRevoke       = "tokens:revoke"
+	PermissionLockoutsManage     = "lockouts:manage"
+	PermissionReportsRead        = "reports:read"
+	PermissionOAuthClientsRead   = "oauth_clients:read"
+	PermissionOAuthClientsManage = "oauth_clients:manage"
+)
+
+// Permissions lists every admin permission.
+var Permissions = []string{
+	PermissionUsersRead,
+	PermissionUsersDelete,
+	PermissionSessionsRead,
+	PermissionSessionsRevoke,
+	PermissionRolesManage,
+	PermissionTokensRevoke,
+	PermissionLockoutsManage,
+	PermissionReportsRead,
+	PermissionOAuthClientsRead,
+	PermissionOAuthClientsManage,
+}
+
+// Role is a named set of admin permissions assigned to operators. Roles are
+// seeded by migrations; permissions are stored space-separated.
+type Role struct {
+	ID          string    `json:"id" db:"id" constraints:"primarykey" description:"Role slug" example:"support"`
+	Name        string    `json:"name" db:"name" constraints:"notnull" description:"Display name" example:"Support"`
+	Description string    `json:"description" db:"description" constraints:"notnull" description:"What the role is for"`
+	Permissions string    `json:"permissions" db:"permissions" constraints:"notnull" description:"Space-separated permissions" example:"users:read sessions:read sessions:revoke"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" constraints:"notnull" default:"now()" description:"Last update time"`
+}
+
+// PermissionList returns the permissions the role grants.
+func (r Role) PermissionList() []string {
+	return strings.Fields(r.Permissions)
+}
+
+// HasPermission reports whether the role grants permission.
+func (r Role) HasPermission(permission string) bool {
+	return slices.Contains(r.PermissionList(), permission)
+}
+
+// Validate validates the Role model.
+func (r Role) Validate() error {
+	return check.All(
+		check.Str(r.ID, "id").Required().MaxLen(64).NotContains(" ").V(),
+		check.Str(r.Name, "name").Required().MaxLen(64).V(),
+		check.Subset(r.PermissionList(), Permissions, "permissions"),
+	).Err()
+}
+
+// Clone returns a deep copy of the Role.
+func (r Role) Clone() Role {
+	return r
+}
+
+// UserRole assigns a Role to a user.
+type UserRole struct {
+	ID        int64     `json:"id" db:"id" constraints:"primarykey" description:"Auto-increment primary key" example:"1"`
+	UserID    string    `json:"user_id" db:"user_id" constraints:"notnull" references:"users(id)" description:"FK to users.id" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	RoleID    string    `json:"role_id" db:"role_id" constraints:"notnull" references:"roles(id)" description:"FK to roles.id" example:"support"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Time the role was assigned"`
+}
+
+// Validate validates the UserRole model.
+func (u UserRole) Validate() error {
+	return check.All(
+		check.Str(u.UserID, "user_id").Required().V(),
+		check.Str(u.RoleID, "role_id").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the UserRole.
+func (u UserRole) Clone() UserRole {
+	return u
+}
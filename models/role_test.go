@@ -0,0 +1,56 @@
+package models
+
+import "testing"
+
+func validRole() Role {
+	return Role{ID: "support", Name: "Support", Permissions: "users:read sessions:read sessions:revoke"}
+}
+
+func TestRole_Validate(t *testing.T) {
+	if err := validRole().Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRole_Validate_UnknownPermission(t *testing.T) {
+	r := validRole()
+	r.Permissions = "users:read users:impersonate"
+	if err := r.Validate(); err == nil {
+		t.Fatal("expected error for unknown permission, got nil")
+	}
+}
+
+func TestRole_Validate_IDWithSpace(t *testing.T) {
+	r := validRole()
+	r.ID = "support staff"
+	if err := r.Validate(); err == nil {
+		t.Fatal("expected error for id with a space, got nil")
+	}
+}
+
+func TestRole_Validate_NoPermissions(t *testing.T) {
+	r := validRole()
+	r.Permissions = ""
+	if err := r.Validate(); err != nil {
+		t.Fatalf("a role without permissions should be valid: %v", err)
+	}
+}
+
+func TestRole_HasPermission(t *testing.T) {
+	r := validRole()
+	if !r.HasPermission(PermissionSessionsRevoke) {
+		t.Error("expected sessions:revoke")
+	}
+	if r.HasPermission(PermissionUsersDelete) {
+		t.Error("unexpected users:delete")
+	}
+}
+
+func TestUserRole_Validate(t *testing.T) {
+	if err := (UserRole{UserID: "u1", RoleID: "support"}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := (UserRole{UserID: "u1"}).Validate(); err == nil {
+		t.Fatal("expected error without role_id, got nil")
+	}
+}
@@ -6,12 +6,64 @@ import (
 	"github.com/zoobzio/check"
 )
 
+// LoginMethod records how a session was established. Provider logins use the
+// provider's name (e.g. "github", "google") as the method.
+type LoginMethod string
+
+const (
+	// LoginMethodPassword is an email and password login.
+	LoginMethodPassword LoginMethod = "password"
+	// LoginMethodMFA is a password login completed with a TOTP or recovery code.
+	LoginMethodMFA LoginMethod = "mfa"
+	// LoginMethodMagicLink is a sign-in through an emailed magic link.
+	LoginMethodMagicLink LoginMethod = "magic_link"
+	// LoginMethodEmailVerify is the sign-in that follows verifying an email address.
+	LoginMethodEmailVerify LoginMethod = "email_verify"
+	// LoginMethodPasskey is a WebAuthn passkey login.
+	LoginMethodPasskey LoginMethod = "passkey"
+)
+
+// Device classes derived from a session's user agent.
+const (
+	DeviceClassDesktop = "desktop"
+	DeviceClassMobile  = "mobile"
+	DeviceClassTablet  = "tablet"
+	DeviceClassBot     = "bot"
+	DeviceClassUnknown = "unknown"
+)
+
 // Session represents an authenticated user session stored in Redis.
 type Session struct {
-	Token     string    `json:"token"`
+	// Token is the bearer token, present only on a newly created session; it
+	// is never persisted.
+	Token string `json:"token,omitempty"`
+	// TokenHash is the keyed hash of Token that the session is stored under.
+	TokenHash string    `json:"token_hash,omitempty"`
 	UserID    string    `json:"user_id"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
+	// LastSeenAt is refreshed on authenticated requests, at most once per
+	// configured interval to bound writes.
+	LastSeenAt time.Time `json:"last_seen_at,omitempty"`
+	// Method is how the user signed in.
+	Method LoginMethod `json:"method,omitempty"`
+	// IP is the client address the session was created from.
+	IP string `json:"ip,omitempty"`
+	// UserAgent is the raw User-Agent header at login; Browser, OS and
+	// DeviceClass are parsed from it.
+	UserAgent   string `json:"user_agent,omitempty"`
+	Browser     string `json:"browser,omitempty"`
+	OS          string `json:"os,omitempty"`
+	DeviceClass string `json:"device_class,omitempty"`
+	// Remember is set when the user asked to stay signed in. Other sessions use
+	// a cookie that ends with the browser session and a shorter idle timeout.
+	Remember bool `json:"remember,omitempty"`
+}
+
+// ID returns the session's public identifier: the keyed hash of its token. It
+// is safe to show to users and staff and cannot be used to authenticate.
+func (s Session) ID() string {
+	return s.TokenHash
 }
 
 // IsExpired reports whether the session has expired.
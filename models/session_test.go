@@ -111,3 +111,10 @@ func TestSession_Clone_Independence(t *testing.T) {
 		t.Errorf("original Token was mutated: got %q", s.Token)
 	}
 }
+
+func TestSession_ID_IsTokenHash(t *testing.T) {
+	s := Session{Token: "raw-token", TokenHash: "5f2b8c"}
+	if s.ID() != s.TokenHash {
+		t.Errorf("ID: got %q want %q", s.ID(), s.TokenHash)
+	}
+}
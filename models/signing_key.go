@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/check"
+	"github.com/zoobzio/sum"
+)
+
+// Algorithms a SigningKey can use, named as in the JWS "alg" header.
+const (
+	SigningAlgorithmEdDSA = "EdDSA"
+	SigningAlgorithmES256 = "ES256"
+)
+
+// SigningKey is a key pair that signs access tokens. A key is published from
+// creation until ExpiresAt, signs new tokens from ActivatesAt until RetiresAt,
+// and outlives its retirement long enough for the tokens it signed to expire.
+type SigningKey struct {
+	ID          string    `json:"id" db:"id" constraints:"primarykey" description:"Key ID, published as the JWK kid" example:"Yp3bHk2xQ0mC8vR1"`
+	Algorithm   string    `json:"algorithm" db:"algorithm" constraints:"notnull" description:"JWS algorithm: EdDSA or ES256" example:"EdDSA"`
+	PrivateKey  string    `json:"-" db:"private_key" constraints:"notnull" store.encrypt:"aes" load.decrypt:"aes" description:"Encrypted base64 PKCS #8 private key"`
+	PublicKey   string    `json:"-" db:"public_key" constraints:"notnull" description:"Base64 PKIX public key"`
+	ActivatesAt time.Time `json:"activates_at" db:"activates_at" constraints:"notnull" description:"Time the key starts signing tokens"`
+	RetiresAt   time.Time `json:"retires_at" db:"retires_at" constraints:"notnull" description:"Time the key stops signing tokens"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at" constraints:"notnull" description:"Time the key is withdrawn from the key set"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+}
+
+// IsActive reports whether the key signs new tokens at now.
+func (k SigningKey) IsActive(now time.Time) bool {
+	return !now.Before(k.ActivatesAt) && now.Before(k.RetiresAt)
+}
+
+// IsExpired reports whether the key is no longer published at now.
+func (k SigningKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// BeforeSave encrypts sensitive fields before database write.
+func (k *SigningKey) BeforeSave(ctx context.Context) error {
+	b := sum.MustUse[*sum.Boundary[SigningKey]](ctx)
+	stored, err := b.Store(ctx, *k)
+	if err != nil {
+		return err
+	}
+	*k = stored
+	return nil
+}
+
+// AfterLoad decrypts sensitive fields after database read.
+func (k *SigningKey) AfterLoad(ctx context.Context) error {
+	b := sum.MustUse[*sum.Boundary[SigningKey]](ctx)
+	loaded, err := b.Load(ctx, *k)
+	if err != nil {
+		return err
+	}
+	*k = loaded
+	return nil
+}
+
+// Validate validates the SigningKey model.
+func (k SigningKey) Validate() error {
+	return check.All(
+		check.Str(k.ID, "id").Required().V(),
+		check.Str(k.Algorithm, "algorithm").Required().OneOf([]string{SigningAlgorithmEdDSA, SigningAlgorithmES256}).V(),
+		check.Str(k.PrivateKey, "private_key").Required().V(),
+		check.Str(k.PublicKey, "public_key").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the SigningKey.
+func (k SigningKey) Clone() SigningKey {
+	return k
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func validSigningKey() SigningKey {
+	now := time.Now()
+	return SigningKey{
+		ID:          "kid",
+		Algorithm:   SigningAlgorithmEdDSA,
+		PrivateKey:  "private",
+		PublicKey:   "public",
+		ActivatesAt: now,
+		RetiresAt:   now.Add(time.Hour),
+		ExpiresAt:   now.Add(2 * time.Hour),
+	}
+}
+
+func TestSigningKey_Validate_Success(t *testing.T) {
+	if err := validSigningKey().Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestSigningKey_Validate_UnknownAlgorithm(t *testing.T) {
+	k := validSigningKey()
+	k.Algorithm = "HS256"
+	if err := k.Validate(); err == nil {
+		t.Fatal("expected error for HS256, got nil")
+	}
+}
+
+func TestSigningKey_Validate_MissingPrivateKey(t *testing.T) {
+	k := validSigningKey()
+	k.PrivateKey = ""
+	if err := k.Validate(); err == nil {
+		t.Fatal("expected error for missing PrivateKey, got nil")
+	}
+}
+
+func TestSigningKey_IsActive(t *testing.T) {
+	k := validSigningKey()
+	if k.IsActive(k.ActivatesAt.Add(-time.Second)) {
+		t.Error("active before ActivatesAt")
+	}
+	if !k.IsActive(k.ActivatesAt) {
+		t.Error("inactive at ActivatesAt")
+	}
+	if k.IsActive(k.RetiresAt) {
+		t.Error("active at RetiresAt")
+	}
+}
+
+func TestSigningKey_IsExpired(t *testing.T) {
+	k := validSigningKey()
+	if k.IsExpired(k.RetiresAt) {
+		t.Error("expired at RetiresAt")
+	}
+	if !k.IsExpired(k.ExpiresAt) {
+		t.Error("not expired at ExpiresAt")
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/check"
+	"github.com/zoobzio/sum"
+)
+
+// TOTPSecret holds a user's time-based one-time password enrollment.
+// A secret is pending until ConfirmedAt is set by a successful first code.
+type TOTPSecret struct {
+	UserID       string     `json:"user_id" db:"user_id" constraints:"primarykey" references:"users(id)" description:"FK to users.id" example:"01942d3a-1234-7abc-8def-0123456789ab"`
+	Secret       string     `json:"-" db:"secret" constraints:"notnull" store.encrypt:"aes" load.decrypt:"aes" description:"Encrypted base32 TOTP secret"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at" description:"Time enrollment was confirmed, null while pending"`
+	LastUsedStep int64      `json:"-" db:"last_used_step" constraints:"notnull" default:"0" description:"Last accepted time step, used to reject replayed codes"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Record creation time"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at" constraints:"notnull" default:"now()" description:"Last update time"`
+}
+
+// IsEnabled reports whether the enrollment has been confirmed.
+func (t TOTPSecret) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// BeforeSave encrypts sensitive fields before database write.
+func (t *TOTPSecret) BeforeSave(ctx context.Context) error {
+	b := sum.MustUse[*sum.Boundary[TOTPSecret]](ctx)
+	stored, err := b.Store(ctx, *t)
+	if err != nil {
+		return err
+	}
+	*t = stored
+	return nil
+}
+
+// AfterLoad decrypts sensitive fields after database read.
+func (t *TOTPSecret) AfterLoad(ctx context.Context) error {
+	b := sum.MustUse[*sum.Boundary[TOTPSecret]](ctx)
+	loaded, err := b.Load(ctx, *t)
+	if err != nil {
+		return err
+	}
+	*t = loaded
+	return nil
+}
+
+// Validate validates the TOTPSecret model.
+func (t TOTPSecret) Validate() error {
+	return check.All(
+		check.Str(t.UserID, "user_id").Required().V(),
+		check.Str(t.Secret, "secret").Required().V(),
+	).Err()
+}
+
+// Clone returns a deep copy of the TOTPSecret.
+func (t TOTPSecret) Clone() TOTPSecret {
+	c := t
+	if t.ConfirmedAt != nil {
+		ts := *t.ConfirmedAt
+		c.ConfirmedAt = &ts
+	}
+	return c
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTOTPSecret_Validate_Success(t *testing.T) {
+	s := TOTPSecret{
+		UserID: "01942d3a-1234-7abc-8def-0123456789ab",
+		Secret: "JBSWY3DPEHPK3PXP",
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestTOTPSecret_Validate_MissingUserID(t *testing.T) {
+	s := TOTPSecret{Secret: "JBSWY3DPEHPK3PXP"}
+	if err := s.Validate(); err == nil {
+		t.Fatal("expected error for missing UserID, got nil")
+	}
+}
+
+func TestTOTPSecret_Validate_MissingSecret(t *testing.T) {
+	s := TOTPSecret{UserID: "01942d3a-1234-7abc-8def-0123456789ab"}
+	if err := s.Validate(); err == nil {
+		t.Fatal("expected error for missing Secret, got nil")
+	}
+}
+
+func TestTOTPSecret_IsEnabled(t *testing.T) {
+	s := TOTPSecret{}
+	if s.IsEnabled() {
+		t.Error("expected pending enrollment to be disabled")
+	}
+	now := time.Now()
+	s.ConfirmedAt = &now
+	if !s.IsEnabled() {
+		t.Error("expected confirmed enrollment to be enabled")
+	}
+}
+
+func TestTOTPSecret_Clone_DeepCopiesConfirmedAt(t *testing.T) {
+	now := time.Now()
+	orig := TOTPSecret{UserID: "uid", Secret: "s", ConfirmedAt: &now}
+	c := orig.Clone()
+
+	later := now.Add(time.Hour)
+	*c.ConfirmedAt = later
+	if !orig.ConfirmedAt.Equal(now) {
+		t.Error("mutating clone's ConfirmedAt should not affect original")
+	}
+}
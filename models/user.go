@@ -11,10 +11,11 @@ import (
 type User struct {
 	ID            string    `json:"id" db:"id" constraints:"primarykey" description:"UUID v7 primary key" example:"01942d3a-1234-7abc-8def-0123456789ab"`
 	Email         string    `json:"email" db:"email" constraints:"notnull,unique" description:"Email address" example:"user@example.com"`
-	PasswordHash  *string   `json:"-" db:"password_hash" description:"argon2id hash, null for passwordless users"`
+	PasswordHash  *string   `json:"-" db:"password_hash" description:"argon2id hash (or an imported legacy hash until next login), null for passwordless users"`
 	EmailVerified bool      `json:"email_verified" db:"email_verified" constraints:"notnull" default:"false" description:"Whether the email address has been verified"`
 	Name          *string   `json:"name,omitempty" db:"name" description:"Display name" example:"Jane Doe"`
 	AvatarURL     *string   `json:"avatar_url,omitempty" db:"avatar_url" description:"Avatar URL" example:"https://avatars.githubusercontent.com/u/1"`
+	IsAdmin       bool      `json:"is_admin" db:"is_admin" constraints:"notnull" default:"false" description:"Whether the user may operate the admin API"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at" constraints:"notnull" default:"now()" description:"Account creation time"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at" constraints:"notnull" default:"now()" description:"Last update time"`
 }
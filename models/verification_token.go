@@ -16,15 +16,21 @@ const (
 	TokenTypeMagicLink TokenType = "magic_link"
 	// TokenTypePasswordReset is issued to authorise a password reset.
 	TokenTypePasswordReset TokenType = "password_reset"
+	// TokenTypeMFAPending is issued after a correct password when a second factor is still required.
+	TokenTypeMFAPending TokenType = "mfa_pending"
 )
 
 // VerificationToken is a short-lived, single-use token for email verification,
-// magic-link sign-in, or password reset flows. Tokens are stored in Redis with
-// a TTL derived from their type.
+// magic-link sign-in, password reset, or pending MFA login flows. Tokens are
+// stored in Redis with a TTL derived from their type.
 type VerificationToken struct {
-	Token     string    `json:"token"`
-	UserID    string    `json:"user_id"`
-	Type      TokenType `json:"type"`
+	Token  string    `json:"token"`
+	UserID string    `json:"user_id"`
+	Type   TokenType `json:"type"`
+	// ReturnTo is the post-login redirect requested when the flow began.
+	ReturnTo string `json:"return_to,omitempty"`
+	// Remember is the "remember me" choice made when a login flow began.
+	Remember  bool      `json:"remember,omitempty"`
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -43,6 +49,7 @@ func (v VerificationToken) Validate() error {
 			string(TokenTypeEmailVerify),
 			string(TokenTypeMagicLink),
 			string(TokenTypePasswordReset),
+			string(TokenTypeMFAPending),
 		}).V(),
 	).Err()
 }
@@ -21,7 +21,7 @@ func TestVerificationToken_Validate_Success(t *testing.T) {
 }
 
 func TestVerificationToken_Validate_AllTypes(t *testing.T) {
-	types := []TokenType{TokenTypeEmailVerify, TokenTypeMagicLink, TokenTypePasswordReset}
+	types := []TokenType{TokenTypeEmailVerify, TokenTypeMagicLink, TokenTypePasswordReset, TokenTypeMFAPending}
 	for _, tt := range types {
 		v := VerificationToken{
 			Token:  "tok",
@@ -0,0 +1,73 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// APITokens provides database access for personal access tokens, which are
+// looked up by the keyed hash of the token.
+type APITokens struct {
+	*sum.Database[models.APIToken]
+	hasher TokenHasher
+}
+
+// NewAPITokens creates a new API tokens store backed by PostgreSQL.
+func NewAPITokens(db *sqlx.DB, renderer astql.Renderer, hasher TokenHasher) (*APITokens, error) {
+	database, err := sum.NewDatabase[models.APIToken](db, "api_tokens", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &APITokens{Database: database, hasher: hasher}, nil
+}
+
+// Create stores token under the hash of raw and returns it with its
+// generated ID.
+func (s *APITokens) Create(ctx context.Context, raw string, token *models.APIToken) (*models.APIToken, error) {
+	token.TokenHash = s.hasher.Hash(raw)
+	return s.Insert().Exec(ctx, token)
+}
+
+// GetByToken retrieves the token whose raw value is raw.
+func (s *APITokens) GetByToken(ctx context.Context, raw string) (*models.APIToken, error) {
+	return s.Select().
+		Where("token_hash", "=", "token_hash").
+		Exec(ctx, map[string]any{"token_hash": s.hasher.Hash(raw)})
+}
+
+// ListByUser retrieves all tokens of a user, newest first.
+func (s *APITokens) ListByUser(ctx context.Context, userID string) ([]*models.APIToken, error) {
+	return s.Query().
+		Where("user_id", "=", "user_id").
+		OrderBy("created_at", "DESC").
+		Exec(ctx, map[string]any{"user_id": userID})
+}
+
+// TouchLastUsed records that the token with ID id authenticated a request at.
+func (s *APITokens) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	_, err := s.Modify().
+		Set("last_used_at", "last_used_at").
+		Where("id", "=", "id").
+		Exec(ctx, map[string]any{
+			"id":           id,
+			"last_used_at": at,
+		})
+	return err
+}
+
+// DeleteByUserAndID removes a token only if it belongs to the given user.
+func (s *APITokens) DeleteByUserAndID(ctx context.Context, userID string, id int64) error {
+	_, err := s.Remove().
+		Where("user_id", "=", "user_id").
+		Where("id", "=", "id").
+		Exec(ctx, map[string]any{
+			"user_id": userID,
+			"id":      id,
+		})
+	return err
+}
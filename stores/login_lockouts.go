@@ -0,0 +1,122 @@
+package stores
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zoobzio/grub"
+	intlockout "github.com/zoobzio/sumatra/internal/lockout"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// loginLockoutPrefix namespaces lockout records. Records were JSON values under
+// "login_lockout:" before failures were counted atomically; those expire on
+// their own.
+const loginLockoutPrefix = "lockout:"
+
+// loginLockoutKey returns the Redis key for a lockout record.
+func loginLockoutKey(subject string) string {
+	return loginLockoutPrefix + subject
+}
+
+// recordFailureScript counts a failed attempt against a lockout record and
+// locks it at the threshold, all in one step so concurrent failures are never
+// lost. Failures older than the window are forgotten first, and each lockout
+// takes the next length from the policy schedule, repeating the last.
+//
+//	KEYS[1] record key
+//	ARGV[1] now (Unix ms)
+//	ARGV[2] failure window (ms)
+//	ARGV[3] threshold
+//	ARGV[4] record TTL (ms)
+//	ARGV[5..] lockout schedule (ms)
+//
+// Returns 1 if the failure started a lockout, otherwise 0.
+var recordFailureScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local record = redis.call("HMGET", KEYS[1], "failures", "lockouts", "last_failure_at")
+local failures = tonumber(record[1]) or 0
+local lockouts = tonumber(record[2]) or 0
+local last = tonumber(record[3])
+if last and now - last > tonumber(ARGV[2]) then
+  failures = 0
+end
+failures = failures + 1
+local locked = 0
+if failures >= tonumber(ARGV[3]) then
+  failures = 0
+  lockouts = lockouts + 1
+  local step = math.min(lockouts, #ARGV - 4)
+  redis.call("HSET", KEYS[1], "locked_until", now + tonumber(ARGV[4 + step]))
+  locked = 1
+end
+redis.call("HSET", KEYS[1], "failures", failures, "lockouts", lockouts, "last_failure_at", now)
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+return locked
+`)
+
+// LoginLockouts provides Redis-backed storage for failed login tracking. Each
+// record is a hash updated by script, keyed by the subject the failures are
+// counted against: a user ID, or a derived key for addresses with no account.
+type LoginLockouts struct {
+	client redis.Cmdable
+}
+
+// NewLoginLockouts creates a new login lockouts store on the given Redis client.
+func NewLoginLockouts(client redis.Cmdable) *LoginLockouts {
+	return &LoginLockouts{client: client}
+}
+
+// Get retrieves the lockout record for a subject, or grub.ErrNotFound.
+func (s *LoginLockouts) Get(ctx context.Context, subject string) (*models.LoginLockout, error) {
+	fields, err := s.client.HGetAll(ctx, loginLockoutKey(subject)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, grub.ErrNotFound
+	}
+	return lockoutFromFields(subject, fields), nil
+}
+
+// RecordFailure counts a failed attempt at now against the subject under
+// policy and keeps the record for ttl after it. It returns the updated record
+// and whether the failure started a lockout.
+func (s *LoginLockouts) RecordFailure(ctx context.Context, subject string, policy intlockout.Policy, now time.Time, ttl time.Duration) (*models.LoginLockout, bool, error) {
+	args := []any{now.UnixMilli(), policy.Window.Milliseconds(), policy.Threshold, ttl.Milliseconds()}
+	for _, d := range policy.Schedule() {
+		args = append(args, d.Milliseconds())
+	}
+	locked, err := recordFailureScript.Run(ctx, s.client, []string{loginLockoutKey(subject)}, args...).Int()
+	if err != nil {
+		return nil, false, err
+	}
+	l, err := s.Get(ctx, subject)
+	if err != nil {
+		return nil, false, err
+	}
+	return l, locked == 1, nil
+}
+
+// Delete removes the lockout record for a subject.
+func (s *LoginLockouts) Delete(ctx context.Context, subject string) error {
+	return s.client.Del(ctx, loginLockoutKey(subject)).Err()
+}
+
+// lockoutFromFields builds a lockout record from its Redis hash. Times are
+// stored as Unix milliseconds.
+func lockoutFromFields(subject string, fields map[string]string) *models.LoginLockout {
+	l := &models.LoginLockout{UserID: subject}
+	l.Failures, _ = strconv.Atoi(fields["failures"])
+	l.Lockouts, _ = strconv.Atoi(fields["lockouts"])
+	if ms, err := strconv.ParseInt(fields["last_failure_at"], 10, 64); err == nil {
+		l.LastFailureAt = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(fields["locked_until"], 10, 64); err == nil {
+		until := time.UnixMilli(ms)
+		l.LockedUntil = &until
+	}
+	return l
+}
@@ -0,0 +1,49 @@
+package stores
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginLockoutKey_Format(t *testing.T) {
+	key := loginLockoutKey("user-123")
+	want := "lockout:user-123"
+	if key != want {
+		t.Errorf("loginLockoutKey: got %q want %q", key, want)
+	}
+}
+
+func TestLockoutFromFields_Locked(t *testing.T) {
+	last := time.UnixMilli(1700000000000)
+	until := last.Add(time.Minute)
+
+	l := lockoutFromFields("user-123", map[string]string{
+		"failures":        "0",
+		"lockouts":        "2",
+		"last_failure_at": "1700000000000",
+		"locked_until":    "1700000060000",
+	})
+	if l.UserID != "user-123" || l.Failures != 0 || l.Lockouts != 2 {
+		t.Errorf("counters: got %+v", l)
+	}
+	if !l.LastFailureAt.Equal(last) {
+		t.Errorf("LastFailureAt: got %v want %v", l.LastFailureAt, last)
+	}
+	if l.LockedUntil == nil || !l.LockedUntil.Equal(until) {
+		t.Errorf("LockedUntil: got %v want %v", l.LockedUntil, until)
+	}
+}
+
+func TestLockoutFromFields_NeverLocked(t *testing.T) {
+	l := lockoutFromFields("user-123", map[string]string{
+		"failures":        "2",
+		"lockouts":        "0",
+		"last_failure_at": "1700000000000",
+	})
+	if l.Failures != 2 {
+		t.Errorf("Failures: got %d want 2", l.Failures)
+	}
+	if l.LockedUntil != nil {
+		t.Errorf("LockedUntil: got %v want nil", l.LockedUntil)
+	}
+}
@@ -0,0 +1,93 @@
+package stores
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+const (
+	oauthRequestPrefix = "oauth_request:"
+	oauthCodePrefix    = "oauth_code:"
+)
+
+// ErrCodeNotFound is returned when an authorization code is unknown, expired
+// or already redeemed.
+var ErrCodeNotFound = errors.New("authorization code not found")
+
+// oauthRequestKey returns the Redis key for an authorization request awaiting consent.
+func oauthRequestKey(id string) string {
+	return oauthRequestPrefix + id
+}
+
+// oauthCodeKey returns the Redis key for an authorization code hash.
+func oauthCodeKey(hash string) string {
+	return oauthCodePrefix + hash
+}
+
+// OAuthAuthorizations provides Redis-backed storage for authorization
+// requests awaiting consent and for the codes issued once they are approved.
+// Codes are keyed by their keyed hash and stored without the raw code.
+type OAuthAuthorizations struct {
+	*sum.Store[models.OAuthAuthorization]
+	client redis.Cmdable
+	hasher TokenHasher
+}
+
+// NewOAuthAuthorizations creates a new OAuth authorizations store backed by a
+// Redis key-value provider. client must address the same database; codes are
+// redeemed through it atomically.
+func NewOAuthAuthorizations(provider grub.StoreProvider, client redis.Cmdable, hasher TokenHasher) (*OAuthAuthorizations, error) {
+	store, err := sum.NewStore[models.OAuthAuthorization](provider, "oauth_authorizations")
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthAuthorizations{Store: store, client: client, hasher: hasher}, nil
+}
+
+// GetRequest retrieves an authorization request awaiting consent by its ID.
+func (s *OAuthAuthorizations) GetRequest(ctx context.Context, id string) (*models.OAuthAuthorization, error) {
+	return s.Store.Get(ctx, oauthRequestKey(id))
+}
+
+// SetRequest stores an authorization request awaiting consent with the given TTL.
+func (s *OAuthAuthorizations) SetRequest(ctx context.Context, request *models.OAuthAuthorization, ttl time.Duration) error {
+	return s.Store.Set(ctx, oauthRequestKey(request.ID), request, ttl)
+}
+
+// DeleteRequest removes an authorization request awaiting consent by its ID.
+func (s *OAuthAuthorizations) DeleteRequest(ctx context.Context, id string) error {
+	return s.Store.Delete(ctx, oauthRequestKey(id))
+}
+
+// SetCode stores an approved authorization under the hash of its Code with
+// the given TTL.
+func (s *OAuthAuthorizations) SetCode(ctx context.Context, authorization *models.OAuthAuthorization, ttl time.Duration) error {
+	stored := authorization.Clone()
+	stored.Code = ""
+	return s.Store.Set(ctx, oauthCodeKey(s.hasher.Hash(authorization.Code)), &stored, ttl)
+}
+
+// TakeCode retrieves and deletes the authorization for code in one step, so a
+// code can be redeemed only once however many requests race for it.
+func (s *OAuthAuthorizations) TakeCode(ctx context.Context, code string) (*models.OAuthAuthorization, error) {
+	raw, err := s.client.GetDel(ctx, oauthCodeKey(s.hasher.Hash(code))).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var authorization models.OAuthAuthorization
+	if err := json.Unmarshal(raw, &authorization); err != nil {
+		return nil, err
+	}
+	authorization.Code = code
+	return &authorization, nil
+}
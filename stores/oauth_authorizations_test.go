@@ -0,0 +1,50 @@
+package stores
+
+import "testing"
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Key helper functions
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestOAuthRequestKey_Format(t *testing.T) {
+	if got, want := oauthRequestKey("abc123"), "oauth_request:abc123"; got != want {
+		t.Errorf("oauthRequestKey: got %q want %q", got, want)
+	}
+}
+
+func TestOAuthCodeKey_Format(t *testing.T) {
+	if got, want := oauthCodeKey("hash"), "oauth_code:hash"; got != want {
+		t.Errorf("oauthCodeKey: got %q want %q", got, want)
+	}
+}
+
+func TestOAuthRefreshKey_Format(t *testing.T) {
+	if got, want := oauthRefreshKey("hash"), "oauth_refresh:hash"; got != want {
+		t.Errorf("oauthRefreshKey: got %q want %q", got, want)
+	}
+}
+
+func TestOAuthRefreshIndexKeys_Format(t *testing.T) {
+	for got, want := range map[string]string{
+		oauthRefreshUsedKey("hash"):     "oauth_refresh_used:hash",
+		oauthRefreshFamilyKey("family"): "oauth_refresh_family:family",
+		oauthRefreshUserKey("user"):     "oauth_refresh_user:user",
+	} {
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+}
+
+func TestOAuthKeys_Distinct(t *testing.T) {
+	keys := map[string]bool{}
+	for _, k := range []string{
+		oauthRequestKey("x"), oauthCodeKey("x"), oauthRefreshKey("x"), oauthRefreshUsedKey("x"),
+		oauthRefreshFamilyKey("x"), oauthRefreshUserKey("x"), sessionKey("x"), verificationKey("x"),
+	} {
+		if keys[k] {
+			t.Errorf("key %q collides", k)
+		}
+		keys[k] = true
+	}
+}
@@ -0,0 +1,39 @@
+package stores
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// OAuthClients provides database access for applications registered with the
+// authorization server, keyed by client ID.
+type OAuthClients struct {
+	*sum.Database[models.OAuthClient]
+}
+
+// NewOAuthClients creates a new OAuth clients store backed by PostgreSQL.
+func NewOAuthClients(db *sqlx.DB, renderer astql.Renderer) (*OAuthClients, error) {
+	database, err := sum.NewDatabase[models.OAuthClient](db, "oauth_clients", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthClients{Database: database}, nil
+}
+
+// List returns a paginated list of clients ordered by created_at DESC.
+func (s *OAuthClients) List(ctx context.Context, limit, offset int) ([]*models.OAuthClient, error) {
+	return s.Query().
+		OrderBy("created_at", "DESC").
+		Limit(limit).
+		Offset(offset).
+		Exec(ctx, nil)
+}
+
+// Count returns the total number of clients.
+func (s *OAuthClients) Count(ctx context.Context) (float64, error) {
+	return s.Database.Count().Exec(ctx, nil)
+}
@@ -0,0 +1,52 @@
+package stores
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// OAuthConsents provides database access for the scopes users have granted clients.
+type OAuthConsents struct {
+	*sum.Database[models.OAuthConsent]
+}
+
+// NewOAuthConsents creates a new OAuth consents store backed by PostgreSQL.
+func NewOAuthConsents(db *sqlx.DB, renderer astql.Renderer) (*OAuthConsents, error) {
+	database, err := sum.NewDatabase[models.OAuthConsent](db, "oauth_consents", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthConsents{Database: database}, nil
+}
+
+// GetByUserAndClient retrieves the consent a user has given a client.
+func (s *OAuthConsents) GetByUserAndClient(ctx context.Context, userID, clientID string) (*models.OAuthConsent, error) {
+	return s.Select().
+		Where("user_id", "=", "user_id").
+		Where("client_id", "=", "client_id").
+		Exec(ctx, map[string]any{
+			"user_id":   userID,
+			"client_id": clientID,
+		})
+}
+
+// Grant adds scopes to the consent a user has given a client, creating it if
+// there is none.
+func (s *OAuthConsents) Grant(ctx context.Context, userID, clientID string, scopes []string) error {
+	existing, err := s.GetByUserAndClient(ctx, userID, clientID)
+	if err != nil || existing == nil {
+		consent := &models.OAuthConsent{UserID: userID, ClientID: clientID}
+		consent.Grant(scopes)
+		_, err := s.Insert().Exec(ctx, consent)
+		return err
+	}
+	existing.Grant(scopes)
+	existing.UpdatedAt = time.Now()
+	return s.Set(ctx, strconv.FormatInt(existing.ID, 10), existing)
+}
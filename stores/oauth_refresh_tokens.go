@@ -0,0 +1,225 @@
+package stores
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+const (
+	oauthRefreshPrefix       = "oauth_refresh:"
+	oauthRefreshUsedPrefix   = "oauth_refresh_used:"
+	oauthRefreshFamilyPrefix = "oauth_refresh_family:"
+	oauthRefreshUserPrefix   = "oauth_refresh_user:"
+)
+
+// refreshFamilyRevoked marks a revoked refresh token family.
+const refreshFamilyRevoked = "revoked"
+
+// ErrRefreshTokenNotFound is returned when a refresh token is unknown,
+// expired, revoked or already used.
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// oauthRefreshKey returns the Redis key for a refresh token hash.
+func oauthRefreshKey(hash string) string {
+	return oauthRefreshPrefix + hash
+}
+
+// oauthRefreshUsedKey returns the Redis key recording the family of a used
+// refresh token hash.
+func oauthRefreshUsedKey(hash string) string {
+	return oauthRefreshUsedPrefix + hash
+}
+
+// oauthRefreshFamilyKey returns the Redis key holding a refresh token
+// family's status.
+func oauthRefreshFamilyKey(family string) string {
+	return oauthRefreshFamilyPrefix + family
+}
+
+// oauthRefreshUserKey returns the Redis key of the set of a user's refresh
+// token families.
+func oauthRefreshUserKey(userID string) string {
+	return oauthRefreshUserPrefix + userID
+}
+
+// refreshFamilyAddScript marks a family live, unless it has been revoked,
+// adds it to its user's set, and keeps both for as long as the newest token.
+//
+//	KEYS[1] family key
+//	KEYS[2] user family set key
+//	ARGV[1] family ID
+//	ARGV[2] TTL (ms)
+var refreshFamilyAddScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= "revoked" then
+  redis.call("SET", KEYS[1], "live", "PX", ARGV[2])
+elseif redis.call("PTTL", KEYS[1]) < tonumber(ARGV[2]) then
+  redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+redis.call("SADD", KEYS[2], ARGV[1])
+redis.call("PEXPIRE", KEYS[2], ARGV[2])
+return 1
+`)
+
+// refreshTakeScript deletes a refresh token and returns it, recording its
+// family under the used-token key for as long as the token would have lived.
+// A token of a revoked family is deleted but not returned.
+//
+//	KEYS[1] token key
+//	KEYS[2] used-token key
+//	ARGV[1] family key prefix
+var refreshTakeScript = redis.NewScript(`
+local raw = redis.call("GET", KEYS[1])
+if not raw then
+  return false
+end
+local ttl = redis.call("PTTL", KEYS[1])
+redis.call("DEL", KEYS[1])
+local family = cjson.decode(raw).family_id
+if type(family) ~= "string" or family == "" then
+  return raw
+end
+if ttl > 0 then
+  redis.call("SET", KEYS[2], family, "PX", ttl)
+end
+if redis.call("GET", ARGV[1] .. family) == "revoked" then
+  return false
+end
+return raw
+`)
+
+// refreshRevokeUserScript revokes every family in a user's set and deletes
+// the set. Family keys are derived from the set members rather than passed in
+// KEYS, so this runs against a single Redis node, not a cluster.
+//
+//	KEYS[1] user family set key
+//	ARGV[1] family key prefix
+var refreshRevokeUserScript = redis.NewScript(`
+for _, family in ipairs(redis.call("SMEMBERS", KEYS[1])) do
+  redis.call("SET", ARGV[1] .. family, "revoked", "XX", "KEEPTTL")
+end
+redis.call("DEL", KEYS[1])
+return 1
+`)
+
+// OAuthRefreshTokens provides Redis-backed storage for refresh tokens issued
+// to OAuth clients, keyed by their keyed hash and stored without the raw token.
+// Tokens rotated from one another share a family, whose status is kept
+// alongside them so the whole family can be revoked at once, and each user's
+// families are indexed so all of them can be.
+type OAuthRefreshTokens struct {
+	*sum.Store[models.OAuthRefreshToken]
+	client redis.Cmdable
+	hasher TokenHasher
+}
+
+// NewOAuthRefreshTokens creates a new OAuth refresh tokens store backed by a
+// Redis key-value provider. client must address the same database; tokens
+// are exchanged through it atomically.
+func NewOAuthRefreshTokens(provider grub.StoreProvider, client redis.Cmdable, hasher TokenHasher) (*OAuthRefreshTokens, error) {
+	store, err := sum.NewStore[models.OAuthRefreshToken](provider, "oauth_refresh_tokens")
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthRefreshTokens{Store: store, client: client, hasher: hasher}, nil
+}
+
+// Get retrieves a refresh token by its token string. Tokens of a revoked
+// family are not found.
+func (s *OAuthRefreshTokens) Get(ctx context.Context, token string) (*models.OAuthRefreshToken, error) {
+	stored, err := s.Store.Get(ctx, oauthRefreshKey(s.hasher.Hash(token)))
+	if err != nil {
+		return nil, err
+	}
+	if stored.FamilyID != "" {
+		status, err := s.client.Get(ctx, oauthRefreshFamilyKey(stored.FamilyID)).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		if status == refreshFamilyRevoked {
+			return nil, ErrRefreshTokenNotFound
+		}
+	}
+	stored.Token = token
+	return stored, nil
+}
+
+// Set stores a refresh token with the given TTL, which must be positive, and
+// indexes its family under its user.
+func (s *OAuthRefreshTokens) Set(ctx context.Context, token *models.OAuthRefreshToken, ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("refresh tokens must expire")
+	}
+	if token.FamilyID != "" {
+		if err := refreshFamilyAddScript.Run(ctx, s.client,
+			[]string{oauthRefreshFamilyKey(token.FamilyID), oauthRefreshUserKey(token.UserID)},
+			token.FamilyID, strconv.FormatInt(ttl.Milliseconds(), 10),
+		).Err(); err != nil {
+			return err
+		}
+	}
+	stored := token.Clone()
+	stored.Token = ""
+	return s.Store.Set(ctx, oauthRefreshKey(s.hasher.Hash(token.Token)), &stored, ttl)
+}
+
+// Take retrieves and deletes a refresh token in one step, so a token can be
+// exchanged only once however many requests race for it. The token's family
+// is remembered for as long as the token would have lived, for UsedFamily.
+// Tokens of a revoked family are deleted but not returned.
+func (s *OAuthRefreshTokens) Take(ctx context.Context, token string) (*models.OAuthRefreshToken, error) {
+	hash := s.hasher.Hash(token)
+	raw, err := refreshTakeScript.Run(ctx, s.client,
+		[]string{oauthRefreshKey(hash), oauthRefreshUsedKey(hash)},
+		oauthRefreshFamilyPrefix,
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored models.OAuthRefreshToken
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return nil, err
+	}
+	stored.Token = token
+	return &stored, nil
+}
+
+// UsedFamily returns the family of a refresh token that has already been
+// taken, or the empty string if token was never issued or has since expired.
+func (s *OAuthRefreshTokens) UsedFamily(ctx context.Context, token string) (string, error) {
+	family, err := s.client.Get(ctx, oauthRefreshUsedKey(s.hasher.Hash(token))).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return family, err
+}
+
+// RevokeFamily revokes every refresh token of a family, including any token
+// rotated into it later. A family whose tokens have all expired is left alone.
+func (s *OAuthRefreshTokens) RevokeFamily(ctx context.Context, family string) error {
+	err := s.client.SetArgs(ctx, oauthRefreshFamilyKey(family), refreshFamilyRevoked, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// RevokeByUser revokes every refresh token family of userID in one round trip.
+func (s *OAuthRefreshTokens) RevokeByUser(ctx context.Context, userID string) error {
+	return refreshRevokeUserScript.Run(ctx, s.client, []string{oauthRefreshUserKey(userID)}, oauthRefreshFamilyPrefix).Err()
+}
+
+// Delete removes a refresh token by its token string.
+func (s *OAuthRefreshTokens) Delete(ctx context.Context, token string) error {
+	return s.Store.Delete(ctx, oauthRefreshKey(s.hasher.Hash(token)))
+}
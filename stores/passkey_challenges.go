@@ -0,0 +1,46 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+const passkeyChallengePrefix = "passkey_challenge:"
+
+// passkeyChallengeKey returns the Redis key for a passkey challenge.
+func passkeyChallengeKey(id string) string {
+	return passkeyChallengePrefix + id
+}
+
+// PasskeyChallenges provides Redis-backed storage for in-flight WebAuthn ceremonies.
+type PasskeyChallenges struct {
+	*sum.Store[models.PasskeyChallenge]
+}
+
+// NewPasskeyChallenges creates a new passkey challenges store backed by a Redis key-value provider.
+func NewPasskeyChallenges(provider grub.StoreProvider) (*PasskeyChallenges, error) {
+	store, err := sum.NewStore[models.PasskeyChallenge](provider, "passkey_challenges")
+	if err != nil {
+		return nil, err
+	}
+	return &PasskeyChallenges{Store: store}, nil
+}
+
+// Get retrieves a passkey challenge by its ID.
+func (s *PasskeyChallenges) Get(ctx context.Context, id string) (*models.PasskeyChallenge, error) {
+	return s.Store.Get(ctx, passkeyChallengeKey(id))
+}
+
+// Set stores a passkey challenge with the given TTL.
+func (s *PasskeyChallenges) Set(ctx context.Context, challenge *models.PasskeyChallenge, ttl time.Duration) error {
+	return s.Store.Set(ctx, passkeyChallengeKey(challenge.ID), challenge, ttl)
+}
+
+// Delete removes a passkey challenge by its ID.
+func (s *PasskeyChallenges) Delete(ctx context.Context, id string) error {
+	return s.Store.Delete(ctx, passkeyChallengeKey(id))
+}
@@ -0,0 +1,21 @@
+package stores
+
+import "testing"
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Key helper functions
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestPasskeyChallengeKey_Format(t *testing.T) {
+	key := passkeyChallengeKey("abc123")
+	want := "passkey_challenge:abc123"
+	if key != want {
+		t.Errorf("passkeyChallengeKey: got %q want %q", key, want)
+	}
+}
+
+func TestPasskeyChallengeKey_DistinctFromVerificationKey(t *testing.T) {
+	if passkeyChallengeKey("tok") == verificationKey("tok") {
+		t.Error("passkey challenge and verification token keys must not collide")
+	}
+}
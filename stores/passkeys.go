@@ -0,0 +1,63 @@
+package stores
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Passkeys provides database access for WebAuthn credentials.
+type Passkeys struct {
+	*sum.Database[models.Passkey]
+}
+
+// NewPasskeys creates a new passkeys store backed by PostgreSQL.
+func NewPasskeys(db *sqlx.DB, renderer astql.Renderer) (*Passkeys, error) {
+	database, err := sum.NewDatabase[models.Passkey](db, "passkeys", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &Passkeys{Database: database}, nil
+}
+
+// Create inserts a new passkey and returns it with its generated ID.
+func (s *Passkeys) Create(ctx context.Context, passkey *models.Passkey) (*models.Passkey, error) {
+	return s.Insert().Exec(ctx, passkey)
+}
+
+// GetByCredentialID retrieves a passkey by its base64url-encoded credential ID.
+func (s *Passkeys) GetByCredentialID(ctx context.Context, credentialID string) (*models.Passkey, error) {
+	return s.Select().
+		Where("credential_id", "=", "credential_id").
+		Exec(ctx, map[string]any{"credential_id": credentialID})
+}
+
+// ListByUser retrieves all passkeys for a user ordered by creation time.
+func (s *Passkeys) ListByUser(ctx context.Context, userID string) ([]*models.Passkey, error) {
+	return s.Query().
+		Where("user_id", "=", "user_id").
+		OrderBy("created_at", "ASC").
+		Exec(ctx, map[string]any{"user_id": userID})
+}
+
+// CountByUser returns the number of passkeys registered to a user.
+func (s *Passkeys) CountByUser(ctx context.Context, userID string) (float64, error) {
+	return s.Database.Count().
+		Where("user_id", "=", "user_id").
+		Exec(ctx, map[string]any{"user_id": userID})
+}
+
+// DeleteByUserAndID removes a passkey only if it belongs to the given user.
+func (s *Passkeys) DeleteByUserAndID(ctx context.Context, userID string, id int64) error {
+	_, err := s.Remove().
+		Where("user_id", "=", "user_id").
+		Where("id", "=", "id").
+		Exec(ctx, map[string]any{
+			"user_id": userID,
+			"id":      id,
+		})
+	return err
+}
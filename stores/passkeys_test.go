@@ -0,0 +1,6 @@
+// Package stores provides data access implementations for morpheus.
+// Unit tests for Passkeys cover what can be verified without a live database.
+// Custom query methods (Create, GetByCredentialID, ListByUser, CountByUser,
+// DeleteByUserAndID) delegate entirely to the sum.Database query builder and
+// are covered by integration tests.
+package stores
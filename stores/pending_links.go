@@ -0,0 +1,66 @@
+package stores
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+const pendingLinkPrefix = "pending_link:"
+
+// pendingLinkKey returns the Redis key for a pending link ID hash.
+func pendingLinkKey(hash string) string {
+	return pendingLinkPrefix + hash
+}
+
+// PendingLinks provides Redis-backed storage for provider logins awaiting
+// confirmation. Links are keyed by the keyed hash of their ID, which is the
+// only part of them carried in the OAuth state cookie.
+type PendingLinks struct {
+	*sum.Store[models.PendingLink]
+	client redis.Cmdable
+	hasher TokenHasher
+}
+
+// NewPendingLinks creates a new pending links store backed by a Redis
+// key-value provider. client must address the same database; links are
+// taken through it atomically.
+func NewPendingLinks(provider grub.StoreProvider, client redis.Cmdable, hasher TokenHasher) (*PendingLinks, error) {
+	store, err := sum.NewStore[models.PendingLink](provider, "pending_links")
+	if err != nil {
+		return nil, err
+	}
+	return &PendingLinks{Store: store, client: client, hasher: hasher}, nil
+}
+
+// Set stores a pending link with the given TTL.
+func (s *PendingLinks) Set(ctx context.Context, link *models.PendingLink, ttl time.Duration) error {
+	stored := link.Clone()
+	stored.ID = ""
+	return s.Store.Set(ctx, pendingLinkKey(s.hasher.Hash(link.ID)), &stored, ttl)
+}
+
+// Take retrieves and deletes a pending link in one step, so it can be
+// confirmed only once. It returns grub.ErrNotFound for an unknown, expired or
+// already taken link.
+func (s *PendingLinks) Take(ctx context.Context, id string) (*models.PendingLink, error) {
+	raw, err := s.client.GetDel(ctx, pendingLinkKey(s.hasher.Hash(id))).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, grub.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored models.PendingLink
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, err
+	}
+	stored.ID = id
+	return &stored, nil
+}
@@ -0,0 +1,19 @@
+package stores
+
+import "testing"
+
+// ──────────────────────────────────────────────────────────────────────────────
+// Key helper functions
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestPendingLinkKey_Format(t *testing.T) {
+	if got, want := pendingLinkKey("hash"), "pending_link:hash"; got != want {
+		t.Errorf("pendingLinkKey: got %q want %q", got, want)
+	}
+}
+
+func TestPendingLinkKey_DistinctFromVerificationKey(t *testing.T) {
+	if pendingLinkKey("tok") == verificationKey("tok") {
+		t.Error("pending link and verification token keys must not collide")
+	}
+}
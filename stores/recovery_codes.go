@@ -0,0 +1,75 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// RecoveryCodes provides database access for MFA recovery codes.
+type RecoveryCodes struct {
+	*sum.Database[models.RecoveryCode]
+}
+
+// NewRecoveryCodes creates a new recovery codes store backed by PostgreSQL.
+func NewRecoveryCodes(db *sqlx.DB, renderer astql.Renderer) (*RecoveryCodes, error) {
+	database, err := sum.NewDatabase[models.RecoveryCode](db, "recovery_codes", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &RecoveryCodes{Database: database}, nil
+}
+
+// ListUnusedByUser retrieves all unconsumed recovery codes for a user.
+func (s *RecoveryCodes) ListUnusedByUser(ctx context.Context, userID string) ([]*models.RecoveryCode, error) {
+	return s.Query().
+		Where("user_id", "=", "user_id").
+		WhereNull("used_at").
+		Exec(ctx, map[string]any{"user_id": userID})
+}
+
+// CountUnusedByUser returns the number of unconsumed recovery codes for a user.
+func (s *RecoveryCodes) CountUnusedByUser(ctx context.Context, userID string) (float64, error) {
+	return s.Database.Count().
+		Where("user_id", "=", "user_id").
+		WhereNull("used_at").
+		Exec(ctx, map[string]any{"user_id": userID})
+}
+
+// Replace discards all existing recovery codes for a user and stores the given set.
+func (s *RecoveryCodes) Replace(ctx context.Context, userID string, codes []*models.RecoveryCode) error {
+	if err := s.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	_, err := s.Insert().ExecBatch(ctx, codes)
+	return err
+}
+
+// MarkUsed consumes a recovery code. The update only matches an unused code,
+// so concurrent attempts with the same code cannot both succeed.
+func (s *RecoveryCodes) MarkUsed(ctx context.Context, id int64) error {
+	_, err := s.Modify().
+		Set("used_at", "used_at").
+		Where("id", "=", "id").
+		WhereNull("used_at").
+		Exec(ctx, map[string]any{
+			"id":      id,
+			"used_at": time.Now(),
+		})
+	return err
+}
+
+// DeleteByUser removes all recovery codes for a user.
+func (s *RecoveryCodes) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := s.Remove().
+		Where("user_id", "=", "user_id").
+		Exec(ctx, map[string]any{"user_id": userID})
+	return err
+}
@@ -0,0 +1,82 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// Roles provides database access for admin roles, keyed by slug, and their
+// assignment to users.
+type Roles struct {
+	*sum.Database[models.Role]
+	assignments *sum.Database[models.UserRole]
+}
+
+// NewRoles creates a new roles store backed by PostgreSQL.
+func NewRoles(db *sqlx.DB, renderer astql.Renderer) (*Roles, error) {
+	database, err := sum.NewDatabase[models.Role](db, "roles", renderer)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := sum.NewDatabase[models.UserRole](db, "user_roles", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &Roles{Database: database, assignments: assignments}, nil
+}
+
+// List returns every role ordered by ID.
+func (s *Roles) List(ctx context.Context) ([]*models.Role, error) {
+	return s.Query().
+		OrderBy("id", "ASC").
+		Exec(ctx, nil)
+}
+
+// ListByUser returns the roles assigned to a user, ordered by ID. Assignments
+// whose role cannot be read are skipped.
+func (s *Roles) ListByUser(ctx context.Context, userID string) ([]*models.Role, error) {
+	assigned, err := s.assignments.Query().
+		Where("user_id", "=", "user_id").
+		OrderBy("role_id", "ASC").
+		Exec(ctx, map[string]any{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]*models.Role, 0, len(assigned))
+	for _, a := range assigned {
+		role, err := s.Get(ctx, a.RoleID)
+		if err != nil || role == nil {
+			continue
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// Assign gives a user a role. The caller checks the user does not hold it
+// already; a duplicate assignment violates a unique constraint.
+func (s *Roles) Assign(ctx context.Context, userID, roleID string) error {
+	_, err := s.assignments.Insert().Exec(ctx, &models.UserRole{
+		UserID:    userID,
+		RoleID:    roleID,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
+
+// Unassign takes a role from a user.
+func (s *Roles) Unassign(ctx context.Context, userID, roleID string) error {
+	_, err := s.assignments.Remove().
+		Where("user_id", "=", "user_id").
+		Where("role_id", "=", "role_id").
+		Exec(ctx, map[string]any{
+			"user_id": userID,
+			"role_id": roleID,
+		})
+	return err
+}
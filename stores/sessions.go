@@ -3,8 +3,11 @@ package stores
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/zoobzio/grub"
 	"github.com/zoobzio/sum"
 	"github.com/zoobzio/sumatra/models"
@@ -15,107 +18,274 @@ const (
 	userIndexPrefix = "user_sessions:"
 )
 
-// sessionKey returns the primary key for a session token.
-func sessionKey(token string) string {
-	return sessionPrefix + token
+// TokenHasher derives the at-rest form of a bearer token. Redis-backed token
+// stores key their records by this hash and never persist the raw token.
+type TokenHasher interface {
+	Hash(token string) string
 }
 
-// userIndexKey returns the index key for a user's session entry.
-func userIndexKey(userID, token string) string {
-	return fmt.Sprintf("%s%s:%s", userIndexPrefix, userID, token)
+// sessionKey returns the primary key for a session token hash.
+func sessionKey(hash string) string {
+	return sessionPrefix + hash
 }
 
-// userIndexScanPrefix returns the prefix used to list all sessions for a user.
-func userIndexScanPrefix(userID string) string {
-	return userIndexPrefix + userID + ":"
+// userIndexKey returns the key of a user's session index: a sorted set of
+// session token hashes scored by expiry in Unix milliseconds.
+func userIndexKey(userID string) string {
+	return userIndexPrefix + userID
 }
 
+// legacyIndexKey returns the key of a per-session index entry, as users'
+// sessions were indexed before the sorted set. Only MigrateLegacy uses it.
+func legacyIndexKey(userID, hash string) string {
+	return fmt.Sprintf("%s%s:%s", userIndexPrefix, userID, hash)
+}
+
+// indexScore returns the index score of a session stored at now with ttl.
+// Sessions without a TTL never expire and score +inf.
+func indexScore(now time.Time, ttl time.Duration) string {
+	if ttl <= 0 {
+		return "+inf"
+	}
+	return strconv.FormatInt(now.Add(ttl).UnixMilli(), 10)
+}
+
+// indexAddScript adds a session to a user's index, prunes entries that have
+// expired, and sets the index to expire with its longest-lived session.
+//
+//	KEYS[1] index key
+//	ARGV[1] session token hash
+//	ARGV[2] expiry score (Unix ms or +inf)
+//	ARGV[3] now (Unix ms)
+var indexAddScript = redis.NewScript(`
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[3])
+local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+if last[2] == nil then
+  return 0
+end
+if last[2] == "inf" then
+  redis.call("PERSIST", KEYS[1])
+else
+  redis.call("PEXPIREAT", KEYS[1], last[2])
+end
+return 1
+`)
+
+// revokeAllScript deletes every session in a user's index except one, and
+// their index entries, returning the hashes of the sessions deleted. Expired
+// entries are pruned first so they are not reported. Session keys are derived
+// from the index members rather than passed in KEYS, so this runs against a
+// single Redis node, not a cluster.
+//
+//	KEYS[1] index key
+//	ARGV[1] session key prefix
+//	ARGV[2] now (Unix ms)
+//	ARGV[3] hash of the session to keep, or ""
+var revokeAllScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", "(" .. ARGV[2])
+local revoked = {}
+for _, hash in ipairs(redis.call("ZRANGE", KEYS[1], 0, -1)) do
+  if hash ~= ARGV[3] then
+    revoked[#revoked + 1] = hash
+  end
+end
+for i = 1, #revoked, 500 do
+  local last = math.min(i + 499, #revoked)
+  local keys = {}
+  for j = i, last do
+    keys[#keys + 1] = ARGV[1] .. revoked[j]
+  end
+  redis.call("DEL", unpack(keys))
+  redis.call("ZREM", KEYS[1], unpack(revoked, i, last))
+end
+return revoked
+`)
+
 // Sessions provides Redis-backed session storage with TTL and user index support.
+// Sessions are keyed by the keyed hash of their token, which doubles as the
+// session's public ID; the raw token exists only in the client's cookie.
+// Each user's sessions are indexed in a sorted set scored by expiry, which is
+// maintained with the client directly since the key-value provider has no
+// sorted set operations.
 type Sessions struct {
 	*sum.Store[models.Session]
+	client redis.Cmdable
+	hasher TokenHasher
 }
 
 // NewSessions creates a new sessions store backed by a Redis key-value provider.
-func NewSessions(provider grub.StoreProvider) (*Sessions, error) {
+// client must address the same Redis database as provider; it maintains the
+// user indexes.
+func NewSessions(provider grub.StoreProvider, client redis.Cmdable, hasher TokenHasher) (*Sessions, error) {
 	store, err := sum.NewStore[models.Session](provider, "sessions")
 	if err != nil {
 		return nil, err
 	}
-	return &Sessions{Store: store}, nil
+	return &Sessions{Store: store, client: client, hasher: hasher}, nil
 }
 
-// Get retrieves a session by its token.
+// Get retrieves a session by its token. The returned session carries the
+// token, which is not stored.
 func (s *Sessions) Get(ctx context.Context, token string) (*models.Session, error) {
-	return s.Store.Get(ctx, sessionKey(token))
+	session, err := s.GetByID(ctx, s.hasher.Hash(token))
+	if err != nil || session == nil {
+		return session, err
+	}
+	session.Token = token
+	return session, nil
+}
+
+// GetByID retrieves a session by its public ID (the hash of its token).
+func (s *Sessions) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	return s.Store.Get(ctx, sessionKey(id))
 }
 
 // Set stores a session with the given TTL.
 // A TTL of 0 means no expiration.
 func (s *Sessions) Set(ctx context.Context, session *models.Session, ttl time.Duration) error {
-	return s.Store.Set(ctx, sessionKey(session.Token), session, ttl)
+	stored := s.atRest(session)
+	return s.Store.Set(ctx, sessionKey(stored.TokenHash), stored, ttl)
 }
 
 // Delete removes a session by its token.
 func (s *Sessions) Delete(ctx context.Context, token string) error {
-	return s.Store.Delete(ctx, sessionKey(token))
+	return s.Store.Delete(ctx, sessionKey(s.hasher.Hash(token)))
 }
 
 // Exists reports whether a session exists for the given token.
 func (s *Sessions) Exists(ctx context.Context, token string) (bool, error) {
-	return s.Store.Exists(ctx, sessionKey(token))
+	return s.Store.Exists(ctx, sessionKey(s.hasher.Hash(token)))
 }
 
-// SetWithUserIndex stores a session and writes a corresponding user index entry.
-// The user index entry enables ListByUser and DeleteByUser operations.
+// SetWithUserIndex stores a session and adds it to its user's index, pruning
+// expired entries from the index in the same step.
+// The index enables ListByUser and DeleteByUser operations.
+// session.TokenHash is filled in from its token if unset.
 // A TTL of 0 means no expiration.
 func (s *Sessions) SetWithUserIndex(ctx context.Context, session *models.Session, ttl time.Duration) error {
-	if err := s.Store.Set(ctx, sessionKey(session.Token), session, ttl); err != nil {
+	stored := s.atRest(session)
+	session.TokenHash = stored.TokenHash
+	if err := s.Store.Set(ctx, sessionKey(stored.TokenHash), stored, ttl); err != nil {
 		return err
 	}
-	// Store an index entry: user_sessions:{userID}:{token} → token
-	// The value is a minimal session stub used only as a marker; the real session
-	// is looked up by token. We reuse models.Session with only the token set.
-	marker := &models.Session{
-		Token:  session.Token,
-		UserID: session.UserID,
-	}
-	return s.Store.Set(ctx, userIndexKey(session.UserID, session.Token), marker, ttl)
+	return s.index(ctx, session.UserID, stored.TokenHash, ttl)
+}
+
+// index adds hash to userID's index, scored by when its session expires.
+func (s *Sessions) index(ctx context.Context, userID, hash string, ttl time.Duration) error {
+	now := time.Now()
+	return indexAddScript.Run(ctx, s.client,
+		[]string{userIndexKey(userID)},
+		hash, indexScore(now, ttl), now.UnixMilli(),
+	).Err()
+}
+
+// DeleteWithUserIndex removes a session and its user index entry atomically.
+func (s *Sessions) DeleteWithUserIndex(ctx context.Context, session *models.Session) error {
+	hash := s.atRest(session).TokenHash
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(hash))
+		pipe.ZRem(ctx, userIndexKey(session.UserID), hash)
+		return nil
+	})
+	return err
 }
 
-// ListByUser returns up to limit session tokens belonging to userID.
-// It scans the user index and returns the token values.
+// ListByUser returns the public IDs of up to limit live sessions belonging to
+// userID, latest expiry first. A limit of 0 returns all of them. Expired
+// entries are pruned from the index in the same round trip.
 func (s *Sessions) ListByUser(ctx context.Context, userID string, limit int) ([]string, error) {
-	keys, err := s.Store.List(ctx, userIndexScanPrefix(userID), limit)
+	key := userIndexKey(userID)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	var ids *redis.StringSliceCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+now)
+		ids = pipe.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{Min: now, Max: "+inf", Count: int64(limit)})
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	tokens := make([]string, 0, len(keys))
-	for _, key := range keys {
-		entry, err := s.Store.Get(ctx, key)
-		if err != nil || entry == nil {
-			continue
-		}
-		tokens = append(tokens, entry.Token)
-	}
-	return tokens, nil
+	return ids.Val(), nil
 }
 
-// DeleteByUser revokes all sessions for userID by scanning the user index
-// and deleting each session and its index entry.
+// DeleteByUser revokes all sessions for userID, deleting each session and the
+// index in a single round trip.
 func (s *Sessions) DeleteByUser(ctx context.Context, userID string) error {
-	keys, err := s.Store.List(ctx, userIndexScanPrefix(userID), 0)
+	_, err := s.DeleteByUserExcept(ctx, userID, "")
+	return err
+}
+
+// DeleteByUserExcept revokes every session of userID except the one with
+// public ID keep, deleting the sessions and their index entries in a single
+// round trip however many there are. It returns the IDs of the sessions
+// revoked. An empty keep revokes them all.
+func (s *Sessions) DeleteByUserExcept(ctx context.Context, userID, keep string) ([]string, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	return revokeAllScript.Run(ctx, s.client, []string{userIndexKey(userID)}, sessionPrefix, now, keep).StringSlice()
+}
+
+// MigrateLegacy rewrites sessions stored under their raw token, as they were
+// before tokens were hashed at rest, to hashed keys, and moves every session
+// into its user's sorted set index, removing the per-session index entries
+// used before. isHash tells hashed keys apart from raw tokens. It is safe to
+// run more than once and returns the number of sessions migrated.
+func (s *Sessions) MigrateLegacy(ctx context.Context, isHash func(string) bool) (int, error) {
+	keys, err := s.Store.List(ctx, sessionPrefix, 0)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	now := time.Now()
+	migrated := 0
 	for _, key := range keys {
-		entry, err := s.Store.Get(ctx, key)
-		if err != nil || entry == nil {
-			// Best-effort: remove the index entry even if session is missing.
-			_ = s.Store.Delete(ctx, key)
+		id := strings.TrimPrefix(key, sessionPrefix)
+		session, err := s.Store.Get(ctx, key)
+		if err != nil || session == nil {
+			continue
+		}
+		ttl := session.ExpiresAt.Sub(now)
+		if isHash(id) {
+			if ttl > 0 {
+				if err := s.index(ctx, session.UserID, id, ttl); err != nil {
+					return migrated, fmt.Errorf("indexing session of user %s: %w", session.UserID, err)
+				}
+				migrated++
+			}
 			continue
 		}
-		_ = s.Store.Delete(ctx, sessionKey(entry.Token))
+		session.Token = id
+		session.TokenHash = ""
+		if ttl > 0 {
+			if err := s.SetWithUserIndex(ctx, session, ttl); err != nil {
+				return migrated, fmt.Errorf("migrating session of user %s: %w", session.UserID, err)
+			}
+			migrated++
+		}
 		_ = s.Store.Delete(ctx, key)
 	}
-	return nil
+
+	// Per-session index entries are the keys with a second segment after the
+	// user ID; the sorted set indexes have none.
+	entries, err := s.Store.List(ctx, userIndexPrefix, 0)
+	if err != nil {
+		return migrated, err
+	}
+	for _, key := range entries {
+		if strings.Contains(strings.TrimPrefix(key, userIndexPrefix), ":") {
+			_ = s.Store.Delete(ctx, key)
+		}
+	}
+	return migrated, nil
+}
+
+// atRest returns the copy of session that is persisted: keyed by its token
+// hash, without the raw token.
+func (s *Sessions) atRest(session *models.Session) *models.Session {
+	stored := session.Clone()
+	if stored.TokenHash == "" {
+		stored.TokenHash = s.hasher.Hash(session.Token)
+	}
+	stored.Token = ""
+	return &stored
 }
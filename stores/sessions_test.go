@@ -1,8 +1,10 @@
 package stores
 
 import (
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -33,93 +35,66 @@ func TestSessionKey_HasPrefix(t *testing.T) {
 }
 
 func TestUserIndexKey_Format(t *testing.T) {
-	key := userIndexKey("user-1", "tok-abc")
-	want := "user_sessions:user-1:tok-abc"
+	key := userIndexKey("user-1")
+	want := "user_sessions:user-1"
 	if key != want {
 		t.Errorf("userIndexKey: got %q want %q", key, want)
 	}
 }
 
 func TestUserIndexKey_HasPrefix(t *testing.T) {
-	key := userIndexKey("uid", "tok")
+	key := userIndexKey("uid")
 	if !strings.HasPrefix(key, userIndexPrefix) {
 		t.Errorf("userIndexKey should start with %q: got %q", userIndexPrefix, key)
 	}
 }
 
-func TestUserIndexKey_ContainsUserID(t *testing.T) {
-	userID := "01942d3a-1234-7abc-8def-0123456789ab"
-	key := userIndexKey(userID, "tok")
-	if !strings.Contains(key, userID) {
-		t.Errorf("userIndexKey should contain userID %q: got %q", userID, key)
-	}
-}
-
-func TestUserIndexKey_ContainsToken(t *testing.T) {
-	token := "mytesttoken"
-	key := userIndexKey("uid", token)
-	if !strings.Contains(key, token) {
-		t.Errorf("userIndexKey should contain token %q: got %q", token, key)
-	}
-}
-
 func TestUserIndexKey_DifferentUsersProduceDifferentKeys(t *testing.T) {
-	k1 := userIndexKey("user-1", "token")
-	k2 := userIndexKey("user-2", "token")
+	k1 := userIndexKey("user-1")
+	k2 := userIndexKey("user-2")
 	if k1 == k2 {
 		t.Errorf("different users should produce different keys: both %q", k1)
 	}
 }
 
-func TestUserIndexKey_DifferentTokensProduceDifferentKeys(t *testing.T) {
-	k1 := userIndexKey("user-1", "token-a")
-	k2 := userIndexKey("user-1", "token-b")
-	if k1 == k2 {
-		t.Errorf("different tokens should produce different keys: both %q", k1)
+func TestLegacyIndexKey_Format(t *testing.T) {
+	key := legacyIndexKey("user-1", "tok-abc")
+	want := "user_sessions:user-1:tok-abc"
+	if key != want {
+		t.Errorf("legacyIndexKey: got %q want %q", key, want)
 	}
 }
 
-func TestUserIndexScanPrefix_Format(t *testing.T) {
-	prefix := userIndexScanPrefix("user-1")
-	want := "user_sessions:user-1:"
-	if prefix != want {
-		t.Errorf("userIndexScanPrefix: got %q want %q", prefix, want)
-	}
-}
+func TestLegacyIndexKey_DistinctFromIndex(t *testing.T) {
+	// MigrateLegacy tells per-session entries apart from the sorted set by the
+	// separator after the user ID.
+	userID := "01942d3a-1234-7abc-8def-0123456789ab"
+	index := strings.TrimPrefix(userIndexKey(userID), userIndexPrefix)
+	legacy := strings.TrimPrefix(legacyIndexKey(userID, "tok"), userIndexPrefix)
 
-func TestUserIndexScanPrefix_HasUserIndexPrefix(t *testing.T) {
-	prefix := userIndexScanPrefix("uid")
-	if !strings.HasPrefix(prefix, userIndexPrefix) {
-		t.Errorf("scan prefix should start with %q: got %q", userIndexPrefix, prefix)
+	if strings.Contains(index, ":") {
+		t.Errorf("index key %q should have no separator after the prefix", index)
 	}
-}
-
-func TestUserIndexScanPrefix_EndsWithColon(t *testing.T) {
-	prefix := userIndexScanPrefix("uid")
-	if !strings.HasSuffix(prefix, ":") {
-		t.Errorf("scan prefix should end with ':': got %q", prefix)
+	if !strings.Contains(legacy, ":") {
+		t.Errorf("legacy key %q should have a separator after the prefix", legacy)
 	}
 }
 
-func TestUserIndexScanPrefix_MatchesUserIndexKeyPrefix(t *testing.T) {
-	// Keys produced by userIndexKey must start with the scan prefix for the
-	// same userID — this is the invariant the ListByUser/DeleteByUser logic
-	// relies on.
-	userID := "user-abc"
-	token := "tok-xyz"
-	key := userIndexKey(userID, token)
-	scanPrefix := userIndexScanPrefix(userID)
+// ──────────────────────────────────────────────────────────────────────────────
+// Index scores
+// ──────────────────────────────────────────────────────────────────────────────
 
-	if !strings.HasPrefix(key, scanPrefix) {
-		t.Errorf("userIndexKey %q should start with scanPrefix %q", key, scanPrefix)
+func TestIndexScore_IsExpiryMillis(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	got := indexScore(now, time.Hour)
+	want := strconv.FormatInt(now.Add(time.Hour).UnixMilli(), 10)
+	if got != want {
+		t.Errorf("indexScore: got %q want %q", got, want)
 	}
 }
 
-func TestUserIndexScanPrefix_DoesNotMatchOtherUser(t *testing.T) {
-	key := userIndexKey("user-1", "tok")
-	scanPrefix := userIndexScanPrefix("user-2")
-
-	if strings.HasPrefix(key, scanPrefix) {
-		t.Errorf("user-1 key %q should NOT match user-2 scan prefix %q", key, scanPrefix)
+func TestIndexScore_NoTTLNeverExpires(t *testing.T) {
+	if got := indexScore(time.Now(), 0); got != "+inf" {
+		t.Errorf("indexScore without TTL: got %q want %q", got, "+inf")
 	}
 }
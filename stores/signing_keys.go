@@ -0,0 +1,41 @@
+package stores
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// SigningKeys provides database access for access token signing keys, keyed by key ID.
+type SigningKeys struct {
+	*sum.Database[models.SigningKey]
+}
+
+// NewSigningKeys creates a new signing keys store backed by PostgreSQL.
+func NewSigningKeys(db *sqlx.DB, renderer astql.Renderer) (*SigningKeys, error) {
+	database, err := sum.NewDatabase[models.SigningKey](db, "signing_keys", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKeys{Database: database}, nil
+}
+
+// ListUnexpired retrieves the keys still published at now, ordered by activation time.
+func (s *SigningKeys) ListUnexpired(ctx context.Context, now time.Time) ([]*models.SigningKey, error) {
+	return s.Query().
+		Where("expires_at", ">", "now").
+		OrderBy("activates_at", "ASC").
+		Exec(ctx, map[string]any{"now": now})
+}
+
+// DeleteExpired removes the keys no longer published at now.
+func (s *SigningKeys) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := s.Remove().
+		Where("expires_at", "<=", "now").
+		Exec(ctx, map[string]any{"now": now})
+	return err
+}
@@ -7,22 +7,41 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
 	"github.com/zoobzio/astql"
 	"github.com/zoobzio/grub"
 )
 
 // Stores is the aggregate of all application data stores.
 type Stores struct {
-	Users              *Users
-	Providers          *Providers
-	Sessions           *Sessions
-	VerificationTokens *VerificationTokens
+	Users               *Users
+	Providers           *Providers
+	Sessions            *Sessions
+	VerificationTokens  *VerificationTokens
+	TOTPSecrets         *TOTPSecrets
+	RecoveryCodes       *RecoveryCodes
+	Passkeys            *Passkeys
+	PasskeyChallenges   *PasskeyChallenges
+	PendingLinks        *PendingLinks
+	LoginLockouts       *LoginLockouts
+	SigningKeys         *SigningKeys
+	OAuthClients        *OAuthClients
+	OAuthConsents       *OAuthConsents
+	OAuthAuthorizations *OAuthAuthorizations
+	OAuthRefreshTokens  *OAuthRefreshTokens
+	APITokens           *APITokens
+	Roles               *Roles
 }
 
 // New initialises all stores and returns the aggregate.
 // db and renderer are required for PostgreSQL-backed stores.
-// sessionProvider is required for the Redis-backed sessions and verification token stores.
-func New(db *sqlx.DB, renderer astql.Renderer, sessionProvider grub.StoreProvider) (*Stores, error) {
+// sessionProvider is required for the Redis-backed sessions, verification token,
+// passkey challenge and OAuth authorization and refresh token stores;
+// redisClient must address the same database, maintains the per-user session
+// indexes, counts failed logins and redeems single-use OAuth tokens. tokenHasher derives
+// the keys sessions, verification tokens and OAuth codes and refresh tokens are
+// stored under, and the hashes API tokens are looked up by.
+func New(db *sqlx.DB, renderer astql.Renderer, sessionProvider grub.StoreProvider, redisClient redis.Cmdable, tokenHasher TokenHasher) (*Stores, error) {
 	users, err := NewUsers(db, renderer)
 	if err != nil {
 		return nil, fmt.Errorf("stores: failed to create users store: %w", err)
@@ -33,20 +52,95 @@ func New(db *sqlx.DB, renderer astql.Renderer, sessionProvider grub.StoreProvide
 		return nil, fmt.Errorf("stores: failed to create providers store: %w", err)
 	}
 
-	sessions, err := NewSessions(sessionProvider)
+	totpSecrets, err := NewTOTPSecrets(db, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create totp secrets store: %w", err)
+	}
+
+	recoveryCodes, err := NewRecoveryCodes(db, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create recovery codes store: %w", err)
+	}
+
+	passkeys, err := NewPasskeys(db, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create passkeys store: %w", err)
+	}
+
+	signingKeys, err := NewSigningKeys(db, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create signing keys store: %w", err)
+	}
+
+	oauthClients, err := NewOAuthClients(db, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create oauth clients store: %w", err)
+	}
+
+	oauthConsents, err := NewOAuthConsents(db, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create oauth consents store: %w", err)
+	}
+
+	apiTokens, err := NewAPITokens(db, renderer, tokenHasher)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create api tokens store: %w", err)
+	}
+
+	roles, err := NewRoles(db, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create roles store: %w", err)
+	}
+
+	sessions, err := NewSessions(sessionProvider, redisClient, tokenHasher)
 	if err != nil {
 		return nil, fmt.Errorf("stores: failed to create sessions store: %w", err)
 	}
 
-	verificationTokens, err := NewVerificationTokens(sessionProvider)
+	verificationTokens, err := NewVerificationTokens(sessionProvider, redisClient, tokenHasher)
 	if err != nil {
 		return nil, fmt.Errorf("stores: failed to create verification tokens store: %w", err)
 	}
 
+	passkeyChallenges, err := NewPasskeyChallenges(sessionProvider)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create passkey challenges store: %w", err)
+	}
+
+	pendingLinks, err := NewPendingLinks(sessionProvider, redisClient, tokenHasher)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create pending links store: %w", err)
+	}
+
+	loginLockouts := NewLoginLockouts(redisClient)
+
+	oauthAuthorizations, err := NewOAuthAuthorizations(sessionProvider, redisClient, tokenHasher)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create oauth authorizations store: %w", err)
+	}
+
+	oauthRefreshTokens, err := NewOAuthRefreshTokens(sessionProvider, redisClient, tokenHasher)
+	if err != nil {
+		return nil, fmt.Errorf("stores: failed to create oauth refresh tokens store: %w", err)
+	}
+
 	return &Stores{
-		Users:              users,
-		Providers:          providers,
-		Sessions:           sessions,
-		VerificationTokens: verificationTokens,
+		Users:               users,
+		Providers:           providers,
+		Sessions:            sessions,
+		VerificationTokens:  verificationTokens,
+		TOTPSecrets:         totpSecrets,
+		RecoveryCodes:       recoveryCodes,
+		Passkeys:            passkeys,
+		PasskeyChallenges:   passkeyChallenges,
+		PendingLinks:        pendingLinks,
+		LoginLockouts:       loginLockouts,
+		SigningKeys:         signingKeys,
+		OAuthClients:        oauthClients,
+		OAuthConsents:       oauthConsents,
+		OAuthAuthorizations: oauthAuthorizations,
+		OAuthRefreshTokens:  oauthRefreshTokens,
+		APITokens:           apiTokens,
+		Roles:               roles,
 	}, nil
 }
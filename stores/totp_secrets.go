@@ -0,0 +1,40 @@
+package stores
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/sum"
+	"github.com/zoobzio/sumatra/models"
+)
+
+// TOTPSecrets provides database access for TOTP enrollments, keyed by user ID.
+type TOTPSecrets struct {
+	*sum.Database[models.TOTPSecret]
+}
+
+// NewTOTPSecrets creates a new TOTP secrets store backed by PostgreSQL.
+func NewTOTPSecrets(db *sqlx.DB, renderer astql.Renderer) (*TOTPSecrets, error) {
+	database, err := sum.NewDatabase[models.TOTPSecret](db, "totp_secrets", renderer)
+	if err != nil {
+		return nil, err
+	}
+	return &TOTPSecrets{Database: database}, nil
+}
+
+// AdvanceStep records step as the user's last accepted TOTP time step. The
+// update only matches an earlier step, so concurrent logins with the same
+// code cannot both succeed; the loser gets an error.
+func (s *TOTPSecrets) AdvanceStep(ctx context.Context, userID string, step int64) error {
+	_, err := s.Modify().
+		Set("last_used_step", "last_used_step").
+		Where("user_id", "=", "user_id").
+		Where("last_used_step", "<", "step").
+		Exec(ctx, map[string]any{
+			"user_id":        userID,
+			"last_used_step": step,
+			"step":           step,
+		})
+	return err
+}
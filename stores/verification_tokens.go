@@ -2,8 +2,13 @@ package stores
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/zoobzio/grub"
 	"github.com/zoobzio/sum"
 	"github.com/zoobzio/sumatra/models"
@@ -11,37 +16,92 @@ import (
 
 const verificationPrefix = "verification:"
 
-// verificationKey returns the Redis key for a verification token.
-func verificationKey(token string) string {
-	return verificationPrefix + token
+// verificationKey returns the Redis key for a verification token hash.
+func verificationKey(hash string) string {
+	return verificationPrefix + hash
 }
 
 // VerificationTokens provides Redis-backed storage for short-lived verification tokens.
+// Tokens are keyed by their keyed hash and stored without the raw token.
 type VerificationTokens struct {
 	*sum.Store[models.VerificationToken]
+	client redis.Cmdable
+	hasher TokenHasher
 }
 
-// NewVerificationTokens creates a new verification tokens store backed by a Redis key-value provider.
-func NewVerificationTokens(provider grub.StoreProvider) (*VerificationTokens, error) {
+// NewVerificationTokens creates a new verification tokens store backed by a
+// Redis key-value provider. client must address the same database; single-use
+// tokens are taken through it atomically.
+func NewVerificationTokens(provider grub.StoreProvider, client redis.Cmdable, hasher TokenHasher) (*VerificationTokens, error) {
 	store, err := sum.NewStore[models.VerificationToken](provider, "verification_tokens")
 	if err != nil {
 		return nil, err
 	}
-	return &VerificationTokens{Store: store}, nil
+	return &VerificationTokens{Store: store, client: client, hasher: hasher}, nil
 }
 
 // Get retrieves a verification token by its token string.
+// The returned record's Token is empty; callers already hold the raw token.
 func (s *VerificationTokens) Get(ctx context.Context, token string) (*models.VerificationToken, error) {
-	return s.Store.Get(ctx, verificationKey(token))
+	return s.Store.Get(ctx, verificationKey(s.hasher.Hash(token)))
 }
 
 // Set stores a verification token with the given TTL.
 // A TTL of 0 means no expiration.
 func (s *VerificationTokens) Set(ctx context.Context, token *models.VerificationToken, ttl time.Duration) error {
-	return s.Store.Set(ctx, verificationKey(token.Token), token, ttl)
+	stored := token.Clone()
+	stored.Token = ""
+	return s.Store.Set(ctx, verificationKey(s.hasher.Hash(token.Token)), &stored, ttl)
+}
+
+// Take retrieves and deletes a verification token in one step, so a token can
+// be redeemed only once however many requests race for it. It returns
+// grub.ErrNotFound for an unknown, expired or already taken token.
+func (s *VerificationTokens) Take(ctx context.Context, token string) (*models.VerificationToken, error) {
+	raw, err := s.client.GetDel(ctx, verificationKey(s.hasher.Hash(token))).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, grub.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored models.VerificationToken
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
 }
 
 // Delete removes a verification token by its token string.
 func (s *VerificationTokens) Delete(ctx context.Context, token string) error {
-	return s.Store.Delete(ctx, verificationKey(token))
+	return s.Store.Delete(ctx, verificationKey(s.hasher.Hash(token)))
+}
+
+// MigrateLegacy rewrites verification tokens stored under their raw token to
+// hashed keys, like Sessions.MigrateLegacy. It returns the number migrated.
+func (s *VerificationTokens) MigrateLegacy(ctx context.Context, isHash func(string) bool) (int, error) {
+	keys, err := s.Store.List(ctx, verificationPrefix, 0)
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for _, key := range keys {
+		raw := strings.TrimPrefix(key, verificationPrefix)
+		if isHash(raw) {
+			continue
+		}
+		legacy, err := s.Store.Get(ctx, key)
+		if err != nil || legacy == nil {
+			continue
+		}
+		legacy.Token = raw
+		if ttl := time.Until(legacy.ExpiresAt); ttl > 0 {
+			if err := s.Set(ctx, legacy, ttl); err != nil {
+				return migrated, fmt.Errorf("migrating %s token of user %s: %w", legacy.Type, legacy.UserID, err)
+			}
+			migrated++
+		}
+		_ = s.Store.Delete(ctx, key)
+	}
+	return migrated, nil
 }
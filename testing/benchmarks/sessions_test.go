@@ -0,0 +1,251 @@
+//go:build testing
+
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zoobzio/grub"
+	grubredis "github.com/zoobzio/grub/redis"
+	"github.com/zoobzio/sum"
+	intsession "github.com/zoobzio/sumatra/internal/session"
+	"github.com/zoobzio/sumatra/models"
+	"github.com/zoobzio/sumatra/stores"
+)
+
+// Session index benchmarks compare the sorted set index in stores.Sessions
+// with the scan-based index it replaced, which kept one key per session under
+// user_sessions:{userID}:{hash} and found them with a prefix scan. They need a
+// Redis at MORPHEUS_REDIS_HOST:MORPHEUS_REDIS_PORT (default localhost:6379)
+// and are skipped without one. Keys are written to MORPHEUS_BENCH_REDIS_DB
+// (default 15), which is flushed between implementations and afterwards.
+
+const (
+	// benchSessionsPerUser is the number of sessions the measured user holds.
+	benchSessionsPerUser = 10
+	// benchBackgroundSessions is the number of other users' sessions in the
+	// keyspace, which a prefix scan has to walk past.
+	benchBackgroundSessions = 10000
+)
+
+var (
+	benchOnce     sync.Once
+	benchClient   *redis.Client
+	benchStore    *stores.Sessions
+	benchSkipped  string
+	benchSetupErr error
+)
+
+// benchRedis connects to the benchmark database and creates the sessions store
+// once per process, skipping b without a Redis.
+func benchRedis(b *testing.B) (*redis.Client, *stores.Sessions) {
+	b.Helper()
+	benchOnce.Do(func() {
+		db := 15
+		if v := os.Getenv("MORPHEUS_BENCH_REDIS_DB"); v != "" {
+			if db, benchSetupErr = strconv.Atoi(v); benchSetupErr != nil {
+				return
+			}
+		}
+		client := redis.NewClient(&redis.Options{
+			Addr:     net.JoinHostPort(envOr("MORPHEUS_REDIS_HOST", "localhost"), envOr("MORPHEUS_REDIS_PORT", "6379")),
+			Password: os.Getenv("MORPHEUS_REDIS_PASSWORD"),
+			DB:       db,
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			benchSkipped = err.Error()
+			return
+		}
+		sum.New()
+		hasher, err := intsession.NewTokenHasher([]byte(strings.Repeat("k", 32)))
+		if err != nil {
+			benchSetupErr = err
+			return
+		}
+		benchClient = client
+		benchStore, benchSetupErr = stores.NewSessions(grubredis.New(client), client, hasher)
+	})
+	if benchSkipped != "" {
+		b.Skipf("redis unavailable: %s", benchSkipped)
+	}
+	if benchSetupErr != nil {
+		b.Fatal(benchSetupErr)
+	}
+	return benchClient, benchStore
+}
+
+// flush empties the benchmark database.
+func flush(b *testing.B, client *redis.Client) {
+	b.Helper()
+	if err := client.FlushDB(context.Background()).Err(); err != nil {
+		b.Fatalf("flushing benchmark database: %v", err)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// scanIndex is the scan-based user index, kept here as the baseline.
+type scanIndex struct {
+	store *grub.Store[models.Session]
+}
+
+func (s *scanIndex) SetWithUserIndex(ctx context.Context, session *models.Session, ttl time.Duration) error {
+	if err := s.store.Set(ctx, "session:"+session.TokenHash, session, ttl); err != nil {
+		return err
+	}
+	marker := &models.Session{TokenHash: session.TokenHash, UserID: session.UserID}
+	return s.store.Set(ctx, "user_sessions:"+session.UserID+":"+session.TokenHash, marker, ttl)
+}
+
+func (s *scanIndex) ListByUser(ctx context.Context, userID string, limit int) ([]string, error) {
+	keys, err := s.store.List(ctx, "user_sessions:"+userID+":", limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		entry, err := s.store.Get(ctx, key)
+		if err != nil || entry == nil || entry.TokenHash == "" {
+			continue
+		}
+		ids = append(ids, entry.TokenHash)
+	}
+	return ids, nil
+}
+
+func (s *scanIndex) DeleteByUser(ctx context.Context, userID string) error {
+	keys, err := s.store.List(ctx, "user_sessions:"+userID+":", 0)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		entry, err := s.store.Get(ctx, key)
+		if err != nil || entry == nil {
+			_ = s.store.Delete(ctx, key)
+			continue
+		}
+		if entry.TokenHash != "" {
+			_ = s.store.Delete(ctx, "session:"+entry.TokenHash)
+		}
+		_ = s.store.Delete(ctx, key)
+	}
+	return nil
+}
+
+// userIndex is the part of both implementations the benchmarks measure.
+type userIndex interface {
+	SetWithUserIndex(ctx context.Context, session *models.Session, ttl time.Duration) error
+	ListByUser(ctx context.Context, userID string, limit int) ([]string, error)
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// benchIndex names an implementation under benchmark.
+type benchIndex struct {
+	name string
+	idx  userIndex
+}
+
+// benchIndexes returns both implementations over the same client.
+func benchIndexes(b *testing.B) (*redis.Client, []benchIndex) {
+	b.Helper()
+	client, sessions := benchRedis(b)
+	b.Cleanup(func() { flush(b, client) })
+	return client, []benchIndex{
+		{name: "scan", idx: &scanIndex{store: grub.NewStore[models.Session](grubredis.New(client))}},
+		{name: "zset", idx: sessions},
+	}
+}
+
+// seed writes n sessions for userID, with tokens unique to seq.
+func seed(b *testing.B, idx userIndex, userID string, n, seq int) {
+	b.Helper()
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		token := fmt.Sprintf("%s-%d-%d", userID, seq, i)
+		s := &models.Session{
+			Token:     token,
+			TokenHash: token,
+			UserID:    userID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(time.Hour),
+		}
+		if err := idx.SetWithUserIndex(ctx, s, time.Hour); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// seedBackground empties the database and fills it with other users'
+// sessions.
+func seedBackground(b *testing.B, client *redis.Client, idx userIndex) {
+	b.Helper()
+	flush(b, client)
+	for u := 0; u < benchBackgroundSessions/benchSessionsPerUser; u++ {
+		seed(b, idx, fmt.Sprintf("background-%d", u), benchSessionsPerUser, 0)
+	}
+}
+
+func BenchmarkSessionsListByUser(b *testing.B) {
+	client, indexes := benchIndexes(b)
+	for _, bi := range indexes {
+		seedBackground(b, client, bi.idx)
+		seed(b, bi.idx, "measured", benchSessionsPerUser, 0)
+		b.Run(bi.name, func(b *testing.B) {
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ids, err := bi.idx.ListByUser(ctx, "measured", 0)
+				if err != nil || len(ids) != benchSessionsPerUser {
+					b.Fatalf("ListByUser: %d ids, %v", len(ids), err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSessionsDeleteByUser(b *testing.B) {
+	client, indexes := benchIndexes(b)
+	for _, bi := range indexes {
+		seedBackground(b, client, bi.idx)
+		b.Run(bi.name, func(b *testing.B) {
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				seed(b, bi.idx, "measured", benchSessionsPerUser, i)
+				b.StartTimer()
+				if err := bi.idx.DeleteByUser(ctx, "measured"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSessionsSetWithUserIndex(b *testing.B) {
+	client, indexes := benchIndexes(b)
+	for _, bi := range indexes {
+		seedBackground(b, client, bi.idx)
+		b.Run(bi.name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				seed(b, bi.idx, "measured", 1, i)
+			}
+		})
+	}
+}
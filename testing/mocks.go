@@ -6,27 +6,42 @@ import (
 	"context"
 	"time"
 
-	apicontracts "github.com/zoobzio/sumatra/api/contracts"
 	admincontracts "github.com/zoobzio/sumatra/admin/contracts"
+	apicontracts "github.com/zoobzio/sumatra/api/contracts"
+	intlockout "github.com/zoobzio/sumatra/internal/lockout"
 	"github.com/zoobzio/sumatra/models"
 )
 
 // Compile-time interface checks.
 var (
-	_ apicontracts.Users     = (*MockAPIUsers)(nil)
-	_ apicontracts.Providers = (*MockAPIProviders)(nil)
-	_ apicontracts.Sessions  = (*MockAPISessions)(nil)
+	_ apicontracts.Users              = (*MockAPIUsers)(nil)
+	_ apicontracts.Providers          = (*MockAPIProviders)(nil)
+	_ apicontracts.Sessions           = (*MockAPISessions)(nil)
+	_ apicontracts.TOTPSecrets        = (*MockAPITOTPSecrets)(nil)
+	_ apicontracts.RecoveryCodes      = (*MockAPIRecoveryCodes)(nil)
+	_ apicontracts.Passkeys           = (*MockAPIPasskeys)(nil)
+	_ apicontracts.PasskeyChallenges  = (*MockAPIPasskeyChallenges)(nil)
+	_ apicontracts.PendingLinks       = (*MockAPIPendingLinks)(nil)
+	_ apicontracts.OAuthRefreshTokens = (*MockAPIOAuthRefreshTokens)(nil)
+	_ apicontracts.LoginLockouts      = (*MockAPILoginLockouts)(nil)
+	_ apicontracts.APITokens          = (*MockAPIAPITokens)(nil)
 
-	_ admincontracts.Users     = (*MockAdminUsers)(nil)
-	_ admincontracts.Sessions  = (*MockAdminSessions)(nil)
-	_ admincontracts.Providers = (*MockAdminProviders)(nil)
+	_ admincontracts.Users              = (*MockAdminUsers)(nil)
+	_ admincontracts.Sessions           = (*MockAdminSessions)(nil)
+	_ admincontracts.Providers          = (*MockAdminProviders)(nil)
+	_ admincontracts.RecoveryCodes      = (*MockAdminRecoveryCodes)(nil)
+	_ admincontracts.LoginLockouts      = (*MockAdminLoginLockouts)(nil)
+	_ admincontracts.OAuthClients       = (*MockAdminOAuthClients)(nil)
+	_ admincontracts.OAuthRefreshTokens = (*MockAdminOAuthRefreshTokens)(nil)
+	_ admincontracts.APITokens          = (*MockAdminAPITokens)(nil)
+	_ admincontracts.Roles              = (*MockAdminRoles)(nil)
 )
 
 // MockAPIUsers is a mock implementation of api/contracts.Users.
 type MockAPIUsers struct {
-	OnGet          func(ctx context.Context, key string) (*models.User, error)
-	OnGetByEmail   func(ctx context.Context, email string) (*models.User, error)
-	OnSet          func(ctx context.Context, key string, user *models.User) error
+	OnGet        func(ctx context.Context, key string) (*models.User, error)
+	OnGetByEmail func(ctx context.Context, email string) (*models.User, error)
+	OnSet        func(ctx context.Context, key string, user *models.User) error
 }
 
 func (m *MockAPIUsers) Get(ctx context.Context, key string) (*models.User, error) {
@@ -52,8 +67,18 @@ func (m *MockAPIUsers) Set(ctx context.Context, key string, user *models.User) e
 
 // MockAPIProviders is a mock implementation of api/contracts.Providers.
 type MockAPIProviders struct {
-	OnGetByProviderUser func(ctx context.Context, providerType models.ProviderType, providerUserID string) (*models.Provider, error)
-	OnSet               func(ctx context.Context, key string, provider *models.Provider) error
+	OnGetByUserAndType    func(ctx context.Context, userID string, providerType models.ProviderType) (*models.Provider, error)
+	OnGetByProviderUser   func(ctx context.Context, providerType models.ProviderType, providerUserID string) (*models.Provider, error)
+	OnSet                 func(ctx context.Context, key string, provider *models.Provider) error
+	OnDeleteByUserAndType func(ctx context.Context, userID string, providerType models.ProviderType) error
+	OnListByUser          func(ctx context.Context, userID string) ([]*models.Provider, error)
+}
+
+func (m *MockAPIProviders) GetByUserAndType(ctx context.Context, userID string, providerType models.ProviderType) (*models.Provider, error) {
+	if m.OnGetByUserAndType != nil {
+		return m.OnGetByUserAndType(ctx, userID, providerType)
+	}
+	return &models.Provider{}, nil
 }
 
 func (m *MockAPIProviders) GetByProviderUser(ctx context.Context, providerType models.ProviderType, providerUserID string) (*models.Provider, error) {
@@ -70,11 +95,29 @@ func (m *MockAPIProviders) Set(ctx context.Context, key string, provider *models
 	return nil
 }
 
+func (m *MockAPIProviders) DeleteByUserAndType(ctx context.Context, userID string, providerType models.ProviderType) error {
+	if m.OnDeleteByUserAndType != nil {
+		return m.OnDeleteByUserAndType(ctx, userID, providerType)
+	}
+	return nil
+}
+
+func (m *MockAPIProviders) ListByUser(ctx context.Context, userID string) ([]*models.Provider, error) {
+	if m.OnListByUser != nil {
+		return m.OnListByUser(ctx, userID)
+	}
+	return nil, nil
+}
+
 // MockAPISessions is a mock implementation of api/contracts.Sessions.
 type MockAPISessions struct {
-	OnGet              func(ctx context.Context, token string) (*models.Session, error)
-	OnSetWithUserIndex func(ctx context.Context, session *models.Session, ttl time.Duration) error
-	OnDelete           func(ctx context.Context, token string) error
+	OnGet                 func(ctx context.Context, token string) (*models.Session, error)
+	OnGetByID             func(ctx context.Context, id string) (*models.Session, error)
+	OnSetWithUserIndex    func(ctx context.Context, session *models.Session, ttl time.Duration) error
+	OnDelete              func(ctx context.Context, token string) error
+	OnDeleteWithUserIndex func(ctx context.Context, session *models.Session) error
+	OnListByUser          func(ctx context.Context, userID string, limit int) ([]string, error)
+	OnDeleteByUserExcept  func(ctx context.Context, userID, keep string) ([]string, error)
 }
 
 func (m *MockAPISessions) Get(ctx context.Context, token string) (*models.Session, error) {
@@ -84,6 +127,13 @@ func (m *MockAPISessions) Get(ctx context.Context, token string) (*models.Sessio
 	return &models.Session{}, nil
 }
 
+func (m *MockAPISessions) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	if m.OnGetByID != nil {
+		return m.OnGetByID(ctx, id)
+	}
+	return &models.Session{}, nil
+}
+
 func (m *MockAPISessions) SetWithUserIndex(ctx context.Context, session *models.Session, ttl time.Duration) error {
 	if m.OnSetWithUserIndex != nil {
 		return m.OnSetWithUserIndex(ctx, session, ttl)
@@ -98,6 +148,267 @@ func (m *MockAPISessions) Delete(ctx context.Context, token string) error {
 	return nil
 }
 
+func (m *MockAPISessions) DeleteWithUserIndex(ctx context.Context, session *models.Session) error {
+	if m.OnDeleteWithUserIndex != nil {
+		return m.OnDeleteWithUserIndex(ctx, session)
+	}
+	return nil
+}
+
+func (m *MockAPISessions) ListByUser(ctx context.Context, userID string, limit int) ([]string, error) {
+	if m.OnListByUser != nil {
+		return m.OnListByUser(ctx, userID, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockAPISessions) DeleteByUserExcept(ctx context.Context, userID, keep string) ([]string, error) {
+	if m.OnDeleteByUserExcept != nil {
+		return m.OnDeleteByUserExcept(ctx, userID, keep)
+	}
+	return nil, nil
+}
+
+// MockAPITOTPSecrets is a mock implementation of api/contracts.TOTPSecrets.
+type MockAPITOTPSecrets struct {
+	OnGet         func(ctx context.Context, userID string) (*models.TOTPSecret, error)
+	OnSet         func(ctx context.Context, userID string, secret *models.TOTPSecret) error
+	OnAdvanceStep func(ctx context.Context, userID string, step int64) error
+	OnDelete      func(ctx context.Context, userID string) error
+}
+
+func (m *MockAPITOTPSecrets) Get(ctx context.Context, userID string) (*models.TOTPSecret, error) {
+	if m.OnGet != nil {
+		return m.OnGet(ctx, userID)
+	}
+	return &models.TOTPSecret{}, nil
+}
+
+func (m *MockAPITOTPSecrets) Set(ctx context.Context, userID string, secret *models.TOTPSecret) error {
+	if m.OnSet != nil {
+		return m.OnSet(ctx, userID, secret)
+	}
+	return nil
+}
+
+func (m *MockAPITOTPSecrets) AdvanceStep(ctx context.Context, userID string, step int64) error {
+	if m.OnAdvanceStep != nil {
+		return m.OnAdvanceStep(ctx, userID, step)
+	}
+	return nil
+}
+
+func (m *MockAPITOTPSecrets) Delete(ctx context.Context, userID string) error {
+	if m.OnDelete != nil {
+		return m.OnDelete(ctx, userID)
+	}
+	return nil
+}
+
+// MockAPIRecoveryCodes is a mock implementation of api/contracts.RecoveryCodes.
+type MockAPIRecoveryCodes struct {
+	OnListUnusedByUser func(ctx context.Context, userID string) ([]*models.RecoveryCode, error)
+	OnReplace          func(ctx context.Context, userID string, codes []*models.RecoveryCode) error
+	OnMarkUsed         func(ctx context.Context, id int64) error
+	OnDeleteByUser     func(ctx context.Context, userID string) error
+}
+
+func (m *MockAPIRecoveryCodes) ListUnusedByUser(ctx context.Context, userID string) ([]*models.RecoveryCode, error) {
+	if m.OnListUnusedByUser != nil {
+		return m.OnListUnusedByUser(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIRecoveryCodes) Replace(ctx context.Context, userID string, codes []*models.RecoveryCode) error {
+	if m.OnReplace != nil {
+		return m.OnReplace(ctx, userID, codes)
+	}
+	return nil
+}
+
+func (m *MockAPIRecoveryCodes) MarkUsed(ctx context.Context, id int64) error {
+	if m.OnMarkUsed != nil {
+		return m.OnMarkUsed(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockAPIRecoveryCodes) DeleteByUser(ctx context.Context, userID string) error {
+	if m.OnDeleteByUser != nil {
+		return m.OnDeleteByUser(ctx, userID)
+	}
+	return nil
+}
+
+// MockAPIPasskeys is a mock implementation of api/contracts.Passkeys.
+type MockAPIPasskeys struct {
+	OnCreate            func(ctx context.Context, passkey *models.Passkey) (*models.Passkey, error)
+	OnSet               func(ctx context.Context, key string, passkey *models.Passkey) error
+	OnGetByCredentialID func(ctx context.Context, credentialID string) (*models.Passkey, error)
+	OnListByUser        func(ctx context.Context, userID string) ([]*models.Passkey, error)
+	OnCountByUser       func(ctx context.Context, userID string) (float64, error)
+	OnDeleteByUserAndID func(ctx context.Context, userID string, id int64) error
+}
+
+func (m *MockAPIPasskeys) Create(ctx context.Context, passkey *models.Passkey) (*models.Passkey, error) {
+	if m.OnCreate != nil {
+		return m.OnCreate(ctx, passkey)
+	}
+	return passkey, nil
+}
+
+func (m *MockAPIPasskeys) Set(ctx context.Context, key string, passkey *models.Passkey) error {
+	if m.OnSet != nil {
+		return m.OnSet(ctx, key, passkey)
+	}
+	return nil
+}
+
+func (m *MockAPIPasskeys) GetByCredentialID(ctx context.Context, credentialID string) (*models.Passkey, error) {
+	if m.OnGetByCredentialID != nil {
+		return m.OnGetByCredentialID(ctx, credentialID)
+	}
+	return &models.Passkey{}, nil
+}
+
+func (m *MockAPIPasskeys) ListByUser(ctx context.Context, userID string) ([]*models.Passkey, error) {
+	if m.OnListByUser != nil {
+		return m.OnListByUser(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIPasskeys) CountByUser(ctx context.Context, userID string) (float64, error) {
+	if m.OnCountByUser != nil {
+		return m.OnCountByUser(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *MockAPIPasskeys) DeleteByUserAndID(ctx context.Context, userID string, id int64) error {
+	if m.OnDeleteByUserAndID != nil {
+		return m.OnDeleteByUserAndID(ctx, userID, id)
+	}
+	return nil
+}
+
+// MockAPIPasskeyChallenges is a mock implementation of api/contracts.PasskeyChallenges.
+type MockAPIPasskeyChallenges struct {
+	OnGet    func(ctx context.Context, id string) (*models.PasskeyChallenge, error)
+	OnSet    func(ctx context.Context, challenge *models.PasskeyChallenge, ttl time.Duration) error
+	OnDelete func(ctx context.Context, id string) error
+}
+
+func (m *MockAPIPasskeyChallenges) Get(ctx context.Context, id string) (*models.PasskeyChallenge, error) {
+	if m.OnGet != nil {
+		return m.OnGet(ctx, id)
+	}
+	return &models.PasskeyChallenge{}, nil
+}
+
+func (m *MockAPIPasskeyChallenges) Set(ctx context.Context, challenge *models.PasskeyChallenge, ttl time.Duration) error {
+	if m.OnSet != nil {
+		return m.OnSet(ctx, challenge, ttl)
+	}
+	return nil
+}
+
+func (m *MockAPIPasskeyChallenges) Delete(ctx context.Context, id string) error {
+	if m.OnDelete != nil {
+		return m.OnDelete(ctx, id)
+	}
+	return nil
+}
+
+// MockAPIOAuthRefreshTokens is a mock implementation of api/contracts.OAuthRefreshTokens.
+type MockAPIOAuthRefreshTokens struct {
+	OnRevokeByUser func(ctx context.Context, userID string) error
+}
+
+func (m *MockAPIOAuthRefreshTokens) RevokeByUser(ctx context.Context, userID string) error {
+	if m.OnRevokeByUser != nil {
+		return m.OnRevokeByUser(ctx, userID)
+	}
+	return nil
+}
+
+// MockAPIPendingLinks is a mock implementation of api/contracts.PendingLinks.
+type MockAPIPendingLinks struct {
+	OnSet  func(ctx context.Context, link *models.PendingLink, ttl time.Duration) error
+	OnTake func(ctx context.Context, id string) (*models.PendingLink, error)
+}
+
+func (m *MockAPIPendingLinks) Set(ctx context.Context, link *models.PendingLink, ttl time.Duration) error {
+	if m.OnSet != nil {
+		return m.OnSet(ctx, link, ttl)
+	}
+	return nil
+}
+
+func (m *MockAPIPendingLinks) Take(ctx context.Context, id string) (*models.PendingLink, error) {
+	if m.OnTake != nil {
+		return m.OnTake(ctx, id)
+	}
+	return &models.PendingLink{}, nil
+}
+
+// MockAPILoginLockouts is a mock implementation of api/contracts.LoginLockouts.
+type MockAPILoginLockouts struct {
+	OnGet           func(ctx context.Context, subject string) (*models.LoginLockout, error)
+	OnRecordFailure func(ctx context.Context, subject string, policy intlockout.Policy, now time.Time, ttl time.Duration) (*models.LoginLockout, bool, error)
+	OnDelete        func(ctx context.Context, subject string) error
+}
+
+func (m *MockAPILoginLockouts) Get(ctx context.Context, subject string) (*models.LoginLockout, error) {
+	if m.OnGet != nil {
+		return m.OnGet(ctx, subject)
+	}
+	return &models.LoginLockout{}, nil
+}
+
+func (m *MockAPILoginLockouts) RecordFailure(ctx context.Context, subject string, policy intlockout.Policy, now time.Time, ttl time.Duration) (*models.LoginLockout, bool, error) {
+	if m.OnRecordFailure != nil {
+		return m.OnRecordFailure(ctx, subject, policy, now, ttl)
+	}
+	return &models.LoginLockout{UserID: subject}, false, nil
+}
+
+func (m *MockAPILoginLockouts) Delete(ctx context.Context, subject string) error {
+	if m.OnDelete != nil {
+		return m.OnDelete(ctx, subject)
+	}
+	return nil
+}
+
+// MockAPIAPITokens is a mock implementation of api/contracts.APITokens.
+type MockAPIAPITokens struct {
+	OnCreate            func(ctx context.Context, raw string, token *models.APIToken) (*models.APIToken, error)
+	OnListByUser        func(ctx context.Context, userID string) ([]*models.APIToken, error)
+	OnDeleteByUserAndID func(ctx context.Context, userID string, id int64) error
+}
+
+func (m *MockAPIAPITokens) Create(ctx context.Context, raw string, token *models.APIToken) (*models.APIToken, error) {
+	if m.OnCreate != nil {
+		return m.OnCreate(ctx, raw, token)
+	}
+	return token, nil
+}
+
+func (m *MockAPIAPITokens) ListByUser(ctx context.Context, userID string) ([]*models.APIToken, error) {
+	if m.OnListByUser != nil {
+		return m.OnListByUser(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIAPITokens) DeleteByUserAndID(ctx context.Context, userID string, id int64) error {
+	if m.OnDeleteByUserAndID != nil {
+		return m.OnDeleteByUserAndID(ctx, userID, id)
+	}
+	return nil
+}
+
 // MockAdminUsers is a mock implementation of admin/contracts.Users.
 type MockAdminUsers struct {
 	OnGet    func(ctx context.Context, key string) (*models.User, error)
@@ -136,22 +447,22 @@ func (m *MockAdminUsers) Delete(ctx context.Context, key string) error {
 
 // MockAdminSessions is a mock implementation of admin/contracts.Sessions.
 type MockAdminSessions struct {
-	OnGet          func(ctx context.Context, token string) (*models.Session, error)
-	OnDelete       func(ctx context.Context, token string) error
-	OnListByUser   func(ctx context.Context, userID string, limit int) ([]string, error)
-	OnDeleteByUser func(ctx context.Context, userID string) error
+	OnGetByID             func(ctx context.Context, id string) (*models.Session, error)
+	OnDeleteWithUserIndex func(ctx context.Context, session *models.Session) error
+	OnListByUser          func(ctx context.Context, userID string, limit int) ([]string, error)
+	OnDeleteByUser        func(ctx context.Context, userID string) error
 }
 
-func (m *MockAdminSessions) Get(ctx context.Context, token string) (*models.Session, error) {
-	if m.OnGet != nil {
-		return m.OnGet(ctx, token)
+func (m *MockAdminSessions) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	if m.OnGetByID != nil {
+		return m.OnGetByID(ctx, id)
 	}
 	return &models.Session{}, nil
 }
 
-func (m *MockAdminSessions) Delete(ctx context.Context, token string) error {
-	if m.OnDelete != nil {
-		return m.OnDelete(ctx, token)
+func (m *MockAdminSessions) DeleteWithUserIndex(ctx context.Context, session *models.Session) error {
+	if m.OnDeleteWithUserIndex != nil {
+		return m.OnDeleteWithUserIndex(ctx, session)
 	}
 	return nil
 }
@@ -181,3 +492,155 @@ func (m *MockAdminProviders) DeleteByUser(ctx context.Context, userID string) er
 	}
 	return nil
 }
+
+// MockAdminRecoveryCodes is a mock implementation of admin/contracts.RecoveryCodes.
+type MockAdminRecoveryCodes struct {
+	OnCountUnusedByUser func(ctx context.Context, userID string) (float64, error)
+}
+
+func (m *MockAdminRecoveryCodes) CountUnusedByUser(ctx context.Context, userID string) (float64, error) {
+	if m.OnCountUnusedByUser != nil {
+		return m.OnCountUnusedByUser(ctx, userID)
+	}
+	return 0, nil
+}
+
+// MockAdminOAuthRefreshTokens is a mock implementation of admin/contracts.OAuthRefreshTokens.
+type MockAdminOAuthRefreshTokens struct {
+	OnRevokeByUser func(ctx context.Context, userID string) error
+}
+
+func (m *MockAdminOAuthRefreshTokens) RevokeByUser(ctx context.Context, userID string) error {
+	if m.OnRevokeByUser != nil {
+		return m.OnRevokeByUser(ctx, userID)
+	}
+	return nil
+}
+
+// MockAdminLoginLockouts is a mock implementation of admin/contracts.LoginLockouts.
+type MockAdminLoginLockouts struct {
+	OnGet    func(ctx context.Context, userID string) (*models.LoginLockout, error)
+	OnDelete func(ctx context.Context, userID string) error
+}
+
+func (m *MockAdminLoginLockouts) Get(ctx context.Context, userID string) (*models.LoginLockout, error) {
+	if m.OnGet != nil {
+		return m.OnGet(ctx, userID)
+	}
+	return &models.LoginLockout{}, nil
+}
+
+func (m *MockAdminLoginLockouts) Delete(ctx context.Context, userID string) error {
+	if m.OnDelete != nil {
+		return m.OnDelete(ctx, userID)
+	}
+	return nil
+}
+
+// MockAdminOAuthClients is a mock implementation of admin/contracts.OAuthClients.
+type MockAdminOAuthClients struct {
+	OnGet    func(ctx context.Context, key string) (*models.OAuthClient, error)
+	OnSet    func(ctx context.Context, key string, client *models.OAuthClient) error
+	OnList   func(ctx context.Context, limit, offset int) ([]*models.OAuthClient, error)
+	OnCount  func(ctx context.Context) (float64, error)
+	OnDelete func(ctx context.Context, key string) error
+}
+
+func (m *MockAdminOAuthClients) Get(ctx context.Context, key string) (*models.OAuthClient, error) {
+	if m.OnGet != nil {
+		return m.OnGet(ctx, key)
+	}
+	return &models.OAuthClient{}, nil
+}
+
+func (m *MockAdminOAuthClients) Set(ctx context.Context, key string, client *models.OAuthClient) error {
+	if m.OnSet != nil {
+		return m.OnSet(ctx, key, client)
+	}
+	return nil
+}
+
+func (m *MockAdminOAuthClients) List(ctx context.Context, limit, offset int) ([]*models.OAuthClient, error) {
+	if m.OnList != nil {
+		return m.OnList(ctx, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockAdminOAuthClients) Count(ctx context.Context) (float64, error) {
+	if m.OnCount != nil {
+		return m.OnCount(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockAdminOAuthClients) Delete(ctx context.Context, key string) error {
+	if m.OnDelete != nil {
+		return m.OnDelete(ctx, key)
+	}
+	return nil
+}
+
+// MockAdminAPITokens is a mock implementation of admin/contracts.APITokens.
+type MockAdminAPITokens struct {
+	OnListByUser        func(ctx context.Context, userID string) ([]*models.APIToken, error)
+	OnDeleteByUserAndID func(ctx context.Context, userID string, id int64) error
+}
+
+func (m *MockAdminAPITokens) ListByUser(ctx context.Context, userID string) ([]*models.APIToken, error) {
+	if m.OnListByUser != nil {
+		return m.OnListByUser(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockAdminAPITokens) DeleteByUserAndID(ctx context.Context, userID string, id int64) error {
+	if m.OnDeleteByUserAndID != nil {
+		return m.OnDeleteByUserAndID(ctx, userID, id)
+	}
+	return nil
+}
+
+// MockAdminRoles is a mock implementation of admin/contracts.Roles.
+type MockAdminRoles struct {
+	OnGet        func(ctx context.Context, key string) (*models.Role, error)
+	OnList       func(ctx context.Context) ([]*models.Role, error)
+	OnListByUser func(ctx context.Context, userID string) ([]*models.Role, error)
+	OnAssign     func(ctx context.Context, userID, roleID string) error
+	OnUnassign   func(ctx context.Context, userID, roleID string) error
+}
+
+func (m *MockAdminRoles) Get(ctx context.Context, key string) (*models.Role, error) {
+	if m.OnGet != nil {
+		return m.OnGet(ctx, key)
+	}
+	return &models.Role{}, nil
+}
+
+func (m *MockAdminRoles) List(ctx context.Context) ([]*models.Role, error) {
+	if m.OnList != nil {
+		return m.OnList(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockAdminRoles) ListByUser(ctx context.Context, userID string) ([]*models.Role, error) {
+	if m.OnListByUser != nil {
+		return m.OnListByUser(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockAdminRoles) Assign(ctx context.Context, userID, roleID string) error {
+	if m.OnAssign != nil {
+		return m.OnAssign(ctx, userID, roleID)
+	}
+	return nil
+}
+
+func (m *MockAdminRoles) Unassign(ctx context.Context, userID, roleID string) error {
+	if m.OnUnassign != nil {
+		return m.OnUnassign(ctx, userID, roleID)
+	}
+	return nil
+}